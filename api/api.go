@@ -116,6 +116,9 @@ func InitApi() {
 	utils.InitHTML()
 
 	app.InitEmailBatching()
+	app.InitChannelAutoArchive()
+	app.InitCustomStatusExpiry()
+	app.InitOutOfOfficeExpiry()
 }
 
 func HandleEtag(etag string, routeName string, w http.ResponseWriter, r *http.Request) bool {
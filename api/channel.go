@@ -168,6 +168,10 @@ func updateChannel(c *Context, w http.ResponseWriter, r *http.Request) {
 		oldChannel.Type = channel.Type
 	}
 
+	if len(channel.PostingPolicy) > 0 {
+		oldChannel.PostingPolicy = channel.PostingPolicy
+	}
+
 	if _, err := app.UpdateChannel(oldChannel); err != nil {
 		c.Err = err
 		return
@@ -457,7 +461,7 @@ func getChannel(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if channel.TeamId != c.TeamId && channel.Type != model.CHANNEL_DIRECT {
+	if channel.TeamId != c.TeamId && !channel.IsGroupOrDirect() {
 		c.Err = model.NewLocAppError("getChannel", "api.channel.get_channel.wrong_team.app_error", map[string]interface{}{"ChannelId": id, "TeamId": c.TeamId}, "")
 		return
 	}
@@ -493,7 +497,7 @@ func getChannelByName(c *Context, w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if channel.TeamId != c.TeamId && channel.Type != model.CHANNEL_DIRECT {
+		if channel.TeamId != c.TeamId && !channel.IsGroupOrDirect() {
 			c.Err = model.NewLocAppError("getChannel", "api.channel.get_channel.wrong_team.app_error", map[string]interface{}{"ChannelName": channelName, "TeamId": c.TeamId}, "")
 			return
 		}
@@ -5,6 +5,7 @@ package api
 
 import (
 	"os/exec"
+	"strings"
 	"testing"
 
 	"github.com/mattermost/platform/app"
@@ -312,6 +313,148 @@ func TestCliRestoreChannel(t *testing.T) {
 	}
 }
 
+func TestCliArchiveChannel(t *testing.T) {
+	if disableCliTests {
+		return
+	}
+
+	th := Setup().InitBasic()
+	channel := th.CreateChannel(th.BasicClient, th.BasicTeam)
+
+	cmd := exec.Command("bash", "-c", "go run ../cmd/platform/*.go channel archive --user "+th.BasicUser.Email+" "+th.BasicTeam.Name+":"+channel.Name)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Log(string(output))
+		t.Fatal(err)
+	}
+
+	if result := <-app.Srv.Store.Channel().Get(channel.Id, false); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if !result.Data.(*model.Channel).IsArchived() {
+		t.Fatal("channel should be archived")
+	}
+
+	cmd2 := exec.Command("bash", "-c", "go run ../cmd/platform/*.go channel unarchive --user "+th.BasicUser.Email+" "+th.BasicTeam.Name+":"+channel.Name)
+	output2, err2 := cmd2.CombinedOutput()
+	if err2 != nil {
+		t.Log(string(output2))
+		t.Fatal(err2)
+	}
+
+	if result := <-app.Srv.Store.Channel().Get(channel.Id, false); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if result.Data.(*model.Channel).IsArchived() {
+		t.Fatal("channel should be unarchived")
+	}
+
+	// should fail without a user to post the system message as
+	cmd3 := exec.Command("bash", "-c", "go run ../cmd/platform/*.go channel archive "+th.BasicTeam.Name+":"+channel.Name)
+	output3, err3 := cmd3.CombinedOutput()
+	if err3 == nil {
+		t.Log(string(output3))
+		t.Fatal()
+	}
+}
+
+func TestCliMoveChannel(t *testing.T) {
+	if disableCliTests {
+		return
+	}
+
+	th := Setup().InitBasic()
+	channel := th.CreateChannel(th.BasicClient, th.BasicTeam)
+	team := th.CreateTeam(th.BasicClient)
+
+	cmd := exec.Command("bash", "-c", "go run ../cmd/platform/*.go channel move --add_members "+th.BasicTeam.Name+":"+channel.Name+" "+team.Name)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Log(string(output))
+		t.Fatal(err)
+	}
+
+	if result := <-app.Srv.Store.Channel().Get(channel.Id, false); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if result.Data.(*model.Channel).TeamId != team.Id {
+		t.Fatal("channel should have been moved")
+	}
+
+	// should fail when moving to the team the channel is already on
+	cmd2 := exec.Command("bash", "-c", "go run ../cmd/platform/*.go channel move "+channel.Id+" "+team.Name)
+	output2, err2 := cmd2.CombinedOutput()
+	if err2 == nil {
+		t.Log(string(output2))
+		t.Fatal()
+	}
+}
+
+func TestCliModifyChannel(t *testing.T) {
+	if disableCliTests {
+		return
+	}
+
+	th := Setup().InitBasic()
+	channel := th.CreateChannel(th.BasicClient, th.BasicTeam)
+
+	cmd := exec.Command("bash", "-c", "go run ../cmd/platform/*.go channel modify --private --user "+th.BasicUser.Email+" "+th.BasicTeam.Name+":"+channel.Name)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Log(string(output))
+		t.Fatal(err)
+	}
+
+	if result := <-app.Srv.Store.Channel().Get(channel.Id, false); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if result.Data.(*model.Channel).Type != model.CHANNEL_PRIVATE {
+		t.Fatal("channel should be private")
+	}
+
+	// should fail when both types are given
+	cmd2 := exec.Command("bash", "-c", "go run ../cmd/platform/*.go channel modify --private --public --user "+th.BasicUser.Email+" "+th.BasicTeam.Name+":"+channel.Name)
+	output2, err2 := cmd2.CombinedOutput()
+	if err2 == nil {
+		t.Log(string(output2))
+		t.Fatal()
+	}
+	cmd3 := exec.Command("bash", "-c", "go run ../cmd/platform/*.go channel modify --posting_policy restricted "+th.BasicTeam.Name+":"+channel.Name)
+	output3, err3 := cmd3.CombinedOutput()
+	if err3 != nil {
+		t.Log(string(output3))
+		t.Fatal(err3)
+	}
+
+	if result := <-app.Srv.Store.Channel().Get(channel.Id, false); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if !result.Data.(*model.Channel).IsPostingRestricted() {
+		t.Fatal("posting should be restricted")
+	}
+}
+
+func TestCliListGroupMessages(t *testing.T) {
+	if disableCliTests {
+		return
+	}
+
+	th := Setup().InitBasic()
+	user3 := th.CreateUser(th.BasicClient)
+
+	channel, err := app.CreateGroupChannel([]string{th.BasicUser.Id, th.BasicUser2.Id, user3.Id}, th.BasicUser.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("bash", "-c", "go run ../cmd/platform/*.go channel list_group_messages "+th.BasicUser.Email)
+	output, err2 := cmd.CombinedOutput()
+	if err2 != nil {
+		t.Log(string(output))
+		t.Fatal(err2)
+	}
+
+	if !strings.Contains(string(output), channel.Id) {
+		t.Log(string(output))
+		t.Fatal("should have listed the group message")
+	}
+}
+
 func TestCliJoinTeam(t *testing.T) {
 	if disableCliTests {
 		return
@@ -449,3 +592,64 @@ func TestCliMakeUserActiveAndInactive(t *testing.T) {
 		t.Fatal()
 	}
 }
+
+func TestCliGroups(t *testing.T) {
+	if disableCliTests {
+		return
+	}
+
+	th := Setup().InitBasic()
+
+	name := "group" + model.NewId()
+
+	cmd := exec.Command("bash", "-c", `go run ../cmd/platform/*.go group create --name "`+name+`" --display_name "Group"`)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Log(string(output))
+		t.Fatal(err)
+	}
+
+	var group *model.Group
+	if result := <-app.Srv.Store.Group().GetByName(name); result.Err != nil {
+		t.Fatal("Failed to create group")
+	} else {
+		group = result.Data.(*model.Group)
+	}
+
+	cmd2 := exec.Command("bash", "-c", "go run ../cmd/platform/*.go group add "+name+" "+th.BasicUser.Email)
+	output2, err2 := cmd2.CombinedOutput()
+	if err2 != nil {
+		t.Log(string(output2))
+		t.Fatal(err2)
+	}
+
+	if result := <-app.Srv.Store.Group().GetMembers(group.Id, 0, 100); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if members := result.Data.([]*model.GroupMember); len(members) != 1 || members[0].UserId != th.BasicUser.Id {
+		t.Fatal("user should be in group")
+	}
+
+	cmd3 := exec.Command("bash", "-c", "go run ../cmd/platform/*.go group remove "+name+" "+th.BasicUser.Email)
+	output3, err3 := cmd3.CombinedOutput()
+	if err3 != nil {
+		t.Log(string(output3))
+		t.Fatal(err3)
+	}
+
+	if result := <-app.Srv.Store.Group().GetMembers(group.Id, 0, 100); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if members := result.Data.([]*model.GroupMember); len(members) != 0 {
+		t.Fatal("user should have been removed from group")
+	}
+
+	cmd4 := exec.Command("bash", "-c", "go run ../cmd/platform/*.go group delete "+name)
+	output4, err4 := cmd4.CombinedOutput()
+	if err4 != nil {
+		t.Log(string(output4))
+		t.Fatal(err4)
+	}
+
+	if result := <-app.Srv.Store.Group().GetByName(name); result.Err == nil {
+		t.Fatal("group should have been deleted")
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mattermost/platform/app"
+	"github.com/mattermost/platform/model"
+)
+
+type DNDProvider struct {
+}
+
+const (
+	CMD_DND = "dnd"
+)
+
+func init() {
+	RegisterCommandProvider(&DNDProvider{})
+}
+
+func (me *DNDProvider) GetTrigger() string {
+	return CMD_DND
+}
+
+func (me *DNDProvider) GetCommand(c *Context) *model.Command {
+	return &model.Command{
+		Trigger:          CMD_DND,
+		AutoComplete:     true,
+		AutoCompleteDesc: c.T("api.command_dnd.desc"),
+		AutoCompleteHint: c.T("api.command_dnd.hint"),
+		DisplayName:      c.T("api.command_dnd.name"),
+	}
+}
+
+func (me *DNDProvider) DoCommand(c *Context, args *model.CommandArgs, message string) *model.CommandResponse {
+	message = strings.TrimSpace(message)
+
+	if len(message) == 0 {
+		app.SetStatusDND(c.Session.UserId, 0)
+		return &model.CommandResponse{ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL, Text: c.T("api.command_dnd.success")}
+	}
+
+	duration, err := time.ParseDuration(message)
+	if err != nil || duration <= 0 {
+		return &model.CommandResponse{ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL, Text: c.T("api.command_dnd.duration.app_error")}
+	}
+
+	app.SetStatusDND(c.Session.UserId, model.GetMillis()+int64(duration/time.Millisecond))
+
+	return &model.CommandResponse{ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL, Text: c.T("api.command_dnd.success_until", map[string]interface{}{"Duration": duration.String()})}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/mattermost/platform/app"
 	"github.com/mattermost/platform/model"
 )
 
@@ -52,3 +53,35 @@ func TestJoinCommands(t *testing.T) {
 		t.Fatal("did not join channel")
 	}
 }
+
+func TestJoinCommandAsGuest(t *testing.T) {
+	th := Setup().InitBasic()
+	Client := th.BasicClient
+
+	guest := th.CreateUser(Client)
+	if ruser, err := app.UpdateUserRoles(guest.Id, model.ROLE_SYSTEM_GUEST.Id); err != nil {
+		t.Fatal(err)
+	} else {
+		LinkUserToTeam(ruser, th.BasicTeam)
+	}
+	Client.Must(Client.AddChannelMember(th.BasicChannel.Id, guest.Id))
+
+	otherChannel := th.CreateChannel(Client, th.BasicTeam)
+
+	GuestClient := th.CreateClient()
+	GuestClient.Must(GuestClient.Login(guest.Email, guest.Password))
+	GuestClient.SetTeamId(th.BasicTeam.Id)
+
+	rs := GuestClient.Must(GuestClient.Command(th.BasicChannel.Id, "/join "+otherChannel.Name)).Data.(*model.CommandResponse)
+	if len(rs.GotoLocation) != 0 {
+		t.Fatal("guests shouldn't be able to join public channels")
+	}
+
+	if _, err := app.GetChannelMember(otherChannel.Id, guest.Id); err == nil {
+		t.Fatal("guest shouldn't have been added to the channel")
+	}
+
+	if err := app.JoinChannel(otherChannel, guest.Id); err == nil {
+		t.Fatal("guests shouldn't be able to join channels")
+	}
+}
@@ -14,6 +14,7 @@ func TestStatusCommands(t *testing.T) {
 	th := Setup().InitBasic()
 	commandAndTest(t, th, "away")
 	commandAndTest(t, th, "offline")
+	commandAndTest(t, th, "dnd")
 	commandAndTest(t, th, "online")
 }
 
@@ -4,6 +4,7 @@
 package api
 
 import (
+	"bytes"
 	"crypto/tls"
 	b64 "encoding/base64"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	l4g "github.com/alecthomas/log4go"
 	"github.com/gorilla/mux"
@@ -273,7 +275,21 @@ func completeOAuth(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	uri := c.GetSiteURL() + "/signup/" + service + "/complete"
 
-	if body, teamId, props, err := AuthorizeOAuthUser(service, code, state, uri); err != nil {
+	nonce := ""
+	if cookie, err := r.Cookie(model.OPENID_NONCE_COOKIE); err == nil {
+		nonce = cookie.Value
+
+		// Each nonce is only good for one login
+		http.SetCookie(w, &http.Cookie{
+			Name:     model.OPENID_NONCE_COOKIE,
+			Value:    "",
+			Path:     "/signup/" + service + "/complete",
+			MaxAge:   -1,
+			HttpOnly: true,
+		})
+	}
+
+	if body, teamId, props, err := AuthorizeOAuthUser(service, code, state, uri, nonce); err != nil {
 		c.Err = err
 		return
 	} else {
@@ -620,7 +636,7 @@ func loginWithOAuth(c *Context, w http.ResponseWriter, r *http.Request) {
 		stateProps["redirect_to"] = redirectTo
 	}
 
-	if authUrl, err := GetAuthorizationCode(c, service, stateProps, loginHint); err != nil {
+	if authUrl, err := GetAuthorizationCode(c, w, r, service, stateProps, loginHint); err != nil {
 		c.Err = err
 		return
 	} else {
@@ -680,7 +696,7 @@ func signupWithOAuth(c *Context, w http.ResponseWriter, r *http.Request) {
 		stateProps["team_id"] = teamId
 	}
 
-	if authUrl, err := GetAuthorizationCode(c, service, stateProps, ""); err != nil {
+	if authUrl, err := GetAuthorizationCode(c, w, r, service, stateProps, ""); err != nil {
 		c.Err = err
 		return
 	} else {
@@ -688,7 +704,7 @@ func signupWithOAuth(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func GetAuthorizationCode(c *Context, service string, props map[string]string, loginHint string) (string, *model.AppError) {
+func GetAuthorizationCode(c *Context, w http.ResponseWriter, r *http.Request, service string, props map[string]string, loginHint string) (string, *model.AppError) {
 
 	sso := utils.Cfg.GetSSOService(service)
 	if sso != nil && !sso.Enable {
@@ -698,6 +714,27 @@ func GetAuthorizationCode(c *Context, service string, props map[string]string, l
 	clientId := sso.Id
 	endpoint := sso.AuthEndpoint
 	scope := sso.Scope
+	nonce := ""
+
+	if provider, ok := einterfaces.GetOauthProvider(service).(einterfaces.OpenIdProvider); ok {
+		var err *model.AppError
+		if endpoint, _, err = provider.GetEndpoints(sso); err != nil {
+			return "", err
+		}
+
+		// The ID token has to contain the nonce, which is kept in a cookie rather than the state so that the token is
+		// tied to the browser that started the login
+		nonce = model.NewId()
+		http.SetCookie(w, &http.Cookie{
+			Name:     model.OPENID_NONCE_COOKIE,
+			Value:    nonce,
+			Path:     "/signup/" + service + "/complete",
+			MaxAge:   model.OPENID_NONCE_COOKIE_MAX_AGE,
+			Expires:  time.Unix(model.GetMillis()/1000+model.OPENID_NONCE_COOKIE_MAX_AGE, 0),
+			HttpOnly: true,
+			Secure:   app.GetProtocol(r) == "https",
+		})
+	}
 
 	props["hash"] = model.HashPassword(clientId)
 	state := b64.StdEncoding.EncodeToString([]byte(model.MapToJson(props)))
@@ -714,10 +751,14 @@ func GetAuthorizationCode(c *Context, service string, props map[string]string, l
 		authUrl += "&login_hint=" + utils.UrlEncode(loginHint)
 	}
 
+	if len(nonce) > 0 {
+		authUrl += "&nonce=" + url.QueryEscape(nonce)
+	}
+
 	return authUrl, nil
 }
 
-func AuthorizeOAuthUser(service, code, state, redirectUri string) (io.ReadCloser, string, map[string]string, *model.AppError) {
+func AuthorizeOAuthUser(service, code, state, redirectUri, nonce string) (io.ReadCloser, string, map[string]string, *model.AppError) {
 	sso := utils.Cfg.GetSSOService(service)
 	if sso == nil || !sso.Enable {
 		return nil, "", nil, model.NewLocAppError("AuthorizeOAuthUser", "api.user.authorize_oauth_user.unsupported.app_error", nil, "service="+service)
@@ -738,6 +779,15 @@ func AuthorizeOAuthUser(service, code, state, redirectUri string) (io.ReadCloser
 
 	teamId := stateProps["team_id"]
 
+	tokenEndpoint := sso.TokenEndpoint
+	provider, isOpenId := einterfaces.GetOauthProvider(service).(einterfaces.OpenIdProvider)
+	if isOpenId {
+		var err *model.AppError
+		if _, tokenEndpoint, err = provider.GetEndpoints(sso); err != nil {
+			return nil, "", nil, err
+		}
+	}
+
 	p := url.Values{}
 	p.Set("client_id", sso.Id)
 	p.Set("client_secret", sso.Secret)
@@ -749,7 +799,7 @@ func AuthorizeOAuthUser(service, code, state, redirectUri string) (io.ReadCloser
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: *utils.Cfg.ServiceSettings.EnableInsecureOutgoingConnections},
 	}
 	client := &http.Client{Transport: tr}
-	req, _ := http.NewRequest("POST", sso.TokenEndpoint, strings.NewReader(p.Encode()))
+	req, _ := http.NewRequest("POST", tokenEndpoint, strings.NewReader(p.Encode()))
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("Accept", "application/json")
@@ -777,6 +827,14 @@ func AuthorizeOAuthUser(service, code, state, redirectUri string) (io.ReadCloser
 		return nil, "", nil, model.NewLocAppError("AuthorizeOAuthUser", "api.user.authorize_oauth_user.missing.app_error", nil, "")
 	}
 
+	if isOpenId {
+		if data, err := provider.GetUserData(sso, ar.AccessToken, ar.IdToken, nonce); err != nil {
+			return nil, "", nil, err
+		} else {
+			return ioutil.NopCloser(bytes.NewReader(data)), teamId, stateProps, nil
+		}
+	}
+
 	p = url.Values{}
 	p.Set("access_token", ar.AccessToken)
 	req, _ = http.NewRequest("GET", sso.UserApiEndpoint, strings.NewReader(""))
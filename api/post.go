@@ -14,10 +14,6 @@ import (
 	"github.com/mattermost/platform/utils"
 )
 
-const OPEN_GRAPH_METADATA_CACHE_SIZE = 10000
-
-var openGraphDataCache = utils.NewLru(OPEN_GRAPH_METADATA_CACHE_SIZE)
-
 func InitPost() {
 	l4g.Debug(utils.T("api.post.init.debug"))
 
@@ -49,6 +45,12 @@ func createPost(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	post.UserId = c.Session.UserId
 
+	// System messages are only ever created by the server, which lets them through posting restrictions
+	if post.IsSystemMessage() {
+		c.Err = model.NewAppError("createPost", "api.post.create_post.system_message.app_error", nil, "type="+post.Type, http.StatusBadRequest)
+		return
+	}
+
 	if !app.SessionHasPermissionToChannel(c.Session, post.ChannelId, model.PERMISSION_CREATE_POST) {
 		c.SetPermissionError(model.PERMISSION_CREATE_POST)
 		return
@@ -436,14 +438,8 @@ func getFileInfosForPost(c *Context, w http.ResponseWriter, r *http.Request) {
 func getOpenGraphMetadata(c *Context, w http.ResponseWriter, r *http.Request) {
 	props := model.StringInterfaceFromJson(r.Body)
 
-	ogJSONGeneric, ok := openGraphDataCache.Get(props["url"])
-	if ok {
-		w.Write(ogJSONGeneric.([]byte))
-		return
-	}
-
 	url := ""
-	ok = false
+	ok := false
 	if url, ok = props["url"].(string); len(url) == 0 || !ok {
 		c.SetInvalidParam("getOpenGraphMetadata", "url")
 		return
@@ -457,6 +453,5 @@ func getOpenGraphMetadata(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	openGraphDataCache.AddWithExpiresInSecs(props["url"], ogJSON, 3600) // Cache would expire after 1 houre
 	w.Write(ogJSON)
 }
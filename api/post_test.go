@@ -378,12 +378,17 @@ func TestUpdatePost(t *testing.T) {
 	}
 
 	post3 := &model.Post{ChannelId: channel1.Id, Message: "a" + model.NewId() + "a", Type: model.POST_JOIN_LEAVE}
-	rpost3, err := Client.CreatePost(post3)
+	if _, err := Client.CreatePost(post3); err == nil {
+		t.Fatal("shouldn't have been able to create a system message")
+	}
+
+	post3.UserId = th.BasicUser.Id
+	rpost3, err := app.CreatePost(post3, channel1.TeamId, false)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	up3 := &model.Post{Id: rpost3.Data.(*model.Post).Id, ChannelId: channel1.Id, Message: "a" + model.NewId() + " update post 3"}
+	up3 := &model.Post{Id: rpost3.Id, ChannelId: channel1.Id, Message: "a" + model.NewId() + " update post 3"}
 	if _, err := Client.UpdatePost(up3); err == nil {
 		t.Fatal("shouldn't have been able to update system message")
 	}
@@ -1130,7 +1135,7 @@ func TestGetFlaggedPosts(t *testing.T) {
 }
 
 func TestGetMessageForNotification(t *testing.T) {
-	Setup().InitBasic()
+	th := Setup().InitBasic()
 
 	testPng := store.Must(app.Srv.Store.FileInfo().Save(&model.FileInfo{
 		CreatorId: model.NewId(),
@@ -1171,6 +1176,18 @@ func TestGetMessageForNotification(t *testing.T) {
 		t.Fatal("should've returned message text")
 	}
 
+	post.ChannelId = th.BasicChannel.Id
+	post.Message = "**test** for @" + th.BasicUser.Username + " in ~" + th.BasicChannel.Name
+	if message := app.GetMessageForNotification(post, translateFunc); message != "test for @"+th.BasicUser.GetDisplayName()+" in ~"+th.BasicChannel.DisplayName {
+		t.Fatal("should've removed formatting and resolved mentions:", message)
+	}
+
+	if message := app.GetMessageHtmlForNotification(post, translateFunc); !strings.HasPrefix(string(message), "<p><strong>test</strong> for @") {
+		t.Fatal("should've rendered the message as html:", message)
+	}
+
+	post.Message = "test"
+
 	post.FileIds = model.StringArray{testPng.Id}
 	store.Must(app.Srv.Store.FileInfo().AttachToPost(testPng.Id, post.Id))
 	if app.GetMessageForNotification(post, translateFunc) != "test" {
@@ -1306,6 +1323,13 @@ func TestGetOpenGraphMetadata(t *testing.T) {
 	th := Setup().InitBasic()
 	Client := th.BasicClient
 
+	// The test server listens on a loopback address, which outgoing requests are otherwise refused
+	allowedInternalConnections := *utils.Cfg.ServiceSettings.AllowedUntrustedInternalConnections
+	defer func() {
+		*utils.Cfg.ServiceSettings.AllowedUntrustedInternalConnections = allowedInternalConnections
+	}()
+	*utils.Cfg.ServiceSettings.AllowedUntrustedInternalConnections = "127.0.0.1"
+
 	ogDataCacheMissCount := 0
 
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1320,6 +1344,8 @@ func TestGetOpenGraphMetadata(t *testing.T) {
 			`)
 		} else if r.URL.Path == "/no-og-data/" {
 			fmt.Fprintln(w, `<html><head></head><body></body></html>`)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
 		}
 	}))
 
@@ -1330,6 +1356,10 @@ func TestGetOpenGraphMetadata(t *testing.T) {
 		// Data should be cached for following
 		{"path": "/og-data/", "title": "Test Title", "cacheMissCount": 2},
 		{"path": "/no-og-data/", "title": "", "cacheMissCount": 2},
+
+		// Failed requests shouldn't be cached
+		{"path": "/error/", "title": "", "cacheMissCount": 3},
+		{"path": "/error/", "title": "", "cacheMissCount": 4},
 	} {
 		res, err := Client.DoApiPost(
 			"/get_opengraph_metadata",
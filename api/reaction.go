@@ -54,11 +54,12 @@ func saveReaction(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	pchan := app.Srv.Store.Post().Get(reaction.PostId)
 
+	var post *model.Post
 	var postHadReactions bool
 	if result := <-pchan; result.Err != nil {
 		c.Err = result.Err
 		return
-	} else if post := result.Data.(*model.PostList).Posts[postId]; post.ChannelId != channelId {
+	} else if post = result.Data.(*model.PostList).Posts[postId]; post.ChannelId != channelId {
 		c.Err = model.NewLocAppError("saveReaction", "api.reaction.save_reaction.mismatched_channel_id.app_error",
 			nil, "channelId="+channelId+", post.ChannelId="+post.ChannelId+", postId="+postId)
 		c.Err.StatusCode = http.StatusBadRequest
@@ -75,6 +76,8 @@ func saveReaction(c *Context, w http.ResponseWriter, r *http.Request) {
 
 		reaction := result.Data.(*model.Reaction)
 
+		go app.SendReactionNotification(reaction, post)
+
 		w.Write([]byte(reaction.ToJson()))
 	}
 }
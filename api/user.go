@@ -55,6 +55,7 @@ func InitUser() {
 	BaseRoutes.Users.Handle("/mfa", ApiAppHandler(checkMfa)).Methods("POST")
 	BaseRoutes.Users.Handle("/generate_mfa_secret", ApiUserRequiredMfa(generateMfaSecret)).Methods("GET")
 	BaseRoutes.Users.Handle("/update_mfa", ApiUserRequiredMfa(updateMfa)).Methods("POST")
+	BaseRoutes.Users.Handle("/generate_mfa_backup_codes", ApiUserRequiredMfa(generateMfaBackupCodes)).Methods("POST")
 
 	BaseRoutes.Users.Handle("/claim/email_to_oauth", ApiAppHandler(emailToOAuth)).Methods("POST")
 	BaseRoutes.Users.Handle("/claim/oauth_to_email", ApiUserRequired(oauthToEmail)).Methods("POST")
@@ -371,6 +372,13 @@ func getUser(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if c.Session.IsGuest() {
+		if err = app.CheckUserVisibleToGuest(c.Session.UserId, user.Id); err != nil {
+			c.Err = err
+			return
+		}
+	}
+
 	etag := user.Etag(utils.Cfg.PrivacySettings.ShowFullName, utils.Cfg.PrivacySettings.ShowEmailAddress)
 
 	if HandleEtag(etag, "Get User", w, r) {
@@ -390,10 +398,19 @@ func getByUsername(c *Context, w http.ResponseWriter, r *http.Request) {
 	var user *model.User
 	var err *model.AppError
 
-	if user, err = app.GetUserByUsername(username); err != nil {
+	if user, err = app.GetUserByUsernameFollowingHistory(username); err != nil {
 		c.Err = err
 		return
-	} else if HandleEtag(user.Etag(utils.Cfg.PrivacySettings.ShowFullName, utils.Cfg.PrivacySettings.ShowEmailAddress), "Get By Username", w, r) {
+	}
+
+	if c.Session.IsGuest() {
+		if err = app.CheckUserVisibleToGuest(c.Session.UserId, user.Id); err != nil {
+			c.Err = err
+			return
+		}
+	}
+
+	if HandleEtag(user.Etag(utils.Cfg.PrivacySettings.ShowFullName, utils.Cfg.PrivacySettings.ShowEmailAddress), "Get By Username", w, r) {
 		return
 	} else {
 		sanitizeProfile(c, user)
@@ -408,10 +425,22 @@ func getByEmail(c *Context, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	email := params["email"]
 
-	if user, err := app.GetUserByEmail(email); err != nil {
+	var user *model.User
+	var err *model.AppError
+
+	if user, err = app.GetUserByEmail(email); err != nil {
 		c.Err = err
 		return
-	} else if HandleEtag(user.Etag(utils.Cfg.PrivacySettings.ShowFullName, utils.Cfg.PrivacySettings.ShowEmailAddress), "Get By Email", w, r) {
+	}
+
+	if c.Session.IsGuest() {
+		if err = app.CheckUserVisibleToGuest(c.Session.UserId, user.Id); err != nil {
+			c.Err = err
+			return
+		}
+	}
+
+	if HandleEtag(user.Etag(utils.Cfg.PrivacySettings.ShowFullName, utils.Cfg.PrivacySettings.ShowEmailAddress), "Get By Email", w, r) {
 		return
 	} else {
 		sanitizeProfile(c, user)
@@ -437,18 +466,26 @@ func getProfiles(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// What guests see depends on their channels, which the etag doesn't cover
 	etag := app.GetUsersEtag()
-	if HandleEtag(etag, "Get Profiles", w, r) {
+	if !c.Session.IsGuest() && HandleEtag(etag, "Get Profiles", w, r) {
 		return
 	}
 
-	if profiles, err := app.GetUsersMap(offset, limit, c.IsSystemAdmin()); err != nil {
-		c.Err = err
+	profiles, appErr := app.GetUsersMap(offset, limit, c.IsSystemAdmin())
+	if appErr == nil && c.Session.IsGuest() {
+		profiles, appErr = app.FilterUserMapVisibleToGuest(c.Session.UserId, profiles)
+	}
+
+	if appErr != nil {
+		c.Err = appErr
 		return
-	} else {
+	}
+
+	if !c.Session.IsGuest() {
 		w.Header().Set(model.HEADER_ETAG_SERVER, etag)
-		w.Write([]byte(model.UserMapToJson(profiles)))
 	}
+	w.Write([]byte(model.UserMapToJson(profiles)))
 }
 
 func getProfilesInTeam(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -474,17 +511,24 @@ func getProfilesInTeam(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 
 	etag := app.GetUsersInTeamEtag(teamId)
-	if HandleEtag(etag, "Get Profiles In Team", w, r) {
+	if !c.Session.IsGuest() && HandleEtag(etag, "Get Profiles In Team", w, r) {
 		return
 	}
 
-	if profiles, err := app.GetUsersInTeamMap(teamId, offset, limit, c.IsSystemAdmin()); err != nil {
-		c.Err = err
+	profiles, appErr := app.GetUsersInTeamMap(teamId, offset, limit, c.IsSystemAdmin())
+	if appErr == nil && c.Session.IsGuest() {
+		profiles, appErr = app.FilterUserMapVisibleToGuest(c.Session.UserId, profiles)
+	}
+
+	if appErr != nil {
+		c.Err = appErr
 		return
-	} else {
+	}
+
+	if !c.Session.IsGuest() {
 		w.Header().Set(model.HEADER_ETAG_SERVER, etag)
-		w.Write([]byte(model.UserMapToJson(profiles)))
 	}
+	w.Write([]byte(model.UserMapToJson(profiles)))
 }
 
 func getProfilesInChannel(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -551,12 +595,17 @@ func getProfilesNotInChannel(c *Context, w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if profiles, err := app.GetUsersNotInChannelMap(c.TeamId, channelId, offset, limit, c.IsSystemAdmin()); err != nil {
-		c.Err = err
+	profiles, appErr := app.GetUsersNotInChannelMap(c.TeamId, channelId, offset, limit, c.IsSystemAdmin())
+	if appErr == nil && c.Session.IsGuest() {
+		profiles, appErr = app.FilterUserMapVisibleToGuest(c.Session.UserId, profiles)
+	}
+
+	if appErr != nil {
+		c.Err = appErr
 		return
-	} else {
-		w.Write([]byte(model.UserMapToJson(profiles)))
 	}
+
+	w.Write([]byte(model.UserMapToJson(profiles)))
 }
 
 func getAudits(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -871,6 +920,16 @@ func updateUserNotify(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if reactions, ok := props[model.REACTION_NOTIFY_PROP]; ok && !model.IsValidReactionNotifyLevel(reactions) {
+		c.SetInvalidParam("updateUserNotify", model.REACTION_NOTIFY_PROP)
+		return
+	}
+
+	if !model.IsValidDNDSchedule(props) {
+		c.SetInvalidParam("updateUserNotify", model.DND_SCHEDULE_START_NOTIFY_PROP)
+		return
+	}
+
 	ruser, err := app.UpdateUserNotifyProps(userId, props, c.GetSiteURL())
 	if err != nil {
 		c.Err = err
@@ -932,7 +991,7 @@ func emailToOAuth(c *Context, w http.ResponseWriter, r *http.Request) {
 	if service == model.USER_AUTH_SERVICE_SAML {
 		m["follow_link"] = c.GetSiteURL() + "/login/sso/saml?action=" + model.OAUTH_ACTION_EMAIL_TO_SSO + "&email=" + email
 	} else {
-		if authUrl, err := GetAuthorizationCode(c, service, stateProps, ""); err != nil {
+		if authUrl, err := GetAuthorizationCode(c, w, r, service, stateProps, ""); err != nil {
 			c.LogAuditWithUserId(user.Id, "fail - oauth issue")
 			c.Err = err
 			return
@@ -1308,8 +1367,23 @@ func updateMfa(c *Context, w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(model.MapToJson(rdata)))
 }
 
+func generateMfaBackupCodes(c *Context, w http.ResponseWriter, r *http.Request) {
+	codes, err := app.GenerateMfaBackupCodes(c.Session.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("")
+
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Pragma", "no-cache")
+	w.Header().Set("Expires", "0")
+	w.Write([]byte(model.ArrayToJson(codes)))
+}
+
 func checkMfa(c *Context, w http.ResponseWriter, r *http.Request) {
-	if !utils.IsLicensed || !*utils.License.Features.MFA || !*utils.Cfg.ServiceSettings.EnableMultifactorAuthentication {
+	if !*utils.Cfg.ServiceSettings.EnableMultifactorAuthentication {
 		rdata := map[string]string{}
 		rdata["mfa_required"] = "false"
 		w.Write([]byte(model.MapToJson(rdata)))
@@ -1413,6 +1487,12 @@ func completeSaml(c *Context, w http.ResponseWriter, r *http.Request) {
 			c.Err.StatusCode = http.StatusFound
 			return
 		}
+
+		// Soft error since the user was still able to log in
+		if err := app.SyncProfileAttributesFromDirectory(user); err != nil {
+			l4g.Error(utils.T("app.profile_attribute.sync.error"), user.Id, err.Error())
+		}
+
 		action := relayProps["action"]
 		switch action {
 		case model.OAUTH_ACTION_SIGNUP:
@@ -1532,6 +1612,10 @@ func searchUsers(c *Context, w http.ResponseWriter, r *http.Request) {
 		profiles, err = app.SearchUsersInTeam(props.TeamId, props.Term, searchOptions)
 	}
 
+	if err == nil && c.Session.IsGuest() {
+		profiles, err = app.FilterUsersVisibleToGuest(c.Session.UserId, profiles)
+	}
+
 	if err != nil {
 		c.Err = err
 		return
@@ -1552,7 +1636,12 @@ func getProfilesByIds(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if profiles, err := app.GetUsersByIds(userIds, c.IsSystemAdmin()); err != nil {
+	profiles, err := app.GetUsersByIds(userIds, c.IsSystemAdmin())
+	if err == nil && c.Session.IsGuest() {
+		profiles, err = app.FilterUsersVisibleToGuest(c.Session.UserId, profiles)
+	}
+
+	if err != nil {
 		c.Err = err
 		return
 	} else {
@@ -1597,6 +1686,13 @@ func autocompleteUsersInChannel(c *Context, w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	if c.Session.IsGuest() {
+		if autocomplete.OutOfChannel, err = app.FilterUsersVisibleToGuest(c.Session.UserId, autocomplete.OutOfChannel); err != nil {
+			c.Err = err
+			return
+		}
+	}
+
 	for _, p := range autocomplete.InChannel {
 		sanitizeProfile(c, p)
 	}
@@ -1635,6 +1731,13 @@ func autocompleteUsersInTeam(c *Context, w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if c.Session.IsGuest() {
+		if autocomplete.InTeam, err = app.FilterUsersVisibleToGuest(c.Session.UserId, autocomplete.InTeam); err != nil {
+			c.Err = err
+			return
+		}
+	}
+
 	for _, p := range autocomplete.InTeam {
 		sanitizeProfile(c, p)
 	}
@@ -1662,6 +1765,13 @@ func autocompleteUsers(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if c.Session.IsGuest() {
+		if profiles, err = app.FilterUsersVisibleToGuest(c.Session.UserId, profiles); err != nil {
+			c.Err = err
+			return
+		}
+	}
+
 	for _, p := range profiles {
 		sanitizeProfile(c, p)
 	}
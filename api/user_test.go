@@ -2608,3 +2608,101 @@ func TestGetByEmail(t *testing.T) {
 		}
 	}
 }
+
+func TestGuestUserSearch(t *testing.T) {
+	th := Setup().InitBasic()
+	Client := th.BasicClient
+
+	guest := th.CreateUser(Client)
+	if ruser, err := app.UpdateUserRoles(guest.Id, model.ROLE_SYSTEM_GUEST.Id); err != nil {
+		t.Fatal(err)
+	} else {
+		LinkUserToTeam(ruser, th.BasicTeam)
+	}
+	Client.Must(Client.AddChannelMember(th.BasicChannel.Id, guest.Id))
+
+	GuestClient := th.CreateClient()
+	GuestClient.Must(GuestClient.Login(guest.Email, guest.Password))
+	GuestClient.SetTeamId(th.BasicTeam.Id)
+
+	if result, err := GuestClient.AutocompleteUsersInTeam(th.BasicUser.Username); err != nil {
+		t.Fatal(err)
+	} else if len(result.Data.(*model.UserAutocompleteInTeam).InTeam) != 1 {
+		t.Fatal("should have found the user in the guest's channel")
+	}
+
+	if result, err := GuestClient.AutocompleteUsersInTeam(th.BasicUser2.Username); err != nil {
+		t.Fatal(err)
+	} else if len(result.Data.(*model.UserAutocompleteInTeam).InTeam) != 0 {
+		t.Fatal("shouldn't have found users outside of the guest's channels")
+	}
+
+	if result, err := GuestClient.SearchUsers(model.UserSearch{Term: th.BasicUser2.Username, TeamId: th.BasicTeam.Id}); err != nil {
+		t.Fatal(err)
+	} else if len(result.Data.([]*model.User)) != 0 {
+		t.Fatal("shouldn't have found users outside of the guest's channels")
+	}
+
+	if _, err := GuestClient.GetMoreChannelsPage(0, 100); err == nil {
+		t.Fatal("guests shouldn't be able to browse channels")
+	}
+
+	otherChannel := th.CreateChannel(Client, th.BasicTeam)
+	if _, err := GuestClient.JoinChannel(otherChannel.Id); err == nil {
+		t.Fatal("guests shouldn't be able to join public channels")
+	}
+
+	if _, err := GuestClient.AddChannelMember(th.BasicChannel.Id, th.BasicUser2.Id); err == nil {
+		t.Fatal("guests shouldn't be able to add people to their channels")
+	}
+}
+
+func TestGetProfilesAsGuest(t *testing.T) {
+	th := Setup().InitBasic()
+	Client := th.BasicClient
+
+	guest := th.CreateUser(Client)
+	if ruser, err := app.UpdateUserRoles(guest.Id, model.ROLE_SYSTEM_GUEST.Id); err != nil {
+		t.Fatal(err)
+	} else {
+		LinkUserToTeam(ruser, th.BasicTeam)
+	}
+	Client.Must(Client.AddChannelMember(th.BasicChannel.Id, guest.Id))
+
+	GuestClient := th.CreateClient()
+	GuestClient.Must(GuestClient.Login(guest.Email, guest.Password))
+	GuestClient.SetTeamId(th.BasicTeam.Id)
+
+	if _, err := GuestClient.GetUser(th.BasicUser.Id, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := GuestClient.GetUser(th.BasicUser2.Id, ""); err == nil {
+		t.Fatal("shouldn't have found a user outside of the guest's channels")
+	}
+
+	if _, err := GuestClient.GetByUsername(th.BasicUser2.Username, ""); err == nil {
+		t.Fatal("shouldn't have found a user outside of the guest's channels")
+	}
+
+	if _, resp := GuestClient.GetByEmail(th.BasicUser2.Email, ""); resp.Error == nil {
+		t.Fatal("shouldn't have found a user outside of the guest's channels")
+	}
+
+	checkVisibleProfiles := func(result *model.Result, shouldFindUser bool) {
+		profiles := result.Data.(map[string]*model.User)
+
+		if _, ok := profiles[th.BasicUser2.Id]; ok {
+			t.Fatal("guests shouldn't see users outside of their channels")
+		}
+
+		if _, ok := profiles[th.BasicUser.Id]; shouldFindUser && !ok {
+			t.Fatal("guests should see the users in their channels")
+		}
+	}
+
+	checkVisibleProfiles(GuestClient.Must(GuestClient.GetProfiles(0, 100, "")), true)
+	checkVisibleProfiles(GuestClient.Must(GuestClient.GetProfilesInTeam(th.BasicTeam.Id, 0, 100, "")), true)
+	checkVisibleProfiles(GuestClient.Must(GuestClient.GetProfilesNotInChannel(th.BasicChannel.Id, 0, 100, "")), false)
+	checkVisibleProfiles(GuestClient.Must(GuestClient.GetProfilesByIds([]string{th.BasicUser.Id, th.BasicUser2.Id})), true)
+}
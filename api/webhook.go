@@ -407,6 +407,12 @@ func incomingWebhook(c *Context, w http.ResponseWriter, r *http.Request) {
 	channelName := parsedRequest.ChannelName
 	webhookType := parsedRequest.Type
 
+	if strings.HasPrefix(webhookType, model.POST_SYSTEM_MESSAGE_PREFIX) {
+		c.Err = model.NewLocAppError("incomingWebhook", "web.incoming_webhook.type.app_error", nil, "type="+webhookType)
+		c.Err.StatusCode = http.StatusBadRequest
+		return
+	}
+
 	// attachments is in here for slack compatibility
 	if parsedRequest.Attachments != nil {
 		if len(parsedRequest.Props) == 0 {
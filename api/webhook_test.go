@@ -651,6 +651,10 @@ func TestIncomingWebhooks(t *testing.T) {
 		t.Fatal("should have failed - no text")
 	}
 
+	if _, err := Client.DoPost(url, "{\"text\":\"this is a test\", \"type\":\""+model.POST_JOIN_CHANNEL+"\"}", "application/json"); err == nil || err.StatusCode != http.StatusBadRequest {
+		t.Fatal("should have failed - system message type")
+	}
+
 	tooLongText := ""
 	for i := 0; i < 8200; i++ {
 		tooLongText += "a"
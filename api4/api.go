@@ -73,6 +73,23 @@ type Routes struct {
 	Emoji  *mux.Router // 'api/v4/emoji/{emoji_id:[A-Za-z0-9]+}'
 
 	Webrtc *mux.Router // 'api/v4/webrtc'
+
+	Image *mux.Router // 'api/v4/image'
+
+	Groups       *mux.Router // 'api/v4/groups'
+	Group        *mux.Router // 'api/v4/groups/{group_id:[A-Za-z0-9]+}'
+	GroupByName  *mux.Router // 'api/v4/groups/name/{group_name:[A-Za-z0-9_-\.]+}'
+	GroupMembers *mux.Router // 'api/v4/groups/{group_id:[A-Za-z0-9]+}/members'
+	GroupMember  *mux.Router // 'api/v4/groups/{group_id:[A-Za-z0-9]+}/members/{user_id:[A-Za-z0-9]+}'
+
+	SidebarCategories *mux.Router // 'api/v4/users/{user_id:[A-Za-z0-9]+}/teams/{team_id:[A-Za-z0-9]+}/channels/categories'
+	SidebarCategory   *mux.Router // 'api/v4/users/{user_id:[A-Za-z0-9]+}/teams/{team_id:[A-Za-z0-9]+}/channels/categories/{category_id:[A-Za-z0-9]{26}}'
+
+	Bots *mux.Router // 'api/v4/bots'
+	Bot  *mux.Router // 'api/v4/bots/{user_id:[A-Za-z0-9]+}'
+
+	ProfileAttributes *mux.Router // 'api/v4/profile_attributes'
+	ProfileAttribute  *mux.Router // 'api/v4/profile_attributes/{attribute_id:[A-Za-z0-9]+}'
 }
 
 var BaseRoutes *Routes
@@ -137,10 +154,34 @@ func InitApi(full bool) {
 
 	BaseRoutes.Webrtc = BaseRoutes.ApiRoot.PathPrefix("/webrtc").Subrouter()
 
+	BaseRoutes.Image = BaseRoutes.ApiRoot.PathPrefix("/image").Subrouter()
+
+	BaseRoutes.Groups = BaseRoutes.ApiRoot.PathPrefix("/groups").Subrouter()
+	BaseRoutes.Group = BaseRoutes.Groups.PathPrefix("/{group_id:[A-Za-z0-9]+}").Subrouter()
+	BaseRoutes.GroupByName = BaseRoutes.Groups.PathPrefix("/name/{group_name:[A-Za-z0-9\\_\\-\\.]+}").Subrouter()
+	BaseRoutes.GroupMembers = BaseRoutes.Group.PathPrefix("/members").Subrouter()
+	BaseRoutes.GroupMember = BaseRoutes.GroupMembers.PathPrefix("/{user_id:[A-Za-z0-9]+}").Subrouter()
+
+	BaseRoutes.SidebarCategories = BaseRoutes.User.PathPrefix("/teams/{team_id:[A-Za-z0-9]+}/channels/categories").Subrouter()
+	// Category ids are matched exactly so that they can't be confused with /order
+	BaseRoutes.SidebarCategory = BaseRoutes.SidebarCategories.PathPrefix("/{category_id:[A-Za-z0-9]{26}}").Subrouter()
+
+	BaseRoutes.Bots = BaseRoutes.ApiRoot.PathPrefix("/bots").Subrouter()
+	BaseRoutes.Bot = BaseRoutes.Bots.PathPrefix("/{user_id:[A-Za-z0-9]+}").Subrouter()
+
+	BaseRoutes.ProfileAttributes = BaseRoutes.ApiRoot.PathPrefix("/profile_attributes").Subrouter()
+	BaseRoutes.ProfileAttribute = BaseRoutes.ProfileAttributes.PathPrefix("/{attribute_id:[A-Za-z0-9]+}").Subrouter()
+
 	InitUser()
 	InitTeam()
 	InitChannel()
 	InitPost()
+	InitImage()
+	InitGroup()
+	InitSidebarCategory()
+	InitStatus()
+	InitBot()
+	InitProfileAttribute()
 
 	app.Srv.Router.Handle("/api/v4/{anything:.*}", http.HandlerFunc(Handle404))
 
@@ -149,6 +190,9 @@ func InitApi(full bool) {
 		utils.InitHTML()
 
 		app.InitEmailBatching()
+		app.InitChannelAutoArchive()
+		app.InitCustomStatusExpiry()
+		app.InitOutOfOfficeExpiry()
 	}
 }
 
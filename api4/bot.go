@@ -0,0 +1,213 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	l4g "github.com/alecthomas/log4go"
+	"github.com/mattermost/platform/app"
+	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
+)
+
+func InitBot() {
+	l4g.Debug(utils.T("api.bot.init.debug"))
+
+	BaseRoutes.Bots.Handle("", ApiSessionRequired(createBot)).Methods("POST")
+	BaseRoutes.Bots.Handle("", ApiSessionRequired(getBots)).Methods("GET")
+
+	BaseRoutes.Bot.Handle("", ApiSessionRequired(getBot)).Methods("GET")
+	BaseRoutes.Bot.Handle("", ApiSessionRequired(disableBot)).Methods("DELETE")
+	BaseRoutes.Bot.Handle("/owner", ApiSessionRequired(updateBotOwner)).Methods("PUT")
+
+	BaseRoutes.Bot.Handle("/tokens", ApiSessionRequired(createBotToken)).Methods("POST")
+	BaseRoutes.Bot.Handle("/tokens", ApiSessionRequired(getBotTokens)).Methods("GET")
+	BaseRoutes.Bot.Handle("/tokens/{token_id:[A-Za-z0-9]+}", ApiSessionRequired(revokeBotToken)).Methods("DELETE")
+}
+
+func createBot(c *Context, w http.ResponseWriter, r *http.Request) {
+	bot := model.UserFromJson(r.Body)
+	if bot == nil {
+		c.SetInvalidParam("bot")
+		return
+	}
+
+	if !app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_BOTS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_BOTS)
+		return
+	}
+
+	// Bots are owned by whoever creates them unless a system admin creates one for someone else
+	ownerId := c.Session.UserId
+	if len(bot.BotOwnerId) > 0 && bot.BotOwnerId != ownerId {
+		if !app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_OTHERS_BOTS) {
+			c.SetPermissionError(model.PERMISSION_MANAGE_OTHERS_BOTS)
+			return
+		}
+		ownerId = bot.BotOwnerId
+	}
+
+	rbot, err := app.CreateBot(bot, ownerId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("bot_user_id=" + rbot.Id + " owner_id=" + ownerId)
+	app.SanitizeProfile(rbot, c.IsSystemAdmin())
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(rbot.ToJson()))
+}
+
+func getBots(c *Context, w http.ResponseWriter, r *http.Request) {
+	ownerId := ""
+	if !app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_OTHERS_BOTS) {
+		if !app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_BOTS) {
+			c.SetPermissionError(model.PERMISSION_MANAGE_BOTS)
+			return
+		}
+		ownerId = c.Session.UserId
+	}
+
+	if bots, err := app.GetBotsPage(ownerId, c.Params.Page, c.Params.PerPage); err != nil {
+		c.Err = err
+		return
+	} else {
+		for _, bot := range bots {
+			app.SanitizeProfile(bot, c.IsSystemAdmin())
+		}
+		w.Write([]byte(model.UserListToJson(bots)))
+	}
+}
+
+func getBot(c *Context, w http.ResponseWriter, r *http.Request) {
+	bot := getManagedBot(c)
+	if c.Err != nil {
+		return
+	}
+
+	app.SanitizeProfile(bot, c.IsSystemAdmin())
+	w.Write([]byte(bot.ToJson()))
+}
+
+func disableBot(c *Context, w http.ResponseWriter, r *http.Request) {
+	bot := getManagedBot(c)
+	if c.Err != nil {
+		return
+	}
+
+	if _, err := app.UpdateActive(bot, false); err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("bot_user_id=" + bot.Id)
+	ReturnStatusOK(w)
+}
+
+func updateBotOwner(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	props := model.MapFromJson(r.Body)
+	ownerId := props["owner_id"]
+	if len(ownerId) != 26 {
+		c.SetInvalidParam("owner_id")
+		return
+	}
+
+	// Handing a bot over is the same as creating one for someone else
+	if !app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_OTHERS_BOTS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_OTHERS_BOTS)
+		return
+	}
+
+	if rbot, err := app.UpdateBotOwner(c.Params.UserId, ownerId); err != nil {
+		c.Err = err
+		return
+	} else {
+		c.LogAudit("bot_user_id=" + rbot.Id + " owner_id=" + ownerId)
+		app.SanitizeProfile(rbot, c.IsSystemAdmin())
+		w.Write([]byte(rbot.ToJson()))
+	}
+}
+
+func createBotToken(c *Context, w http.ResponseWriter, r *http.Request) {
+	bot := getManagedBot(c)
+	if c.Err != nil {
+		return
+	}
+
+	if token, err := app.CreateBotToken(bot.Id); err != nil {
+		c.Err = err
+		return
+	} else {
+		c.LogAudit("bot_user_id=" + bot.Id + " token_id=" + token.Id)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(token.ToJson()))
+	}
+}
+
+func getBotTokens(c *Context, w http.ResponseWriter, r *http.Request) {
+	bot := getManagedBot(c)
+	if c.Err != nil {
+		return
+	}
+
+	if tokens, err := app.GetBotTokens(bot.Id); err != nil {
+		c.Err = err
+		return
+	} else {
+		w.Write([]byte(model.SessionsToJson(tokens)))
+	}
+}
+
+func revokeBotToken(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireTokenId()
+	if c.Err != nil {
+		return
+	}
+
+	bot := getManagedBot(c)
+	if c.Err != nil {
+		return
+	}
+
+	if err := app.RevokeBotToken(bot.Id, c.Params.TokenId); err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("bot_user_id=" + bot.Id + " token_id=" + c.Params.TokenId)
+	ReturnStatusOK(w)
+}
+
+// getManagedBot returns the bot in the URL if the session is allowed to manage it. Users can manage the bots they
+// own while system admins can manage every bot.
+func getManagedBot(c *Context) *model.User {
+	c.RequireUserId()
+	if c.Err != nil {
+		return nil
+	}
+
+	bot, err := app.GetBot(c.Params.UserId)
+	if err != nil {
+		c.Err = err
+		return nil
+	}
+
+	if app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_OTHERS_BOTS) {
+		return bot
+	}
+
+	if bot.BotOwnerId != c.Session.UserId || !app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_BOTS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_OTHERS_BOTS)
+		return nil
+	}
+
+	return bot
+}
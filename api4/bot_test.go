@@ -0,0 +1,154 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
+)
+
+func TestCreateBot(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	onlyAdminIntegrations := *utils.Cfg.ServiceSettings.EnableOnlyAdminIntegrations
+	defer func() {
+		*utils.Cfg.ServiceSettings.EnableOnlyAdminIntegrations = onlyAdminIntegrations
+		utils.SetDefaultRolesBasedOnConfig()
+	}()
+	*utils.Cfg.ServiceSettings.EnableOnlyAdminIntegrations = true
+	utils.SetDefaultRolesBasedOnConfig()
+
+	_, resp := Client.CreateBot(&model.User{Username: GenerateTestUsername()})
+	CheckForbiddenStatus(t, resp)
+
+	*utils.Cfg.ServiceSettings.EnableOnlyAdminIntegrations = false
+	utils.SetDefaultRolesBasedOnConfig()
+
+	bot, resp := Client.CreateBot(&model.User{Username: GenerateTestUsername(), Nickname: "Build Bot"})
+	CheckNoError(t, resp)
+
+	if !bot.IsBot || bot.BotOwnerId != th.BasicUser.Id {
+		t.Fatal("bot should be owned by its creator")
+	}
+
+	_, resp = Client.CreateBot(&model.User{Username: GenerateTestUsername(), BotOwnerId: th.BasicUser2.Id})
+	CheckForbiddenStatus(t, resp)
+
+	bot2, resp := th.SystemAdminClient.CreateBot(&model.User{Username: GenerateTestUsername(), BotOwnerId: th.BasicUser2.Id})
+	CheckNoError(t, resp)
+
+	if bot2.BotOwnerId != th.BasicUser2.Id {
+		t.Fatal("system admins should be able to create bots for others")
+	}
+
+	_, resp = th.SystemAdminClient.CreateBot(&model.User{Username: GenerateTestUsername(), BotOwnerId: bot.Id})
+	CheckBadRequestStatus(t, resp)
+
+	bots, resp := Client.GetBots(0, 100)
+	CheckNoError(t, resp)
+
+	if len(bots) != 1 || bots[0].Id != bot.Id {
+		t.Fatal("should only have returned the bots owned by the user")
+	}
+
+	_, resp = Client.GetBot(bot.Id)
+	CheckNoError(t, resp)
+
+	_, resp = Client.GetBot(bot2.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.GetBot(th.BasicUser2.Id)
+	CheckNotFoundStatus(t, resp)
+
+	_, resp = Client.UpdateBotOwner(bot.Id, th.BasicUser2.Id)
+	CheckForbiddenStatus(t, resp)
+
+	bot, resp = th.SystemAdminClient.UpdateBotOwner(bot.Id, th.BasicUser2.Id)
+	CheckNoError(t, resp)
+
+	if bot.BotOwnerId != th.BasicUser2.Id {
+		t.Fatal("owner should've been updated")
+	}
+
+	_, resp = Client.GetBot(bot.Id)
+	CheckForbiddenStatus(t, resp)
+}
+
+func TestBotTokens(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	onlyAdminIntegrations := *utils.Cfg.ServiceSettings.EnableOnlyAdminIntegrations
+	defer func() {
+		*utils.Cfg.ServiceSettings.EnableOnlyAdminIntegrations = onlyAdminIntegrations
+		utils.SetDefaultRolesBasedOnConfig()
+	}()
+	*utils.Cfg.ServiceSettings.EnableOnlyAdminIntegrations = false
+	utils.SetDefaultRolesBasedOnConfig()
+
+	bot, resp := th.SystemAdminClient.CreateBot(&model.User{Username: GenerateTestUsername(), BotOwnerId: th.BasicUser.Id})
+	CheckNoError(t, resp)
+
+	token, resp := Client.CreateBotToken(bot.Id)
+	CheckNoError(t, resp)
+
+	if len(token.Token) == 0 || token.ExpiresAt != 0 || !token.IsBotToken() {
+		t.Fatal("should've returned a token that doesn't expire")
+	}
+
+	botClient := th.CreateClient()
+	botClient.AuthToken = token.Token
+	botClient.AuthType = model.HEADER_BEARER
+
+	ruser, resp := botClient.GetUser(bot.Id, "")
+	CheckNoError(t, resp)
+
+	if !ruser.IsBot {
+		t.Fatal("user should've been marked as a bot")
+	}
+
+	_, resp = th.CreateClient().Login(bot.Username, "password1")
+	CheckUnauthorizedStatus(t, resp)
+
+	tokens, resp := Client.GetBotTokens(bot.Id)
+	CheckNoError(t, resp)
+
+	if len(tokens) != 1 || tokens[0].Id != token.Id || len(tokens[0].Token) != 0 {
+		t.Fatal("should've returned the token without its value")
+	}
+
+	_, resp = th.Client.CreateBotToken(th.BasicUser2.Id)
+	CheckNotFoundStatus(t, resp)
+
+	_, resp = Client.RevokeBotToken(bot.Id, model.NewId())
+	CheckNotFoundStatus(t, resp)
+
+	ok, resp := Client.RevokeBotToken(bot.Id, token.Id)
+	CheckNoError(t, resp)
+
+	if !ok {
+		t.Fatal("should have returned true")
+	}
+
+	_, resp = botClient.GetUser(bot.Id, "")
+	CheckUnauthorizedStatus(t, resp)
+
+	token, resp = Client.CreateBotToken(bot.Id)
+	CheckNoError(t, resp)
+
+	_, resp = Client.DisableBot(bot.Id)
+	CheckNoError(t, resp)
+
+	botClient.AuthToken = token.Token
+	_, resp = botClient.GetUser(bot.Id, "")
+	CheckUnauthorizedStatus(t, resp)
+
+	_, resp = Client.CreateBotToken(bot.Id)
+	CheckBadRequestStatus(t, resp)
+}
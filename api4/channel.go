@@ -5,6 +5,7 @@ package api4
 
 import (
 	"net/http"
+	"strconv"
 
 	l4g "github.com/alecthomas/log4go"
 	"github.com/mattermost/platform/app"
@@ -12,11 +13,23 @@ import (
 	"github.com/mattermost/platform/utils"
 )
 
+const (
+	CHANNEL_ANALYTICS_DEFAULT_RANGE = 30 * app.DAY_MILLISECONDS
+)
+
 func InitChannel() {
 	l4g.Debug(utils.T("api.channel.init.debug"))
 
 	BaseRoutes.Channels.Handle("", ApiSessionRequired(createChannel)).Methods("POST")
 	BaseRoutes.Channels.Handle("/direct", ApiSessionRequired(createDirectChannel)).Methods("POST")
+	BaseRoutes.Channels.Handle("/group", ApiSessionRequired(createGroupChannel)).Methods("POST")
+
+	BaseRoutes.Channel.Handle("/privacy", ApiSessionRequired(updateChannelPrivacy)).Methods("PUT")
+	BaseRoutes.Channel.Handle("/posting_policy", ApiSessionRequired(updateChannelPostingPolicy)).Methods("PUT")
+	BaseRoutes.Channel.Handle("/archive", ApiSessionRequired(archiveChannel)).Methods("POST")
+	BaseRoutes.Channel.Handle("/unarchive", ApiSessionRequired(unarchiveChannel)).Methods("POST")
+	BaseRoutes.Channel.Handle("/move", ApiSessionRequired(moveChannel)).Methods("POST")
+	BaseRoutes.Channel.Handle("/analytics", ApiSessionRequired(getChannelAnalytics)).Methods("GET")
 
 	BaseRoutes.ChannelMembers.Handle("", ApiSessionRequired(getChannelMembers)).Methods("GET")
 	BaseRoutes.ChannelMembersForUser.Handle("", ApiSessionRequired(getChannelMembersForUser)).Methods("GET")
@@ -88,6 +101,44 @@ func createDirectChannel(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func createGroupChannel(c *Context, w http.ResponseWriter, r *http.Request) {
+	userIds := model.ArrayFromJson(r.Body)
+	allowed := false
+
+	if len(userIds) == 0 {
+		c.SetInvalidParam("user_ids")
+		return
+	}
+
+	for _, id := range userIds {
+		if len(id) != 26 {
+			c.SetInvalidParam("user_id")
+			return
+		}
+		if id == c.Session.UserId {
+			allowed = true
+		}
+	}
+
+	if !app.SessionHasPermissionTo(c.Session, model.PERMISSION_CREATE_GROUP_CHANNEL) {
+		c.SetPermissionError(model.PERMISSION_CREATE_GROUP_CHANNEL)
+		return
+	}
+
+	if !allowed && !app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	if groupChannel, err := app.CreateGroupChannel(userIds, c.Session.UserId); err != nil {
+		c.Err = err
+		return
+	} else {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(groupChannel.ToJson()))
+	}
+}
+
 func getChannelMembers(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequireChannelId()
 	if c.Err != nil {
@@ -149,3 +200,244 @@ func getChannelMembersForUser(c *Context, w http.ResponseWriter, r *http.Request
 		w.Write([]byte(members.ToJson()))
 	}
 }
+
+func updateChannelPrivacy(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	props := model.MapFromJson(r.Body)
+	privacy := props["privacy"]
+	if privacy != model.CHANNEL_OPEN && privacy != model.CHANNEL_PRIVATE {
+		c.SetInvalidParam("privacy")
+		return
+	}
+
+	channel, err := app.GetChannel(c.Params.ChannelId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	// Converting a channel requires being able to manage it both before and after the change
+	for _, channelType := range []string{channel.Type, privacy} {
+		if channelType == model.CHANNEL_OPEN && !app.SessionHasPermissionToChannel(c.Session, channel.Id, model.PERMISSION_MANAGE_PUBLIC_CHANNEL_PROPERTIES) {
+			c.SetPermissionError(model.PERMISSION_MANAGE_PUBLIC_CHANNEL_PROPERTIES)
+			return
+		}
+
+		if channelType == model.CHANNEL_PRIVATE && !app.SessionHasPermissionToChannel(c.Session, channel.Id, model.PERMISSION_MANAGE_PRIVATE_CHANNEL_PROPERTIES) {
+			c.SetPermissionError(model.PERMISSION_MANAGE_PRIVATE_CHANNEL_PROPERTIES)
+			return
+		}
+	}
+
+	rchannel, err := app.UpdateChannelPrivacy(channel, privacy, c.Session.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("name=" + rchannel.Name + " type=" + rchannel.Type)
+	w.Write([]byte(rchannel.ToJson()))
+}
+
+func updateChannelPostingPolicy(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	props := model.MapFromJson(r.Body)
+	postingPolicy := props["posting_policy"]
+	if postingPolicy != model.CHANNEL_POSTING_POLICY_ALL && postingPolicy != model.CHANNEL_POSTING_POLICY_RESTRICTED {
+		c.SetInvalidParam("posting_policy")
+		return
+	}
+
+	channel, err := app.GetChannel(c.Params.ChannelId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if channel.Type == model.CHANNEL_OPEN && !app.SessionHasPermissionToChannel(c.Session, channel.Id, model.PERMISSION_MANAGE_PUBLIC_CHANNEL_PROPERTIES) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_PUBLIC_CHANNEL_PROPERTIES)
+		return
+	}
+
+	if channel.Type == model.CHANNEL_PRIVATE && !app.SessionHasPermissionToChannel(c.Session, channel.Id, model.PERMISSION_MANAGE_PRIVATE_CHANNEL_PROPERTIES) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_PRIVATE_CHANNEL_PROPERTIES)
+		return
+	}
+
+	rchannel, err := app.UpdateChannelPostingPolicy(channel, postingPolicy)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("name=" + rchannel.Name + " posting_policy=" + rchannel.PostingPolicy)
+	w.Write([]byte(rchannel.ToJson()))
+}
+
+func archiveChannel(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	channel, err := app.GetChannel(c.Params.ChannelId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if !sessionCanArchiveChannel(c, channel) {
+		return
+	}
+
+	rchannel, err := app.ArchiveChannel(channel, c.Session.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("name=" + channel.Name)
+	w.Write([]byte(rchannel.ToJson()))
+}
+
+func unarchiveChannel(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	channel, err := app.GetChannel(c.Params.ChannelId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if !sessionCanArchiveChannel(c, channel) {
+		return
+	}
+
+	rchannel, err := app.UnarchiveChannel(channel, c.Session.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("name=" + channel.Name)
+	w.Write([]byte(rchannel.ToJson()))
+}
+
+func moveChannel(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	props := model.MapFromJson(r.Body)
+	teamId := props["team_id"]
+	if len(teamId) != 26 {
+		c.SetInvalidParam("team_id")
+		return
+	}
+
+	if !app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	channel, err := app.GetChannel(c.Params.ChannelId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	team, err := app.GetTeam(teamId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	rchannel, err := app.MoveChannel(channel, team, props["add_members"] == "true", c.Session.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("name=" + rchannel.Name + " team_id=" + team.Id)
+	w.Write([]byte(rchannel.ToJson()))
+}
+
+func getChannelAnalytics(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	query := r.URL.Query()
+
+	name := query.Get("name")
+	if len(name) == 0 {
+		name = "standard"
+	}
+
+	endTime := model.GetMillis()
+	if len(query.Get("end")) > 0 {
+		if val, err := strconv.ParseInt(query.Get("end"), 10, 64); err != nil {
+			c.SetInvalidParam("end")
+			return
+		} else {
+			endTime = val
+		}
+	}
+
+	startTime := endTime - CHANNEL_ANALYTICS_DEFAULT_RANGE
+	if len(query.Get("start")) > 0 {
+		if val, err := strconv.ParseInt(query.Get("start"), 10, 64); err != nil {
+			c.SetInvalidParam("start")
+			return
+		} else {
+			startTime = val
+		}
+	}
+
+	if !app.SessionHasPermissionToChannel(c.Session, c.Params.ChannelId, model.PERMISSION_VIEW_CHANNEL_ANALYTICS) {
+		c.SetPermissionError(model.PERMISSION_VIEW_CHANNEL_ANALYTICS)
+		return
+	}
+
+	rows, err := app.GetChannelAnalytics(name, c.Params.ChannelId, startTime, endTime)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if rows == nil {
+		c.SetInvalidParam("name")
+		return
+	}
+
+	w.Write([]byte(rows.ToJson()))
+}
+
+// sessionCanArchiveChannel checks that the session is allowed to archive or unarchive the channel, which requires
+// the same permissions as deleting it, and sets a permission error on the context if it isn't.
+func sessionCanArchiveChannel(c *Context, channel *model.Channel) bool {
+	if channel.Type == model.CHANNEL_OPEN && !app.SessionHasPermissionToChannel(c.Session, channel.Id, model.PERMISSION_DELETE_PUBLIC_CHANNEL) {
+		c.SetPermissionError(model.PERMISSION_DELETE_PUBLIC_CHANNEL)
+		return false
+	}
+
+	if channel.Type == model.CHANNEL_PRIVATE && !app.SessionHasPermissionToChannel(c.Session, channel.Id, model.PERMISSION_DELETE_PRIVATE_CHANNEL) {
+		c.SetPermissionError(model.PERMISSION_DELETE_PRIVATE_CHANNEL)
+		return false
+	}
+
+	return true
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/mattermost/platform/app"
 	"github.com/mattermost/platform/model"
 	"github.com/mattermost/platform/utils"
 )
@@ -221,6 +222,72 @@ func TestCreateDirectChannel(t *testing.T) {
 	CheckNoError(t, resp)
 }
 
+func TestCreateGroupChannel(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+	user1 := th.BasicUser
+	user2 := th.BasicUser2
+	user3 := th.CreateUser()
+
+	gm, resp := Client.CreateGroupChannel([]string{user1.Id, user2.Id, user3.Id})
+	CheckNoError(t, resp)
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatal("wrong status code")
+	}
+
+	if gm.Type != model.CHANNEL_GROUP || gm.TeamId != "" {
+		t.Fatal("should have created a group channel without a team")
+	}
+
+	if gm.Name != model.GetGroupNameFromUserIds([]string{user1.Id, user2.Id, user3.Id}) {
+		t.Fatal("group name didn't match")
+	}
+
+	rgm, resp := Client.CreateGroupChannel([]string{user3.Id, user1.Id, user2.Id})
+	CheckNoError(t, resp)
+
+	if rgm.Id != gm.Id {
+		t.Fatal("should have returned the existing group channel")
+	}
+
+	members, resp := Client.GetChannelMembers(gm.Id, 0, 60, "")
+	CheckNoError(t, resp)
+
+	if len(*members) != 3 {
+		t.Fatal("should have 3 members")
+	}
+
+	_, resp = Client.CreateGroupChannel([]string{user1.Id, user2.Id})
+	CheckBadRequestStatus(t, resp)
+	CheckErrorMessage(t, resp, "api.channel.create_group.bad_size.app_error")
+
+	userIds := []string{user1.Id}
+	for i := 0; i < model.CHANNEL_GROUP_MAX_USERS; i++ {
+		userIds = append(userIds, model.NewId())
+	}
+	_, resp = Client.CreateGroupChannel(userIds)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.CreateGroupChannel([]string{user1.Id, user2.Id, model.NewId()})
+	CheckBadRequestStatus(t, resp)
+	CheckErrorMessage(t, resp, "api.channel.create_group.bad_user.app_error")
+
+	_, resp = Client.CreateGroupChannel([]string{user1.Id, user2.Id, "junk"})
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.CreateGroupChannel([]string{user2.Id, user3.Id, model.NewId()})
+	CheckForbiddenStatus(t, resp)
+
+	Client.Logout()
+	_, resp = Client.CreateGroupChannel([]string{user1.Id, user2.Id, user3.Id})
+	CheckUnauthorizedStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.CreateGroupChannel([]string{user1.Id, user2.Id, user3.Id})
+	CheckNoError(t, resp)
+}
+
 func TestGetChannelMembers(t *testing.T) {
 	th := Setup().InitBasic().InitSystemAdmin()
 	defer TearDown()
@@ -365,3 +432,321 @@ func TestGetChannelMembersForUser(t *testing.T) {
 	_, resp = th.SystemAdminClient.GetChannelMembersForUser(th.BasicUser.Id, th.BasicTeam.Id, "")
 	CheckNoError(t, resp)
 }
+
+func TestUpdateChannelPrivacy(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	channel := th.CreatePublicChannel()
+	post := th.CreatePostWithClient(Client, channel)
+
+	rchannel, resp := Client.UpdateChannelPrivacy(channel.Id, model.CHANNEL_PRIVATE)
+	CheckNoError(t, resp)
+
+	if rchannel.Type != model.CHANNEL_PRIVATE || rchannel.Id != channel.Id {
+		t.Fatal("channel should have been made private in place")
+	}
+
+	_, resp = Client.GetPost(post.Id, "")
+	CheckNoError(t, resp)
+
+	list, resp := Client.GetPostsForChannel(channel.Id, 0, 60, "")
+	CheckNoError(t, resp)
+
+	if list.Posts[list.Order[0]].Type != model.POST_CHANGE_CHANNEL_TYPE {
+		t.Fatal("should have posted a system message")
+	}
+
+	_, resp = Client.UpdateChannelPrivacy(channel.Id, model.CHANNEL_PRIVATE)
+	CheckBadRequestStatus(t, resp)
+	CheckErrorMessage(t, resp, "api.channel.update_channel_privacy.unchanged.app_error")
+
+	rchannel, resp = Client.UpdateChannelPrivacy(channel.Id, model.CHANNEL_OPEN)
+	CheckNoError(t, resp)
+
+	if rchannel.Type != model.CHANNEL_OPEN {
+		t.Fatal("channel should have been made public")
+	}
+
+	_, resp = Client.UpdateChannelPrivacy(channel.Id, model.CHANNEL_DIRECT)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.UpdateChannelPrivacy("junk", model.CHANNEL_PRIVATE)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.UpdateChannelPrivacy(th.BasicChannel2.Id, model.CHANNEL_PRIVATE)
+	CheckNoError(t, resp)
+
+	// Check permissions with policy config changes
+	isLicensed := utils.IsLicensed
+	license := utils.License
+	restrictPublicChannel := *utils.Cfg.TeamSettings.RestrictPublicChannelManagement
+	restrictPrivateChannel := *utils.Cfg.TeamSettings.RestrictPrivateChannelManagement
+	defer func() {
+		*utils.Cfg.TeamSettings.RestrictPublicChannelManagement = restrictPublicChannel
+		*utils.Cfg.TeamSettings.RestrictPrivateChannelManagement = restrictPrivateChannel
+		utils.IsLicensed = isLicensed
+		utils.License = license
+		utils.SetDefaultRolesBasedOnConfig()
+	}()
+	*utils.Cfg.TeamSettings.RestrictPublicChannelManagement = model.PERMISSIONS_ALL
+	*utils.Cfg.TeamSettings.RestrictPrivateChannelManagement = model.PERMISSIONS_TEAM_ADMIN
+	utils.SetDefaultRolesBasedOnConfig()
+	utils.IsLicensed = true
+	utils.License = &model.License{Features: &model.Features{}}
+	utils.License.Features.SetDefaults()
+
+	_, resp = Client.UpdateChannelPrivacy(channel.Id, model.CHANNEL_PRIVATE)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.UpdateChannelPrivacy(channel.Id, model.CHANNEL_PRIVATE)
+	CheckNoError(t, resp)
+
+	Client.Logout()
+	_, resp = Client.UpdateChannelPrivacy(channel.Id, model.CHANNEL_OPEN)
+	CheckUnauthorizedStatus(t, resp)
+}
+
+func TestArchiveChannel(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	channel := th.CreatePublicChannel()
+	post := th.CreatePostWithClient(Client, channel)
+
+	rchannel, resp := Client.ArchiveChannel(channel.Id)
+	CheckNoError(t, resp)
+
+	if !rchannel.IsArchived() || rchannel.DeleteAt != 0 {
+		t.Fatal("channel should be archived but not deleted")
+	}
+
+	_, resp = Client.ArchiveChannel(channel.Id)
+	CheckBadRequestStatus(t, resp)
+	CheckErrorMessage(t, resp, "api.channel.archive_channel.archived.app_error")
+
+	_, resp = Client.CreatePost(&model.Post{ChannelId: channel.Id, Message: "zz" + model.NewId() + "a"})
+	CheckBadRequestStatus(t, resp)
+	CheckErrorMessage(t, resp, "api.post.create_post.can_not_post_to_archived.error")
+
+	_, resp = Client.GetPost(post.Id, "")
+	CheckNoError(t, resp)
+
+	list, resp := Client.GetPostsForChannel(channel.Id, 0, 60, "")
+	CheckNoError(t, resp)
+
+	if list.Posts[list.Order[0]].Type != model.POST_CHANNEL_ARCHIVED {
+		t.Fatal("should have posted a system message")
+	}
+
+	rchannel, resp = Client.UnarchiveChannel(channel.Id)
+	CheckNoError(t, resp)
+
+	if rchannel.IsArchived() {
+		t.Fatal("channel should be unarchived")
+	}
+
+	_, resp = Client.UnarchiveChannel(channel.Id)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.CreatePost(&model.Post{ChannelId: channel.Id, Message: "zz" + model.NewId() + "a"})
+	CheckNoError(t, resp)
+
+	_, resp = Client.ArchiveChannel("junk")
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.ArchiveChannel(model.NewId())
+	CheckErrorMessage(t, resp, "store.sql_channel.get.existing.app_error")
+
+	privateChannel := th.CreatePrivateChannel()
+
+	th.LoginBasic2()
+	_, resp = Client.ArchiveChannel(privateChannel.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.ArchiveChannel(privateChannel.Id)
+	CheckNoError(t, resp)
+
+	Client.Logout()
+	_, resp = Client.UnarchiveChannel(privateChannel.Id)
+	CheckUnauthorizedStatus(t, resp)
+}
+
+func TestMoveChannel(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	channel := th.CreatePublicChannel()
+	team := th.CreateTeamWithClient(th.SystemAdminClient)
+
+	_, resp := Client.MoveChannel(channel.Id, team.Id, false)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.MoveChannel(channel.Id, "junk", false)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.MoveChannel(channel.Id, th.BasicTeam.Id, false)
+	CheckBadRequestStatus(t, resp)
+	CheckErrorMessage(t, resp, "api.channel.move_channel.same_team.app_error")
+
+	rchannel, resp := th.SystemAdminClient.MoveChannel(channel.Id, team.Id, false)
+	CheckNoError(t, resp)
+
+	if rchannel.TeamId != team.Id {
+		t.Fatal("channel should have been moved")
+	}
+
+	if _, err := app.GetChannelMember(channel.Id, th.BasicUser.Id); err == nil {
+		t.Fatal("user outside of the team should have been removed from the channel")
+	}
+
+	channel2 := th.CreatePublicChannel()
+
+	rchannel, resp = th.SystemAdminClient.MoveChannel(channel2.Id, team.Id, true)
+	CheckNoError(t, resp)
+
+	if _, err := app.GetChannelMember(channel2.Id, th.BasicUser.Id); err != nil {
+		t.Fatal("user should have stayed in the channel")
+	}
+
+	if _, err := app.GetTeamMember(team.Id, th.BasicUser.Id); err != nil {
+		t.Fatal("user should have been added to the team")
+	}
+
+	archived := th.CreatePublicChannel()
+	_, resp = Client.ArchiveChannel(archived.Id)
+	CheckNoError(t, resp)
+
+	_, resp = th.SystemAdminClient.MoveChannel(archived.Id, team.Id, false)
+	CheckBadRequestStatus(t, resp)
+	CheckErrorMessage(t, resp, "api.channel.move_channel.archived.app_error")
+
+	Client.Logout()
+	_, resp = Client.MoveChannel(channel.Id, th.BasicTeam.Id, false)
+	CheckUnauthorizedStatus(t, resp)
+}
+
+func TestUpdateChannelPostingPolicy(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	channel := th.CreatePublicChannel()
+	app.AddUserToChannel(th.BasicUser2, channel)
+
+	rchannel, resp := Client.UpdateChannelPostingPolicy(channel.Id, model.CHANNEL_POSTING_POLICY_RESTRICTED)
+	CheckNoError(t, resp)
+
+	if !rchannel.IsPostingRestricted() {
+		t.Fatal("posting should be restricted")
+	}
+
+	_, resp = Client.UpdateChannelPostingPolicy(channel.Id, "junk")
+	CheckBadRequestStatus(t, resp)
+
+	// the creator of the channel is a channel admin
+	th.CreatePostWithClient(Client, channel)
+
+	th.LoginBasic2()
+
+	_, resp = Client.CreatePost(&model.Post{ChannelId: channel.Id, Message: "zz" + model.NewId() + "a"})
+	CheckForbiddenStatus(t, resp)
+	CheckErrorMessage(t, resp, "api.post.create_post.restricted.app_error")
+
+	_, resp = Client.CreatePost(&model.Post{ChannelId: channel.Id, Message: "zz" + model.NewId() + "a", Type: model.POST_JOIN_CHANNEL})
+	CheckBadRequestStatus(t, resp)
+	CheckErrorMessage(t, resp, "api.post.create_post.system_message.app_error")
+
+	if _, err := app.CreateWebhookPost(th.BasicUser2.Id, th.BasicTeam.Id, channel.Id, "message", "", "", nil, ""); err == nil {
+		t.Fatal("webhook posts should follow the posting policy")
+	}
+
+	if _, err := app.UpdateChannelMemberRoles(channel.Id, th.BasicUser2.Id, model.ROLE_CHANNEL_USER.Id+" "+model.ROLE_CHANNEL_ANNOUNCER.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	_, resp = Client.CreatePost(&model.Post{ChannelId: channel.Id, Message: "zz" + model.NewId() + "a"})
+	CheckNoError(t, resp)
+
+	_, resp = th.SystemAdminClient.UpdateChannelPostingPolicy(channel.Id, model.CHANNEL_POSTING_POLICY_ALL)
+	CheckNoError(t, resp)
+
+	Client.Logout()
+	_, resp = Client.UpdateChannelPostingPolicy(channel.Id, model.CHANNEL_POSTING_POLICY_RESTRICTED)
+	CheckUnauthorizedStatus(t, resp)
+}
+
+func TestGetChannelAnalytics(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	channel := th.CreatePublicChannel()
+
+	_, resp := Client.CreatePost(&model.Post{ChannelId: channel.Id, Message: "a" + model.NewId() + "a"})
+	CheckNoError(t, resp)
+
+	end := model.GetMillis() + 1000
+	start := end - 7*app.DAY_MILLISECONDS
+
+	rows, resp := Client.GetChannelAnalytics(channel.Id, "standard", start, end)
+	CheckNoError(t, resp)
+
+	if len(rows) != 4 {
+		t.Fatal("wrong number of rows")
+	}
+
+	if rows[0].Name != "post_count" || rows[0].Value != 1 {
+		t.Fatal("wrong post count")
+	}
+
+	if rows[1].Name != "poster_count" || rows[1].Value != 1 {
+		t.Fatal("wrong poster count")
+	}
+
+	if rows[2].Name != "member_count" || rows[2].Value != 1 {
+		t.Fatal("wrong member count")
+	}
+
+	rows, resp = Client.GetChannelAnalytics(channel.Id, "top_posters", start, end)
+	CheckNoError(t, resp)
+
+	if len(rows) != 1 || rows[0].Name != th.BasicUser.Id {
+		t.Fatal("wrong top posters")
+	}
+
+	rows, resp = Client.GetChannelAnalytics(channel.Id, "post_counts_day", start, end)
+	CheckNoError(t, resp)
+
+	if len(rows) != 1 || rows[0].Value != 1 {
+		t.Fatal("wrong post counts by day")
+	}
+
+	_, resp = Client.GetChannelAnalytics(channel.Id, "member_counts_day", start, end)
+	CheckNoError(t, resp)
+
+	_, resp = Client.GetChannelAnalytics(channel.Id, "file_counts_day", start, end)
+	CheckNoError(t, resp)
+
+	_, resp = Client.GetChannelAnalytics(channel.Id, "junk", start, end)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.GetChannelAnalytics(channel.Id, "standard", end, start)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.GetChannelAnalytics(channel.Id, "standard", end-400*app.DAY_MILLISECONDS, end)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.GetChannelAnalytics(th.BasicChannel.Id, "standard", start, end)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.GetChannelAnalytics(th.BasicChannel.Id, "standard", start, end)
+	CheckNoError(t, resp)
+
+	Client.Logout()
+	_, resp = Client.GetChannelAnalytics(channel.Id, "standard", start, end)
+	CheckUnauthorizedStatus(t, resp)
+}
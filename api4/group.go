@@ -0,0 +1,210 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	l4g "github.com/alecthomas/log4go"
+	"github.com/mattermost/platform/app"
+	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
+)
+
+func InitGroup() {
+	l4g.Debug(utils.T("api.group.init.debug"))
+
+	BaseRoutes.Groups.Handle("", ApiSessionRequired(createGroup)).Methods("POST")
+	BaseRoutes.Groups.Handle("", ApiSessionRequired(getGroups)).Methods("GET")
+
+	BaseRoutes.Group.Handle("", ApiSessionRequired(getGroup)).Methods("GET")
+	BaseRoutes.Group.Handle("", ApiSessionRequired(updateGroup)).Methods("PUT")
+	BaseRoutes.Group.Handle("", ApiSessionRequired(deleteGroup)).Methods("DELETE")
+	BaseRoutes.GroupByName.Handle("", ApiSessionRequired(getGroupByName)).Methods("GET")
+
+	BaseRoutes.GroupMembers.Handle("", ApiSessionRequired(getGroupMembers)).Methods("GET")
+	BaseRoutes.GroupMembers.Handle("", ApiSessionRequired(addGroupMember)).Methods("POST")
+	BaseRoutes.GroupMember.Handle("", ApiSessionRequired(removeGroupMember)).Methods("DELETE")
+}
+
+func createGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	group := model.GroupFromJson(r.Body)
+	if group == nil {
+		c.SetInvalidParam("group")
+		return
+	}
+
+	if !app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_GROUPS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_GROUPS)
+		return
+	}
+
+	group.CreatorId = c.Session.UserId
+
+	rgroup, err := app.CreateGroup(group)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("name=" + rgroup.Name)
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(rgroup.ToJson()))
+}
+
+func getGroups(c *Context, w http.ResponseWriter, r *http.Request) {
+	if groups, err := app.GetGroups(c.Params.Page, c.Params.PerPage); err != nil {
+		c.Err = err
+		return
+	} else {
+		w.Write([]byte(model.GroupListToJson(groups)))
+	}
+}
+
+func getGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireGroupId()
+	if c.Err != nil {
+		return
+	}
+
+	if group, err := app.GetGroup(c.Params.GroupId); err != nil {
+		c.Err = err
+		return
+	} else {
+		w.Write([]byte(group.ToJson()))
+	}
+}
+
+func getGroupByName(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireGroupName()
+	if c.Err != nil {
+		return
+	}
+
+	if group, err := app.GetGroupByName(c.Params.GroupName); err != nil {
+		c.Err = err
+		return
+	} else {
+		w.Write([]byte(group.ToJson()))
+	}
+}
+
+func updateGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireGroupId()
+	if c.Err != nil {
+		return
+	}
+
+	group := model.GroupFromJson(r.Body)
+	if group == nil {
+		c.SetInvalidParam("group")
+		return
+	}
+
+	if group.Id != c.Params.GroupId {
+		c.SetInvalidParam("group_id")
+		return
+	}
+
+	if !app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_GROUPS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_GROUPS)
+		return
+	}
+
+	rgroup, err := app.UpdateGroup(group)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("name=" + rgroup.Name)
+	w.Write([]byte(rgroup.ToJson()))
+}
+
+func deleteGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireGroupId()
+	if c.Err != nil {
+		return
+	}
+
+	if !app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_GROUPS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_GROUPS)
+		return
+	}
+
+	if err := app.DeleteGroup(c.Params.GroupId); err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("group_id=" + c.Params.GroupId)
+	ReturnStatusOK(w)
+}
+
+func getGroupMembers(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireGroupId()
+	if c.Err != nil {
+		return
+	}
+
+	if members, err := app.GetGroupMembers(c.Params.GroupId, c.Params.Page, c.Params.PerPage); err != nil {
+		c.Err = err
+		return
+	} else {
+		w.Write([]byte(model.GroupMembersToJson(members)))
+	}
+}
+
+func addGroupMember(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireGroupId()
+	if c.Err != nil {
+		return
+	}
+
+	member := model.GroupMemberFromJson(r.Body)
+	if member == nil {
+		c.SetInvalidParam("group_member")
+		return
+	}
+
+	if len(member.UserId) != 26 {
+		c.SetInvalidParam("user_id")
+		return
+	}
+
+	if !app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_GROUPS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_GROUPS)
+		return
+	}
+
+	rmember, err := app.AddGroupMember(c.Params.GroupId, member.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("group_id=" + c.Params.GroupId + " user_id=" + member.UserId)
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(rmember.ToJson()))
+}
+
+func removeGroupMember(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireGroupId().RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_GROUPS) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_GROUPS)
+		return
+	}
+
+	if err := app.RemoveGroupMember(c.Params.GroupId, c.Params.UserId); err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("group_id=" + c.Params.GroupId + " user_id=" + c.Params.UserId)
+	ReturnStatusOK(w)
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/mattermost/platform/model"
+)
+
+func TestCreateGroup(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	group := &model.Group{Name: "g" + model.NewId(), DisplayName: "Group"}
+
+	_, resp := Client.CreateGroup(group)
+	CheckForbiddenStatus(t, resp)
+
+	rgroup, resp := th.SystemAdminClient.CreateGroup(group)
+	CheckNoError(t, resp)
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatal("wrong status code")
+	}
+
+	if rgroup.Name != group.Name {
+		t.Fatal("names did not match")
+	}
+
+	if rgroup.CreatorId != th.SystemAdminUser.Id {
+		t.Fatal("creator id should be set")
+	}
+
+	_, resp = th.SystemAdminClient.CreateGroup(group)
+	CheckBadRequestStatus(t, resp)
+	CheckErrorMessage(t, resp, "store.sql_group.save.name_exists.app_error")
+
+	_, resp = th.SystemAdminClient.CreateGroup(&model.Group{Name: th.BasicUser.Username, DisplayName: "Group"})
+	CheckBadRequestStatus(t, resp)
+	CheckErrorMessage(t, resp, "app.group.name_taken_by_user.app_error")
+
+	_, resp = th.SystemAdminClient.CreateGroup(&model.Group{Name: "here", DisplayName: "Group"})
+	CheckErrorMessage(t, resp, "model.group.is_valid.name.app_error")
+
+	Client.Logout()
+	_, resp = Client.CreateGroup(group)
+	CheckUnauthorizedStatus(t, resp)
+}
+
+func TestGetGroup(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	group, resp := th.SystemAdminClient.CreateGroup(&model.Group{Name: "g" + model.NewId(), DisplayName: "Group"})
+	CheckNoError(t, resp)
+
+	rgroup, resp := Client.GetGroup(group.Id)
+	CheckNoError(t, resp)
+
+	if rgroup.Id != group.Id {
+		t.Fatal("wrong group")
+	}
+
+	rgroup, resp = Client.GetGroupByName(group.Name)
+	CheckNoError(t, resp)
+
+	if rgroup.Id != group.Id {
+		t.Fatal("wrong group")
+	}
+
+	_, resp = Client.GetGroup(model.NewId())
+	CheckNotFoundStatus(t, resp)
+
+	_, resp = Client.GetGroup("junk")
+	CheckBadRequestStatus(t, resp)
+
+	groups, resp := Client.GetGroups(0, 100)
+	CheckNoError(t, resp)
+
+	found := false
+	for _, g := range groups {
+		if g.Id == group.Id {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Fatal("group should be listed")
+	}
+
+	Client.Logout()
+	_, resp = Client.GetGroup(group.Id)
+	CheckUnauthorizedStatus(t, resp)
+}
+
+func TestUpdateGroup(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	group, resp := th.SystemAdminClient.CreateGroup(&model.Group{Name: "g" + model.NewId(), DisplayName: "Group"})
+	CheckNoError(t, resp)
+
+	group.DisplayName = "Updated"
+	group.Name = "g" + model.NewId()
+
+	_, resp = Client.UpdateGroup(group)
+	CheckForbiddenStatus(t, resp)
+
+	rgroup, resp := th.SystemAdminClient.UpdateGroup(group)
+	CheckNoError(t, resp)
+
+	if rgroup.DisplayName != "Updated" || rgroup.Name != group.Name {
+		t.Fatal("group should've been updated")
+	}
+
+	group.Name = th.BasicUser.Username
+	_, resp = th.SystemAdminClient.UpdateGroup(group)
+	CheckErrorMessage(t, resp, "app.group.name_taken_by_user.app_error")
+}
+
+func TestDeleteGroup(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	group, resp := th.SystemAdminClient.CreateGroup(&model.Group{Name: "g" + model.NewId(), DisplayName: "Group"})
+	CheckNoError(t, resp)
+
+	_, resp = Client.DeleteGroup(group.Id)
+	CheckForbiddenStatus(t, resp)
+
+	pass, resp := th.SystemAdminClient.DeleteGroup(group.Id)
+	CheckNoError(t, resp)
+
+	if !pass {
+		t.Fatal("should have passed")
+	}
+
+	_, resp = Client.GetGroup(group.Id)
+	CheckNotFoundStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.DeleteGroup(group.Id)
+	CheckNotFoundStatus(t, resp)
+}
+
+func TestGroupMembers(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	group, resp := th.SystemAdminClient.CreateGroup(&model.Group{Name: "g" + model.NewId(), DisplayName: "Group"})
+	CheckNoError(t, resp)
+
+	_, resp = Client.AddGroupMember(group.Id, th.BasicUser2.Id)
+	CheckForbiddenStatus(t, resp)
+
+	member, resp := th.SystemAdminClient.AddGroupMember(group.Id, th.BasicUser2.Id)
+	CheckNoError(t, resp)
+
+	if member.GroupId != group.Id || member.UserId != th.BasicUser2.Id {
+		t.Fatal("wrong member")
+	}
+
+	_, resp = th.SystemAdminClient.AddGroupMember(group.Id, th.BasicUser2.Id)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.AddGroupMember(group.Id, model.NewId())
+	CheckNotFoundStatus(t, resp)
+
+	members, resp := Client.GetGroupMembers(group.Id, 0, 100)
+	CheckNoError(t, resp)
+
+	if len(members) != 1 || members[0].UserId != th.BasicUser2.Id {
+		t.Fatal("wrong members")
+	}
+
+	_, resp = Client.RemoveGroupMember(group.Id, th.BasicUser2.Id)
+	CheckForbiddenStatus(t, resp)
+
+	pass, resp := th.SystemAdminClient.RemoveGroupMember(group.Id, th.BasicUser2.Id)
+	CheckNoError(t, resp)
+
+	if !pass {
+		t.Fatal("should have passed")
+	}
+
+	members, resp = Client.GetGroupMembers(group.Id, 0, 100)
+	CheckNoError(t, resp)
+
+	if len(members) != 0 {
+		t.Fatal("should have no members")
+	}
+}
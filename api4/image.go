@@ -0,0 +1,40 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+	"strconv"
+
+	l4g "github.com/alecthomas/log4go"
+	"github.com/mattermost/platform/app"
+	"github.com/mattermost/platform/utils"
+)
+
+func InitImage() {
+	l4g.Debug(utils.T("api.image.init.debug"))
+
+	BaseRoutes.Image.Handle("", ApiSessionRequiredTrustRequester(getImage)).Methods("GET")
+}
+
+func getImage(c *Context, w http.ResponseWriter, r *http.Request) {
+	imageURL := r.URL.Query().Get("url")
+	if len(imageURL) == 0 {
+		c.SetInvalidParam("url")
+		return
+	}
+
+	image, err := app.GetProxiedImage(imageURL)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Header().Set("Content-Type", image.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(image.Data)))
+	w.Header().Set("Cache-Control", "max-age="+strconv.Itoa(*utils.Cfg.ServiceSettings.ImageProxyCacheInMinutes*60)+", private")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+	w.Write(image.Data)
+}
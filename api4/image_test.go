@@ -0,0 +1,52 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mattermost/platform/utils"
+)
+
+func TestGetProxiedImage(t *testing.T) {
+	th := Setup().InitBasic()
+	defer TearDown()
+	Client := th.Client
+
+	enableImageProxy := *utils.Cfg.ServiceSettings.EnableImageProxy
+	defer func() {
+		*utils.Cfg.ServiceSettings.EnableImageProxy = enableImageProxy
+	}()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("not really a png"))
+	}))
+	defer server.Close()
+
+	*utils.Cfg.ServiceSettings.EnableImageProxy = false
+	_, resp := Client.GetProxiedImage(server.URL)
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Fatal("should have failed with the proxy disabled")
+	}
+
+	*utils.Cfg.ServiceSettings.EnableImageProxy = true
+
+	_, resp = Client.GetProxiedImage("")
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.GetProxiedImage("ftp://example.com/image.png")
+	CheckBadRequestStatus(t, resp)
+	CheckErrorMessage(t, resp, "app.image_proxy.invalid_url.app_error")
+
+	// The test server listens on a loopback address, which the proxy must refuse to reach
+	_, resp = Client.GetProxiedImage(server.URL)
+	CheckErrorMessage(t, resp, "app.image_proxy.fetch.app_error")
+
+	Client.Logout()
+	_, resp = Client.GetProxiedImage(server.URL)
+	CheckUnauthorizedStatus(t, resp)
+}
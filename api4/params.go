@@ -17,18 +17,24 @@ const (
 )
 
 type ApiParams struct {
-	UserId    string
-	TeamId    string
-	ChannelId string
-	PostId    string
-	FileId    string
-	CommandId string
-	HookId    string
-	EmojiId   string
-	Email     string
-	Username  string
-	Page      int
-	PerPage   int
+	UserId      string
+	TeamId      string
+	ChannelId   string
+	PostId      string
+	FileId      string
+	CommandId   string
+	HookId      string
+	EmojiId     string
+	EmojiName   string
+	GroupId     string
+	GroupName   string
+	CategoryId  string
+	TokenId     string
+	AttributeId string
+	Email       string
+	Username    string
+	Page        int
+	PerPage     int
 }
 
 func ApiParamsFromRequest(r *http.Request) *ApiParams {
@@ -68,6 +74,30 @@ func ApiParamsFromRequest(r *http.Request) *ApiParams {
 		params.EmojiId = val
 	}
 
+	if val, ok := props["emoji_name"]; ok {
+		params.EmojiName = val
+	}
+
+	if val, ok := props["group_id"]; ok {
+		params.GroupId = val
+	}
+
+	if val, ok := props["group_name"]; ok {
+		params.GroupName = val
+	}
+
+	if val, ok := props["category_id"]; ok {
+		params.CategoryId = val
+	}
+
+	if val, ok := props["token_id"]; ok {
+		params.TokenId = val
+	}
+
+	if val, ok := props["attribute_id"]; ok {
+		params.AttributeId = val
+	}
+
 	if val, ok := props["email"]; ok {
 		params.Email = val
 	}
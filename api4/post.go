@@ -18,6 +18,8 @@ func InitPost() {
 	BaseRoutes.Posts.Handle("", ApiSessionRequired(createPost)).Methods("POST")
 	BaseRoutes.Post.Handle("", ApiSessionRequired(getPost)).Methods("GET")
 	BaseRoutes.Post.Handle("/thread", ApiSessionRequired(getPostThread)).Methods("GET")
+	BaseRoutes.Post.Handle("/reactions/summary", ApiSessionRequired(getReactionSummary)).Methods("GET")
+	BaseRoutes.Post.Handle("/reactions/{emoji_name:[A-Za-z0-9_+\\-]+}/users", ApiSessionRequired(getReactionUsers)).Methods("GET")
 	BaseRoutes.PostsForChannel.Handle("", ApiSessionRequired(getPostsForChannel)).Methods("GET")
 }
 
@@ -30,6 +32,12 @@ func createPost(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	post.UserId = c.Session.UserId
 
+	// System messages are only ever created by the server, which lets them through posting restrictions
+	if post.IsSystemMessage() {
+		c.Err = model.NewAppError("createPost", "api.post.create_post.system_message.app_error", nil, "type="+post.Type, http.StatusBadRequest)
+		return
+	}
+
 	if !app.SessionHasPermissionToChannel(c.Session, post.ChannelId, model.PERMISSION_CREATE_POST) {
 		c.SetPermissionError(model.PERMISSION_CREATE_POST)
 		return
@@ -117,3 +125,41 @@ func getPostThread(c *Context, w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(list.ToJson()))
 	}
 }
+
+func getReactionSummary(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePostId()
+	if c.Err != nil {
+		return
+	}
+
+	if !app.SessionHasPermissionToChannelByPost(c.Session, c.Params.PostId, model.PERMISSION_READ_CHANNEL) {
+		c.SetPermissionError(model.PERMISSION_READ_CHANNEL)
+		return
+	}
+
+	if summaries, err := app.GetReactionSummaryForPost(c.Params.PostId, c.Session.UserId); err != nil {
+		c.Err = err
+		return
+	} else {
+		w.Write([]byte(model.ReactionSummariesToJson(summaries)))
+	}
+}
+
+func getReactionUsers(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePostId().RequireEmojiName()
+	if c.Err != nil {
+		return
+	}
+
+	if !app.SessionHasPermissionToChannelByPost(c.Session, c.Params.PostId, model.PERMISSION_READ_CHANNEL) {
+		c.SetPermissionError(model.PERMISSION_READ_CHANNEL)
+		return
+	}
+
+	if users, err := app.GetUsersForReaction(c.Params.PostId, c.Params.EmojiName, c.Params.Page, c.Params.PerPage, app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM)); err != nil {
+		c.Err = err
+		return
+	} else {
+		w.Write([]byte(model.UserListToJson(users)))
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/mattermost/platform/app"
 	"github.com/mattermost/platform/model"
 )
 
@@ -247,3 +248,63 @@ func TestGetPostThread(t *testing.T) {
 	list, resp = th.SystemAdminClient.GetPostThread(th.BasicPost.Id, "")
 	CheckNoError(t, resp)
 }
+
+func TestGetReactionSummary(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	now := model.GetMillis()
+	reactions := []*model.Reaction{
+		{UserId: th.BasicUser.Id, PostId: th.BasicPost.Id, EmojiName: "smile", CreateAt: now},
+		{UserId: th.BasicUser2.Id, PostId: th.BasicPost.Id, EmojiName: "smile", CreateAt: now + 1},
+		{UserId: th.BasicUser2.Id, PostId: th.BasicPost.Id, EmojiName: "+1", CreateAt: now + 2},
+	}
+	for _, reaction := range reactions {
+		if result := <-app.Srv.Store.Reaction().Save(reaction); result.Err != nil {
+			t.Fatal(result.Err)
+		}
+	}
+
+	summaries, resp := Client.GetReactionSummary(th.BasicPost.Id)
+	CheckNoError(t, resp)
+
+	if len(summaries) != 2 {
+		t.Fatal("should have two emojis")
+	}
+
+	if summaries[0].EmojiName != "smile" || summaries[0].Count != 2 || !summaries[0].Reacted {
+		t.Fatal("wrong summary for smile", summaries[0])
+	}
+
+	if summaries[1].EmojiName != "+1" || summaries[1].Count != 1 || summaries[1].Reacted {
+		t.Fatal("wrong summary for +1", summaries[1])
+	}
+
+	users, resp := Client.GetReactionUsers(th.BasicPost.Id, "smile", 0, 100)
+	CheckNoError(t, resp)
+
+	if len(users) != 2 || users[0].Id != th.BasicUser.Id || users[1].Id != th.BasicUser2.Id {
+		t.Fatal("wrong users")
+	}
+
+	users, resp = Client.GetReactionUsers(th.BasicPost.Id, "smile", 1, 1)
+	CheckNoError(t, resp)
+
+	if len(users) != 1 || users[0].Id != th.BasicUser2.Id {
+		t.Fatal("wrong page of users")
+	}
+
+	_, resp = Client.GetReactionSummary("junk")
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.GetReactionSummary(model.NewId())
+	CheckForbiddenStatus(t, resp)
+
+	Client.Logout()
+	_, resp = Client.GetReactionSummary(th.BasicPost.Id)
+	CheckUnauthorizedStatus(t, resp)
+
+	_, resp = Client.GetReactionUsers(th.BasicPost.Id, "smile", 0, 100)
+	CheckUnauthorizedStatus(t, resp)
+}
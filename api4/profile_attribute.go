@@ -0,0 +1,176 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	l4g "github.com/alecthomas/log4go"
+	"github.com/mattermost/platform/app"
+	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
+)
+
+func InitProfileAttribute() {
+	l4g.Debug(utils.T("api.profile_attribute.init.debug"))
+
+	BaseRoutes.ProfileAttributes.Handle("", ApiSessionRequired(createProfileAttribute)).Methods("POST")
+	BaseRoutes.ProfileAttributes.Handle("", ApiSessionRequired(getProfileAttributes)).Methods("GET")
+
+	BaseRoutes.ProfileAttribute.Handle("", ApiSessionRequired(updateProfileAttribute)).Methods("PUT")
+	BaseRoutes.ProfileAttribute.Handle("", ApiSessionRequired(deleteProfileAttribute)).Methods("DELETE")
+	BaseRoutes.ProfileAttribute.Handle("/users", ApiSessionRequired(searchUsersByProfileAttribute)).Methods("GET")
+
+	BaseRoutes.User.Handle("/profile_attributes", ApiSessionRequired(updateUserProfileAttributes)).Methods("PUT")
+}
+
+func createProfileAttribute(c *Context, w http.ResponseWriter, r *http.Request) {
+	attribute := model.ProfileAttributeFromJson(r.Body)
+	if attribute == nil {
+		c.SetInvalidParam("profile_attribute")
+		return
+	}
+
+	if !app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	if rattribute, err := app.CreateProfileAttribute(attribute); err != nil {
+		c.Err = err
+		return
+	} else {
+		c.LogAudit("name=" + rattribute.Name)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(rattribute.ToJson()))
+	}
+}
+
+func getProfileAttributes(c *Context, w http.ResponseWriter, r *http.Request) {
+	// No permission check required
+
+	if attributes, err := app.GetProfileAttributes(); err != nil {
+		c.Err = err
+		return
+	} else {
+		w.Write([]byte(model.ProfileAttributeListToJson(attributes)))
+	}
+}
+
+func updateProfileAttribute(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireAttributeId()
+	if c.Err != nil {
+		return
+	}
+
+	attribute := model.ProfileAttributeFromJson(r.Body)
+	if attribute == nil {
+		c.SetInvalidParam("profile_attribute")
+		return
+	}
+
+	if attribute.Id != c.Params.AttributeId {
+		c.SetInvalidParam("id")
+		return
+	}
+
+	if !app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	if rattribute, err := app.UpdateProfileAttribute(attribute); err != nil {
+		c.Err = err
+		return
+	} else {
+		c.LogAudit("name=" + rattribute.Name)
+		w.Write([]byte(rattribute.ToJson()))
+	}
+}
+
+func deleteProfileAttribute(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireAttributeId()
+	if c.Err != nil {
+		return
+	}
+
+	if !app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	if err := app.DeleteProfileAttribute(c.Params.AttributeId); err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAudit("attribute_id=" + c.Params.AttributeId)
+	ReturnStatusOK(w)
+}
+
+func searchUsersByProfileAttribute(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireAttributeId()
+	if c.Err != nil {
+		return
+	}
+
+	term := r.URL.Query().Get("term")
+	if len(term) == 0 {
+		c.SetInvalidParam("term")
+		return
+	}
+
+	attribute, err := app.GetProfileAttribute(c.Params.AttributeId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	// Searching by a private attribute would reveal its values
+	if attribute.Visibility == model.PROFILE_ATTRIBUTE_VISIBILITY_PRIVATE && !app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	profiles, err := app.SearchUsersByProfileAttribute(attribute.Id, term, c.Params.PerPage)
+	if err == nil && c.Session.IsGuest() {
+		profiles, err = app.FilterUsersVisibleToGuest(c.Session.UserId, profiles)
+	}
+
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	for _, p := range profiles {
+		app.SanitizeProfile(p, c.IsSystemAdmin())
+	}
+
+	w.Write([]byte(model.UserListToJson(profiles)))
+}
+
+func updateUserProfileAttributes(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	values := model.MapFromJson(r.Body)
+	if len(values) == 0 {
+		c.SetInvalidParam("profile_attributes")
+		return
+	}
+
+	if !app.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	if rvalues, err := app.UpdateUserProfileAttributes(c.Params.UserId, values); err != nil {
+		c.Err = err
+		return
+	} else {
+		w.Write([]byte(model.MapToJson(rvalues)))
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/mattermost/platform/model"
+)
+
+func TestProfileAttributes(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	department := &model.ProfileAttribute{Name: "department" + model.NewId()[:8], DisplayName: "Department", Type: model.PROFILE_ATTRIBUTE_TYPE_TEXT}
+	phone := &model.ProfileAttribute{Name: "phone" + model.NewId()[:8], DisplayName: "Phone", Type: model.PROFILE_ATTRIBUTE_TYPE_PHONE, Visibility: model.PROFILE_ATTRIBUTE_VISIBILITY_PRIVATE}
+
+	_, resp := Client.CreateProfileAttribute(department)
+	CheckForbiddenStatus(t, resp)
+
+	department, resp = th.SystemAdminClient.CreateProfileAttribute(department)
+	CheckNoError(t, resp)
+
+	if department.Visibility != model.PROFILE_ATTRIBUTE_VISIBILITY_ALWAYS {
+		t.Fatal("should have defaulted to always visible")
+	}
+
+	phone, resp = th.SystemAdminClient.CreateProfileAttribute(phone)
+	CheckNoError(t, resp)
+
+	_, resp = th.SystemAdminClient.CreateProfileAttribute(&model.ProfileAttribute{Name: department.Name, DisplayName: "Other", Type: model.PROFILE_ATTRIBUTE_TYPE_TEXT})
+	CheckBadRequestStatus(t, resp)
+
+	attributes, resp := Client.GetProfileAttributes()
+	CheckNoError(t, resp)
+
+	found := 0
+	for _, attribute := range attributes {
+		if attribute.Id == department.Id || attribute.Id == phone.Id {
+			found++
+		}
+	}
+
+	if found != 2 {
+		t.Fatal("should have returned both attributes")
+	}
+
+	values, resp := Client.UpdateUserProfileAttributes(th.BasicUser.Id, map[string]string{department.Name: "Engineering", phone.Name: "+1 555 1234"})
+	CheckNoError(t, resp)
+
+	if values[department.Name] != "Engineering" || values[phone.Name] != "+1 555 1234" {
+		t.Fatal("should have returned the saved values")
+	}
+
+	_, resp = Client.UpdateUserProfileAttributes(th.BasicUser.Id, map[string]string{phone.Name: "call me"})
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.UpdateUserProfileAttributes(th.BasicUser.Id, map[string]string{"junk" + model.NewId()[:8]: "value"})
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.UpdateUserProfileAttributes(th.BasicUser2.Id, map[string]string{department.Name: "Sales"})
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.UpdateUserProfileAttributes(th.BasicUser2.Id, map[string]string{department.Name: "Sales"})
+	CheckNoError(t, resp)
+
+	user, resp := Client.GetUser(th.BasicUser.Id, "")
+	CheckNoError(t, resp)
+
+	if user.ProfileAttributes[department.Name] != "Engineering" || user.ProfileAttributes[phone.Name] != "+1 555 1234" {
+		t.Fatal("users should see all of their own attributes")
+	}
+
+	user, resp = Client.GetUser(th.BasicUser2.Id, "")
+	CheckNoError(t, resp)
+
+	if user.ProfileAttributes[department.Name] != "Sales" {
+		t.Fatal("should have returned the attributes of other users")
+	}
+
+	Client.Logout()
+	th.LoginBasic2()
+
+	user, resp = Client.GetUser(th.BasicUser.Id, "")
+	CheckNoError(t, resp)
+
+	if user.ProfileAttributes[department.Name] != "Engineering" {
+		t.Fatal("should have returned always visible attributes")
+	}
+
+	if _, ok := user.ProfileAttributes[phone.Name]; ok {
+		t.Fatal("should not have returned private attributes of other users")
+	}
+
+	user, resp = th.SystemAdminClient.GetUser(th.BasicUser.Id, "")
+	CheckNoError(t, resp)
+
+	if user.ProfileAttributes[phone.Name] != "+1 555 1234" {
+		t.Fatal("system admins should see private attributes")
+	}
+
+	users, resp := Client.SearchUsersByProfileAttribute(department.Id, "eng", 100)
+	CheckNoError(t, resp)
+
+	if len(users) != 1 || users[0].Id != th.BasicUser.Id {
+		t.Fatal("should have found the user by attribute value")
+	}
+
+	_, resp = Client.SearchUsersByProfileAttribute(department.Id, "", 100)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.SearchUsersByProfileAttribute(phone.Id, "+1", 100)
+	CheckForbiddenStatus(t, resp)
+
+	users, resp = th.SystemAdminClient.SearchUsersByProfileAttribute(phone.Id, "+1", 100)
+	CheckNoError(t, resp)
+
+	if len(users) != 1 || users[0].Id != th.BasicUser.Id {
+		t.Fatal("system admins should be able to search private attributes")
+	}
+
+	values, resp = th.SystemAdminClient.UpdateUserProfileAttributes(th.BasicUser.Id, map[string]string{department.Name: ""})
+	CheckNoError(t, resp)
+
+	if _, ok := values[department.Name]; ok {
+		t.Fatal("an empty value should have cleared the attribute")
+	}
+
+	department.DisplayName = "Team"
+	_, resp = Client.UpdateProfileAttribute(department)
+	CheckForbiddenStatus(t, resp)
+
+	rdepartment, resp := th.SystemAdminClient.UpdateProfileAttribute(department)
+	CheckNoError(t, resp)
+
+	if rdepartment.DisplayName != "Team" || rdepartment.CreateAt != department.CreateAt {
+		t.Fatal("should have updated the display name only")
+	}
+
+	_, resp = Client.DeleteProfileAttribute(department.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.DeleteProfileAttribute(department.Id)
+	CheckNoError(t, resp)
+
+	_, resp = th.SystemAdminClient.DeleteProfileAttribute(department.Id)
+	CheckNotFoundStatus(t, resp)
+
+	user, resp = th.SystemAdminClient.GetUser(th.BasicUser2.Id, "")
+	CheckNoError(t, resp)
+
+	if _, ok := user.ProfileAttributes[department.Name]; ok {
+		t.Fatal("deleting the attribute should have removed its values")
+	}
+}
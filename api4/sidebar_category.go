@@ -0,0 +1,170 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	l4g "github.com/alecthomas/log4go"
+	"github.com/mattermost/platform/app"
+	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
+)
+
+func InitSidebarCategory() {
+	l4g.Debug(utils.T("api.sidebar_category.init.debug"))
+
+	BaseRoutes.SidebarCategories.Handle("", ApiSessionRequired(getSidebarCategories)).Methods("GET")
+	BaseRoutes.SidebarCategories.Handle("", ApiSessionRequired(createSidebarCategory)).Methods("POST")
+	BaseRoutes.SidebarCategories.Handle("/order", ApiSessionRequired(updateSidebarCategoryOrder)).Methods("PUT")
+
+	BaseRoutes.SidebarCategory.Handle("", ApiSessionRequired(getSidebarCategory)).Methods("GET")
+	BaseRoutes.SidebarCategory.Handle("", ApiSessionRequired(updateSidebarCategory)).Methods("PUT")
+	BaseRoutes.SidebarCategory.Handle("", ApiSessionRequired(deleteSidebarCategory)).Methods("DELETE")
+}
+
+// sessionCanManageSidebar checks that the session belongs to the user whose sidebar is requested and that the user is
+// on the team, and sets an error on the context if not.
+func sessionCanManageSidebar(c *Context) bool {
+	if !app.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return false
+	}
+
+	if !app.SessionHasPermissionToTeam(c.Session, c.Params.TeamId, model.PERMISSION_VIEW_TEAM) {
+		c.SetPermissionError(model.PERMISSION_VIEW_TEAM)
+		return false
+	}
+
+	return true
+}
+
+func getSidebarCategories(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId().RequireTeamId()
+	if c.Err != nil {
+		return
+	}
+
+	if !sessionCanManageSidebar(c) {
+		return
+	}
+
+	if categories, err := app.GetSidebarCategories(c.Params.UserId, c.Params.TeamId); err != nil {
+		c.Err = err
+		return
+	} else {
+		w.Write([]byte(model.SidebarCategoryListToJson(categories)))
+	}
+}
+
+func createSidebarCategory(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId().RequireTeamId()
+	if c.Err != nil {
+		return
+	}
+
+	category := model.SidebarCategoryFromJson(r.Body)
+	if category == nil {
+		c.SetInvalidParam("category")
+		return
+	}
+
+	if !sessionCanManageSidebar(c) {
+		return
+	}
+
+	rcategory, err := app.CreateSidebarCategory(c.Params.UserId, c.Params.TeamId, category)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(rcategory.ToJson()))
+}
+
+func updateSidebarCategoryOrder(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId().RequireTeamId()
+	if c.Err != nil {
+		return
+	}
+
+	categoryIds := model.ArrayFromJson(r.Body)
+	if len(categoryIds) == 0 {
+		c.SetInvalidParam("order")
+		return
+	}
+
+	if !sessionCanManageSidebar(c) {
+		return
+	}
+
+	if err := app.UpdateSidebarCategoryOrder(c.Params.UserId, c.Params.TeamId, categoryIds); err != nil {
+		c.Err = err
+		return
+	}
+
+	ReturnStatusOK(w)
+}
+
+func getSidebarCategory(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId().RequireTeamId().RequireCategoryId()
+	if c.Err != nil {
+		return
+	}
+
+	if !sessionCanManageSidebar(c) {
+		return
+	}
+
+	if category, err := app.GetSidebarCategory(c.Params.UserId, c.Params.TeamId, c.Params.CategoryId); err != nil {
+		c.Err = err
+		return
+	} else {
+		w.Write([]byte(category.ToJson()))
+	}
+}
+
+func updateSidebarCategory(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId().RequireTeamId().RequireCategoryId()
+	if c.Err != nil {
+		return
+	}
+
+	category := model.SidebarCategoryFromJson(r.Body)
+	if category == nil || category.Id != c.Params.CategoryId {
+		c.SetInvalidParam("category")
+		return
+	}
+
+	if !sessionCanManageSidebar(c) {
+		return
+	}
+
+	rcategory, err := app.UpdateSidebarCategory(c.Params.UserId, c.Params.TeamId, category)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	w.Write([]byte(rcategory.ToJson()))
+}
+
+func deleteSidebarCategory(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId().RequireTeamId().RequireCategoryId()
+	if c.Err != nil {
+		return
+	}
+
+	if !sessionCanManageSidebar(c) {
+		return
+	}
+
+	if err := app.DeleteSidebarCategory(c.Params.UserId, c.Params.TeamId, c.Params.CategoryId); err != nil {
+		c.Err = err
+		return
+	}
+
+	ReturnStatusOK(w)
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/mattermost/platform/app"
+	"github.com/mattermost/platform/model"
+)
+
+func TestGetSidebarCategories(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	categories, resp := Client.GetSidebarCategories(th.BasicUser.Id, th.BasicTeam.Id)
+	CheckNoError(t, resp)
+
+	if len(categories) != 3 {
+		t.Fatal("should have created the default categories")
+	}
+
+	if categories[0].Type != model.SIDEBAR_CATEGORY_FAVORITES || categories[1].Type != model.SIDEBAR_CATEGORY_CHANNELS || categories[2].Type != model.SIDEBAR_CATEGORY_DIRECT_MESSAGES {
+		t.Fatal("default categories are in the wrong order")
+	}
+
+	found := false
+	for _, channelId := range categories[1].ChannelIds {
+		if channelId == th.BasicChannel.Id {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Fatal("channels should be in the channels category by default")
+	}
+
+	again, resp := Client.GetSidebarCategories(th.BasicUser.Id, th.BasicTeam.Id)
+	CheckNoError(t, resp)
+
+	if len(again) != 3 || again[0].Id != categories[0].Id {
+		t.Fatal("default categories should only be created once")
+	}
+
+	_, resp = Client.GetSidebarCategories(th.BasicUser2.Id, th.BasicTeam.Id)
+	CheckForbiddenStatus(t, resp)
+
+	// Requests that create the default categories at the same time shouldn't create them twice
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := app.GetSidebarCategories(th.BasicUser2.Id, th.BasicTeam.Id); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if categories, err := app.GetSidebarCategories(th.BasicUser2.Id, th.BasicTeam.Id); err != nil {
+		t.Fatal(err)
+	} else if len(categories) != 3 {
+		t.Fatal("should have created the default categories once", len(categories))
+	}
+
+	_, resp = Client.GetSidebarCategories(th.BasicUser.Id, model.NewId())
+	CheckForbiddenStatus(t, resp)
+
+	Client.Logout()
+	_, resp = Client.GetSidebarCategories(th.BasicUser.Id, th.BasicTeam.Id)
+	CheckUnauthorizedStatus(t, resp)
+}
+
+func TestCreateUpdateDeleteSidebarCategory(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	category := &model.SidebarCategory{
+		DisplayName: "Projects",
+		Type:        model.SIDEBAR_CATEGORY_FAVORITES,
+		ChannelIds:  model.StringArray{th.BasicChannel.Id},
+	}
+
+	rcategory, resp := Client.CreateSidebarCategory(th.BasicUser.Id, th.BasicTeam.Id, category)
+	CheckNoError(t, resp)
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatal("wrong status code")
+	}
+
+	if rcategory.Type != model.SIDEBAR_CATEGORY_CUSTOM {
+		t.Fatal("created categories should be custom")
+	}
+
+	categories, resp := Client.GetSidebarCategories(th.BasicUser.Id, th.BasicTeam.Id)
+	CheckNoError(t, resp)
+
+	if len(categories) != 4 || categories[3].Id != rcategory.Id {
+		t.Fatal("custom category should be added last")
+	}
+
+	for _, channelId := range categories[1].ChannelIds {
+		if channelId == th.BasicChannel.Id {
+			t.Fatal("channel should have been taken out of the channels category")
+		}
+	}
+
+	_, resp = Client.CreateSidebarCategory(th.BasicUser.Id, th.BasicTeam.Id, &model.SidebarCategory{DisplayName: "Other", ChannelIds: model.StringArray{model.NewId()}})
+	CheckBadRequestStatus(t, resp)
+
+	favorites := categories[0]
+	favorites.ChannelIds = model.StringArray{th.BasicChannel.Id}
+	favorites.Collapsed = true
+	favorites.DisplayName = "Renamed"
+
+	rfavorites, resp := Client.UpdateSidebarCategory(th.BasicUser.Id, th.BasicTeam.Id, favorites)
+	CheckNoError(t, resp)
+
+	if !rfavorites.Collapsed || len(rfavorites.ChannelIds) != 1 || rfavorites.DisplayName == "Renamed" {
+		t.Fatal("favorites should have been updated except for the name")
+	}
+
+	rcategory, resp = Client.GetSidebarCategory(th.BasicUser.Id, th.BasicTeam.Id, rcategory.Id)
+	CheckNoError(t, resp)
+
+	if len(rcategory.ChannelIds) != 0 {
+		t.Fatal("channel should have been moved to favorites")
+	}
+
+	order := []string{rcategory.Id, categories[0].Id, categories[1].Id, categories[2].Id}
+	_, resp = Client.UpdateSidebarCategoryOrder(th.BasicUser.Id, th.BasicTeam.Id, order)
+	CheckNoError(t, resp)
+
+	categories, resp = Client.GetSidebarCategories(th.BasicUser.Id, th.BasicTeam.Id)
+	CheckNoError(t, resp)
+
+	if categories[0].Id != rcategory.Id {
+		t.Fatal("categories should have been reordered")
+	}
+
+	_, resp = Client.UpdateSidebarCategoryOrder(th.BasicUser.Id, th.BasicTeam.Id, order[1:])
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.DeleteSidebarCategory(th.BasicUser.Id, th.BasicTeam.Id, favorites.Id)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.DeleteSidebarCategory(th.BasicUser.Id, th.BasicTeam.Id, rcategory.Id)
+	CheckNoError(t, resp)
+
+	_, resp = Client.GetSidebarCategory(th.BasicUser.Id, th.BasicTeam.Id, rcategory.Id)
+	CheckNotFoundStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.DeleteSidebarCategory(th.BasicUser.Id, th.BasicTeam.Id, favorites.Id)
+	CheckBadRequestStatus(t, resp)
+}
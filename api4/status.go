@@ -0,0 +1,200 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"net/http"
+
+	l4g "github.com/alecthomas/log4go"
+	"github.com/mattermost/platform/app"
+	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
+)
+
+func InitStatus() {
+	l4g.Debug(utils.T("api.status.init.debug"))
+
+	BaseRoutes.User.Handle("/status", ApiSessionRequired(getUserStatus)).Methods("GET")
+	BaseRoutes.User.Handle("/status", ApiSessionRequired(updateUserStatus)).Methods("PUT")
+
+	BaseRoutes.User.Handle("/custom_status", ApiSessionRequired(getUserCustomStatus)).Methods("GET")
+	BaseRoutes.User.Handle("/custom_status", ApiSessionRequired(updateUserCustomStatus)).Methods("PUT")
+	BaseRoutes.User.Handle("/custom_status", ApiSessionRequired(removeUserCustomStatus)).Methods("DELETE")
+
+	BaseRoutes.User.Handle("/out_of_office", ApiSessionRequired(getUserOutOfOffice)).Methods("GET")
+	BaseRoutes.User.Handle("/out_of_office", ApiSessionRequired(updateUserOutOfOffice)).Methods("PUT")
+	BaseRoutes.User.Handle("/out_of_office", ApiSessionRequired(removeUserOutOfOffice)).Methods("DELETE")
+}
+
+func getUserStatus(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	// No permission check required
+
+	if status, err := app.GetStatusForOthers(c.Params.UserId); err != nil {
+		// Users without a status are offline
+		w.Write([]byte((&model.Status{UserId: c.Params.UserId, Status: model.STATUS_OFFLINE}).ToJson()))
+	} else {
+		w.Write([]byte(status.ToJson()))
+	}
+}
+
+func updateUserStatus(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	status := model.StatusFromJson(r.Body)
+	if status == nil || status.UserId != c.Params.UserId || !model.IsValidStatus(status.Status) {
+		c.SetInvalidParam("status")
+		return
+	}
+
+	if status.Status == model.STATUS_DND && status.DNDEndTime != 0 && status.DNDEndTime <= model.GetMillis() {
+		c.SetInvalidParam("dnd_end_time")
+		return
+	}
+
+	if !app.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	switch status.Status {
+	case model.STATUS_ONLINE:
+		app.SetStatusOnline(c.Params.UserId, "", true)
+	case model.STATUS_AWAY:
+		app.SetStatusAwayIfNeeded(c.Params.UserId, true)
+	case model.STATUS_OFFLINE:
+		app.SetStatusOffline(c.Params.UserId, true)
+	case model.STATUS_DND:
+		app.SetStatusDND(c.Params.UserId, status.DNDEndTime)
+	}
+
+	getUserStatus(c, w, r)
+}
+
+func getUserCustomStatus(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	// No permission check required
+
+	if status, err := app.GetCustomStatus(c.Params.UserId); err != nil {
+		c.Err = err
+		return
+	} else {
+		w.Write([]byte(status.ToJson()))
+	}
+}
+
+func updateUserCustomStatus(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	status := model.CustomStatusFromJson(r.Body)
+	if status == nil {
+		c.SetInvalidParam("custom_status")
+		return
+	}
+
+	if !app.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	if status, err := app.SetCustomStatus(c.Params.UserId, status); err != nil {
+		c.Err = err
+		return
+	} else {
+		w.Write([]byte(status.ToJson()))
+	}
+}
+
+func removeUserCustomStatus(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !app.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	if err := app.RemoveCustomStatus(c.Params.UserId); err != nil {
+		c.Err = err
+		return
+	}
+
+	ReturnStatusOK(w)
+}
+
+func getUserOutOfOffice(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	// No permission check required since the message is sent to anyone who messages the user
+
+	if outOfOffice, err := app.GetOutOfOffice(c.Params.UserId); err != nil {
+		c.Err = err
+		return
+	} else {
+		w.Write([]byte(outOfOffice.ToJson()))
+	}
+}
+
+func updateUserOutOfOffice(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	outOfOffice := model.OutOfOfficeFromJson(r.Body)
+	if outOfOffice == nil {
+		c.SetInvalidParam("out_of_office")
+		return
+	}
+
+	if !app.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	if outOfOffice, err := app.SetOutOfOffice(c.Params.UserId, outOfOffice); err != nil {
+		c.Err = err
+		return
+	} else {
+		w.Write([]byte(outOfOffice.ToJson()))
+	}
+}
+
+func removeUserOutOfOffice(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !app.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	if err := app.RemoveOutOfOffice(c.Params.UserId); err != nil {
+		c.Err = err
+		return
+	}
+
+	ReturnStatusOK(w)
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/mattermost/platform/app"
+	"github.com/mattermost/platform/model"
+)
+
+func TestUpdateUserStatus(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	endTime := model.GetMillis() + 60*60*1000
+	status, resp := Client.UpdateUserStatus(th.BasicUser.Id, &model.Status{UserId: th.BasicUser.Id, Status: model.STATUS_DND, DNDEndTime: endTime})
+	CheckNoError(t, resp)
+
+	if status.Status != model.STATUS_DND || status.DNDEndTime != endTime || !status.Manual {
+		t.Fatal("status should be do not disturb")
+	}
+
+	status, resp = th.SystemAdminClient.GetUserStatus(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	if status.Status != model.STATUS_DND {
+		t.Fatal("wrong status")
+	}
+
+	_, resp = Client.UpdateUserStatus(th.BasicUser.Id, &model.Status{UserId: th.BasicUser.Id, Status: model.STATUS_DND, DNDEndTime: model.GetMillis() - 1000})
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.UpdateUserStatus(th.BasicUser.Id, &model.Status{UserId: th.BasicUser.Id, Status: "junk"})
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.UpdateUserStatus(th.BasicUser2.Id, &model.Status{UserId: th.BasicUser2.Id, Status: model.STATUS_DND})
+	CheckForbiddenStatus(t, resp)
+
+	status, resp = Client.UpdateUserStatus(th.BasicUser.Id, &model.Status{UserId: th.BasicUser.Id, Status: model.STATUS_ONLINE})
+	CheckNoError(t, resp)
+
+	if status.Status != model.STATUS_ONLINE || status.DNDEndTime != 0 {
+		t.Fatal("do not disturb should've been turned off")
+	}
+
+	_, resp = th.SystemAdminClient.UpdateUserStatus(th.BasicUser2.Id, &model.Status{UserId: th.BasicUser2.Id, Status: model.STATUS_AWAY})
+	CheckNoError(t, resp)
+
+	// Other users can't see which channel someone is viewing
+	app.SetStatusOnline(th.BasicUser2.Id, "", true)
+	if err := app.SetActiveChannel(th.BasicUser2.Id, th.BasicChannel.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	status, resp = Client.GetUserStatus(th.BasicUser2.Id)
+	CheckNoError(t, resp)
+
+	if status.Status != model.STATUS_ONLINE || len(status.ActiveChannel) != 0 {
+		t.Fatal("shouldn't have returned the active channel")
+	}
+
+	// Do not disturb that has ended is reported as the status it falls back to
+	app.SetStatusDND(th.BasicUser2.Id, model.GetMillis()-1000)
+
+	status, resp = Client.GetUserStatus(th.BasicUser2.Id)
+	CheckNoError(t, resp)
+
+	if status.Status != model.STATUS_ONLINE || status.DNDEndTime != 0 {
+		t.Fatal("shouldn't still be do not disturb", status.Status)
+	}
+
+	Client.Logout()
+	_, resp = Client.GetUserStatus(th.BasicUser.Id)
+	CheckUnauthorizedStatus(t, resp)
+}
+
+func TestUserCustomStatus(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	_, resp := Client.GetUserCustomStatus(th.BasicUser.Id)
+	CheckNotFoundStatus(t, resp)
+
+	status, resp := Client.UpdateUserCustomStatus(th.BasicUser.Id, &model.CustomStatus{Emoji: "calendar", Text: "In a meeting"})
+	CheckNoError(t, resp)
+
+	if status.UserId != th.BasicUser.Id || status.Emoji != "calendar" || status.Text != "In a meeting" {
+		t.Fatal("custom status should've been set")
+	}
+
+	status, resp = th.SystemAdminClient.GetUserCustomStatus(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	if status.Text != "In a meeting" {
+		t.Fatal("wrong custom status")
+	}
+
+	_, resp = Client.UpdateUserCustomStatus(th.BasicUser.Id, &model.CustomStatus{})
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.UpdateUserCustomStatus(th.BasicUser.Id, &model.CustomStatus{Emoji: "calendar", ExpiresAt: model.GetMillis() - 1000})
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.UpdateUserCustomStatus(th.BasicUser2.Id, &model.CustomStatus{Emoji: "calendar"})
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.RemoveUserCustomStatus(th.BasicUser2.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.UpdateUserCustomStatus(th.BasicUser2.Id, &model.CustomStatus{Emoji: "palm_tree"})
+	CheckNoError(t, resp)
+
+	ok, resp := Client.RemoveUserCustomStatus(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	if !ok {
+		t.Fatal("should have returned true")
+	}
+
+	_, resp = Client.GetUserCustomStatus(th.BasicUser.Id)
+	CheckNotFoundStatus(t, resp)
+}
+
+func TestUserOutOfOffice(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	_, resp := Client.GetUserOutOfOffice(th.BasicUser.Id)
+	CheckNotFoundStatus(t, resp)
+
+	endAt := model.GetMillis() + 24*60*60*1000
+	outOfOffice, resp := Client.UpdateUserOutOfOffice(th.BasicUser.Id, &model.OutOfOffice{Message: "On vacation", EndAt: endAt})
+	CheckNoError(t, resp)
+
+	if outOfOffice.UserId != th.BasicUser.Id || outOfOffice.Message != "On vacation" || outOfOffice.EndAt != endAt {
+		t.Fatal("out of office should've been set")
+	}
+
+	outOfOffice, resp = th.SystemAdminClient.GetUserOutOfOffice(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	if outOfOffice.Message != "On vacation" {
+		t.Fatal("wrong out of office")
+	}
+
+	_, resp = Client.UpdateUserOutOfOffice(th.BasicUser.Id, &model.OutOfOffice{EndAt: endAt})
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.UpdateUserOutOfOffice(th.BasicUser.Id, &model.OutOfOffice{Message: "On vacation", StartAt: 1000, EndAt: model.GetMillis() - 1000})
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.UpdateUserOutOfOffice(th.BasicUser2.Id, &model.OutOfOffice{Message: "On vacation", EndAt: endAt})
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.RemoveUserOutOfOffice(th.BasicUser2.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.UpdateUserOutOfOffice(th.BasicUser2.Id, &model.OutOfOffice{Message: "On leave", EndAt: endAt})
+	CheckNoError(t, resp)
+
+	ok, resp := Client.RemoveUserOutOfOffice(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	if !ok {
+		t.Fatal("should have returned true")
+	}
+
+	_, resp = Client.GetUserOutOfOffice(th.BasicUser.Id)
+	CheckNotFoundStatus(t, resp)
+}
@@ -5,6 +5,7 @@ package api4
 
 import (
 	"net/http"
+	"strconv"
 
 	l4g "github.com/alecthomas/log4go"
 	"github.com/mattermost/platform/app"
@@ -24,6 +25,10 @@ func InitUser() {
 	BaseRoutes.User.Handle("", ApiSessionRequired(deleteUser)).Methods("DELETE")
 	BaseRoutes.User.Handle("/roles", ApiSessionRequired(updateUserRoles)).Methods("PUT")
 	BaseRoutes.User.Handle("/password", ApiSessionRequired(updatePassword)).Methods("PUT")
+	BaseRoutes.User.Handle("/tokens", ApiSessionRequired(createUserAccessToken)).Methods("POST")
+	BaseRoutes.User.Handle("/tokens", ApiSessionRequired(getUserAccessTokens)).Methods("GET")
+	BaseRoutes.User.Handle("/tokens/{token_id:[A-Za-z0-9]+}", ApiSessionRequired(revokeUserAccessToken)).Methods("DELETE")
+	BaseRoutes.User.Handle("/username_history", ApiSessionRequired(getUsernameHistory)).Methods("GET")
 	BaseRoutes.Users.Handle("/password/reset", ApiHandler(resetPassword)).Methods("POST")
 	BaseRoutes.Users.Handle("/password/reset/send", ApiHandler(sendPasswordReset)).Methods("POST")
 
@@ -82,6 +87,18 @@ func getUser(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if c.Session.IsGuest() {
+		if err = app.CheckUserVisibleToGuest(c.Session.UserId, user.Id); err != nil {
+			c.Err = err
+			return
+		}
+	}
+
+	if err = app.AddProfileAttributesToUser(user, app.SessionHasPermissionToUser(c.Session, user.Id)); err != nil {
+		c.Err = err
+		return
+	}
+
 	etag := user.Etag(utils.Cfg.PrivacySettings.ShowFullName, utils.Cfg.PrivacySettings.ShowEmailAddress)
 
 	if HandleEtag(etag, "Get User", w, r) {
@@ -105,7 +122,19 @@ func getUserByUsername(c *Context, w http.ResponseWriter, r *http.Request) {
 	var user *model.User
 	var err *model.AppError
 
-	if user, err = app.GetUserByUsername(c.Params.Username); err != nil {
+	if user, err = app.GetUserByUsernameFollowingHistory(c.Params.Username); err != nil {
+		c.Err = err
+		return
+	}
+
+	if c.Session.IsGuest() {
+		if err = app.CheckUserVisibleToGuest(c.Session.UserId, user.Id); err != nil {
+			c.Err = err
+			return
+		}
+	}
+
+	if err = app.AddProfileAttributesToUser(user, app.SessionHasPermissionToUser(c.Session, user.Id)); err != nil {
 		c.Err = err
 		return
 	}
@@ -138,6 +167,18 @@ func getUserByEmail(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if c.Session.IsGuest() {
+		if err = app.CheckUserVisibleToGuest(c.Session.UserId, user.Id); err != nil {
+			c.Err = err
+			return
+		}
+	}
+
+	if err = app.AddProfileAttributesToUser(user, app.SessionHasPermissionToUser(c.Session, user.Id)); err != nil {
+		c.Err = err
+		return
+	}
+
 	etag := user.Etag(utils.Cfg.PrivacySettings.ShowFullName, utils.Cfg.PrivacySettings.ShowEmailAddress)
 
 	if HandleEtag(etag, "Get User", w, r) {
@@ -200,6 +241,11 @@ func getUsers(c *Context, w http.ResponseWriter, r *http.Request) {
 		profiles, err = app.GetUsersPage(c.Params.Page, c.Params.PerPage, c.IsSystemAdmin())
 	}
 
+	// Guests only see the people in their channels, which the members of a channel they can read already are
+	if err == nil && len(inChannelId) == 0 && c.Session.IsGuest() {
+		profiles, err = app.FilterUsersVisibleToGuest(c.Session.UserId, profiles)
+	}
+
 	if err != nil {
 		c.Err = err
 		return
@@ -221,7 +267,12 @@ func getUsersByIds(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	// No permission check required
 
-	if users, err := app.GetUsersByIds(userIds, c.IsSystemAdmin()); err != nil {
+	users, err := app.GetUsersByIds(userIds, c.IsSystemAdmin())
+	if err == nil && c.Session.IsGuest() {
+		users, err = app.FilterUsersVisibleToGuest(c.Session.UserId, users)
+	}
+
+	if err != nil {
 		c.Err = err
 		return
 	} else {
@@ -350,6 +401,102 @@ func updatePassword(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func createUserAccessToken(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	props := model.MapFromJson(r.Body)
+
+	var expiresAt int64
+	if len(props["expires_at"]) > 0 {
+		var err error
+		if expiresAt, err = strconv.ParseInt(props["expires_at"], 10, 64); err != nil {
+			c.SetInvalidParam("expires_at")
+			return
+		}
+	}
+
+	if !app.SessionHasPermissionTo(c.Session, model.PERMISSION_CREATE_USER_ACCESS_TOKEN) {
+		c.SetPermissionError(model.PERMISSION_CREATE_USER_ACCESS_TOKEN)
+		return
+	}
+
+	if !app.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	if token, err := app.CreateUserAccessToken(c.Params.UserId, props["description"], expiresAt); err != nil {
+		c.Err = err
+		return
+	} else {
+		c.LogAuditWithUserId(c.Params.UserId, "token_id="+token.Id)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(token.ToJson()))
+	}
+}
+
+func getUserAccessTokens(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !app.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	if tokens, err := app.GetUserAccessTokens(c.Params.UserId); err != nil {
+		c.Err = err
+		return
+	} else {
+		w.Write([]byte(model.SessionsToJson(tokens)))
+	}
+}
+
+func getUsernameHistory(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !app.SessionHasPermissionTo(c.Session, model.PERMISSION_MANAGE_SYSTEM) {
+		c.SetPermissionError(model.PERMISSION_MANAGE_SYSTEM)
+		return
+	}
+
+	if history, err := app.GetUsernameHistory(c.Params.UserId); err != nil {
+		c.Err = err
+		return
+	} else {
+		w.Write([]byte(model.UsernameHistoryListToJson(history)))
+	}
+}
+
+func revokeUserAccessToken(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId().RequireTokenId()
+	if c.Err != nil {
+		return
+	}
+
+	// Revoking doesn't need the permission to create tokens so that users can still get rid of old ones
+	if !app.SessionHasPermissionToUser(c.Session, c.Params.UserId) {
+		c.SetPermissionError(model.PERMISSION_EDIT_OTHER_USERS)
+		return
+	}
+
+	if err := app.RevokeUserAccessToken(c.Params.UserId, c.Params.TokenId); err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAuditWithUserId(c.Params.UserId, "token_id="+c.Params.TokenId)
+	ReturnStatusOK(w)
+}
+
 func resetPassword(c *Context, w http.ResponseWriter, r *http.Request) {
 	props := model.MapFromJson(r.Body)
 
@@ -319,6 +319,31 @@ func TestUpdateUser(t *testing.T) {
 		t.Fatal("LastPasswordUpdate should not have updated")
 	}
 
+	if ruser.Timezone[model.USER_TIMEZONE_USE_AUTOMATIC] != "true" {
+		t.Fatal("should have defaulted to the automatic timezone")
+	}
+
+	ruser.Timezone = model.StringMap{model.USER_TIMEZONE_USE_AUTOMATIC: "false", model.USER_TIMEZONE_MANUAL: "America/Toronto"}
+	ruser, resp = Client.UpdateUser(ruser)
+	CheckNoError(t, resp)
+
+	if ruser.GetPreferredTimezone() != "America/Toronto" {
+		t.Fatal("timezone did not update properly")
+	}
+
+	ruser.Timezone = nil
+	ruser, resp = Client.UpdateUser(ruser)
+	CheckNoError(t, resp)
+
+	if ruser.GetPreferredTimezone() != "America/Toronto" {
+		t.Fatal("timezone should not have been cleared")
+	}
+
+	ruser.Timezone = model.StringMap{model.USER_TIMEZONE_MANUAL: "Mars/Olympus_Mons"}
+	_, resp = Client.UpdateUser(ruser)
+	CheckBadRequestStatus(t, resp)
+	ruser.Timezone = nil
+
 	ruser.Id = "junk"
 	_, resp = Client.UpdateUser(ruser)
 	CheckBadRequestStatus(t, resp)
@@ -735,3 +760,215 @@ func TestResetPassword(t *testing.T) {
 	_, resp = Client.SendPasswordResetEmail(user.Email)
 	CheckBadRequestStatus(t, resp)
 }
+
+func TestUserAccessTokens(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	enableUserAccessTokens := *utils.Cfg.ServiceSettings.EnableUserAccessTokens
+	defer func() {
+		*utils.Cfg.ServiceSettings.EnableUserAccessTokens = enableUserAccessTokens
+		utils.SetDefaultRolesBasedOnConfig()
+	}()
+	*utils.Cfg.ServiceSettings.EnableUserAccessTokens = false
+	utils.SetDefaultRolesBasedOnConfig()
+
+	_, resp := Client.CreateUserAccessToken(th.BasicUser.Id, "scripts", 0)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = th.SystemAdminClient.CreateUserAccessToken(th.BasicUser.Id, "scripts", 0)
+	CheckForbiddenStatus(t, resp)
+
+	if _, err := app.CreateUserAccessToken(th.BasicUser.Id, "scripts", 0); err == nil {
+		t.Fatal("shouldn't be able to create a token while they're disabled")
+	}
+
+	*utils.Cfg.ServiceSettings.EnableUserAccessTokens = true
+	utils.SetDefaultRolesBasedOnConfig()
+
+	token, resp := Client.CreateUserAccessToken(th.BasicUser.Id, "scripts", 0)
+	CheckNoError(t, resp)
+
+	if len(token.Token) == 0 || !token.IsUserAccessToken() || token.Props[model.SESSION_PROP_DESCRIPTION] != "scripts" {
+		t.Fatal("should've returned the token")
+	}
+
+	_, resp = Client.CreateUserAccessToken(th.BasicUser.Id, "expired", model.GetMillis()-1000)
+	CheckBadRequestStatus(t, resp)
+
+	_, resp = Client.CreateUserAccessToken(th.BasicUser2.Id, "scripts", 0)
+	CheckForbiddenStatus(t, resp)
+
+	onBehalfToken, resp := th.SystemAdminClient.CreateUserAccessToken(th.BasicUser2.Id, "on behalf", model.GetMillis()+60*60*1000)
+	CheckNoError(t, resp)
+
+	onBehalfClient := th.CreateClient()
+	onBehalfClient.AuthToken = onBehalfToken.Token
+	onBehalfClient.AuthType = model.HEADER_BEARER
+
+	_, resp = onBehalfClient.GetUser(th.BasicUser2.Id, "")
+	CheckNoError(t, resp)
+
+	tokenClient := th.CreateClient()
+	tokenClient.AuthToken = token.Token
+	tokenClient.AuthType = model.HEADER_BEARER
+
+	_, resp = tokenClient.GetUser(th.BasicUser.Id, "")
+	CheckNoError(t, resp)
+
+	tokens, resp := Client.GetUserAccessTokens(th.BasicUser.Id)
+	CheckNoError(t, resp)
+
+	if len(tokens) != 1 || tokens[0].Id != token.Id || len(tokens[0].Token) != 0 {
+		t.Fatal("should've returned the token without its value")
+	}
+
+	_, resp = Client.GetUserAccessTokens(th.BasicUser2.Id)
+	CheckForbiddenStatus(t, resp)
+
+	*utils.Cfg.ServiceSettings.EnableUserAccessTokens = false
+	utils.SetDefaultRolesBasedOnConfig()
+
+	_, resp = tokenClient.GetUser(th.BasicUser.Id, "")
+	CheckUnauthorizedStatus(t, resp)
+
+	_, resp = onBehalfClient.GetUser(th.BasicUser2.Id, "")
+	CheckUnauthorizedStatus(t, resp)
+
+	*utils.Cfg.ServiceSettings.EnableUserAccessTokens = true
+	utils.SetDefaultRolesBasedOnConfig()
+
+	_, resp = Client.RevokeUserAccessToken(th.BasicUser2.Id, token.Id)
+	CheckForbiddenStatus(t, resp)
+
+	_, resp = Client.RevokeUserAccessToken(th.BasicUser.Id, model.NewId())
+	CheckNotFoundStatus(t, resp)
+
+	ok, resp := Client.RevokeUserAccessToken(th.BasicUser.Id, token.Id)
+	CheckNoError(t, resp)
+
+	if !ok {
+		t.Fatal("should have returned true")
+	}
+
+	_, resp = tokenClient.GetUser(th.BasicUser.Id, "")
+	CheckUnauthorizedStatus(t, resp)
+}
+
+func TestUsernameHistory(t *testing.T) {
+	th := Setup().InitBasic().InitSystemAdmin()
+	defer TearDown()
+	Client := th.Client
+
+	user := th.BasicUser
+	oldUsername := user.Username
+	user.Username = GenerateTestUsername()
+
+	_, resp := Client.UpdateUser(user)
+	CheckNoError(t, resp)
+
+	ruser, resp := Client.GetUserByUsername(oldUsername, "")
+	CheckNoError(t, resp)
+
+	if ruser.Id != user.Id || ruser.Username != user.Username {
+		t.Fatal("old username should have redirected to the user")
+	}
+
+	_, resp = Client.GetUsernameHistory(user.Id)
+	CheckForbiddenStatus(t, resp)
+
+	history, resp := th.SystemAdminClient.GetUsernameHistory(user.Id)
+	CheckNoError(t, resp)
+
+	if len(history) != 1 || history[0].OldUsername != oldUsername || history[0].NewUsername != user.Username {
+		t.Fatal("username change should have been recorded")
+	}
+
+	redirectDays := *utils.Cfg.TeamSettings.UsernameRedirectDays
+	defer func() {
+		*utils.Cfg.TeamSettings.UsernameRedirectDays = redirectDays
+	}()
+	*utils.Cfg.TeamSettings.UsernameRedirectDays = 0
+
+	_, resp = Client.GetUserByUsername(oldUsername, "")
+	CheckNotFoundStatus(t, resp)
+
+	*utils.Cfg.TeamSettings.UsernameRedirectDays = redirectDays
+
+	// The old username stops redirecting once someone else takes it
+	other := th.CreateUser()
+	other.Username = oldUsername
+	_, resp = th.SystemAdminClient.UpdateUser(other)
+	CheckNoError(t, resp)
+
+	ruser, resp = Client.GetUserByUsername(oldUsername, "")
+	CheckNoError(t, resp)
+
+	if ruser.Id != other.Id {
+		t.Fatal("should have returned the user who has the username now")
+	}
+}
+
+func TestGetUsersAsGuest(t *testing.T) {
+	th := Setup().InitBasic()
+	defer TearDown()
+
+	guest := th.CreateUser()
+	if ruser, err := app.UpdateUserRoles(guest.Id, model.ROLE_SYSTEM_GUEST.Id); err != nil {
+		t.Fatal(err)
+	} else {
+		LinkUserToTeam(ruser, th.BasicTeam)
+		if _, err := app.AddUserToChannel(ruser, th.BasicChannel); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Basic user 2 isn't in any channel with the guest
+	if err := app.LeaveChannel(th.BasicChannel.Id, th.BasicUser2.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	GuestClient := th.CreateClient()
+	_, resp := GuestClient.Login(guest.Email, guest.Password)
+	CheckNoError(t, resp)
+
+	_, resp = GuestClient.GetUser(guest.Id, "")
+	CheckNoError(t, resp)
+
+	_, resp = GuestClient.GetUser(th.BasicUser.Id, "")
+	CheckNoError(t, resp)
+
+	_, resp = GuestClient.GetUser(th.BasicUser2.Id, "")
+	CheckNotFoundStatus(t, resp)
+
+	_, resp = GuestClient.GetUserByUsername(th.BasicUser2.Username, "")
+	CheckNotFoundStatus(t, resp)
+
+	checkVisibleUsers := func(users []*model.User) {
+		found := false
+		for _, user := range users {
+			if user.Id == th.BasicUser2.Id {
+				t.Fatal("guests shouldn't see users outside of their channels")
+			} else if user.Id == th.BasicUser.Id {
+				found = true
+			}
+		}
+
+		if !found {
+			t.Fatal("guests should see the users in their channels")
+		}
+	}
+
+	users, resp := GuestClient.GetUsers(0, 100, "")
+	CheckNoError(t, resp)
+	checkVisibleUsers(users)
+
+	users, resp = GuestClient.GetUsersInTeam(th.BasicTeam.Id, 0, 100, "")
+	CheckNoError(t, resp)
+	checkVisibleUsers(users)
+
+	users, resp = GuestClient.GetUsersByIds([]string{th.BasicUser.Id, th.BasicUser2.Id})
+	CheckNoError(t, resp)
+	checkVisibleUsers(users)
+}
@@ -106,6 +106,9 @@ func ReloadConfig() {
 
 	// start/restart email batching job if necessary
 	InitEmailBatching()
+	InitChannelAutoArchive()
+	InitCustomStatusExpiry()
+	InitOutOfOfficeExpiry()
 }
 
 func SaveConfig(cfg *model.Config) *model.AppError {
@@ -146,6 +149,9 @@ func SaveConfig(cfg *model.Config) *model.AppError {
 
 	// start/restart email batching job if necessary
 	InitEmailBatching()
+	InitChannelAutoArchive()
+	InitCustomStatusExpiry()
+	InitOutOfOfficeExpiry()
 
 	return nil
 }
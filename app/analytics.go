@@ -4,6 +4,8 @@
 package app
 
 import (
+	"net/http"
+
 	l4g "github.com/alecthomas/log4go"
 	"github.com/mattermost/platform/einterfaces"
 	"github.com/mattermost/platform/model"
@@ -14,6 +16,9 @@ import (
 const (
 	DAY_MILLISECONDS   = 24 * 60 * 60 * 1000
 	MONTH_MILLISECONDS = 31 * DAY_MILLISECONDS
+
+	CHANNEL_ANALYTICS_MAX_RANGE   = 366 * DAY_MILLISECONDS
+	CHANNEL_ANALYTICS_TOP_POSTERS = 10
 )
 
 func GetAnalytics(name string, teamId string) (model.AnalyticsRows, *model.AppError) {
@@ -230,6 +235,83 @@ func GetAnalytics(name string, teamId string) (model.AnalyticsRows, *model.AppEr
 	return nil, nil
 }
 
+// GetChannelAnalytics returns the analytics of a single channel over a date range, in milliseconds. It returns nil
+// for an unknown name, like GetAnalytics.
+func GetChannelAnalytics(name string, channelId string, startTime int64, endTime int64) (model.AnalyticsRows, *model.AppError) {
+	if startTime > endTime || endTime-startTime > CHANNEL_ANALYTICS_MAX_RANGE {
+		return nil, model.NewAppError("GetChannelAnalytics", "app.analytics.get_channel_analytics.range.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if name == "standard" {
+		var rows model.AnalyticsRows = make([]*model.AnalyticsRow, 4)
+		rows[0] = &model.AnalyticsRow{Name: "post_count", Value: 0}
+		rows[1] = &model.AnalyticsRow{Name: "poster_count", Value: 0}
+		rows[2] = &model.AnalyticsRow{Name: "member_count", Value: 0}
+		rows[3] = &model.AnalyticsRow{Name: "file_count", Value: 0}
+
+		postChan := Srv.Store.Post().AnalyticsPostCountsByDayForChannel(channelId, startTime, endTime)
+		posterChan := Srv.Store.Post().AnalyticsPosterCountForChannel(channelId, startTime, endTime)
+		memberChan := Srv.Store.Channel().GetMemberCount(channelId, true)
+		fileChan := Srv.Store.Post().AnalyticsFileCountsByDayForChannel(channelId, startTime, endTime)
+
+		if r := <-postChan; r.Err != nil {
+			return nil, r.Err
+		} else {
+			for _, row := range r.Data.(model.AnalyticsRows) {
+				rows[0].Value += row.Value
+			}
+		}
+
+		if r := <-posterChan; r.Err != nil {
+			return nil, r.Err
+		} else {
+			rows[1].Value = float64(r.Data.(int64))
+		}
+
+		if r := <-memberChan; r.Err != nil {
+			return nil, r.Err
+		} else {
+			rows[2].Value = float64(r.Data.(int64))
+		}
+
+		if r := <-fileChan; r.Err != nil {
+			return nil, r.Err
+		} else {
+			for _, row := range r.Data.(model.AnalyticsRows) {
+				rows[3].Value += row.Value
+			}
+		}
+
+		return rows, nil
+	} else if name == "post_counts_day" {
+		if r := <-Srv.Store.Post().AnalyticsPostCountsByDayForChannel(channelId, startTime, endTime); r.Err != nil {
+			return nil, r.Err
+		} else {
+			return r.Data.(model.AnalyticsRows), nil
+		}
+	} else if name == "top_posters" {
+		if r := <-Srv.Store.Post().AnalyticsTopPostersForChannel(channelId, startTime, endTime, CHANNEL_ANALYTICS_TOP_POSTERS); r.Err != nil {
+			return nil, r.Err
+		} else {
+			return r.Data.(model.AnalyticsRows), nil
+		}
+	} else if name == "member_counts_day" {
+		if r := <-Srv.Store.Channel().AnalyticsMemberCountsByDay(channelId, startTime, endTime); r.Err != nil {
+			return nil, r.Err
+		} else {
+			return r.Data.(model.AnalyticsRows), nil
+		}
+	} else if name == "file_counts_day" {
+		if r := <-Srv.Store.Post().AnalyticsFileCountsByDayForChannel(channelId, startTime, endTime); r.Err != nil {
+			return nil, r.Err
+		} else {
+			return r.Data.(model.AnalyticsRows), nil
+		}
+	}
+
+	return nil, nil
+}
+
 func GetRecentlyActiveUsersForTeam(teamId string) (map[string]*model.User, *model.AppError) {
 	if result := <-Srv.Store.User().GetRecentlyActiveUsersForTeam(teamId); result.Err != nil {
 		return nil, result.Err
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strings"
 
+	l4g "github.com/alecthomas/log4go"
 	"github.com/mattermost/platform/einterfaces"
 	"github.com/mattermost/platform/model"
 	"github.com/mattermost/platform/utils"
@@ -70,6 +71,11 @@ func checkLdapUserPasswordAndAllCriteria(ldapId *string, password string, mfaTok
 		user = ldapUser
 	}
 
+	// Soft error since the user was still able to log in
+	if err := SyncProfileAttributesFromDirectory(user); err != nil {
+		l4g.Error(utils.T("app.profile_attribute.sync.error"), user.Id, err.Error())
+	}
+
 	if err := CheckUserMfa(user, mfaToken); err != nil {
 		return nil, err
 	}
@@ -95,6 +101,10 @@ func CheckUserAdditionalAuthenticationCriteria(user *model.User, mfaToken string
 		return err
 	}
 
+	if err := checkUserNotBot(user); err != nil {
+		return err
+	}
+
 	if err := checkUserLoginAttempts(user); err != nil {
 		return err
 	}
@@ -103,7 +113,7 @@ func CheckUserAdditionalAuthenticationCriteria(user *model.User, mfaToken string
 }
 
 func CheckUserMfa(user *model.User, token string) *model.AppError {
-	if !user.MfaActive || !utils.IsLicensed || !*utils.License.Features.MFA || !*utils.Cfg.ServiceSettings.EnableMultifactorAuthentication {
+	if !user.MfaActive || !*utils.Cfg.ServiceSettings.EnableMultifactorAuthentication {
 		return nil
 	}
 
@@ -112,7 +122,7 @@ func CheckUserMfa(user *model.User, token string) *model.AppError {
 		return model.NewLocAppError("checkUserMfa", "api.user.check_user_mfa.not_available.app_error", nil, "")
 	}
 
-	if ok, err := mfaInterface.ValidateToken(user.MfaSecret, token); err != nil {
+	if ok, err := mfaInterface.ValidateToken(user, token); err != nil {
 		return err
 	} else if !ok {
 		return model.NewLocAppError("checkUserMfa", "api.user.check_user_mfa.bad_code.app_error", nil, "")
@@ -143,8 +153,15 @@ func checkUserNotDisabled(user *model.User) *model.AppError {
 	return nil
 }
 
+func checkUserNotBot(user *model.User) *model.AppError {
+	if user.IsBot {
+		return model.NewAppError("Login", "api.user.login.bot_login_forbidden.app_error", nil, "user_id="+user.Id, http.StatusUnauthorized)
+	}
+	return nil
+}
+
 func authenticateUser(user *model.User, password, mfaToken string) (*model.User, *model.AppError) {
-	ldapAvailable := *utils.Cfg.LdapSettings.Enable && einterfaces.GetLdapInterface() != nil && utils.IsLicensed && *utils.License.Features.LDAP
+	ldapAvailable := *utils.Cfg.LdapSettings.Enable && einterfaces.GetLdapInterface() != nil
 
 	if user.AuthService == model.USER_AUTH_SERVICE_LDAP {
 		if !ldapAvailable {
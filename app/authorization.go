@@ -17,6 +17,10 @@ func SessionHasPermissionToTeam(session model.Session, teamId string, permission
 		return false
 	}
 
+	if session.IsGuest() && !guestCanUseTeamPermission(permission) {
+		return SessionHasPermissionTo(session, permission)
+	}
+
 	teamMember := session.GetTeamByTeamId(teamId)
 	if teamMember != nil {
 		if CheckIfRolesGrantPermission(teamMember.GetRoles(), permission.Id) {
@@ -32,6 +36,10 @@ func SessionHasPermissionToChannel(session model.Session, channelId string, perm
 		return false
 	}
 
+	if session.IsGuest() && !guestCanUseChannelPermission(permission) {
+		return false
+	}
+
 	channelMember, err := GetChannelMember(channelId, session.UserId)
 	if err == nil {
 		roles := channelMember.GetRoles()
@@ -40,6 +48,11 @@ func SessionHasPermissionToChannel(session model.Session, channelId string, perm
 		}
 	}
 
+	// Guests only have access to the channels they're members of
+	if session.IsGuest() {
+		return false
+	}
+
 	var channel *model.Channel
 	channel, err = GetChannel(channelId)
 	if err == nil {
@@ -50,6 +63,10 @@ func SessionHasPermissionToChannel(session model.Session, channelId string, perm
 }
 
 func SessionHasPermissionToChannelByPost(session model.Session, postId string, permission *model.Permission) bool {
+	if session.IsGuest() && !guestCanUseChannelPermission(permission) {
+		return false
+	}
+
 	var channelMember *model.ChannelMember
 	if result := <-Srv.Store.Channel().GetMemberForPost(postId, session.UserId); result.Err == nil {
 		channelMember = result.Data.(*model.ChannelMember)
@@ -59,6 +76,10 @@ func SessionHasPermissionToChannelByPost(session model.Session, postId string, p
 		}
 	}
 
+	if session.IsGuest() {
+		return false
+	}
+
 	if result := <-Srv.Store.Channel().GetForPost(postId); result.Err == nil {
 		channel := result.Data.(*model.Channel)
 		return SessionHasPermissionToTeam(session, channel.TeamId, permission)
@@ -112,6 +133,12 @@ func HasPermissionToTeam(askingUserId string, teamId string, permission *model.P
 		return false
 	}
 
+	if user, err := GetUser(askingUserId); err != nil {
+		return false
+	} else if user.IsGuest() && !guestCanUseTeamPermission(permission) {
+		return HasPermissionTo(askingUserId, permission)
+	}
+
 	teamMember, err := GetTeamMember(teamId, askingUserId)
 	if err != nil {
 		return false
@@ -195,3 +222,15 @@ func CheckIfRolesGrantPermission(roles []string, permissionId string) bool {
 
 	return false
 }
+
+// guestCanUseTeamPermission returns true if guests get the permission from the teams they're in. They can see their
+// teams but can't browse, join or create channels in them.
+func guestCanUseTeamPermission(permission *model.Permission) bool {
+	return permission.Id == model.PERMISSION_VIEW_TEAM.Id
+}
+
+// guestCanUseChannelPermission returns true if guests get the permission from the channels they're in. They can't
+// bring anyone else into their channels.
+func guestCanUseChannelPermission(permission *model.Permission) bool {
+	return permission.Id != model.PERMISSION_MANAGE_PUBLIC_CHANNEL_MEMBERS.Id && permission.Id != model.PERMISSION_MANAGE_PRIVATE_CHANNEL_MEMBERS.Id
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/mattermost/platform/model"
+)
+
+// CreateBot creates a bot user owned by the given user. The bot gets the same default roles as any other user but
+// can only authenticate with the tokens created for it.
+func CreateBot(bot *model.User, ownerId string) (*model.User, *model.AppError) {
+	if owner, err := GetUser(ownerId); err != nil {
+		return nil, err
+	} else if owner.IsBot {
+		return nil, model.NewAppError("CreateBot", "app.bot.create.owner_is_bot.app_error", nil, "owner_id="+ownerId, http.StatusBadRequest)
+	}
+
+	bot.MakeBot(ownerId)
+	bot.Roles = model.ROLE_SYSTEM_USER.Id
+
+	if len(bot.Locale) == 0 {
+		bot.Locale = model.DEFAULT_LOCALE
+	}
+
+	if rbot, err := createUser(bot); err != nil {
+		return nil, err
+	} else {
+		message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_NEW_USER, "", "", "", nil)
+		message.Add("user_id", rbot.Id)
+		go Publish(message)
+
+		return rbot, nil
+	}
+}
+
+func GetBot(botUserId string) (*model.User, *model.AppError) {
+	if bot, err := GetUser(botUserId); err != nil {
+		return nil, err
+	} else if !bot.IsBot {
+		return nil, model.NewAppError("GetBot", "app.bot.get.not_bot.app_error", nil, "user_id="+botUserId, http.StatusNotFound)
+	} else {
+		return bot, nil
+	}
+}
+
+// GetBotsPage returns the bots owned by the given user, or every bot if no owner is given.
+func GetBotsPage(ownerId string, page int, perPage int) ([]*model.User, *model.AppError) {
+	if result := <-Srv.Store.User().GetBots(ownerId, page*perPage, perPage); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([]*model.User), nil
+	}
+}
+
+// UpdateBotOwner hands the bot over to another user, for example before its owner leaves.
+func UpdateBotOwner(botUserId string, ownerId string) (*model.User, *model.AppError) {
+	bot, err := GetBot(botUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if owner, err := GetUser(ownerId); err != nil {
+		return nil, err
+	} else if owner.IsBot {
+		return nil, model.NewAppError("UpdateBotOwner", "app.bot.create.owner_is_bot.app_error", nil, "owner_id="+ownerId, http.StatusBadRequest)
+	}
+
+	bot.BotOwnerId = ownerId
+
+	if result := <-Srv.Store.User().Update(bot, true); result.Err != nil {
+		return nil, result.Err
+	} else {
+		InvalidateCacheForUser(bot.Id)
+		return result.Data.([2]*model.User)[0], nil
+	}
+}
+
+// CreateBotToken creates a session for the bot that doesn't expire. The token of the returned session is the only
+// way for the bot to authenticate and it can't be retrieved again later.
+func CreateBotToken(botUserId string) (*model.Session, *model.AppError) {
+	bot, err := GetBot(botUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return createTokenSession(bot, model.SESSION_TYPE_BOT_TOKEN, "", 0)
+}
+
+// GetBotTokens returns the tokens of the bot with the token values removed.
+func GetBotTokens(botUserId string) ([]*model.Session, *model.AppError) {
+	return getTokenSessions(botUserId, model.SESSION_TYPE_BOT_TOKEN)
+}
+
+func RevokeBotToken(botUserId string, tokenId string) *model.AppError {
+	return revokeTokenSession(botUserId, tokenId, model.SESSION_TYPE_BOT_TOKEN)
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/mattermost/platform/model"
+)
+
+func TestCreateBot(t *testing.T) {
+	th := Setup().InitBasic()
+
+	bot, err := CreateBot(&model.User{Username: "bot" + model.NewId()[:10], Password: "password1"}, th.BasicUser.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bot.IsBot || bot.BotOwnerId != th.BasicUser.Id || len(bot.Password) != 0 {
+		t.Fatal("should've created a bot without a password")
+	}
+
+	if _, err := authenticateUser(bot, "password1", ""); err == nil {
+		t.Fatal("bots shouldn't be able to log in")
+	}
+
+	if _, err := CreateBot(&model.User{Username: "bot" + model.NewId()[:10]}, bot.Id); err == nil {
+		t.Fatal("bots shouldn't be able to own bots")
+	}
+
+	token, err := CreateBotToken(bot.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if session, err := GetSession(token.Token); err != nil {
+		t.Fatal(err)
+	} else if session.UserId != bot.Id || !session.IsBotToken() {
+		t.Fatal("token should've been a session for the bot")
+	}
+
+	if _, err := CreateBotToken(th.BasicUser.Id); err == nil {
+		t.Fatal("shouldn't be able to create a bot token for a regular user")
+	}
+}
@@ -6,6 +6,7 @@ package app
 import (
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 
 	l4g "github.com/alecthomas/log4go"
@@ -135,6 +136,10 @@ func CreateChannelWithUser(channel *model.Channel, userId string) (*model.Channe
 		return nil, model.NewAppError("CreateChannelWithUser", "api.channel.create_channel.direct_channel.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if channel.Type == model.CHANNEL_GROUP {
+		return nil, model.NewAppError("CreateChannelWithUser", "api.channel.create_channel.group_channel.app_error", nil, "", http.StatusBadRequest)
+	}
+
 	if strings.Index(channel.Name, "__") > 0 {
 		return nil, model.NewAppError("CreateChannelWithUser", "api.channel.create_channel.invalid_character.app_error", nil, "", http.StatusBadRequest)
 	}
@@ -191,12 +196,21 @@ func CreateDirectChannel(userId string, otherUserId string) (*model.Channel, *mo
 	uc1 := Srv.Store.User().Get(userId)
 	uc2 := Srv.Store.User().Get(otherUserId)
 
+	var user, otherUser *model.User
 	if result := <-uc1; result.Err != nil {
 		return nil, model.NewAppError("CreateDirectChannel", "api.channel.create_direct_channel.invalid_user.app_error", nil, userId, http.StatusBadRequest)
+	} else {
+		user = result.Data.(*model.User)
 	}
 
 	if result := <-uc2; result.Err != nil {
 		return nil, model.NewAppError("CreateDirectChannel", "api.channel.create_direct_channel.invalid_user.app_error", nil, otherUserId, http.StatusBadRequest)
+	} else {
+		otherUser = result.Data.(*model.User)
+	}
+
+	if err := checkGuestCanDirectMessage(user, otherUser); err != nil {
+		return nil, err
 	}
 
 	if result := <-Srv.Store.Channel().CreateDirectChannel(userId, otherUserId); result.Err != nil {
@@ -219,6 +233,79 @@ func CreateDirectChannel(userId string, otherUserId string) (*model.Channel, *mo
 	}
 }
 
+// CreateGroupChannel returns the group message channel between the given users, creating it first if it doesn't
+// exist yet.
+func CreateGroupChannel(userIds []string, creatorId string) (*model.Channel, *model.AppError) {
+	userIds = utils.RemoveDuplicatesFromStringArray(userIds)
+	if len(userIds) < model.CHANNEL_GROUP_MIN_USERS || len(userIds) > model.CHANNEL_GROUP_MAX_USERS {
+		return nil, model.NewAppError("CreateGroupChannel", "api.channel.create_group.bad_size.app_error",
+			map[string]interface{}{"Min": model.CHANNEL_GROUP_MIN_USERS, "Max": model.CHANNEL_GROUP_MAX_USERS}, "", http.StatusBadRequest)
+	}
+
+	name := model.GetGroupNameFromUserIds(userIds)
+	if result := <-Srv.Store.Channel().GetByName("", name, true); result.Err == nil {
+		return result.Data.(*model.Channel), nil
+	}
+
+	var users []*model.User
+	if result := <-Srv.Store.User().GetProfileByIds(userIds, true); result.Err != nil {
+		return nil, result.Err
+	} else {
+		users = result.Data.([]*model.User)
+	}
+
+	if len(users) != len(userIds) {
+		return nil, model.NewAppError("CreateGroupChannel", "api.channel.create_group.bad_user.app_error", nil, "user_ids="+model.ArrayToJson(userIds), http.StatusBadRequest)
+	}
+
+	usernames := make([]string, len(users))
+	for i, user := range users {
+		usernames[i] = user.Username
+	}
+	sort.Strings(usernames)
+
+	displayName := strings.Join(usernames, ", ")
+	if runes := []rune(displayName); len(runes) > model.CHANNEL_DISPLAY_NAME_MAX_RUNES {
+		displayName = string(runes[:model.CHANNEL_DISPLAY_NAME_MAX_RUNES-3]) + "..."
+	}
+
+	channel := &model.Channel{
+		Name:        name,
+		DisplayName: displayName,
+		Type:        model.CHANNEL_GROUP,
+		CreatorId:   creatorId,
+	}
+
+	members := make([]*model.ChannelMember, len(userIds))
+	for i, userId := range userIds {
+		members[i] = &model.ChannelMember{
+			UserId:      userId,
+			NotifyProps: model.GetDefaultChannelNotifyProps(),
+			Roles:       model.ROLE_CHANNEL_USER.Id,
+		}
+	}
+
+	if result := <-Srv.Store.Channel().SaveGroupChannel(channel, members); result.Err != nil {
+		if result.Err.Id == store.CHANNEL_EXISTS_ERROR {
+			return result.Data.(*model.Channel), nil
+		} else {
+			return nil, result.Err
+		}
+	} else {
+		channel := result.Data.(*model.Channel)
+
+		for _, userId := range userIds {
+			InvalidateCacheForUser(userId)
+		}
+
+		message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_GROUP_ADDED, "", channel.Id, "", nil)
+		message.Add("teammate_ids", model.ArrayToJson(userIds))
+		Publish(message)
+
+		return channel, nil
+	}
+}
+
 func UpdateChannel(channel *model.Channel) (*model.Channel, *model.AppError) {
 	if result := <-Srv.Store.Channel().Update(channel); result.Err != nil {
 		return nil, result.Err
@@ -228,6 +315,314 @@ func UpdateChannel(channel *model.Channel) (*model.Channel, *model.AppError) {
 	}
 }
 
+// UpdateChannelPrivacy converts a public channel into a private one or the reverse while keeping its members and
+// history.
+func UpdateChannelPrivacy(channel *model.Channel, channelType string, userId string) (*model.Channel, *model.AppError) {
+	if channelType != model.CHANNEL_OPEN && channelType != model.CHANNEL_PRIVATE {
+		return nil, model.NewAppError("UpdateChannelPrivacy", "api.channel.update_channel_privacy.type.app_error", nil, "type="+channelType, http.StatusBadRequest)
+	}
+
+	if channel.Type != model.CHANNEL_OPEN && channel.Type != model.CHANNEL_PRIVATE {
+		return nil, model.NewAppError("UpdateChannelPrivacy", "api.channel.update_channel_privacy.channel_type.app_error", nil, "type="+channel.Type, http.StatusBadRequest)
+	}
+
+	if channel.Type == channelType {
+		return nil, model.NewAppError("UpdateChannelPrivacy", "api.channel.update_channel_privacy.unchanged.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if channel.DeleteAt > 0 {
+		return nil, model.NewAppError("UpdateChannelPrivacy", "api.channel.update_channel_privacy.deleted.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if channel.IsArchived() {
+		return nil, model.NewAppError("UpdateChannelPrivacy", "api.channel.update_channel_privacy.archived.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if channel.Name == model.DEFAULT_CHANNEL {
+		return nil, model.NewAppError("UpdateChannelPrivacy", "api.channel.update_channel_privacy.default.app_error", map[string]interface{}{"Channel": model.DEFAULT_CHANNEL}, "", http.StatusBadRequest)
+	}
+
+	user, err := GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *channel
+	updated.Type = channelType
+
+	rchannel, err := UpdateChannel(&updated)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := postChannelPrivacyMessage(user, rchannel); err != nil {
+		l4g.Error(utils.T("api.channel.update_channel_privacy.failed_post.error"), err)
+	}
+
+	message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_CHANNEL_UPDATED, "", rchannel.Id, "", nil)
+	message.Add("channel", rchannel.ToJson())
+	Publish(message)
+
+	return rchannel, nil
+}
+
+// UpdateChannelPostingPolicy changes who can post in a channel. With CHANNEL_POSTING_POLICY_RESTRICTED only channel
+// admins and members with the channel_announcer role can post or reply.
+func UpdateChannelPostingPolicy(channel *model.Channel, postingPolicy string) (*model.Channel, *model.AppError) {
+	if postingPolicy != model.CHANNEL_POSTING_POLICY_ALL && postingPolicy != model.CHANNEL_POSTING_POLICY_RESTRICTED {
+		return nil, model.NewAppError("UpdateChannelPostingPolicy", "api.channel.update_channel_posting_policy.policy.app_error", nil, "posting_policy="+postingPolicy, http.StatusBadRequest)
+	}
+
+	if channel.Type != model.CHANNEL_OPEN && channel.Type != model.CHANNEL_PRIVATE {
+		return nil, model.NewAppError("UpdateChannelPostingPolicy", "api.channel.update_channel_posting_policy.channel_type.app_error", nil, "type="+channel.Type, http.StatusBadRequest)
+	}
+
+	if channel.DeleteAt > 0 {
+		return nil, model.NewAppError("UpdateChannelPostingPolicy", "api.channel.update_channel_posting_policy.deleted.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	updated := *channel
+	updated.PostingPolicy = postingPolicy
+
+	rchannel, err := UpdateChannel(&updated)
+	if err != nil {
+		return nil, err
+	}
+
+	message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_CHANNEL_UPDATED, "", rchannel.Id, "", nil)
+	message.Add("channel", rchannel.ToJson())
+	Publish(message)
+
+	return rchannel, nil
+}
+
+func postChannelPrivacyMessage(user *model.User, channel *model.Channel) *model.AppError {
+	T := utils.GetUserTranslations(user.Locale)
+
+	messageId := "api.channel.update_channel_privacy.to_public"
+	if channel.Type == model.CHANNEL_PRIVATE {
+		messageId = "api.channel.update_channel_privacy.to_private"
+	}
+
+	post := &model.Post{
+		ChannelId: channel.Id,
+		Message:   fmt.Sprintf(T(messageId), user.Username),
+		Type:      model.POST_CHANGE_CHANNEL_TYPE,
+		UserId:    user.Id,
+		Props: model.StringInterface{
+			"username": user.Username,
+			"type":     channel.Type,
+		},
+	}
+
+	if _, err := CreatePost(post, channel.TeamId, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MoveChannel moves a public or private channel, along with its posts, files and webhooks, to another team. Members
+// that don't belong to the destination team are either added to it or removed from the channel depending on
+// addMembers.
+func MoveChannel(channel *model.Channel, team *model.Team, addMembers bool, userId string) (*model.Channel, *model.AppError) {
+	if channel.Type != model.CHANNEL_OPEN && channel.Type != model.CHANNEL_PRIVATE {
+		return nil, model.NewAppError("MoveChannel", "api.channel.move_channel.type.app_error", nil, "type="+channel.Type, http.StatusBadRequest)
+	}
+
+	if channel.DeleteAt > 0 {
+		return nil, model.NewAppError("MoveChannel", "api.channel.move_channel.deleted.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if channel.IsArchived() {
+		return nil, model.NewAppError("MoveChannel", "api.channel.move_channel.archived.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if channel.Name == model.DEFAULT_CHANNEL {
+		return nil, model.NewAppError("MoveChannel", "api.channel.move_channel.default.app_error", map[string]interface{}{"Channel": model.DEFAULT_CHANNEL}, "", http.StatusBadRequest)
+	}
+
+	if channel.TeamId == team.Id {
+		return nil, model.NewAppError("MoveChannel", "api.channel.move_channel.same_team.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if result := <-Srv.Store.Channel().GetByNameIncludeDeleted(team.Id, channel.Name, false); result.Err == nil {
+		return nil, model.NewAppError("MoveChannel", "api.channel.move_channel.name_exists.app_error", map[string]interface{}{"Channel": channel.Name}, "", http.StatusBadRequest)
+	}
+
+	if err := moveChannelMembers(channel, team, addMembers, userId); err != nil {
+		return nil, err
+	}
+
+	oldChannel := *channel
+
+	updated := *channel
+	updated.TeamId = team.Id
+
+	rchannel, err := UpdateChannel(&updated)
+	if err != nil {
+		return nil, err
+	}
+	InvalidateCacheForChannel(&oldChannel)
+
+	if err := moveChannelWebhooks(rchannel); err != nil {
+		l4g.Error(utils.T("api.channel.move_channel.webhooks.error"), rchannel.Id, err)
+	}
+
+	if err := moveChannelFiles(rchannel, oldChannel.TeamId); err != nil {
+		l4g.Error(utils.T("api.channel.move_channel.files.error"), rchannel.Id, err)
+	}
+
+	InvalidateCacheForChannelMembers(rchannel.Id)
+
+	message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_CHANNEL_UPDATED, "", rchannel.Id, "", nil)
+	message.Add("channel", rchannel.ToJson())
+	Publish(message)
+
+	return rchannel, nil
+}
+
+func moveChannelMembers(channel *model.Channel, team *model.Team, addMembers bool, userId string) *model.AppError {
+	var userIds []string
+	for page := 0; ; page++ {
+		members, err := GetChannelMembersPage(channel.Id, page, 100)
+		if err != nil {
+			return err
+		}
+
+		for _, member := range *members {
+			userIds = append(userIds, member.UserId)
+		}
+
+		if len(*members) < 100 {
+			break
+		}
+	}
+
+	if len(userIds) == 0 {
+		return nil
+	}
+
+	teamMembers, err := GetTeamMembersByIds(team.Id, userIds)
+	if err != nil {
+		return err
+	}
+
+	inTeam := make(map[string]bool, len(teamMembers))
+	for _, teamMember := range teamMembers {
+		inTeam[teamMember.UserId] = true
+	}
+
+	var missingIds []string
+	for _, id := range userIds {
+		if !inTeam[id] {
+			missingIds = append(missingIds, id)
+		}
+	}
+
+	if len(missingIds) == 0 {
+		return nil
+	}
+
+	if !addMembers {
+		for _, id := range missingIds {
+			if err := RemoveUserFromChannel(id, userId, channel); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var users []*model.User
+	if result := <-Srv.Store.User().GetProfileByIds(missingIds, true); result.Err != nil {
+		return result.Err
+	} else {
+		users = result.Data.([]*model.User)
+	}
+
+	for _, user := range users {
+		if err := JoinUserToTeam(team, user); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func moveChannelWebhooks(channel *model.Channel) *model.AppError {
+	var hooks []*model.IncomingWebhook
+	if result := <-Srv.Store.Webhook().GetIncomingByChannel(channel.Id); result.Err != nil {
+		return result.Err
+	} else {
+		hooks = result.Data.([]*model.IncomingWebhook)
+	}
+
+	if result := <-Srv.Store.Webhook().UpdateTeamIdForChannel(channel.Id, channel.TeamId); result.Err != nil {
+		return result.Err
+	}
+
+	for _, hook := range hooks {
+		InvalidateCacheForWebhook(hook.Id)
+	}
+
+	return nil
+}
+
+func moveChannelFiles(channel *model.Channel, oldTeamId string) *model.AppError {
+	var infos []*model.FileInfo
+	if result := <-Srv.Store.FileInfo().GetForChannel(channel.Id); result.Err != nil {
+		return result.Err
+	} else {
+		infos = result.Data.([]*model.FileInfo)
+	}
+
+	oldPrefix := "teams/" + oldTeamId + "/channels/" + channel.Id + "/"
+	newPrefix := "teams/" + channel.TeamId + "/channels/" + channel.Id + "/"
+
+	movePath := func(oldPath string) (string, *model.AppError) {
+		if !strings.HasPrefix(oldPath, oldPrefix) {
+			return oldPath, nil
+		}
+
+		newPath := newPrefix + strings.TrimPrefix(oldPath, oldPrefix)
+		if err := MoveFile(oldPath, newPath); err != nil {
+			return oldPath, err
+		}
+
+		return newPath, nil
+	}
+
+	for _, info := range infos {
+		var err *model.AppError
+
+		// Files that can't be moved stay readable at their old location, so a failure is only logged
+		if info.Path, err = movePath(info.Path); err != nil {
+			l4g.Error(utils.T("api.channel.move_channel.file.error"), info.Id, err)
+			continue
+		}
+
+		if info.ThumbnailPath != "" {
+			if info.ThumbnailPath, err = movePath(info.ThumbnailPath); err != nil {
+				l4g.Error(utils.T("api.channel.move_channel.file.error"), info.Id, err)
+			}
+		}
+
+		if info.PreviewPath != "" {
+			if info.PreviewPath, err = movePath(info.PreviewPath); err != nil {
+				l4g.Error(utils.T("api.channel.move_channel.file.error"), info.Id, err)
+			}
+		}
+
+		if result := <-Srv.Store.FileInfo().UpdatePaths(info); result.Err != nil {
+			return result.Err
+		}
+
+		Srv.Store.FileInfo().InvalidateFileInfosForPostCache(info.PostId)
+	}
+
+	return nil
+}
+
 func UpdateChannelMemberRoles(channelId string, userId string, newRoles string) (*model.ChannelMember, *model.AppError) {
 	var member *model.ChannelMember
 	var err *model.AppError
@@ -341,11 +736,120 @@ func DeleteChannel(channel *model.Channel, userId string) *model.AppError {
 	return nil
 }
 
+// ArchiveChannel makes a channel read-only. Its history can still be read and searched, but no one can post in it or
+// join it, and its members can't be changed until it's unarchived.
+func ArchiveChannel(channel *model.Channel, userId string) (*model.Channel, *model.AppError) {
+	if channel.DeleteAt > 0 {
+		err := model.NewLocAppError("ArchiveChannel", "api.channel.archive_channel.deleted.app_error", nil, "")
+		err.StatusCode = http.StatusBadRequest
+		return nil, err
+	}
+
+	if channel.IsArchived() {
+		err := model.NewLocAppError("ArchiveChannel", "api.channel.archive_channel.archived.app_error", nil, "")
+		err.StatusCode = http.StatusBadRequest
+		return nil, err
+	}
+
+	if channel.Type != model.CHANNEL_OPEN && channel.Type != model.CHANNEL_PRIVATE {
+		err := model.NewLocAppError("ArchiveChannel", "api.channel.archive_channel.type.app_error", nil, "")
+		err.StatusCode = http.StatusBadRequest
+		return nil, err
+	}
+
+	if channel.Name == model.DEFAULT_CHANNEL {
+		err := model.NewLocAppError("ArchiveChannel", "api.channel.archive_channel.cannot.app_error", map[string]interface{}{"Channel": model.DEFAULT_CHANNEL}, "")
+		err.StatusCode = http.StatusBadRequest
+		return nil, err
+	}
+
+	user, err := GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// The system message has to be posted before the channel becomes read-only
+	if err := postChannelArchiveMessage(user, channel, model.POST_CHANNEL_ARCHIVED, "api.channel.archive_channel.post"); err != nil {
+		l4g.Error(utils.T("api.channel.archive_channel.failed_post.error"), err)
+	}
+
+	return setChannelArchiveAt(channel, model.GetMillis())
+}
+
+// UnarchiveChannel makes an archived channel writable again.
+func UnarchiveChannel(channel *model.Channel, userId string) (*model.Channel, *model.AppError) {
+	if !channel.IsArchived() {
+		err := model.NewLocAppError("UnarchiveChannel", "api.channel.unarchive_channel.not_archived.app_error", nil, "")
+		err.StatusCode = http.StatusBadRequest
+		return nil, err
+	}
+
+	user, err := GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	rchannel, err := setChannelArchiveAt(channel, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := postChannelArchiveMessage(user, rchannel, model.POST_CHANNEL_UNARCHIVED, "api.channel.unarchive_channel.post"); err != nil {
+		l4g.Error(utils.T("api.channel.archive_channel.failed_post.error"), err)
+	}
+
+	return rchannel, nil
+}
+
+func setChannelArchiveAt(channel *model.Channel, archiveAt int64) (*model.Channel, *model.AppError) {
+	updateAt := model.GetMillis()
+	if result := <-Srv.Store.Channel().SetArchiveAt(channel.Id, archiveAt, updateAt); result.Err != nil {
+		return nil, result.Err
+	}
+	InvalidateCacheForChannel(channel)
+
+	rchannel := *channel
+	rchannel.ArchiveAt = archiveAt
+	rchannel.UpdateAt = updateAt
+
+	message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_CHANNEL_UPDATED, "", rchannel.Id, "", nil)
+	message.Add("channel", rchannel.ToJson())
+	Publish(message)
+
+	return &rchannel, nil
+}
+
+func postChannelArchiveMessage(user *model.User, channel *model.Channel, postType string, messageId string) *model.AppError {
+	T := utils.GetUserTranslations(user.Locale)
+
+	post := &model.Post{
+		ChannelId: channel.Id,
+		Message:   fmt.Sprintf(T(messageId), user.Username),
+		Type:      postType,
+		UserId:    user.Id,
+		Props: model.StringInterface{
+			"username": user.Username,
+		},
+	}
+
+	if _, err := CreatePost(post, channel.TeamId, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func addUserToChannel(user *model.User, channel *model.Channel) (*model.ChannelMember, *model.AppError) {
 	if channel.DeleteAt > 0 {
 		return nil, model.NewLocAppError("AddUserToChannel", "api.channel.add_user_to_channel.deleted.app_error", nil, "")
 	}
 
+	if channel.IsArchived() {
+		err := model.NewLocAppError("AddUserToChannel", "api.channel.add_user_to_channel.archived.app_error", nil, "")
+		err.StatusCode = http.StatusBadRequest
+		return nil, err
+	}
+
 	if channel.Type != model.CHANNEL_OPEN && channel.Type != model.CHANNEL_PRIVATE {
 		return nil, model.NewLocAppError("AddUserToChannel", "api.channel.add_user_to_channel.type.app_error", nil, "")
 	}
@@ -567,6 +1071,13 @@ func GetChannelsForUser(teamId string, userId string) (*model.ChannelList, *mode
 }
 
 func GetChannelsUserNotIn(teamId string, userId string, offset int, limit int) (*model.ChannelList, *model.AppError) {
+	// Guests can't browse channels since they can only be in the ones they were added to
+	if guest, err := isGuest(userId); err != nil {
+		return nil, err
+	} else if guest {
+		return &model.ChannelList{}, nil
+	}
+
 	if result := <-Srv.Store.Channel().GetMoreChannels(teamId, userId, offset, limit); result.Err != nil {
 		return nil, result.Err
 	} else {
@@ -634,6 +1145,11 @@ func JoinChannel(channel *model.Channel, userId string) *model.AppError {
 	} else {
 		user := uresult.Data.(*model.User)
 
+		// Guests only get into channels that someone else adds them to
+		if user.IsGuest() {
+			return model.NewAppError("JoinChannel", "api.channel.join_channel.guest.app_error", nil, "user_id="+user.Id, http.StatusForbidden)
+		}
+
 		if channel.Type == model.CHANNEL_OPEN {
 			if _, err := AddUserToChannel(user, channel); err != nil {
 				return err
@@ -690,6 +1206,12 @@ func LeaveChannel(channelId string, userId string) *model.AppError {
 			return err
 		}
 
+		if channel.Type == model.CHANNEL_GROUP {
+			err := model.NewLocAppError("LeaveChannel", "api.channel.leave.group.app_error", nil, "")
+			err.StatusCode = http.StatusBadRequest
+			return err
+		}
+
 		if channel.Type == model.CHANNEL_PRIVATE && membersCount == 1 {
 			err := model.NewLocAppError("LeaveChannel", "api.channel.leave.last_member.app_error", nil, "userId="+user.Id)
 			err.StatusCode = http.StatusBadRequest
@@ -768,6 +1290,12 @@ func RemoveUserFromChannel(userIdToRemove string, removerUserId string, channel
 		return err
 	}
 
+	if channel.IsArchived() {
+		err := model.NewLocAppError("RemoveUserFromChannel", "api.channel.remove_user_from_channel.archived.app_error", nil, "")
+		err.StatusCode = http.StatusBadRequest
+		return err
+	}
+
 	if channel.Name == model.DEFAULT_CHANNEL {
 		return model.NewLocAppError("RemoveUserFromChannel", "api.channel.remove.default.app_error", map[string]interface{}{"Channel": model.DEFAULT_CHANNEL}, "")
 	}
@@ -836,6 +1364,12 @@ func SearchChannels(teamId string, term string) (*model.ChannelList, *model.AppE
 }
 
 func SearchChannelsUserNotIn(teamId string, userId string, term string) (*model.ChannelList, *model.AppError) {
+	if guest, err := isGuest(userId); err != nil {
+		return nil, err
+	} else if guest {
+		return &model.ChannelList{}, nil
+	}
+
 	if result := <-Srv.Store.Channel().SearchMore(userId, teamId, term); result.Err != nil {
 		return nil, result.Err
 	} else {
@@ -0,0 +1,162 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"time"
+
+	l4g "github.com/alecthomas/log4go"
+	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
+)
+
+const (
+	CHANNEL_AUTO_ARCHIVE_TASK_NAME          = "Channel Auto Archive"
+	CHANNEL_AUTO_ARCHIVE_INTERVAL           = time.Hour
+	CHANNEL_AUTO_ARCHIVE_DEFAULT_GRACE_DAYS = 7
+)
+
+// InitChannelAutoArchive starts or stops the task that archives inactive channels to match the config.
+func InitChannelAutoArchive() {
+	if task := model.GetTaskByName(CHANNEL_AUTO_ARCHIVE_TASK_NAME); task != nil {
+		task.Cancel()
+	}
+
+	if *utils.Cfg.TeamSettings.EnableChannelAutoArchive {
+		model.CreateRecurringTask(CHANNEL_AUTO_ARCHIVE_TASK_NAME, func() {
+			if err := AutoArchiveInactiveChannels(); err != nil {
+				l4g.Error(utils.T("app.channel_auto_archive.run.error"), err.Error())
+			}
+		}, CHANNEL_AUTO_ARCHIVE_INTERVAL)
+	}
+}
+
+// AutoArchiveInactiveChannels goes through the teams that have an auto-archive policy, warns the channels that
+// haven't had any posts for too long and archives the ones that are still inactive once the grace period is over.
+func AutoArchiveInactiveChannels() *model.AppError {
+	teams, err := GetAllTeams()
+	if err != nil {
+		return err
+	}
+
+	now := model.GetMillis()
+	for _, team := range teams {
+		if team.DeleteAt > 0 || team.ChannelAutoArchiveDays <= 0 {
+			continue
+		}
+
+		if err := autoArchiveInactiveChannelsForTeam(team, now); err != nil {
+			l4g.Error(utils.T("app.channel_auto_archive.team.error"), team.Id, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func autoArchiveInactiveChannelsForTeam(team *model.Team, now int64) *model.AppError {
+	graceDays := team.ChannelAutoArchiveGraceDays
+	if graceDays == 0 {
+		graceDays = CHANNEL_AUTO_ARCHIVE_DEFAULT_GRACE_DAYS
+	}
+
+	inactiveSince := now - int64(team.ChannelAutoArchiveDays)*DAY_MILLISECONDS
+	gracePeriod := int64(graceDays) * DAY_MILLISECONDS
+
+	var channels []*model.Channel
+	if result := <-Srv.Store.Channel().GetChannelsForAutoArchive(team.Id, inactiveSince); result.Err != nil {
+		return result.Err
+	} else {
+		channels = result.Data.([]*model.Channel)
+	}
+
+	for _, channel := range channels {
+		var err *model.AppError
+
+		if team.IsChannelAutoArchiveExempt(channel) {
+			if channel.InactiveWarningAt > 0 {
+				err = setChannelInactiveWarningAt(channel, 0)
+			}
+		} else if channel.InactiveWarningAt == 0 {
+			err = warnInactiveChannel(channel, team.ChannelAutoArchiveDays, graceDays)
+		} else if channel.LastPostAt > channel.InactiveWarningAt {
+			// Someone posted after the warning so the channel is active again
+			err = setChannelInactiveWarningAt(channel, 0)
+		} else if now-channel.InactiveWarningAt >= gracePeriod {
+			err = archiveInactiveChannel(channel, now)
+		}
+
+		if err != nil {
+			l4g.Error(utils.T("app.channel_auto_archive.channel.error"), channel.Id, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func warnInactiveChannel(channel *model.Channel, inactiveDays int, graceDays int) *model.AppError {
+	warningAt := model.GetMillis()
+
+	message := utils.T("app.channel_auto_archive.warning.post", map[string]interface{}{"InactiveDays": inactiveDays, "GraceDays": graceDays})
+	if post, err := postAutoArchiveMessage(channel, model.POST_CHANNEL_INACTIVE, message); err != nil {
+		return err
+	} else if post != nil {
+		// The warning counts as a post, so activity after it has to be compared to its time
+		warningAt = post.UpdateAt
+	}
+
+	return setChannelInactiveWarningAt(channel, warningAt)
+}
+
+func archiveInactiveChannel(channel *model.Channel, now int64) *model.AppError {
+	// The system message has to be posted before the channel becomes read-only
+	if _, err := postAutoArchiveMessage(channel, model.POST_CHANNEL_ARCHIVED, utils.T("app.channel_auto_archive.archived.post")); err != nil {
+		l4g.Error(utils.T("api.channel.archive_channel.failed_post.error"), err)
+	}
+
+	_, err := setChannelArchiveAt(channel, now)
+	return err
+}
+
+func setChannelInactiveWarningAt(channel *model.Channel, warningAt int64) *model.AppError {
+	if result := <-Srv.Store.Channel().SetInactiveWarningAt(channel.Id, warningAt); result.Err != nil {
+		return result.Err
+	}
+
+	InvalidateCacheForChannel(channel)
+	channel.InactiveWarningAt = warningAt
+
+	return nil
+}
+
+// postAutoArchiveMessage posts a system message on behalf of the channel creator or, if they're gone, of another
+// member. Nothing is posted to a channel without any members since there's nobody to read it.
+func postAutoArchiveMessage(channel *model.Channel, postType string, message string) (*model.Post, *model.AppError) {
+	userId := ""
+	if len(channel.CreatorId) > 0 {
+		if user, err := GetUser(channel.CreatorId); err == nil && user.DeleteAt == 0 {
+			userId = user.Id
+		}
+	}
+
+	if len(userId) == 0 {
+		if members, err := GetChannelMembersPage(channel.Id, 0, 1); err != nil {
+			return nil, err
+		} else if len(*members) > 0 {
+			userId = (*members)[0].UserId
+		}
+	}
+
+	if len(userId) == 0 {
+		return nil, nil
+	}
+
+	post := &model.Post{
+		ChannelId: channel.Id,
+		Message:   message,
+		Type:      postType,
+		UserId:    userId,
+	}
+
+	return CreatePost(post, channel.TeamId, false)
+}
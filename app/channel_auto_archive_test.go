@@ -0,0 +1,76 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/mattermost/platform/model"
+)
+
+func TestAutoArchiveInactiveChannels(t *testing.T) {
+	th := Setup().InitBasic()
+
+	team := th.BasicTeam
+	inactive := th.BasicChannel
+	exempt := th.CreatePrivateChannel(team)
+	revived := th.CreateChannel(team)
+
+	team.ChannelAutoArchiveDays = 1
+	team.ChannelAutoArchiveGraceDays = 2
+	team.ChannelAutoArchiveExemptions = model.StringArray{exempt.Name}
+
+	getChannel := func(channelId string) *model.Channel {
+		channel, err := GetChannel(channelId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return channel
+	}
+
+	now := model.GetMillis() + 2*DAY_MILLISECONDS
+	if err := autoArchiveInactiveChannelsForTeam(team, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if getChannel(inactive.Id).InactiveWarningAt == 0 || getChannel(revived.Id).InactiveWarningAt == 0 {
+		t.Fatal("inactive channels should've been warned")
+	}
+
+	if getChannel(exempt.Id).InactiveWarningAt != 0 {
+		t.Fatal("exempt channels shouldn't be warned")
+	}
+
+	if townSquare, err := GetChannelByName(model.DEFAULT_CHANNEL, team.Id); err != nil {
+		t.Fatal(err)
+	} else if townSquare.InactiveWarningAt != 0 {
+		t.Fatal("the default channel should always be exempt")
+	}
+
+	th.CreatePost(revived)
+
+	if err := autoArchiveInactiveChannelsForTeam(team, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if getChannel(inactive.Id).IsArchived() {
+		t.Fatal("channel shouldn't be archived before the end of the grace period")
+	}
+
+	if getChannel(revived.Id).InactiveWarningAt != 0 {
+		t.Fatal("warning should've been cleared after a post")
+	}
+
+	if err := autoArchiveInactiveChannelsForTeam(team, now+3*DAY_MILLISECONDS); err != nil {
+		t.Fatal(err)
+	}
+
+	if !getChannel(inactive.Id).IsArchived() {
+		t.Fatal("channel should've been archived after the grace period")
+	}
+
+	if getChannel(revived.Id).IsArchived() {
+		t.Fatal("channel with recent posts shouldn't be archived")
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"time"
+
+	l4g "github.com/alecthomas/log4go"
+	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
+)
+
+const (
+	CUSTOM_STATUS_EXPIRY_TASK_NAME = "Custom Status Expiry"
+	CUSTOM_STATUS_EXPIRY_INTERVAL  = 5 * time.Minute
+)
+
+// InitCustomStatusExpiry starts the task that clears custom statuses once they expire.
+func InitCustomStatusExpiry() {
+	if task := model.GetTaskByName(CUSTOM_STATUS_EXPIRY_TASK_NAME); task != nil {
+		task.Cancel()
+	}
+
+	model.CreateRecurringTask(CUSTOM_STATUS_EXPIRY_TASK_NAME, func() {
+		if err := ClearExpiredCustomStatuses(); err != nil {
+			l4g.Error(utils.T("app.custom_status.clear_expired.error"), err.Error())
+		}
+	}, CUSTOM_STATUS_EXPIRY_INTERVAL)
+}
+
+func GetCustomStatus(userId string) (*model.CustomStatus, *model.AppError) {
+	if result := <-Srv.Store.CustomStatus().Get(userId); result.Err != nil {
+		return nil, result.Err
+	} else {
+		status := result.Data.(*model.CustomStatus)
+		if status.IsExpired(model.GetMillis()) {
+			// The expiry task may not have gotten to it yet
+			return nil, model.NewAppError("GetCustomStatus", "app.custom_status.get.expired.app_error", nil, "user_id="+userId, http.StatusNotFound)
+		}
+		return status, nil
+	}
+}
+
+func SetCustomStatus(userId string, status *model.CustomStatus) (*model.CustomStatus, *model.AppError) {
+	status.UserId = userId
+
+	if status.IsExpired(model.GetMillis()) {
+		return nil, model.NewAppError("SetCustomStatus", "app.custom_status.set.expires_at.app_error", nil, "user_id="+userId, http.StatusBadRequest)
+	}
+
+	if result := <-Srv.Store.CustomStatus().SaveOrUpdate(status); result.Err != nil {
+		return nil, result.Err
+	} else {
+		status = result.Data.(*model.CustomStatus)
+	}
+
+	publishCustomStatusChange(userId, status)
+
+	return status, nil
+}
+
+func RemoveCustomStatus(userId string) *model.AppError {
+	if result := <-Srv.Store.CustomStatus().Delete(userId); result.Err != nil {
+		return result.Err
+	}
+
+	publishCustomStatusChange(userId, nil)
+
+	return nil
+}
+
+// ClearExpiredCustomStatuses removes every custom status that has reached its expiry and lets clients know.
+func ClearExpiredCustomStatuses() *model.AppError {
+	now := model.GetMillis()
+
+	var statuses []*model.CustomStatus
+	if result := <-Srv.Store.CustomStatus().GetExpired(now); result.Err != nil {
+		return result.Err
+	} else {
+		statuses = result.Data.([]*model.CustomStatus)
+	}
+
+	for _, status := range statuses {
+		// The status is only deleted if it's still expired since the user may have set a new one in the meantime
+		if result := <-Srv.Store.CustomStatus().DeleteExpired(status.UserId, now); result.Err != nil {
+			l4g.Error(utils.T("app.custom_status.clear_expired.user.error"), status.UserId, result.Err.Error())
+		} else if result.Data.(bool) {
+			publishCustomStatusChange(status.UserId, nil)
+		}
+	}
+
+	return nil
+}
+
+// addCustomStatusesToUsers fills in the custom status of each user that has one that hasn't expired.
+func addCustomStatusesToUsers(users []*model.User) *model.AppError {
+	if len(users) == 0 {
+		return nil
+	}
+
+	userIds := make([]string, len(users))
+	for i, user := range users {
+		userIds[i] = user.Id
+	}
+
+	var statuses []*model.CustomStatus
+	if result := <-Srv.Store.CustomStatus().GetByIds(userIds); result.Err != nil {
+		return result.Err
+	} else {
+		statuses = result.Data.([]*model.CustomStatus)
+	}
+
+	now := model.GetMillis()
+	statusMap := make(map[string]*model.CustomStatus, len(statuses))
+	for _, status := range statuses {
+		if !status.IsExpired(now) {
+			statusMap[status.UserId] = status
+		}
+	}
+
+	for _, user := range users {
+		user.CustomStatus = statusMap[user.Id]
+	}
+
+	return nil
+}
+
+func publishCustomStatusChange(userId string, status *model.CustomStatus) {
+	event := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_CUSTOM_STATUS_CHANGE, "", "", userId, nil)
+	event.Add("user_id", userId)
+	if status != nil {
+		event.Add("custom_status", status.ToJson())
+	} else {
+		event.Add("custom_status", "")
+	}
+	go Publish(event)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/mattermost/platform/model"
+)
+
+func TestCustomStatusInAutocomplete(t *testing.T) {
+	th := Setup().InitBasic()
+
+	if _, err := SetCustomStatus(th.BasicUser.Id, &model.CustomStatus{Emoji: "calendar", Text: "In a meeting"}); err != nil {
+		t.Fatal(err)
+	}
+	defer RemoveCustomStatus(th.BasicUser.Id)
+
+	autocomplete, err := AutocompleteUsersInChannel(th.BasicTeam.Id, th.BasicChannel.Id, th.BasicUser.Username, map[string]bool{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+	for _, user := range autocomplete.InChannel {
+		if user.Id == th.BasicUser.Id {
+			found = true
+			if user.CustomStatus == nil || user.CustomStatus.Text != "In a meeting" {
+				t.Fatal("custom status should've been included")
+			}
+		}
+	}
+
+	if !found {
+		t.Fatal("user should've been found")
+	}
+}
+
+func TestClearExpiredCustomStatuses(t *testing.T) {
+	th := Setup().InitBasic()
+
+	// Saved directly since expired statuses can't be set through the app
+	expired := &model.CustomStatus{UserId: th.BasicUser.Id, Emoji: "palm_tree", ExpiresAt: model.GetMillis() - 1000}
+	if result := <-Srv.Store.CustomStatus().SaveOrUpdate(expired); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	if _, err := SetCustomStatus(th.BasicUser2.Id, &model.CustomStatus{Emoji: "calendar", ExpiresAt: model.GetMillis() + 60*60*1000}); err != nil {
+		t.Fatal(err)
+	}
+	defer RemoveCustomStatus(th.BasicUser2.Id)
+
+	if _, err := GetCustomStatus(th.BasicUser.Id); err == nil {
+		t.Fatal("expired custom status shouldn't be returned")
+	}
+
+	if err := ClearExpiredCustomStatuses(); err != nil {
+		t.Fatal(err)
+	}
+
+	if result := <-Srv.Store.CustomStatus().Get(th.BasicUser.Id); result.Err == nil {
+		t.Fatal("expired custom status should've been cleared")
+	}
+
+	if _, err := GetCustomStatus(th.BasicUser2.Id); err != nil {
+		t.Fatal(err)
+	}
+}
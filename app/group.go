@@ -0,0 +1,175 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/platform/model"
+)
+
+func CreateGroup(group *model.Group) (*model.Group, *model.AppError) {
+	group.Name = strings.ToLower(group.Name)
+
+	if err := checkGroupNameIsFree(group.Name); err != nil {
+		return nil, err
+	}
+
+	if result := <-Srv.Store.Group().Save(group); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.Group), nil
+	}
+}
+
+// checkGroupNameIsFree makes sure that a group name can't shadow a username, since both are mentioned with @name.
+func checkGroupNameIsFree(name string) *model.AppError {
+	if result := <-Srv.Store.User().GetByUsername(name); result.Err == nil {
+		return model.NewAppError("checkGroupNameIsFree", "app.group.name_taken_by_user.app_error", map[string]interface{}{"Name": name}, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// checkUsernameIsFree makes sure that a username can't shadow a group name, since both are mentioned with @name.
+func checkUsernameIsFree(username string) *model.AppError {
+	if result := <-Srv.Store.Group().GetByName(strings.ToLower(username)); result.Err == nil {
+		return model.NewAppError("checkUsernameIsFree", "app.group.username_taken_by_group.app_error", map[string]interface{}{"Name": username}, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func GetGroup(groupId string) (*model.Group, *model.AppError) {
+	if result := <-Srv.Store.Group().Get(groupId); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.Group), nil
+	}
+}
+
+func GetGroupByName(name string) (*model.Group, *model.AppError) {
+	if result := <-Srv.Store.Group().GetByName(strings.ToLower(name)); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.Group), nil
+	}
+}
+
+func GetGroups(page, perPage int) ([]*model.Group, *model.AppError) {
+	if result := <-Srv.Store.Group().GetAll(page*perPage, perPage); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([]*model.Group), nil
+	}
+}
+
+func UpdateGroup(group *model.Group) (*model.Group, *model.AppError) {
+	oldGroup, err := GetGroup(group.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	group.Name = strings.ToLower(group.Name)
+	if group.Name != oldGroup.Name {
+		if err := checkGroupNameIsFree(group.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	oldGroup.Name = group.Name
+	oldGroup.DisplayName = group.DisplayName
+	oldGroup.Description = group.Description
+
+	if result := <-Srv.Store.Group().Update(oldGroup); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.Group), nil
+	}
+}
+
+func DeleteGroup(groupId string) *model.AppError {
+	if result := <-Srv.Store.Group().Delete(groupId, model.GetMillis()); result.Err != nil {
+		return result.Err
+	}
+
+	return nil
+}
+
+func GetGroupMembers(groupId string, page, perPage int) ([]*model.GroupMember, *model.AppError) {
+	if result := <-Srv.Store.Group().GetMembers(groupId, page*perPage, perPage); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([]*model.GroupMember), nil
+	}
+}
+
+func AddGroupMember(groupId string, userId string) (*model.GroupMember, *model.AppError) {
+	if _, err := GetGroup(groupId); err != nil {
+		return nil, err
+	}
+
+	if _, err := GetUser(userId); err != nil {
+		return nil, err
+	}
+
+	if result := <-Srv.Store.Group().SaveMember(&model.GroupMember{GroupId: groupId, UserId: userId}); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.GroupMember), nil
+	}
+}
+
+func RemoveGroupMember(groupId string, userId string) *model.AppError {
+	if result := <-Srv.Store.Group().RemoveMember(groupId, userId); result.Err != nil {
+		return result.Err
+	}
+
+	return nil
+}
+
+// GetGroupMentionMembers returns the members of every group that is @mentioned in the message, keyed by group name.
+func GetGroupMentionMembers(message string) (map[string][]string, *model.AppError) {
+	names := getPossibleMentionNames(message)
+	if len(names) == 0 {
+		return map[string][]string{}, nil
+	}
+
+	if result := <-Srv.Store.Group().GetMemberIdsByGroupNames(names); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(map[string][]string), nil
+	}
+}
+
+func getPossibleMentionNames(message string) []string {
+	names := []string{}
+	seen := make(map[string]bool)
+
+	addName := func(word string) {
+		if !strings.HasPrefix(word, "@") {
+			return
+		}
+
+		name := strings.ToLower(strings.TrimLeft(word, "@"))
+		if model.IsValidGroupName(name) && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	// Check both the whole word and its pieces split on punctuation, the same way GetExplicitMentions does
+	for _, word := range strings.Fields(message) {
+		addName(word)
+
+		for _, splitWord := range strings.FieldsFunc(word, func(c rune) bool {
+			return model.SplitRunes[c]
+		}) {
+			addName(splitWord)
+		}
+	}
+
+	return names
+}
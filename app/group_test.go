@@ -0,0 +1,72 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/mattermost/platform/model"
+)
+
+func TestGetPossibleMentionNames(t *testing.T) {
+	names := getPossibleMentionNames("hey @oncall, @Support and @first.last @all @here @channel email@example.com @oncall")
+
+	expected := map[string]bool{"oncall": true, "support": true, "first.last": true, "first": true}
+	if len(names) != len(expected) {
+		t.Fatal("wrong number of names", names)
+	}
+
+	for _, name := range names {
+		if !expected[name] {
+			t.Fatal("unexpected name", name)
+		}
+	}
+}
+
+func TestGroupMentions(t *testing.T) {
+	th := Setup().InitBasic()
+
+	group, err := CreateGroup(&model.Group{Name: "G" + model.NewId(), DisplayName: "Group"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := CreateGroup(&model.Group{Name: th.BasicUser.Username, DisplayName: "Group"}); err == nil {
+		t.Fatal("shouldn't be able to create a group with the same name as a user")
+	}
+
+	if _, err := CreateUser(&model.User{Email: "success+" + model.NewId() + "@simulator.amazonses.com", Username: group.Name, Password: "passwd1"}); err == nil {
+		t.Fatal("shouldn't be able to create a user with the same name as a group")
+	}
+
+	user := *th.BasicUser
+	user.Username = group.Name
+	if _, err := UpdateUser(&user, "", false); err == nil {
+		t.Fatal("shouldn't be able to rename a user to the name of a group")
+	}
+
+	if _, err := AddGroupMember(group.Id, th.BasicUser.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := AddGroupMember(group.Id, model.NewId()); err == nil {
+		t.Fatal("shouldn't be able to add a user that doesn't exist")
+	}
+
+	if members, err := GetGroupMentionMembers("hello @" + group.Name); err != nil {
+		t.Fatal(err)
+	} else if ids := members[group.Name]; len(ids) != 1 || ids[0] != th.BasicUser.Id {
+		t.Fatal("should've returned the group's members")
+	}
+
+	if err := DeleteGroup(group.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	if members, err := GetGroupMentionMembers("hello @" + group.Name); err != nil {
+		t.Fatal(err)
+	} else if _, ok := members[group.Name]; ok {
+		t.Fatal("shouldn't return deleted groups")
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/mattermost/platform/model"
+)
+
+func getUserIdsInSameChannels(userId string) (map[string]bool, *model.AppError) {
+	if result := <-Srv.Store.Channel().GetUserIdsInSameChannels(userId); result.Err != nil {
+		return nil, result.Err
+	} else {
+		// Users can always see themselves, even when they aren't in any channels
+		userIds := map[string]bool{userId: true}
+		for _, id := range result.Data.([]string) {
+			userIds[id] = true
+		}
+		return userIds, nil
+	}
+}
+
+// FilterUsersVisibleToGuest removes the users that the guest doesn't share a channel with.
+func FilterUsersVisibleToGuest(guestId string, users []*model.User) ([]*model.User, *model.AppError) {
+	visible, err := getUserIdsInSameChannels(guestId)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := []*model.User{}
+	for _, user := range users {
+		if visible[user.Id] {
+			filtered = append(filtered, user)
+		}
+	}
+
+	return filtered, nil
+}
+
+// FilterUserMapVisibleToGuest is FilterUsersVisibleToGuest for users keyed by id.
+func FilterUserMapVisibleToGuest(guestId string, users map[string]*model.User) (map[string]*model.User, *model.AppError) {
+	list := make([]*model.User, 0, len(users))
+	for _, user := range users {
+		list = append(list, user)
+	}
+
+	filtered, err := FilterUsersVisibleToGuest(guestId, list)
+	if err != nil {
+		return nil, err
+	}
+
+	userMap := make(map[string]*model.User, len(filtered))
+	for _, user := range filtered {
+		userMap[user.Id] = user
+	}
+
+	return userMap, nil
+}
+
+// CheckUserVisibleToGuest returns a not found error if the guest doesn't share a channel with the user.
+func CheckUserVisibleToGuest(guestId string, userId string) *model.AppError {
+	if visible, err := getUserIdsInSameChannels(guestId); err != nil {
+		return err
+	} else if !visible[userId] {
+		return model.NewAppError("CheckUserVisibleToGuest", "app.guest.user_not_visible.app_error", nil, "guest_id="+guestId+", user_id="+userId, http.StatusNotFound)
+	}
+
+	return nil
+}
+
+// checkGuestCanDirectMessage makes sure that guests only open direct channels with people from their channels.
+func checkGuestCanDirectMessage(user *model.User, otherUser *model.User) *model.AppError {
+	if user.Id == otherUser.Id || (!user.IsGuest() && !otherUser.IsGuest()) {
+		return nil
+	}
+
+	if visible, err := getUserIdsInSameChannels(user.Id); err != nil {
+		return err
+	} else if !visible[otherUser.Id] {
+		return model.NewAppError("checkGuestCanDirectMessage", "app.guest.direct_channel.not_in_channel.app_error", nil, "user_id="+user.Id+", other_user_id="+otherUser.Id, http.StatusForbidden)
+	}
+
+	return nil
+}
+
+func isGuest(userId string) (bool, *model.AppError) {
+	if user, err := GetUser(userId); err != nil {
+		return false, err
+	} else {
+		return user.IsGuest(), nil
+	}
+}
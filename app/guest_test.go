@@ -0,0 +1,79 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/mattermost/platform/model"
+)
+
+func TestGuestAccess(t *testing.T) {
+	th := Setup().InitBasic()
+
+	guest := th.CreateUser()
+	if _, err := UpdateUserRoles(guest.Id, model.ROLE_SYSTEM_GUEST.Id); err != nil {
+		t.Fatal(err)
+	}
+	guest, _ = GetUser(guest.Id)
+	LinkUserToTeam(guest, th.BasicTeam)
+
+	if channels, err := GetChannelsForUser(th.BasicTeam.Id, guest.Id); err != nil {
+		t.Fatal(err)
+	} else if len(*channels) != 0 {
+		t.Fatal("guests shouldn't join the default channels")
+	}
+
+	if _, err := AddUserToChannel(guest, th.BasicChannel); err != nil {
+		t.Fatal(err)
+	}
+
+	teamMember, _ := GetTeamMember(th.BasicTeam.Id, guest.Id)
+	session := model.Session{UserId: guest.Id, Roles: guest.Roles, TeamMembers: []*model.TeamMember{teamMember}}
+
+	if !SessionHasPermissionToChannel(session, th.BasicChannel.Id, model.PERMISSION_CREATE_POST) {
+		t.Fatal("guests should be able to post in their channels")
+	}
+
+	if SessionHasPermissionToChannel(session, th.BasicChannel.Id, model.PERMISSION_MANAGE_PUBLIC_CHANNEL_MEMBERS) {
+		t.Fatal("guests shouldn't be able to add people to their channels")
+	}
+
+	otherChannel := th.CreateChannel(th.BasicTeam)
+	if SessionHasPermissionToChannel(session, otherChannel.Id, model.PERMISSION_READ_CHANNEL) {
+		t.Fatal("guests shouldn't be able to read channels they aren't in")
+	}
+
+	if !SessionHasPermissionToTeam(session, th.BasicTeam.Id, model.PERMISSION_VIEW_TEAM) {
+		t.Fatal("guests should be able to view their teams")
+	}
+
+	if SessionHasPermissionToTeam(session, th.BasicTeam.Id, model.PERMISSION_JOIN_PUBLIC_CHANNELS) {
+		t.Fatal("guests shouldn't be able to join public channels")
+	}
+
+	if channels, err := GetChannelsUserNotIn(th.BasicTeam.Id, guest.Id, 0, 100); err != nil {
+		t.Fatal(err)
+	} else if len(*channels) != 0 {
+		t.Fatal("guests shouldn't be able to browse channels")
+	}
+
+	if users, err := FilterUsersVisibleToGuest(guest.Id, []*model.User{th.BasicUser, th.BasicUser2}); err != nil {
+		t.Fatal(err)
+	} else if len(users) != 1 || users[0].Id != th.BasicUser.Id {
+		t.Fatal("guests should only see the people in their channels")
+	}
+
+	if _, err := CreateDirectChannel(guest.Id, th.BasicUser.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := CreateDirectChannel(guest.Id, th.BasicUser2.Id); err == nil {
+		t.Fatal("guests shouldn't be able to message people outside of their channels")
+	}
+
+	if _, err := CreateDirectChannel(th.BasicUser2.Id, guest.Id); err == nil {
+		t.Fatal("people outside of their channels shouldn't be able to message guests")
+	}
+}
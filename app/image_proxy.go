@@ -0,0 +1,88 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
+)
+
+const (
+	IMAGE_PROXY_CACHE_SIZE = 500
+
+	// Larger images are still proxied, but aren't kept in memory afterwards
+	IMAGE_PROXY_MAX_CACHED_IMAGE_SIZE = 1048576 // 1 MB
+)
+
+var imageProxyCache = utils.NewLru(IMAGE_PROXY_CACHE_SIZE)
+
+type ProxiedImage struct {
+	ContentType string
+	Data        []byte
+}
+
+func GetProxiedImage(imageURL string) (*ProxiedImage, *model.AppError) {
+	if !*utils.Cfg.ServiceSettings.EnableImageProxy {
+		return nil, model.NewAppError("GetProxiedImage", "app.image_proxy.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	if !model.IsValidHttpUrl(imageURL) {
+		return nil, model.NewAppError("GetProxiedImage", "app.image_proxy.invalid_url.app_error", nil, "url="+imageURL, http.StatusBadRequest)
+	}
+
+	if cached, ok := imageProxyCache.Get(imageURL); ok {
+		return cached.(*ProxiedImage), nil
+	}
+
+	res, err := utils.HttpClient().Get(imageURL)
+	if err != nil {
+		return nil, model.NewAppError("GetProxiedImage", "app.image_proxy.fetch.app_error", nil, "url="+imageURL+", err="+err.Error(), http.StatusBadGateway)
+	}
+	defer CloseBody(res)
+
+	if res.StatusCode != http.StatusOK {
+		return nil, model.NewAppError("GetProxiedImage", "app.image_proxy.fetch.app_error", nil, "url="+imageURL+", status="+res.Status, http.StatusBadGateway)
+	}
+
+	contentType := res.Header.Get("Content-Type")
+	if !isProxiableImageType(contentType) {
+		return nil, model.NewAppError("GetProxiedImage", "app.image_proxy.not_an_image.app_error", nil, "url="+imageURL+", content_type="+contentType, http.StatusBadRequest)
+	}
+
+	maxSize := *utils.Cfg.ServiceSettings.ImageProxyMaxImageSize
+	if res.ContentLength > maxSize {
+		return nil, model.NewAppError("GetProxiedImage", "app.image_proxy.too_large.app_error", nil, "url="+imageURL, http.StatusBadRequest)
+	}
+
+	// Read one byte past the limit so that we can tell when a server lied about or omitted the content length
+	data, err := ioutil.ReadAll(io.LimitReader(res.Body, maxSize+1))
+	if err != nil {
+		return nil, model.NewAppError("GetProxiedImage", "app.image_proxy.fetch.app_error", nil, "url="+imageURL+", err="+err.Error(), http.StatusBadGateway)
+	} else if int64(len(data)) > maxSize {
+		return nil, model.NewAppError("GetProxiedImage", "app.image_proxy.too_large.app_error", nil, "url="+imageURL, http.StatusBadRequest)
+	}
+
+	image := &ProxiedImage{
+		ContentType: contentType,
+		Data:        data,
+	}
+
+	if cacheTime := *utils.Cfg.ServiceSettings.ImageProxyCacheInMinutes; cacheTime > 0 && len(data) <= IMAGE_PROXY_MAX_CACHED_IMAGE_SIZE {
+		imageProxyCache.AddWithExpiresInSecs(imageURL, image, int64(cacheTime*60))
+	}
+
+	return image, nil
+}
+
+func isProxiableImageType(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+
+	// SVGs can carry scripts, so they are never served back from our own origin
+	return strings.HasPrefix(mediaType, "image/") && mediaType != "image/svg+xml"
+}
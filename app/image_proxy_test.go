@@ -0,0 +1,22 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+)
+
+func TestIsProxiableImageType(t *testing.T) {
+	for _, contentType := range []string{"image/png", "image/jpeg", "IMAGE/GIF", "image/webp; charset=binary"} {
+		if !isProxiableImageType(contentType) {
+			t.Fatal("should be proxiable", contentType)
+		}
+	}
+
+	for _, contentType := range []string{"", "text/html", "application/octet-stream", "image/svg+xml", "image/svg+xml; charset=utf-8"} {
+		if isProxiableImageType(contentType) {
+			t.Fatal("should not be proxiable", contentType)
+		}
+	}
+}
@@ -56,6 +56,8 @@ type UserImportData struct {
 	Roles       *string `json:"roles"`
 	Locale      *string `json:"locale"`
 
+	ProfileAttributes *map[string]string `json:"profile_attributes"`
+
 	Teams *[]UserTeamImportData `json:"teams"`
 }
 
@@ -369,8 +371,10 @@ func ImportUser(data *UserImportData, dryRun bool) *model.AppError {
 	user.Roles = roles
 
 	if user.Id == "" {
-		if _, err := createUser(user); err != nil {
+		if ruser, err := createUser(user); err != nil {
 			return err
+		} else {
+			user = ruser
 		}
 	} else {
 		if _, err := UpdateUser(user, utils.GetSiteURL(), false); err != nil {
@@ -395,6 +399,13 @@ func ImportUser(data *UserImportData, dryRun bool) *model.AppError {
 		}
 	}
 
+	// Imported attributes are allowed to overwrite the ones that are synced from LDAP or SAML
+	if data.ProfileAttributes != nil {
+		if err := saveProfileAttributeValues(user, *data.ProfileAttributes, true); err != nil {
+			return err
+		}
+	}
+
 	return ImportUserTeams(*data.Username, data.Teams)
 }
 
@@ -541,6 +552,14 @@ func validateUserImportData(data *UserImportData) *model.AppError {
 		return model.NewAppError("BulkImport", "app.import.validate_user_import_data.roles_invalid.error", nil, "", http.StatusBadRequest)
 	}
 
+	if data.ProfileAttributes != nil {
+		for name, value := range *data.ProfileAttributes {
+			if len(name) == 0 || utf8.RuneCountInString(value) > model.PROFILE_ATTRIBUTE_VALUE_MAX_RUNES {
+				return model.NewAppError("BulkImport", "app.import.validate_user_import_data.profile_attribute_invalid.error", nil, "", http.StatusBadRequest)
+			}
+		}
+	}
+
 	if data.Teams != nil {
 		return validateUserTeamsImportData(data.Teams)
 	} else {
@@ -14,7 +14,7 @@ import (
 
 func SyncLdap() {
 	go func() {
-		if utils.IsLicensed && *utils.License.Features.LDAP && *utils.Cfg.LdapSettings.Enable {
+		if *utils.Cfg.LdapSettings.Enable {
 			if ldapI := einterfaces.GetLdapInterface(); ldapI != nil {
 				ldapI.SyncNow()
 			} else {
@@ -25,7 +25,7 @@ func SyncLdap() {
 }
 
 func TestLdap() *model.AppError {
-	if ldapI := einterfaces.GetLdapInterface(); ldapI != nil && utils.IsLicensed && *utils.License.Features.LDAP && *utils.Cfg.LdapSettings.Enable {
+	if ldapI := einterfaces.GetLdapInterface(); ldapI != nil && *utils.Cfg.LdapSettings.Enable {
 		if err := ldapI.RunTest(); err != nil {
 			err.StatusCode = 500
 			return err
@@ -38,3 +38,19 @@ func TestLdap() *model.AppError {
 
 	return nil
 }
+
+// UpdateUserFromDirectory saves the changes to a user that were made in their LDAP account. Unlike UpdateUser, this can
+// change the username and email address of an LDAP user.
+func UpdateUserFromDirectory(user *model.User) (*model.User, *model.AppError) {
+	if result := <-Srv.Store.User().Update(user, true); result.Err != nil {
+		return nil, result.Err
+	} else {
+		rusers := result.Data.([2]*model.User)
+
+		recordUsernameChange(rusers[1], rusers[0])
+
+		InvalidateCacheForUser(user.Id)
+
+		return rusers[0], nil
+	}
+}
@@ -54,12 +54,29 @@ func SendNotifications(post *model.Post, team *model.Team, channel *model.Channe
 		if post.Props["from_webhook"] == "true" {
 			mentionedUserIds[post.UserId] = true
 		}
+	} else if channel.Type == model.CHANNEL_GROUP {
+		// like direct messages, every message in a group message is treated as a mention of the other members
+		for userId := range profileMap {
+			if userId != post.UserId || post.Props["from_webhook"] == "true" {
+				mentionedUserIds[userId] = true
+			}
+		}
 	} else {
-		keywords := GetMentionKeywordsInChannel(profileMap)
+		groupMembers, err := GetGroupMentionMembers(post.Message)
+		if err != nil {
+			l4g.Warn(utils.T("api.post.send_notifications.group_mentions.error"), post.Id, err)
+		}
+
+		keywords := GetMentionKeywordsInChannel(profileMap, groupMembers)
 
 		var potentialOtherMentions []string
 		mentionedUserIds, potentialOtherMentions, hereNotification, channelNotification, allNotification = GetExplicitMentions(post.Message, keywords)
 
+		// mentions of usernames that were recently given up still reach the channel members who had them
+		if len(potentialOtherMentions) > 0 {
+			potentialOtherMentions = addOldUsernameMentions(mentionedUserIds, potentialOtherMentions, profileMap)
+		}
+
 		// get users that have comment thread mentions enabled
 		if len(post.RootId) > 0 {
 			if result := <-Srv.Store.Post().Get(post.RootId); result.Err != nil {
@@ -98,6 +115,45 @@ func SendNotifications(post *model.Post, team *model.Team, channel *model.Channe
 		}
 	}
 
+	// Out of office replies go out for direct messages and mentions of specific users, but not for channel wide mentions
+	if !post.IsSystemMessage() && post.Props["from_webhook"] != "true" && !sender.IsBot && !channelNotification && !allNotification {
+		recipients := make([]*model.User, 0, len(mentionedUserIds))
+		for id := range mentionedUserIds {
+			if profile, ok := profileMap[id]; ok && id != sender.Id {
+				recipients = append(recipients, profile)
+			}
+		}
+
+		go sendOutOfOfficeReplies(post, team.Id, channel, sender, recipients)
+	}
+
+	T := utils.GetUserTranslations(sender.Locale)
+
+	// If the channel has more than 1K users then @here is disabled
+	if hereNotification && int64(len(profileMap)) > *utils.Cfg.TeamSettings.MaxNotificationsPerChannel {
+		hereNotification = false
+		SendEphemeralPost(
+			team.Id,
+			post.UserId,
+			&model.Post{
+				ChannelId: post.ChannelId,
+				Message:   T("api.post.disabled_here", map[string]interface{}{"Users": *utils.Cfg.TeamSettings.MaxNotificationsPerChannel}),
+				CreateAt:  post.CreateAt + 1,
+			},
+		)
+	}
+
+	// @here only notifies the channel members who are currently online
+	if hereNotification {
+		if hereUserIds, err := getHereMentionUserIds(profileMap, post.UserId); err != nil {
+			return nil, err
+		} else {
+			for _, id := range hereUserIds {
+				mentionedUserIds[id] = true
+			}
+		}
+	}
+
 	mentionedUsersList := make([]string, 0, len(mentionedUserIds))
 	for id := range mentionedUserIds {
 		mentionedUsersList = append(mentionedUsersList, id)
@@ -147,28 +203,12 @@ func SendNotifications(post *model.Post, team *model.Team, channel *model.Channe
 				}
 			}
 
-			if userAllowsEmails && status.Status != model.STATUS_ONLINE && profileMap[id].DeleteAt == 0 {
+			if userAllowsEmails && status.Status != model.STATUS_ONLINE && profileMap[id].DeleteAt == 0 && !IsUserInDND(profileMap[id], status) {
 				sendNotificationEmail(post, profileMap[id], channel, team, senderName[id], sender)
 			}
 		}
 	}
 
-	T := utils.GetUserTranslations(sender.Locale)
-
-	// If the channel has more than 1K users then @here is disabled
-	if hereNotification && int64(len(profileMap)) > *utils.Cfg.TeamSettings.MaxNotificationsPerChannel {
-		hereNotification = false
-		SendEphemeralPost(
-			team.Id,
-			post.UserId,
-			&model.Post{
-				ChannelId: post.ChannelId,
-				Message:   T("api.post.disabled_here", map[string]interface{}{"Users": *utils.Cfg.TeamSettings.MaxNotificationsPerChannel}),
-				CreateAt:  post.CreateAt + 1,
-			},
-		)
-	}
-
 	// If the channel has more than 1K users then @channel is disabled
 	if channelNotification && int64(len(profileMap)) > *utils.Cfg.TeamSettings.MaxNotificationsPerChannel {
 		SendEphemeralPost(
@@ -195,27 +235,6 @@ func SendNotifications(post *model.Post, team *model.Team, channel *model.Channe
 		)
 	}
 
-	if hereNotification {
-		if result := <-Srv.Store.Status().GetOnline(); result.Err != nil {
-			return nil, result.Err
-		} else {
-			statuses := result.Data.([]*model.Status)
-			for _, status := range statuses {
-				if status.UserId == post.UserId {
-					continue
-				}
-
-				_, profileFound := profileMap[status.UserId]
-				_, alreadyMentioned := mentionedUserIds[status.UserId]
-
-				if status.Status == model.STATUS_ONLINE && profileFound && !alreadyMentioned {
-					mentionedUsersList = append(mentionedUsersList, status.UserId)
-					updateMentionChans = append(updateMentionChans, Srv.Store.Channel().IncrementMentionCount(post.ChannelId, status.UserId))
-				}
-			}
-		}
-	}
-
 	// Make sure all mention updates are complete to prevent race
 	// Probably better to batch these DB updates in the future
 	// MUST be completed before push notifications send
@@ -225,18 +244,7 @@ func SendNotifications(post *model.Post, team *model.Team, channel *model.Channe
 		}
 	}
 
-	sendPushNotifications := false
-	if *utils.Cfg.EmailSettings.SendPushNotifications {
-		pushServer := *utils.Cfg.EmailSettings.PushNotificationServer
-		if pushServer == model.MHPNS && (!utils.IsLicensed || !*utils.License.Features.MHPNS) {
-			l4g.Warn(utils.T("api.post.send_notifications_and_forget.push_notification.mhpnsWarn"))
-			sendPushNotifications = false
-		} else {
-			sendPushNotifications = true
-		}
-	}
-
-	if sendPushNotifications {
+	if shouldSendPushNotifications() {
 		for _, id := range mentionedUsersList {
 			var status *model.Status
 			var err *model.AppError
@@ -299,7 +307,7 @@ func SendNotifications(post *model.Post, team *model.Team, channel *model.Channe
 }
 
 func sendNotificationEmail(post *model.Post, user *model.User, channel *model.Channel, team *model.Team, senderName string, sender *model.User) *model.AppError {
-	if channel.Type == model.CHANNEL_DIRECT && channel.TeamId != team.Id {
+	if channel.IsGroupOrDirect() && channel.TeamId != team.Id {
 		// this message is a cross-team DM so it we need to find a team that the recipient is on to use in the link
 		if result := <-Srv.Store.Team().GetTeamsByUserId(user.Id); result.Err != nil {
 			return result.Err
@@ -350,7 +358,7 @@ func sendNotificationEmail(post *model.Post, user *model.User, channel *model.Ch
 	var mailParameters map[string]interface{}
 
 	teamURL := utils.GetSiteURL() + "/" + team.Name
-	tm := time.Unix(post.CreateAt/1000, 0)
+	tm := time.Unix(post.CreateAt/1000, 0).In(user.GetTimezoneLocation())
 
 	userLocale := utils.GetUserTranslations(user.Locale)
 	month := userLocale(tm.Month().String())
@@ -380,7 +388,7 @@ func sendNotificationEmail(post *model.Post, user *model.User, channel *model.Ch
 
 	bodyPage := utils.NewHTMLTemplate("post_body", user.Locale)
 	bodyPage.Props["SiteURL"] = utils.GetSiteURL()
-	bodyPage.Props["PostMessage"] = GetMessageForNotification(post, userLocale)
+	bodyPage.Html["PostMessage"] = GetMessageHtmlForNotification(post, userLocale)
 	if team.Name != "select_team" {
 		bodyPage.Props["TeamLink"] = teamURL + "/pl/" + post.Id
 	} else {
@@ -407,10 +415,57 @@ func sendNotificationEmail(post *model.Post, user *model.User, channel *model.Ch
 	return nil
 }
 
+// GetMessageForNotification returns the post's message as plain text for push notifications, or a description
+// of its attachments if it has no message.
 func GetMessageForNotification(post *model.Post, translateFunc i18n.TranslateFunc) string {
 	if len(strings.TrimSpace(post.Message)) != 0 || len(post.FileIds) == 0 {
-		return post.Message
+		return utils.MarkdownToPlainText(model.ClearMentionTags(post.Message), getMarkdownOptionsForPost(post))
+	}
+
+	return getAttachmentsMessageForNotification(post, translateFunc)
+}
+
+// GetMessageHtmlForNotification returns the post's message rendered as HTML for notification emails, or a
+// description of its attachments if it has no message.
+func GetMessageHtmlForNotification(post *model.Post, translateFunc i18n.TranslateFunc) template.HTML {
+	if len(strings.TrimSpace(post.Message)) != 0 || len(post.FileIds) == 0 {
+		return template.HTML(utils.MarkdownToHTML(model.ClearMentionTags(post.Message), getMarkdownOptionsForPost(post)))
+	}
+
+	return template.HTML(html.EscapeString(getAttachmentsMessageForNotification(post, translateFunc)))
+}
+
+// getMarkdownOptionsForPost resolves mentions in a post to the display names of users and of channels on the
+// post's team. The post's channel is only looked up if the message mentions a channel.
+func getMarkdownOptionsForPost(post *model.Post) *utils.MarkdownOptions {
+	var channel *model.Channel
+
+	return &utils.MarkdownOptions{
+		ResolveUserMention: func(username string) (string, bool) {
+			if result := <-Srv.Store.User().GetByUsername(username); result.Err != nil {
+				return "", false
+			} else {
+				return result.Data.(*model.User).GetDisplayName(), true
+			}
+		},
+		ResolveChannelMention: func(channelName string) (string, bool) {
+			if channel == nil {
+				var err *model.AppError
+				if channel, err = GetChannel(post.ChannelId); err != nil {
+					return "", false
+				}
+			}
+
+			if result := <-Srv.Store.Channel().GetByName(channel.TeamId, channelName, true); result.Err != nil {
+				return "", false
+			} else {
+				return result.Data.(*model.Channel).DisplayName, true
+			}
+		},
 	}
+}
+
+func getAttachmentsMessageForNotification(post *model.Post, translateFunc i18n.TranslateFunc) string {
 
 	// extract the filenames from their paths and determine what type of files are attached
 	var infos []*model.FileInfo
@@ -442,6 +497,19 @@ func GetMessageForNotification(post *model.Post, translateFunc i18n.TranslateFun
 	}
 }
 
+func shouldSendPushNotifications() bool {
+	if !*utils.Cfg.EmailSettings.SendPushNotifications {
+		return false
+	}
+
+	if *utils.Cfg.EmailSettings.PushNotificationServer == model.MHPNS && (!utils.IsLicensed || !*utils.License.Features.MHPNS) {
+		l4g.Warn(utils.T("api.post.send_notifications_and_forget.push_notification.mhpnsWarn"))
+		return false
+	}
+
+	return true
+}
+
 func sendPushNotification(post *model.Post, user *model.User, channel *model.Channel, senderName string, wasMentioned bool) *model.AppError {
 	sessions, err := getMobileAppSessions(user.Id)
 	if err != nil {
@@ -473,14 +541,20 @@ func sendPushNotification(post *model.Post, user *model.User, channel *model.Cha
 	if *utils.Cfg.EmailSettings.PushNotificationContents == model.FULL_NOTIFICATION {
 		if channel.Type == model.CHANNEL_DIRECT {
 			msg.Category = model.CATEGORY_DM
-			msg.Message = "@" + senderName + ": " + model.ClearMentionTags(post.Message)
+			msg.Message = "@" + senderName + ": " + GetMessageForNotification(post, userLocale)
 		} else {
-			msg.Message = senderName + userLocale("api.post.send_notifications_and_forget.push_in") + channelName + ": " + model.ClearMentionTags(post.Message)
+			if channel.Type == model.CHANNEL_GROUP {
+				msg.Category = model.CATEGORY_DM
+			}
+			msg.Message = senderName + userLocale("api.post.send_notifications_and_forget.push_in") + channelName + ": " + GetMessageForNotification(post, userLocale)
 		}
 	} else {
 		if channel.Type == model.CHANNEL_DIRECT {
 			msg.Category = model.CATEGORY_DM
 			msg.Message = senderName + userLocale("api.post.send_notifications_and_forget.push_message")
+		} else if channel.Type == model.CHANNEL_GROUP {
+			msg.Category = model.CATEGORY_DM
+			msg.Message = senderName + userLocale("api.post.send_notifications_and_forget.push_mention") + channelName
 		} else if wasMentioned {
 			msg.Message = senderName + userLocale("api.post.send_notifications_and_forget.push_mention") + channelName
 		} else {
@@ -596,6 +670,35 @@ func sendOutOfChannelMentions(sender *model.User, post *model.Post, teamId strin
 	return nil
 }
 
+// getHereMentionUserIds returns the ids of the channel members, other than the sender, who are online and
+// have channel-wide mentions turned on. Statuses are read through the status cache.
+func getHereMentionUserIds(profiles map[string]*model.User, senderId string) ([]string, *model.AppError) {
+	userIds := make([]string, 0, len(profiles))
+	for id := range profiles {
+		if id != senderId && profiles[id].NotifyProps["channel"] == "true" {
+			userIds = append(userIds, id)
+		}
+	}
+
+	if len(userIds) == 0 {
+		return userIds, nil
+	}
+
+	statuses, err := GetStatusesByIds(userIds)
+	if err != nil {
+		return nil, err
+	}
+
+	onlineUserIds := make([]string, 0, len(userIds))
+	for _, id := range userIds {
+		if statuses[id] == model.STATUS_ONLINE {
+			onlineUserIds = append(onlineUserIds, id)
+		}
+	}
+
+	return onlineUserIds, nil
+}
+
 // Given a message and a map mapping mention keywords to the users who use them, returns a map of mentioned
 // users and a slice of potential mention users not in the channel and whether or not @here was mentioned.
 func GetExplicitMentions(message string, keywords map[string][]string) (map[string]bool, []string, bool, bool, bool) {
@@ -678,11 +781,25 @@ func GetExplicitMentions(message string, keywords map[string][]string) (map[stri
 	return mentioned, potentialOthersMentioned, hereMentioned, channelMentioned, allMentioned
 }
 
-// Given a map of user IDs to profiles, returns a list of mention
-// keywords for all users in the channel.
-func GetMentionKeywordsInChannel(profiles map[string]*model.User) map[string][]string {
+// Given a map of user IDs to profiles and a map of group names to the IDs of
+// their members, returns a list of mention keywords for all users in the channel.
+// Group mentions only include the group's members that are in the channel.
+func GetMentionKeywordsInChannel(profiles map[string]*model.User, groupMembers map[string][]string) map[string][]string {
 	keywords := make(map[string][]string)
 
+	for name, memberIds := range groupMembers {
+		groupMention := "@" + strings.ToLower(name)
+
+		// Always add the keyword, even when no members are in the channel, so that the group isn't
+		// mistaken for a user who needs to be invited
+		keywords[groupMention] = []string{}
+		for _, id := range memberIds {
+			if _, ok := profiles[id]; ok {
+				keywords[groupMention] = append(keywords[groupMention], id)
+			}
+		}
+	}
+
 	for id, profile := range profiles {
 		userMention := "@" + strings.ToLower(profile.Username)
 		keywords[userMention] = append(keywords[userMention], id)
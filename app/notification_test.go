@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
 )
 
 func TestSendNotifications(t *testing.T) {
@@ -36,6 +37,45 @@ func TestSendNotifications(t *testing.T) {
 	}
 }
 
+func TestSendNotificationsToOldUsername(t *testing.T) {
+	th := Setup().InitBasic()
+
+	AddUserToChannel(th.BasicUser2, th.BasicChannel)
+
+	oldUsername := th.BasicUser2.Username
+	th.BasicUser2.Username = "un" + model.NewId()
+	if _, err := UpdateUser(th.BasicUser2, utils.GetSiteURL(), false); err != nil {
+		t.Fatal(err)
+	}
+
+	post, err := CreatePost(&model.Post{
+		UserId:    th.BasicUser.Id,
+		ChannelId: th.BasicChannel.Id,
+		Message:   "hey @" + oldUsername,
+	}, th.BasicTeam.Id, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mentions, err := SendNotifications(post, th.BasicTeam, th.BasicChannel, th.BasicUser); err != nil {
+		t.Fatal(err)
+	} else if len(mentions) != 1 || mentions[0] != th.BasicUser2.Id {
+		t.Fatal("user should have been mentioned by their old username", mentions)
+	}
+
+	redirectDays := *utils.Cfg.TeamSettings.UsernameRedirectDays
+	defer func() {
+		*utils.Cfg.TeamSettings.UsernameRedirectDays = redirectDays
+	}()
+	*utils.Cfg.TeamSettings.UsernameRedirectDays = 0
+
+	if mentions, err := SendNotifications(post, th.BasicTeam, th.BasicChannel, th.BasicUser); err != nil {
+		t.Fatal(err)
+	} else if len(mentions) != 0 {
+		t.Fatal("old usernames shouldn't be followed when redirects are disabled", mentions)
+	}
+}
+
 func TestGetExplicitMentions(t *testing.T) {
 	id1 := model.NewId()
 	id2 := model.NewId()
@@ -190,7 +230,7 @@ func TestGetMentionKeywords(t *testing.T) {
 	}
 
 	profiles := map[string]*model.User{user1.Id: user1}
-	mentions := GetMentionKeywordsInChannel(profiles)
+	mentions := GetMentionKeywordsInChannel(profiles, nil)
 	if len(mentions) != 3 {
 		t.Fatal("should've returned three mention keywords")
 	} else if ids, ok := mentions["user"]; !ok || ids[0] != user1.Id {
@@ -212,7 +252,7 @@ func TestGetMentionKeywords(t *testing.T) {
 	}
 
 	profiles = map[string]*model.User{user2.Id: user2}
-	mentions = GetMentionKeywordsInChannel(profiles)
+	mentions = GetMentionKeywordsInChannel(profiles, nil)
 	if len(mentions) != 2 {
 		t.Fatal("should've returned two mention keyword")
 	} else if ids, ok := mentions["First"]; !ok || ids[0] != user2.Id {
@@ -230,7 +270,7 @@ func TestGetMentionKeywords(t *testing.T) {
 	}
 
 	profiles = map[string]*model.User{user3.Id: user3}
-	mentions = GetMentionKeywordsInChannel(profiles)
+	mentions = GetMentionKeywordsInChannel(profiles, nil)
 	if len(mentions) != 3 {
 		t.Fatal("should've returned three mention keywords")
 	} else if ids, ok := mentions["@channel"]; !ok || ids[0] != user3.Id {
@@ -252,7 +292,7 @@ func TestGetMentionKeywords(t *testing.T) {
 	}
 
 	profiles = map[string]*model.User{user4.Id: user4}
-	mentions = GetMentionKeywordsInChannel(profiles)
+	mentions = GetMentionKeywordsInChannel(profiles, nil)
 	if len(mentions) != 6 {
 		t.Fatal("should've returned six mention keywords")
 	} else if ids, ok := mentions["user"]; !ok || ids[0] != user4.Id {
@@ -294,7 +334,7 @@ func TestGetMentionKeywords(t *testing.T) {
 		user3.Id: user3,
 		user4.Id: user4,
 	}
-	mentions = GetMentionKeywordsInChannel(profiles)
+	mentions = GetMentionKeywordsInChannel(profiles, nil)
 	if len(mentions) != 6 {
 		t.Fatal("should've returned six mention keywords")
 	} else if ids, ok := mentions["user"]; !ok || len(ids) != 2 || (ids[0] != user1.Id && ids[1] != user1.Id) || (ids[0] != user4.Id && ids[1] != user4.Id) {
@@ -311,3 +351,58 @@ func TestGetMentionKeywords(t *testing.T) {
 		t.Fatal("should've mentioned user3 and user4 with @all")
 	}
 }
+
+func TestGetMentionKeywordsWithGroups(t *testing.T) {
+	Setup()
+
+	user1 := &model.User{Id: model.NewId(), Username: "user1", NotifyProps: map[string]string{}}
+	user2 := &model.User{Id: model.NewId(), Username: "user2", NotifyProps: map[string]string{}}
+	outsider := model.NewId()
+
+	profiles := map[string]*model.User{user1.Id: user1, user2.Id: user2}
+	groupMembers := map[string][]string{
+		"oncall":  {user1.Id, outsider},
+		"nobody":  {outsider},
+		"empty":   {},
+		"Support": {user2.Id},
+	}
+
+	mentions := GetMentionKeywordsInChannel(profiles, groupMembers)
+	if ids, ok := mentions["@oncall"]; !ok || len(ids) != 1 || ids[0] != user1.Id {
+		t.Fatal("should've only mentioned the group member in the channel")
+	} else if ids, ok := mentions["@nobody"]; !ok || len(ids) != 0 {
+		t.Fatal("should've added a keyword for a group without members in the channel")
+	} else if ids, ok := mentions["@empty"]; !ok || len(ids) != 0 {
+		t.Fatal("should've added a keyword for an empty group")
+	} else if ids, ok := mentions["@support"]; !ok || len(ids) != 1 || ids[0] != user2.Id {
+		t.Fatal("should've lower cased the group mention")
+	}
+
+	if mentioned, potential, _, _, _ := GetExplicitMentions("ping @oncall and @nobody, thanks", mentions); len(mentioned) != 1 || !mentioned[user1.Id] {
+		t.Fatal("should've mentioned the group member")
+	} else if len(potential) != 0 {
+		t.Fatal("groups shouldn't be treated as potential out of channel mentions")
+	}
+}
+
+func TestGetHereMentionUserIds(t *testing.T) {
+	Setup()
+
+	sender := &model.User{Id: model.NewId(), NotifyProps: map[string]string{"channel": "true"}}
+	online := &model.User{Id: model.NewId(), NotifyProps: map[string]string{"channel": "true"}}
+	away := &model.User{Id: model.NewId(), NotifyProps: map[string]string{"channel": "true"}}
+	optedOut := &model.User{Id: model.NewId(), NotifyProps: map[string]string{"channel": "false"}}
+
+	for _, user := range []*model.User{sender, online, optedOut} {
+		AddStatusCacheSkipClusterSend(&model.Status{UserId: user.Id, Status: model.STATUS_ONLINE})
+	}
+	AddStatusCacheSkipClusterSend(&model.Status{UserId: away.Id, Status: model.STATUS_AWAY})
+
+	profiles := map[string]*model.User{sender.Id: sender, online.Id: online, away.Id: away, optedOut.Id: optedOut}
+
+	if ids, err := getHereMentionUserIds(profiles, sender.Id); err != nil {
+		t.Fatal(err)
+	} else if len(ids) != 1 || ids[0] != online.Id {
+		t.Fatal("should've only returned the online user with channel mentions enabled", ids)
+	}
+}
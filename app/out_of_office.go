@@ -0,0 +1,162 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"time"
+
+	l4g "github.com/alecthomas/log4go"
+	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
+)
+
+const (
+	OUT_OF_OFFICE_EXPIRY_TASK_NAME = "Out Of Office Expiry"
+	OUT_OF_OFFICE_EXPIRY_INTERVAL  = 5 * time.Minute
+)
+
+// InitOutOfOfficeExpiry starts the task that turns off out of office replies once their period is over.
+func InitOutOfOfficeExpiry() {
+	if task := model.GetTaskByName(OUT_OF_OFFICE_EXPIRY_TASK_NAME); task != nil {
+		task.Cancel()
+	}
+
+	model.CreateRecurringTask(OUT_OF_OFFICE_EXPIRY_TASK_NAME, func() {
+		if err := ClearExpiredOutOfOffice(); err != nil {
+			l4g.Error(utils.T("app.out_of_office.clear_expired.error"), err.Error())
+		}
+	}, OUT_OF_OFFICE_EXPIRY_INTERVAL)
+}
+
+func GetOutOfOffice(userId string) (*model.OutOfOffice, *model.AppError) {
+	if result := <-Srv.Store.OutOfOffice().Get(userId); result.Err != nil {
+		return nil, result.Err
+	} else {
+		outOfOffice := result.Data.(*model.OutOfOffice)
+		if outOfOffice.IsExpired(model.GetMillis()) {
+			// The expiry task may not have gotten to it yet
+			return nil, model.NewAppError("GetOutOfOffice", "app.out_of_office.get.expired.app_error", nil, "user_id="+userId, http.StatusNotFound)
+		}
+		return outOfOffice, nil
+	}
+}
+
+func SetOutOfOffice(userId string, outOfOffice *model.OutOfOffice) (*model.OutOfOffice, *model.AppError) {
+	outOfOffice.UserId = userId
+
+	if outOfOffice.IsExpired(model.GetMillis()) {
+		return nil, model.NewAppError("SetOutOfOffice", "app.out_of_office.set.end_at.app_error", nil, "user_id="+userId, http.StatusBadRequest)
+	}
+
+	if result := <-Srv.Store.OutOfOffice().SaveOrUpdate(outOfOffice); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.OutOfOffice), nil
+	}
+}
+
+func RemoveOutOfOffice(userId string) *model.AppError {
+	if result := <-Srv.Store.OutOfOffice().Delete(userId); result.Err != nil {
+		return result.Err
+	}
+
+	return nil
+}
+
+// ClearExpiredOutOfOffice turns off the out of office replies of every user whose period is over.
+func ClearExpiredOutOfOffice() *model.AppError {
+	now := model.GetMillis()
+
+	var outOfOffices []*model.OutOfOffice
+	if result := <-Srv.Store.OutOfOffice().GetExpired(now); result.Err != nil {
+		return result.Err
+	} else {
+		outOfOffices = result.Data.([]*model.OutOfOffice)
+	}
+
+	for _, outOfOffice := range outOfOffices {
+		// The user may have saved a new period in the meantime, which has to be left alone
+		if result := <-Srv.Store.OutOfOffice().DeleteExpired(outOfOffice.UserId, now); result.Err != nil {
+			l4g.Error(utils.T("app.out_of_office.clear_expired.user.error"), outOfOffice.UserId, result.Err.Error())
+		}
+	}
+
+	return nil
+}
+
+// sendOutOfOfficeReplies replies to the sender of a post on behalf of the recipients that are out of office. Replies
+// to direct messages are posted in the channel by the recipient, while replies to mentions elsewhere are only shown
+// to the sender so that they don't clutter the channel. Each sender gets at most one reply a day from each recipient,
+// where the day is in the recipient's timezone.
+func sendOutOfOfficeReplies(post *model.Post, teamId string, channel *model.Channel, sender *model.User, recipients []*model.User) {
+	if len(recipients) == 0 {
+		return
+	}
+
+	recipientMap := make(map[string]*model.User, len(recipients))
+	userIds := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		recipientMap[recipient.Id] = recipient
+		userIds = append(userIds, recipient.Id)
+	}
+
+	var outOfOffices []*model.OutOfOffice
+	if result := <-Srv.Store.OutOfOffice().GetByIds(userIds); result.Err != nil {
+		l4g.Error(utils.T("app.out_of_office.reply.error"), post.Id, result.Err.Error())
+		return
+	} else {
+		outOfOffices = result.Data.([]*model.OutOfOffice)
+	}
+
+	now := model.GetMillis()
+	T := utils.GetUserTranslations(sender.Locale)
+
+	for _, outOfOffice := range outOfOffices {
+		recipient := recipientMap[outOfOffice.UserId]
+
+		if !outOfOffice.IsActive(now) {
+			continue
+		}
+
+		if result := <-Srv.Store.OutOfOffice().ClaimReply(&model.OutOfOfficeReply{UserId: recipient.Id, SenderId: sender.Id, ReplyAt: now}, startOfDay(recipient, now)); result.Err != nil {
+			l4g.Error(utils.T("app.out_of_office.reply.error"), post.Id, result.Err.Error())
+			continue
+		} else if !result.Data.(bool) {
+			continue
+		}
+
+		if channel.Type == model.CHANNEL_DIRECT {
+			reply := &model.Post{
+				ChannelId: channel.Id,
+				UserId:    recipient.Id,
+				Message:   outOfOffice.Message,
+				Type:      model.POST_OUT_OF_OFFICE,
+			}
+
+			if _, err := CreatePost(reply, "", false); err != nil {
+				l4g.Error(utils.T("app.out_of_office.reply.error"), post.Id, err.Error())
+			}
+		} else {
+			SendEphemeralPost(
+				teamId,
+				sender.Id,
+				&model.Post{
+					ChannelId: channel.Id,
+					UserId:    recipient.Id,
+					Message:   T("app.out_of_office.reply.ephemeral", map[string]interface{}{"Username": recipient.Username, "Message": outOfOffice.Message}),
+					CreateAt:  post.CreateAt + 1,
+				},
+			)
+		}
+	}
+}
+
+// startOfDay returns the time that the current day started at in the user's timezone.
+func startOfDay(user *model.User, now int64) int64 {
+	location := user.GetTimezoneLocation()
+	year, month, day := time.Unix(0, now*int64(time.Millisecond)).In(location).Date()
+
+	return time.Date(year, month, day, 0, 0, 0, 0, location).UnixNano() / int64(time.Millisecond)
+}
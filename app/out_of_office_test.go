@@ -0,0 +1,94 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/platform/model"
+)
+
+func TestOutOfOfficeReplies(t *testing.T) {
+	th := Setup().InitBasic()
+
+	if _, err := SetOutOfOffice(th.BasicUser2.Id, &model.OutOfOffice{Message: "On vacation", EndAt: model.GetMillis() + 60*60*1000}); err != nil {
+		t.Fatal(err)
+	}
+	defer RemoveOutOfOffice(th.BasicUser2.Id)
+
+	channel, err := CreateDirectChannel(th.BasicUser.Id, th.BasicUser2.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := CreatePost(&model.Post{UserId: th.BasicUser.Id, ChannelId: channel.Id, Message: "are you there?"}, "", false); err != nil {
+			t.Fatal(err)
+		}
+
+		// The replies are sent in the background
+		time.Sleep(time.Second)
+	}
+
+	list, err := GetPostsPage(channel.Id, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replies := 0
+	for _, post := range list.Posts {
+		if post.Type == model.POST_OUT_OF_OFFICE {
+			replies++
+			if post.UserId != th.BasicUser2.Id || post.Message != "On vacation" {
+				t.Fatal("reply should've been posted by the user who is out of office")
+			}
+		}
+	}
+
+	if replies != 1 {
+		t.Fatal("should've replied once a day", replies)
+	}
+
+	if _, err := CreatePost(&model.Post{UserId: th.BasicUser2.Id, ChannelId: channel.Id, Message: "back soon"}, "", false); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(time.Second)
+
+	if result := <-Srv.Store.OutOfOffice().GetReply(th.BasicUser.Id, th.BasicUser2.Id); result.Err == nil {
+		t.Fatal("users who aren't out of office shouldn't reply")
+	}
+}
+
+func TestClearExpiredOutOfOffice(t *testing.T) {
+	th := Setup().InitBasic()
+
+	// Saved directly since expired periods can't be set through the app
+	expired := &model.OutOfOffice{UserId: th.BasicUser.Id, Message: "On vacation", StartAt: 1000, EndAt: model.GetMillis() - 1000}
+	if result := <-Srv.Store.OutOfOffice().SaveOrUpdate(expired); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	if _, err := SetOutOfOffice(th.BasicUser2.Id, &model.OutOfOffice{Message: "On leave", EndAt: model.GetMillis() + 60*60*1000}); err != nil {
+		t.Fatal(err)
+	}
+	defer RemoveOutOfOffice(th.BasicUser2.Id)
+
+	if _, err := GetOutOfOffice(th.BasicUser.Id); err == nil {
+		t.Fatal("expired out of office shouldn't be returned")
+	}
+
+	if err := ClearExpiredOutOfOffice(); err != nil {
+		t.Fatal(err)
+	}
+
+	if result := <-Srv.Store.OutOfOffice().Get(th.BasicUser.Id); result.Err == nil {
+		t.Fatal("expired out of office should've been cleared")
+	}
+
+	if _, err := GetOutOfOffice(th.BasicUser2.Id); err != nil {
+		t.Fatal(err)
+	}
+}
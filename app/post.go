@@ -15,6 +15,10 @@ import (
 	"github.com/mattermost/platform/utils"
 )
 
+const LINK_METADATA_CACHE_SIZE = 10000
+
+var linkMetadataCache = utils.NewLru(LINK_METADATA_CACHE_SIZE)
+
 func CreatePostAsUser(post *model.Post) (*model.Post, *model.AppError) {
 	// Check that channel has not been deleted
 	var channel *model.Channel
@@ -54,11 +58,32 @@ func CreatePostAsUser(post *model.Post) (*model.Post, *model.AppError) {
 }
 
 func CreatePost(post *model.Post, teamId string, triggerWebhooks bool) (*model.Post, *model.AppError) {
+	cchan := Srv.Store.Channel().Get(post.ChannelId, true)
+
 	var pchan store.StoreChannel
 	if len(post.RootId) > 0 {
 		pchan = Srv.Store.Post().Get(post.RootId)
 	}
 
+	// Archived channels are read-only, but a missing channel is left for the store to deal with
+	if result := <-cchan; result.Err == nil {
+		channel := result.Data.(*model.Channel)
+
+		if channel.IsArchived() {
+			err := model.NewLocAppError("createPost", "api.post.create_post.can_not_post_to_archived.error", nil, "")
+			err.StatusCode = http.StatusBadRequest
+			return nil, err
+		}
+
+		// System messages such as join and leave messages are still posted in announcement channels. Clients and
+		// webhooks can't create system messages, so these only ever come from the server.
+		if channel.IsPostingRestricted() && !post.IsSystemMessage() && !HasPermissionToChannel(post.UserId, channel.Id, model.PERMISSION_CREATE_POST_RESTRICTED) {
+			err := model.NewLocAppError("createPost", "api.post.create_post.restricted.app_error", nil, "user_id="+post.UserId)
+			err.StatusCode = http.StatusForbidden
+			return nil, err
+		}
+	}
+
 	// Verify the parent/child relationships are correct
 	if pchan != nil {
 		if presult := <-pchan; presult.Err != nil {
@@ -502,17 +527,31 @@ func GetFileInfosForPost(postId string) ([]*model.FileInfo, *model.AppError) {
 }
 
 func GetOpenGraphMetadata(url string) *opengraph.OpenGraph {
+	if cached, ok := linkMetadataCache.Get(url); ok {
+		return cached.(*opengraph.OpenGraph)
+	}
+
 	og := opengraph.NewOpenGraph()
 
-	res, err := http.Get(url)
+	res, err := utils.HttpClient().Get(url)
 	if err != nil {
+		l4g.Debug("GetOpenGraphMetadata request failed for url=%v with err=%v", url, err.Error())
 		return og
 	}
 	defer CloseBody(res)
 
+	if res.StatusCode != http.StatusOK {
+		return og
+	}
+
 	if err := og.ProcessHTML(res.Body); err != nil {
 		return og
 	}
 
+	// Only successful lookups are cached so that a site which is temporarily down gets retried
+	if cacheTime := *utils.Cfg.ServiceSettings.LinkMetadataCacheInMinutes; cacheTime > 0 {
+		linkMetadataCache.AddWithExpiresInSecs(url, og, int64(cacheTime*60))
+	}
+
 	return og
 }
@@ -0,0 +1,202 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/mattermost/platform/model"
+)
+
+func CreateProfileAttribute(attribute *model.ProfileAttribute) (*model.ProfileAttribute, *model.AppError) {
+	if result := <-Srv.Store.ProfileAttribute().Save(attribute); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.ProfileAttribute), nil
+	}
+}
+
+func GetProfileAttribute(attributeId string) (*model.ProfileAttribute, *model.AppError) {
+	if result := <-Srv.Store.ProfileAttribute().Get(attributeId); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.ProfileAttribute), nil
+	}
+}
+
+func GetProfileAttributes() ([]*model.ProfileAttribute, *model.AppError) {
+	if result := <-Srv.Store.ProfileAttribute().GetAll(); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([]*model.ProfileAttribute), nil
+	}
+}
+
+func UpdateProfileAttribute(attribute *model.ProfileAttribute) (*model.ProfileAttribute, *model.AppError) {
+	oldAttribute, err := GetProfileAttribute(attribute.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	attribute.CreateAt = oldAttribute.CreateAt
+
+	if result := <-Srv.Store.ProfileAttribute().Update(attribute); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.ProfileAttribute), nil
+	}
+}
+
+// DeleteProfileAttribute deletes the attribute and the values that every user has for it.
+func DeleteProfileAttribute(attributeId string) *model.AppError {
+	if _, err := GetProfileAttribute(attributeId); err != nil {
+		return err
+	}
+
+	if result := <-Srv.Store.ProfileAttribute().Delete(attributeId); result.Err != nil {
+		return result.Err
+	}
+
+	return nil
+}
+
+// GetUserProfileAttributes returns the values of the user's profile attributes keyed by attribute name. Private
+// attributes are left out unless includePrivate is set.
+func GetUserProfileAttributes(userId string, includePrivate bool) (model.StringMap, *model.AppError) {
+	achan := Srv.Store.ProfileAttribute().GetAll()
+	vchan := Srv.Store.ProfileAttribute().GetValuesForUser(userId)
+
+	attributes := make(map[string]*model.ProfileAttribute)
+	if result := <-achan; result.Err != nil {
+		return nil, result.Err
+	} else {
+		for _, attribute := range result.Data.([]*model.ProfileAttribute) {
+			attributes[attribute.Id] = attribute
+		}
+	}
+
+	values := make(model.StringMap)
+	if result := <-vchan; result.Err != nil {
+		return nil, result.Err
+	} else {
+		for _, value := range result.Data.([]*model.ProfileAttributeValue) {
+			if attribute, ok := attributes[value.AttributeId]; ok {
+				if includePrivate || attribute.Visibility != model.PROFILE_ATTRIBUTE_VISIBILITY_PRIVATE {
+					values[attribute.Name] = value.Value
+				}
+			}
+		}
+	}
+
+	return values, nil
+}
+
+func AddProfileAttributesToUser(user *model.User, includePrivate bool) *model.AppError {
+	if values, err := GetUserProfileAttributes(user.Id, includePrivate); err != nil {
+		return err
+	} else {
+		user.ProfileAttributes = values
+		return nil
+	}
+}
+
+// UpdateUserProfileAttributes sets the given attributes for the user, keyed by attribute name. An empty value clears
+// the attribute. Attributes that are synced from the user's LDAP or SAML account can't be changed here since they'd be
+// overwritten the next time the user logs in.
+func UpdateUserProfileAttributes(userId string, values model.StringMap) (model.StringMap, *model.AppError) {
+	user, err := GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := saveProfileAttributeValues(user, values, false); err != nil {
+		return nil, err
+	}
+
+	return GetUserProfileAttributes(userId, true)
+}
+
+// SyncProfileAttributesFromDirectory saves the attributes that are kept in sync with the user's LDAP or SAML account on
+// login. DoLogin of the LDAP and SAML implementations puts the values of the directory attributes that profile attributes
+// are mapped to through their LdapAttribute or SamlAttribute in user.ProfileAttributes, keyed by profile attribute name,
+// and any synced attribute that is missing from there is cleared. Nothing is synced if the implementation didn't provide
+// any attributes at all.
+func SyncProfileAttributesFromDirectory(user *model.User) *model.AppError {
+	if user.ProfileAttributes == nil {
+		return nil
+	}
+
+	attributes, err := GetProfileAttributes()
+	if err != nil {
+		return err
+	}
+
+	values := make(model.StringMap)
+	for _, attribute := range attributes {
+		if attribute.IsSyncedFrom(user.AuthService) {
+			values[attribute.Name] = user.ProfileAttributes[attribute.Name]
+		}
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	return saveProfileAttributeValues(user, values, true)
+}
+
+func saveProfileAttributeValues(user *model.User, values model.StringMap, allowSynced bool) *model.AppError {
+	attributes, err := GetProfileAttributes()
+	if err != nil {
+		return err
+	}
+
+	attributesByName := make(map[string]*model.ProfileAttribute)
+	for _, attribute := range attributes {
+		attributesByName[attribute.Name] = attribute
+	}
+
+	// Validate everything up front so that an invalid value doesn't leave the profile half updated
+	for name, value := range values {
+		if attribute, ok := attributesByName[name]; !ok {
+			return model.NewAppError("saveProfileAttributeValues", "app.profile_attribute.save_values.unknown.app_error", map[string]interface{}{"Name": name}, "user_id="+user.Id, http.StatusBadRequest)
+		} else if !allowSynced && attribute.IsSyncedFrom(user.AuthService) {
+			return model.NewAppError("saveProfileAttributeValues", "app.profile_attribute.save_values.synced.app_error", map[string]interface{}{"Name": name}, "user_id="+user.Id, http.StatusBadRequest)
+		} else if len(value) > 0 && !attribute.IsValidValue(value) {
+			return model.NewAppError("saveProfileAttributeValues", "app.profile_attribute.save_values.invalid.app_error", map[string]interface{}{"Name": name}, "user_id="+user.Id, http.StatusBadRequest)
+		}
+	}
+
+	for name, value := range values {
+		attribute := attributesByName[name]
+
+		if len(value) == 0 {
+			if result := <-Srv.Store.ProfileAttribute().DeleteValue(user.Id, attribute.Id); result.Err != nil {
+				return result.Err
+			}
+		} else {
+			if result := <-Srv.Store.ProfileAttribute().SaveValue(&model.ProfileAttributeValue{UserId: user.Id, AttributeId: attribute.Id, Value: value}); result.Err != nil {
+				return result.Err
+			}
+		}
+	}
+
+	// Bump the user so that the etags of their profile change
+	if result := <-Srv.Store.User().UpdateUpdateAt(user.Id); result.Err != nil {
+		return result.Err
+	}
+
+	InvalidateCacheForUser(user.Id)
+
+	return nil
+}
+
+// SearchUsersByProfileAttribute returns the active users whose value for the attribute starts with the term.
+func SearchUsersByProfileAttribute(attributeId string, term string, limit int) ([]*model.User, *model.AppError) {
+	if result := <-Srv.Store.ProfileAttribute().SearchUsers(attributeId, term, limit); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([]*model.User), nil
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"strings"
+
+	l4g "github.com/alecthomas/log4go"
+	"github.com/mattermost/platform/einterfaces"
+	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
+)
+
+func GetReactionSummaryForPost(postId string, userId string) ([]*model.ReactionSummary, *model.AppError) {
+	if result := <-Srv.Store.Reaction().GetSummaryForPost(postId, userId); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([]*model.ReactionSummary), nil
+	}
+}
+
+// GetUsersForReaction returns a page of the users who reacted to a post with the given emoji, in the order that
+// they reacted.
+func GetUsersForReaction(postId string, emojiName string, page int, perPage int, asAdmin bool) ([]*model.User, *model.AppError) {
+	var userIds []string
+	if result := <-Srv.Store.Reaction().GetUserIdsForEmoji(postId, emojiName, page*perPage, perPage); result.Err != nil {
+		return nil, result.Err
+	} else {
+		userIds = result.Data.([]string)
+	}
+
+	if len(userIds) == 0 {
+		return []*model.User{}, nil
+	}
+
+	users, err := GetUsersByIds(userIds, asAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	usersById := make(map[string]*model.User, len(users))
+	for _, user := range users {
+		usersById[user.Id] = user
+	}
+
+	orderedUsers := make([]*model.User, 0, len(users))
+	for _, userId := range userIds {
+		if user, ok := usersById[userId]; ok {
+			orderedUsers = append(orderedUsers, user)
+		}
+	}
+
+	return orderedUsers, nil
+}
+
+// SendReactionNotification sends a push notification to the author of a post when someone else reacts to it, if
+// the author's reactions notify prop allows it for the post's channel.
+func SendReactionNotification(reaction *model.Reaction, post *model.Post) *model.AppError {
+	if reaction.UserId == post.UserId || !shouldSendPushNotifications() {
+		return nil
+	}
+
+	author, err := GetUser(post.UserId)
+	if err != nil {
+		return err
+	}
+
+	level := author.NotifyProps[model.REACTION_NOTIFY_PROP]
+	if level != model.REACTION_NOTIFY_DM && level != model.REACTION_NOTIFY_ALL {
+		return nil
+	}
+
+	channel, err := GetChannel(post.ChannelId)
+	if err != nil {
+		return err
+	}
+
+	if level == model.REACTION_NOTIFY_DM && !channel.IsGroupOrDirect() {
+		return nil
+	}
+
+	status, err := GetStatus(author.Id)
+	if err != nil {
+		status = &model.Status{UserId: author.Id, Status: model.STATUS_OFFLINE, Manual: false, LastActivityAt: 0, ActiveChannel: ""}
+	}
+
+	if !DoesStatusAllowPushNotification(author, status, channel.Id) {
+		return nil
+	}
+
+	reactor, err := GetUser(reaction.UserId)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := getMobileAppSessions(author.Id)
+	if err != nil {
+		return err
+	}
+
+	userLocale := utils.GetUserTranslations(author.Locale)
+
+	msg := model.PushNotification{}
+	if badge := <-Srv.Store.User().GetUnreadCount(author.Id); badge.Err != nil {
+		msg.Badge = 1
+		l4g.Error(utils.T("store.sql_user.get_unread_count.app_error"), author.Id, badge.Err)
+	} else {
+		msg.Badge = int(badge.Data.(int64))
+	}
+	msg.Type = model.PUSH_TYPE_MESSAGE
+	msg.TeamId = channel.TeamId
+	msg.ChannelId = channel.Id
+	msg.ChannelName = channel.Name
+	msg.Message = userLocale("app.reaction.send_reaction_notification.push_message", map[string]interface{}{
+		"Username":  reactor.Username,
+		"EmojiName": reaction.EmojiName,
+	})
+
+	for _, session := range sessions {
+		tmpMessage := *model.PushNotificationFromJson(strings.NewReader(msg.ToJson()))
+		tmpMessage.SetDeviceIdAndPlatform(session.DeviceId)
+		go sendToPushProxy(tmpMessage)
+
+		if einterfaces.GetMetricsInterface() != nil {
+			einterfaces.GetMetricsInterface().IncrementPostSentPush()
+		}
+	}
+
+	return nil
+}
@@ -4,6 +4,9 @@
 package app
 
 import (
+	"net/http"
+	"unicode/utf8"
+
 	"github.com/mattermost/platform/einterfaces"
 	"github.com/mattermost/platform/model"
 	"github.com/mattermost/platform/utils"
@@ -50,7 +53,6 @@ func GetSession(token string) (*model.Session, *model.AppError) {
 				return nil, model.NewLocAppError("GetSession", "api.context.invalid_token.error", map[string]interface{}{"Token": token, "Error": sessionResult.Err.DetailedError}, "")
 			} else {
 				AddSessionToCache(session)
-				return session, nil
 			}
 		}
 	}
@@ -59,6 +61,12 @@ func GetSession(token string) (*model.Session, *model.AppError) {
 		return nil, model.NewLocAppError("GetSession", "api.context.invalid_token.error", map[string]interface{}{"Token": token}, "")
 	}
 
+	// Personal access tokens stop working while they're turned off, including ones that a system admin created for
+	// someone else
+	if session.IsUserAccessToken() && !*utils.Cfg.ServiceSettings.EnableUserAccessTokens {
+		return nil, model.NewLocAppError("GetSession", "api.context.invalid_token.error", map[string]interface{}{"Token": token}, "user_access_token")
+	}
+
 	return session, nil
 }
 
@@ -178,3 +186,61 @@ func AttachDeviceId(sessionId string, deviceId string, expiresAt int64) *model.A
 
 	return nil
 }
+
+// createTokenSession creates a session of the given type that isn't tied to a login. It lasts until it's revoked or
+// until expiresAt if that isn't zero, and its token is only available in the returned session.
+func createTokenSession(user *model.User, tokenType string, description string, expiresAt int64) (*model.Session, *model.AppError) {
+	if user.DeleteAt > 0 {
+		return nil, model.NewAppError("createTokenSession", "app.session.create_token.inactive.app_error", nil, "user_id="+user.Id, http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(description) > model.SESSION_DESCRIPTION_MAX_RUNES {
+		return nil, model.NewAppError("createTokenSession", "app.session.create_token.description.app_error", nil, "user_id="+user.Id, http.StatusBadRequest)
+	}
+
+	if expiresAt != 0 && expiresAt <= model.GetMillis() {
+		return nil, model.NewAppError("createTokenSession", "app.session.create_token.expires_at.app_error", nil, "user_id="+user.Id, http.StatusBadRequest)
+	}
+
+	session := &model.Session{UserId: user.Id, Roles: user.GetRawRoles(), ExpiresAt: expiresAt}
+	session.AddProp(model.SESSION_PROP_TYPE, tokenType)
+	if len(description) > 0 {
+		session.AddProp(model.SESSION_PROP_DESCRIPTION, description)
+	}
+
+	return CreateSession(session)
+}
+
+// getTokenSessions returns the sessions of the given type for the user with the token values removed.
+func getTokenSessions(userId string, tokenType string) ([]*model.Session, *model.AppError) {
+	sessions, err := GetSessions(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := []*model.Session{}
+	for _, session := range sessions {
+		if session.Props[model.SESSION_PROP_TYPE] == tokenType {
+			session.Sanitize()
+			tokens = append(tokens, session)
+		}
+	}
+
+	return tokens, nil
+}
+
+func revokeTokenSession(userId string, tokenId string, tokenType string) *model.AppError {
+	var session *model.Session
+	if result := <-Srv.Store.Session().Get(tokenId); result.Err != nil {
+		return model.NewAppError("revokeTokenSession", "app.session.revoke_token.not_found.app_error", nil, "token_id="+tokenId, http.StatusNotFound)
+	} else {
+		session = result.Data.(*model.Session)
+	}
+
+	// Sessions can also be looked up by token, so make sure that it was the id that matched
+	if session.Id != tokenId || session.UserId != userId || session.Props[model.SESSION_PROP_TYPE] != tokenType {
+		return model.NewAppError("revokeTokenSession", "app.session.revoke_token.not_found.app_error", nil, "token_id="+tokenId, http.StatusNotFound)
+	}
+
+	return RevokeSession(session)
+}
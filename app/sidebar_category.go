@@ -0,0 +1,363 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
+)
+
+var defaultSidebarCategoryTypes = []string{
+	model.SIDEBAR_CATEGORY_FAVORITES,
+	model.SIDEBAR_CATEGORY_CHANNELS,
+	model.SIDEBAR_CATEGORY_DIRECT_MESSAGES,
+}
+
+// GetSidebarCategories returns the sidebar categories of a user on a team in display order, creating the default
+// ones the first time. Channels that the user belongs to but that aren't in any category are added to the channels
+// or direct messages category, and channels that the user has left are dropped.
+func GetSidebarCategories(userId string, teamId string) ([]*model.SidebarCategory, *model.AppError) {
+	categories, err := getOrCreateSidebarCategories(userId, teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := getSidebarChannels(userId, teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	arrangeSidebarChannels(categories, channels)
+
+	return categories, nil
+}
+
+func GetSidebarCategory(userId string, teamId string, categoryId string) (*model.SidebarCategory, *model.AppError) {
+	categories, err := GetSidebarCategories(userId, teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, category := range categories {
+		if category.Id == categoryId {
+			return category, nil
+		}
+	}
+
+	return nil, model.NewAppError("GetSidebarCategory", "app.sidebar_category.get.not_found.app_error", nil, "category_id="+categoryId, http.StatusNotFound)
+}
+
+func CreateSidebarCategory(userId string, teamId string, category *model.SidebarCategory) (*model.SidebarCategory, *model.AppError) {
+	categories, err := GetSidebarCategories(userId, teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	category.Id = ""
+	category.UserId = userId
+	category.TeamId = teamId
+	category.Type = model.SIDEBAR_CATEGORY_CUSTOM
+	category.SortOrder = int64(len(categories))
+
+	if category.ChannelIds, err = checkSidebarChannelIds(userId, teamId, category.ChannelIds); err != nil {
+		return nil, err
+	}
+
+	var rcategory *model.SidebarCategory
+	if result := <-Srv.Store.SidebarCategory().Save(category); result.Err != nil {
+		return nil, result.Err
+	} else {
+		rcategory = result.Data.(*model.SidebarCategory)
+	}
+
+	updated, err := removeChannelsFromOtherCategories(categories, rcategory)
+	if err != nil {
+		return nil, err
+	}
+
+	publishSidebarCategoryEvent(model.WEBSOCKET_EVENT_SIDEBAR_CATEGORY_CREATED, userId, teamId, []*model.SidebarCategory{rcategory})
+	if len(updated) > 0 {
+		publishSidebarCategoryEvent(model.WEBSOCKET_EVENT_SIDEBAR_CATEGORY_UPDATED, userId, teamId, updated)
+	}
+
+	return rcategory, nil
+}
+
+// UpdateSidebarCategory changes the channels and collapsed state of a category, as well as the name of a custom
+// category. Channels added to the category are taken out of the category they were in before.
+func UpdateSidebarCategory(userId string, teamId string, category *model.SidebarCategory) (*model.SidebarCategory, *model.AppError) {
+	categories, err := GetSidebarCategories(userId, teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing *model.SidebarCategory
+	for _, c := range categories {
+		if c.Id == category.Id {
+			existing = c
+			break
+		}
+	}
+
+	if existing == nil {
+		return nil, model.NewAppError("UpdateSidebarCategory", "app.sidebar_category.get.not_found.app_error", nil, "category_id="+category.Id, http.StatusNotFound)
+	}
+
+	if existing.Type == model.SIDEBAR_CATEGORY_CUSTOM && len(category.DisplayName) > 0 {
+		existing.DisplayName = category.DisplayName
+	}
+
+	existing.Collapsed = category.Collapsed
+
+	if existing.ChannelIds, err = checkSidebarChannelIds(userId, teamId, category.ChannelIds); err != nil {
+		return nil, err
+	}
+
+	if result := <-Srv.Store.SidebarCategory().Update(existing); result.Err != nil {
+		return nil, result.Err
+	}
+
+	updated, err := removeChannelsFromOtherCategories(categories, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	publishSidebarCategoryEvent(model.WEBSOCKET_EVENT_SIDEBAR_CATEGORY_UPDATED, userId, teamId, append([]*model.SidebarCategory{existing}, updated...))
+
+	return existing, nil
+}
+
+// UpdateSidebarCategoryOrder reorders all the categories of a user on a team. The given ids must be exactly the ids
+// of those categories.
+func UpdateSidebarCategoryOrder(userId string, teamId string, categoryIds []string) *model.AppError {
+	categories, err := GetSidebarCategories(userId, teamId)
+	if err != nil {
+		return err
+	}
+
+	if len(categoryIds) != len(categories) {
+		return model.NewAppError("UpdateSidebarCategoryOrder", "app.sidebar_category.update_order.ids.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	sortOrders := make(map[string]int64, len(categoryIds))
+	for i, categoryId := range categoryIds {
+		sortOrders[categoryId] = int64(i)
+	}
+
+	for _, category := range categories {
+		if sortOrder, ok := sortOrders[category.Id]; !ok {
+			return model.NewAppError("UpdateSidebarCategoryOrder", "app.sidebar_category.update_order.ids.app_error", nil, "", http.StatusBadRequest)
+		} else {
+			category.SortOrder = sortOrder
+		}
+	}
+
+	for _, category := range categories {
+		if result := <-Srv.Store.SidebarCategory().Update(category); result.Err != nil {
+			return result.Err
+		}
+	}
+
+	message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_SIDEBAR_CATEGORY_ORDER_UPDATED, teamId, "", userId, nil)
+	message.Add("order", model.ArrayToJson(categoryIds))
+	go Publish(message)
+
+	return nil
+}
+
+// DeleteSidebarCategory deletes a custom category. Its channels go back to the channels and direct messages
+// categories.
+func DeleteSidebarCategory(userId string, teamId string, categoryId string) *model.AppError {
+	category, err := GetSidebarCategory(userId, teamId, categoryId)
+	if err != nil {
+		return err
+	}
+
+	if category.Type != model.SIDEBAR_CATEGORY_CUSTOM {
+		return model.NewAppError("DeleteSidebarCategory", "app.sidebar_category.delete.not_custom.app_error", nil, "category_id="+categoryId, http.StatusBadRequest)
+	}
+
+	if result := <-Srv.Store.SidebarCategory().Delete(categoryId); result.Err != nil {
+		return result.Err
+	}
+
+	message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_SIDEBAR_CATEGORY_DELETED, teamId, "", userId, nil)
+	message.Add("category_id", categoryId)
+	go Publish(message)
+
+	return nil
+}
+
+func getOrCreateSidebarCategories(userId string, teamId string) ([]*model.SidebarCategory, *model.AppError) {
+	if result := <-Srv.Store.SidebarCategory().GetForTeam(userId, teamId); result.Err != nil {
+		return nil, result.Err
+	} else if categories := result.Data.([]*model.SidebarCategory); len(categories) > 0 {
+		return categories, nil
+	}
+
+	user, err := GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	T := utils.GetUserTranslations(user.Locale)
+
+	categories := make([]*model.SidebarCategory, 0, len(defaultSidebarCategoryTypes))
+	alreadyCreated := false
+	for i, categoryType := range defaultSidebarCategoryTypes {
+		category := &model.SidebarCategory{
+			UserId:      userId,
+			TeamId:      teamId,
+			Type:        categoryType,
+			DisplayName: T("app.sidebar_category.default." + categoryType),
+			SortOrder:   int64(i),
+		}
+
+		if result := <-Srv.Store.SidebarCategory().Save(category); result.Err != nil {
+			// Another request for the same user created the default categories at the same time
+			if result.Err.Id != "store.sql_sidebar_category.save.exists.app_error" {
+				return nil, result.Err
+			}
+			alreadyCreated = true
+		} else {
+			categories = append(categories, result.Data.(*model.SidebarCategory))
+		}
+	}
+
+	if alreadyCreated {
+		if result := <-Srv.Store.SidebarCategory().GetForTeam(userId, teamId); result.Err != nil {
+			return nil, result.Err
+		} else {
+			return result.Data.([]*model.SidebarCategory), nil
+		}
+	}
+
+	return categories, nil
+}
+
+func getSidebarChannels(userId string, teamId string) (model.ChannelList, *model.AppError) {
+	if channels, err := GetChannelsForUser(teamId, userId); err != nil {
+		// The store treats a user without any channels on the team as an error
+		if err.Id == "store.sql_channel.get_channels.not_found.app_error" {
+			return model.ChannelList{}, nil
+		}
+		return nil, err
+	} else {
+		return *channels, nil
+	}
+}
+
+// arrangeSidebarChannels makes the categories match the channels that the user currently belongs to without
+// persisting anything.
+func arrangeSidebarChannels(categories []*model.SidebarCategory, channels model.ChannelList) {
+	channelsById := make(map[string]*model.Channel, len(channels))
+	for _, channel := range channels {
+		channelsById[channel.Id] = channel
+	}
+
+	placed := make(map[string]bool, len(channels))
+	var channelsCategory, directMessagesCategory *model.SidebarCategory
+
+	for _, category := range categories {
+		channelIds := model.StringArray{}
+		for _, channelId := range category.ChannelIds {
+			if channelsById[channelId] != nil && !placed[channelId] {
+				channelIds = append(channelIds, channelId)
+				placed[channelId] = true
+			}
+		}
+		category.ChannelIds = channelIds
+
+		if category.Type == model.SIDEBAR_CATEGORY_CHANNELS {
+			channelsCategory = category
+		} else if category.Type == model.SIDEBAR_CATEGORY_DIRECT_MESSAGES {
+			directMessagesCategory = category
+		}
+	}
+
+	for _, channel := range channels {
+		if placed[channel.Id] {
+			continue
+		}
+
+		if channel.IsGroupOrDirect() && directMessagesCategory != nil {
+			directMessagesCategory.ChannelIds = append(directMessagesCategory.ChannelIds, channel.Id)
+		} else if channelsCategory != nil {
+			channelsCategory.ChannelIds = append(channelsCategory.ChannelIds, channel.Id)
+		}
+	}
+}
+
+// checkSidebarChannelIds removes duplicates from a list of channels to put in a category and makes sure that the
+// user belongs to all of them.
+func checkSidebarChannelIds(userId string, teamId string, channelIds []string) (model.StringArray, *model.AppError) {
+	channels, err := getSidebarChannels(userId, teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	isMember := make(map[string]bool, len(channels))
+	for _, channel := range channels {
+		isMember[channel.Id] = true
+	}
+
+	seen := make(map[string]bool, len(channelIds))
+	checked := model.StringArray{}
+	for _, channelId := range channelIds {
+		if !isMember[channelId] {
+			return nil, model.NewAppError("checkSidebarChannelIds", "app.sidebar_category.channel_ids.app_error", nil, "channel_id="+channelId, http.StatusBadRequest)
+		}
+
+		if !seen[channelId] {
+			checked = append(checked, channelId)
+			seen[channelId] = true
+		}
+	}
+
+	return checked, nil
+}
+
+// removeChannelsFromOtherCategories keeps each channel in a single category by taking the channels of the given
+// category out of the others. It returns the categories that changed.
+func removeChannelsFromOtherCategories(categories []*model.SidebarCategory, category *model.SidebarCategory) ([]*model.SidebarCategory, *model.AppError) {
+	inCategory := make(map[string]bool, len(category.ChannelIds))
+	for _, channelId := range category.ChannelIds {
+		inCategory[channelId] = true
+	}
+
+	var updated []*model.SidebarCategory
+	for _, other := range categories {
+		if other.Id == category.Id {
+			continue
+		}
+
+		channelIds := model.StringArray{}
+		for _, channelId := range other.ChannelIds {
+			if !inCategory[channelId] {
+				channelIds = append(channelIds, channelId)
+			}
+		}
+
+		if len(channelIds) == len(other.ChannelIds) {
+			continue
+		}
+
+		other.ChannelIds = channelIds
+		if result := <-Srv.Store.SidebarCategory().Update(other); result.Err != nil {
+			return nil, result.Err
+		}
+
+		updated = append(updated, other)
+	}
+
+	return updated, nil
+}
+
+func publishSidebarCategoryEvent(event string, userId string, teamId string, categories []*model.SidebarCategory) {
+	message := model.NewWebSocketEvent(event, teamId, "", userId, nil)
+	message.Add("categories", model.SidebarCategoryListToJson(categories))
+	go Publish(message)
+}
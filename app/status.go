@@ -4,6 +4,8 @@
 package app
 
 import (
+	"time"
+
 	l4g "github.com/alecthomas/log4go"
 
 	"github.com/mattermost/platform/einterfaces"
@@ -103,7 +105,7 @@ func SetStatusOnline(userId string, sessionId string, manual bool) {
 		status = &model.Status{UserId: userId, Status: model.STATUS_ONLINE, Manual: false, LastActivityAt: model.GetMillis(), ActiveChannel: ""}
 		broadcast = true
 	} else {
-		if status.Manual && !manual {
+		if status.Manual && !manual && !isDNDOver(status) {
 			return // manually set status always overrides non-manual one
 		}
 
@@ -118,6 +120,7 @@ func SetStatusOnline(userId string, sessionId string, manual bool) {
 		status.Status = model.STATUS_ONLINE
 		status.Manual = false // for "online" there's no manual setting
 		status.LastActivityAt = model.GetMillis()
+		status.DNDEndTime = 0
 	}
 
 	AddStatusCache(status)
@@ -153,7 +156,7 @@ func SetStatusOnline(userId string, sessionId string, manual bool) {
 
 func SetStatusOffline(userId string, manual bool) {
 	status, err := GetStatus(userId)
-	if err == nil && status.Manual && !manual {
+	if err == nil && status.Manual && !manual && !isDNDOver(status) {
 		return // manually set status always overrides non-manual one
 	}
 
@@ -178,7 +181,7 @@ func SetStatusAwayIfNeeded(userId string, manual bool) {
 		status = &model.Status{UserId: userId, Status: model.STATUS_OFFLINE, Manual: manual, LastActivityAt: 0, ActiveChannel: ""}
 	}
 
-	if !manual && status.Manual {
+	if !manual && status.Manual && !isDNDOver(status) {
 		return // manually set status always overrides non-manual one
 	}
 
@@ -195,6 +198,7 @@ func SetStatusAwayIfNeeded(userId string, manual bool) {
 	status.Status = model.STATUS_AWAY
 	status.Manual = manual
 	status.ActiveChannel = ""
+	status.DNDEndTime = 0
 
 	AddStatusCache(status)
 
@@ -208,6 +212,57 @@ func SetStatusAwayIfNeeded(userId string, manual bool) {
 	go Publish(event)
 }
 
+// SetStatusDND puts the user in Do Not Disturb mode until the given time, or until they change their status if the
+// end time is zero.
+func SetStatusDND(userId string, endTime int64) {
+	status, err := GetStatus(userId)
+	if err != nil {
+		status = &model.Status{UserId: userId, Status: model.STATUS_OFFLINE, Manual: true, LastActivityAt: 0, ActiveChannel: ""}
+	}
+
+	status.Status = model.STATUS_DND
+	status.Manual = true
+	status.DNDEndTime = endTime
+
+	AddStatusCache(status)
+
+	if result := <-Srv.Store.Status().SaveOrUpdate(status); result.Err != nil {
+		l4g.Error(utils.T("api.status.save_status.error"), userId, result.Err)
+	}
+
+	event := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_STATUS_CHANGE, "", "", status.UserId, nil)
+	event.Add("status", model.STATUS_DND)
+	event.Add("user_id", status.UserId)
+	event.Add("dnd_end_time", endTime)
+	go Publish(event)
+}
+
+// isDNDOver returns whether the status is a Do Not Disturb mode that has reached its end time, which automatic
+// status changes are allowed to replace.
+func isDNDOver(status *model.Status) bool {
+	return status.Status == model.STATUS_DND && !status.IsDND(model.GetMillis())
+}
+
+// IsUserInDND returns whether notifications to the user should be held back, either because they turned on Do Not
+// Disturb or because it's within their quiet hours.
+func IsUserInDND(user *model.User, status *model.Status) bool {
+	if status != nil && status.IsDND(model.GetMillis()) {
+		return true
+	}
+
+	props := user.NotifyProps
+	if len(props[model.DND_SCHEDULE_TIMEZONE_NOTIFY_PROP]) == 0 && len(user.GetPreferredTimezone()) > 0 {
+		// Quiet hours without a timezone of their own follow the user's timezone
+		props = model.StringMap{}
+		for key, value := range user.NotifyProps {
+			props[key] = value
+		}
+		props[model.DND_SCHEDULE_TIMEZONE_NOTIFY_PROP] = user.GetPreferredTimezone()
+	}
+
+	return model.IsInDNDSchedule(props, time.Now())
+}
+
 func GetStatusFromCache(userId string) *model.Status {
 	if result, ok := statusCache.Get(userId); ok {
 		status := result.(*model.Status)
@@ -232,6 +287,29 @@ func GetStatus(userId string) (*model.Status, *model.AppError) {
 	}
 }
 
+// GetStatusForOthers returns the status of the user as other users should see it. The channel they're viewing is left
+// out, and a Do Not Disturb mode that has ended is reported as the status that the next automatic update will set.
+func GetStatusForOthers(userId string) (*model.Status, *model.AppError) {
+	status, err := GetStatus(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	status.ActiveChannel = ""
+
+	if isDNDOver(status) {
+		if IsUserAway(status.LastActivityAt) {
+			status.Status = model.STATUS_AWAY
+		} else {
+			status.Status = model.STATUS_ONLINE
+		}
+		status.Manual = false
+		status.DNDEndTime = 0
+	}
+
+	return status, nil
+}
+
 func IsUserAway(lastActivityAt int64) bool {
 	return model.GetMillis()-lastActivityAt >= *utils.Cfg.TeamSettings.UserStatusAwayTimeout*1000
 }
@@ -243,6 +321,10 @@ func DoesStatusAllowPushNotification(user *model.User, status *model.Status, cha
 		return false
 	}
 
+	if IsUserInDND(user, status) {
+		return false
+	}
+
 	if pushStatus, ok := props["push_status"]; (pushStatus == model.STATUS_ONLINE || !ok) && (status.ActiveChannel != channelId || model.GetMillis()-status.LastActivityAt > model.STATUS_CHANNEL_TIMEOUT) {
 		return true
 	} else if pushStatus == model.STATUS_AWAY && (status.Status == model.STATUS_AWAY || status.Status == model.STATUS_OFFLINE) {
@@ -0,0 +1,93 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mattermost/platform/model"
+)
+
+func TestDoesStatusAllowPushNotificationWithDND(t *testing.T) {
+	user := &model.User{Id: model.NewId(), NotifyProps: model.StringMap{"push": model.USER_NOTIFY_ALL, "push_status": model.STATUS_OFFLINE}}
+	channelId := model.NewId()
+
+	status := &model.Status{UserId: user.Id, Status: model.STATUS_OFFLINE}
+	if !DoesStatusAllowPushNotification(user, status, channelId) {
+		t.Fatal("should allow push notifications when offline")
+	}
+
+	status = &model.Status{UserId: user.Id, Status: model.STATUS_DND, Manual: true}
+	if DoesStatusAllowPushNotification(user, status, channelId) {
+		t.Fatal("shouldn't allow push notifications in do not disturb")
+	}
+
+	status.DNDEndTime = model.GetMillis() - 1000
+	if IsUserInDND(user, status) {
+		t.Fatal("do not disturb should be over")
+	}
+
+	status = &model.Status{UserId: user.Id, Status: model.STATUS_OFFLINE}
+	user.NotifyProps[model.DND_SCHEDULE_START_NOTIFY_PROP] = "00:00"
+	user.NotifyProps[model.DND_SCHEDULE_END_NOTIFY_PROP] = "23:59"
+	if DoesStatusAllowPushNotification(user, status, channelId) {
+		t.Fatal("shouldn't allow push notifications during quiet hours")
+	}
+}
+
+func TestIsUserInDNDWithTimezone(t *testing.T) {
+	location, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("timezone data isn't available")
+	}
+
+	// Quiet hours for the current hour in Tokyo, which is never the current hour in UTC
+	hour := time.Now().In(location).Hour()
+	user := &model.User{Id: model.NewId(), NotifyProps: model.StringMap{
+		model.DND_SCHEDULE_START_NOTIFY_PROP: fmt.Sprintf("%02d:00", hour),
+		model.DND_SCHEDULE_END_NOTIFY_PROP:   fmt.Sprintf("%02d:00", (hour+1)%24),
+	}}
+
+	if IsUserInDND(user, nil) {
+		t.Fatal("quiet hours should default to UTC")
+	}
+
+	user.Timezone = model.StringMap{model.USER_TIMEZONE_USE_AUTOMATIC: "false", model.USER_TIMEZONE_MANUAL: "Asia/Tokyo"}
+	if !IsUserInDND(user, nil) {
+		t.Fatal("quiet hours should follow the user's timezone")
+	}
+
+	if _, ok := user.NotifyProps[model.DND_SCHEDULE_TIMEZONE_NOTIFY_PROP]; ok {
+		t.Fatal("shouldn't have changed the user's notify props")
+	}
+
+	user.NotifyProps[model.DND_SCHEDULE_TIMEZONE_NOTIFY_PROP] = "UTC"
+	if IsUserInDND(user, nil) {
+		t.Fatal("the timezone of the quiet hours should take precedence")
+	}
+}
+
+func TestSetStatusDND(t *testing.T) {
+	th := Setup().InitBasic()
+
+	SetStatusDND(th.BasicUser.Id, model.GetMillis()+60*1000)
+
+	SetStatusAwayIfNeeded(th.BasicUser.Id, false)
+	if status, err := GetStatus(th.BasicUser.Id); err != nil {
+		t.Fatal(err)
+	} else if status.Status != model.STATUS_DND {
+		t.Fatal("automatic status changes shouldn't end do not disturb")
+	}
+
+	SetStatusDND(th.BasicUser.Id, model.GetMillis()-1000)
+
+	SetStatusOnline(th.BasicUser.Id, "", false)
+	if status, err := GetStatus(th.BasicUser.Id); err != nil {
+		t.Fatal(err)
+	} else if status.Status != model.STATUS_ONLINE || status.DNDEndTime != 0 {
+		t.Fatal("activity should end do not disturb once it's over")
+	}
+}
@@ -93,6 +93,9 @@ func UpdateTeam(team *model.Team) (*model.Team, *model.AppError) {
 	oldTeam.AllowOpenInvite = team.AllowOpenInvite
 	oldTeam.CompanyName = team.CompanyName
 	oldTeam.AllowedDomains = team.AllowedDomains
+	oldTeam.ChannelAutoArchiveDays = team.ChannelAutoArchiveDays
+	oldTeam.ChannelAutoArchiveGraceDays = team.ChannelAutoArchiveGraceDays
+	oldTeam.ChannelAutoArchiveExemptions = team.ChannelAutoArchiveExemptions
 
 	if result := <-Srv.Store.Team().Update(oldTeam); result.Err != nil {
 		return nil, result.Err
@@ -283,9 +286,11 @@ func JoinUserToTeam(team *model.Team, user *model.User) *model.AppError {
 		channelRole = model.ROLE_CHANNEL_USER.Id + " " + model.ROLE_CHANNEL_ADMIN.Id
 	}
 
-	// Soft error if there is an issue joining the default channels
-	if err := JoinDefaultChannels(team.Id, user, channelRole); err != nil {
-		l4g.Error(utils.T("api.user.create_user.joining.error"), user.Id, team.Id, err)
+	// Soft error if there is an issue joining the default channels. Guests only join the channels they're added to.
+	if !user.IsGuest() {
+		if err := JoinDefaultChannels(team.Id, user, channelRole); err != nil {
+			l4g.Error(utils.T("api.user.create_user.joining.error"), user.Id, team.Id, err)
+		}
 	}
 
 	ClearSessionCacheForUser(user.Id)
@@ -421,7 +426,7 @@ func LeaveTeam(team *model.Team, user *model.User) *model.AppError {
 	}
 
 	for _, channel := range *channelList {
-		if channel.Type != model.CHANNEL_DIRECT {
+		if !channel.IsGroupOrDirect() {
 			InvalidateCacheForChannelMembers(channel.Id)
 			if result := <-Srv.Store.Channel().RemoveMember(channel.Id, user.Id); result.Err != nil {
 				return result.Err
@@ -162,6 +162,10 @@ func CreateUser(user *model.User) (*model.User, *model.AppError) {
 
 	user.Roles = model.ROLE_SYSTEM_USER.Id
 
+	// Bots can only be created through CreateBot
+	user.IsBot = false
+	user.BotOwnerId = ""
+
 	// Below is a special case where the first user in the entire
 	// system is granted the system_admin role
 	if result := <-Srv.Store.User().GetTotalUsersCount(); result.Err != nil {
@@ -190,7 +194,11 @@ func CreateUser(user *model.User) (*model.User, *model.AppError) {
 func createUser(user *model.User) (*model.User, *model.AppError) {
 	user.MakeNonNil()
 
-	if err := utils.IsPasswordValid(user.Password); user.AuthService == "" && err != nil {
+	if err := utils.IsPasswordValid(user.Password); user.AuthService == "" && !user.IsBot && err != nil {
+		return nil, err
+	}
+
+	if err := checkUsernameIsFree(user.Username); err != nil {
 		return nil, err
 	}
 
@@ -298,7 +306,7 @@ func CheckUserDomain(user *model.User, domains string) bool {
 	return matched
 }
 
-// Check if the username is already used by another user. Return false if the username is invalid.
+// Check if the username is already used by another user or a group. Return false if the username is invalid.
 func IsUsernameTaken(name string) bool {
 
 	if !model.IsValidUsername(name) {
@@ -306,7 +314,7 @@ func IsUsernameTaken(name string) bool {
 	}
 
 	if result := <-Srv.Store.User().GetByUsername(name); result.Err != nil {
-		return false
+		return checkUsernameIsFree(name) != nil
 	}
 
 	return true
@@ -351,7 +359,7 @@ func GetUserByAuth(authData *string, authService string) (*model.User, *model.Ap
 }
 
 func GetUserForLogin(loginId string, onlyLdap bool) (*model.User, *model.AppError) {
-	ldapAvailable := *utils.Cfg.LdapSettings.Enable && einterfaces.GetLdapInterface() != nil && utils.IsLicensed && *utils.License.Features.LDAP
+	ldapAvailable := *utils.Cfg.LdapSettings.Enable && einterfaces.GetLdapInterface() != nil
 
 	if result := <-Srv.Store.User().GetForLogin(
 		loginId,
@@ -592,6 +600,23 @@ func DeactivateMfa(userId string) *model.AppError {
 	return nil
 }
 
+// GenerateMfaBackupCodes replaces the user's MFA backup codes with new ones and returns them.
+func GenerateMfaBackupCodes(userId string) ([]string, *model.AppError) {
+	mfaInterface := einterfaces.GetMfaInterface()
+	if mfaInterface == nil {
+		err := model.NewLocAppError("GenerateMfaBackupCodes", "api.user.update_mfa.not_available.app_error", nil, "")
+		err.StatusCode = http.StatusNotImplemented
+		return nil, err
+	}
+
+	user, err := GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return mfaInterface.GenerateBackupCodes(user)
+}
+
 func CreateProfileImage(username string, userId string) ([]byte, *model.AppError) {
 	colors := []color.NRGBA{
 		{197, 8, 126, 255},
@@ -865,11 +890,19 @@ func UpdateUserAsUser(user *model.User, siteURL string, asAdmin bool) (*model.Us
 }
 
 func UpdateUser(user *model.User, siteURL string, sendNotifications bool) (*model.User, *model.AppError) {
+	if err := checkUsernameIsFree(user.Username); err != nil {
+		if oldUser, getErr := GetUser(user.Id); getErr != nil || oldUser.Username != user.Username {
+			return nil, err
+		}
+	}
+
 	if result := <-Srv.Store.User().Update(user, false); result.Err != nil {
 		return nil, result.Err
 	} else {
 		rusers := result.Data.([2]*model.User)
 
+		recordUsernameChange(rusers[1], rusers[0])
+
 		if sendNotifications {
 			if rusers[0].Email != rusers[1].Email {
 				go func() {
@@ -1120,6 +1153,34 @@ func PermanentDeleteUser(user *model.User) *model.AppError {
 		return result.Err
 	}
 
+	if result := <-Srv.Store.Group().PermanentDeleteMembersByUser(user.Id); result.Err != nil {
+		return result.Err
+	}
+
+	if result := <-Srv.Store.SidebarCategory().PermanentDeleteByUser(user.Id); result.Err != nil {
+		return result.Err
+	}
+
+	if result := <-Srv.Store.CustomStatus().Delete(user.Id); result.Err != nil {
+		return result.Err
+	}
+
+	if result := <-Srv.Store.ProfileAttribute().PermanentDeleteValuesByUser(user.Id); result.Err != nil {
+		return result.Err
+	}
+
+	if result := <-Srv.Store.OutOfOffice().Delete(user.Id); result.Err != nil {
+		return result.Err
+	}
+
+	if result := <-Srv.Store.UsernameHistory().PermanentDeleteByUser(user.Id); result.Err != nil {
+		return result.Err
+	}
+
+	if result := <-Srv.Store.Mfa().PermanentDeleteByUser(user.Id); result.Err != nil {
+		return result.Err
+	}
+
 	if result := <-Srv.Store.Post().PermanentDeleteByUser(user.Id); result.Err != nil {
 		return result.Err
 	}
@@ -1208,6 +1269,14 @@ func AutocompleteUsersInChannel(teamId string, channelId string, term string, se
 		autocomplete.OutOfChannel = result.Data.([]*model.User)
 	}
 
+	if err := addCustomStatusesToUsers(autocomplete.InChannel); err != nil {
+		return nil, err
+	}
+
+	if err := addCustomStatusesToUsers(autocomplete.OutOfChannel); err != nil {
+		return nil, err
+	}
+
 	return autocomplete, nil
 }
 
@@ -0,0 +1,35 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
+)
+
+// CreateUserAccessToken creates a personal access token that authenticates as the user without a password or MFA.
+// The token of the returned session can't be retrieved again later.
+func CreateUserAccessToken(userId string, description string, expiresAt int64) (*model.Session, *model.AppError) {
+	if !*utils.Cfg.ServiceSettings.EnableUserAccessTokens {
+		return nil, model.NewAppError("CreateUserAccessToken", "app.user_access_token.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	user, err := GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return createTokenSession(user, model.SESSION_TYPE_USER_ACCESS_TOKEN, description, expiresAt)
+}
+
+// GetUserAccessTokens returns the personal access tokens of the user with the token values removed.
+func GetUserAccessTokens(userId string) ([]*model.Session, *model.AppError) {
+	return getTokenSessions(userId, model.SESSION_TYPE_USER_ACCESS_TOKEN)
+}
+
+func RevokeUserAccessToken(userId string, tokenId string) *model.AppError {
+	return revokeTokenSession(userId, tokenId, model.SESSION_TYPE_USER_ACCESS_TOKEN)
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"strings"
+
+	l4g "github.com/alecthomas/log4go"
+	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
+)
+
+// recordUsernameChange keeps track of the user's old username so that it can be redirected to them for a while. Failing
+// to record it isn't fatal since the username has already changed by then.
+func recordUsernameChange(oldUser *model.User, newUser *model.User) {
+	if oldUser.Username == newUser.Username {
+		return
+	}
+
+	history := &model.UsernameHistory{UserId: newUser.Id, OldUsername: oldUser.Username, NewUsername: newUser.Username}
+	if result := <-Srv.Store.UsernameHistory().Save(history); result.Err != nil {
+		l4g.Error(utils.T("app.username_history.record.error"), newUser.Id, result.Err.Error())
+	}
+}
+
+func GetUsernameHistory(userId string) ([]*model.UsernameHistory, *model.AppError) {
+	if result := <-Srv.Store.UsernameHistory().GetByUser(userId); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.([]*model.UsernameHistory), nil
+	}
+}
+
+// getUserIdsByOldUsernames returns a map from old username to the id of the user who gave it up within the configured
+// redirect period. Usernames that belong to someone now aren't redirected.
+func getUserIdsByOldUsernames(usernames []string) (map[string]string, *model.AppError) {
+	redirectDays := *utils.Cfg.TeamSettings.UsernameRedirectDays
+	if redirectDays == 0 || len(usernames) == 0 {
+		return map[string]string{}, nil
+	}
+
+	since := model.GetMillis() - int64(redirectDays)*24*60*60*1000
+
+	if result := <-Srv.Store.UsernameHistory().GetUserIdsByOldUsernames(usernames, since); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(map[string]string), nil
+	}
+}
+
+// GetUserByUsernameFollowingHistory returns the user with the username or, if nobody has it, the user who recently
+// changed away from it.
+func GetUserByUsernameFollowingHistory(username string) (*model.User, *model.AppError) {
+	user, err := GetUserByUsername(username)
+	if err == nil || err.StatusCode != http.StatusNotFound {
+		return user, err
+	}
+
+	if userIds, historyErr := getUserIdsByOldUsernames([]string{username}); historyErr != nil {
+		return nil, historyErr
+	} else if userId, ok := userIds[strings.ToLower(username)]; ok {
+		return GetUser(userId)
+	}
+
+	return nil, err
+}
+
+// addOldUsernameMentions adds the channel members who recently changed away from any of the potentially mentioned
+// usernames to the mentioned users, and returns the usernames that still couldn't be matched to a channel member.
+func addOldUsernameMentions(mentionedUserIds map[string]bool, potentialMentions []string, profileMap map[string]*model.User) []string {
+	userIds, err := getUserIdsByOldUsernames(potentialMentions)
+	if err != nil {
+		l4g.Warn(utils.T("app.username_history.mentions.error"), err.Error())
+		return potentialMentions
+	}
+
+	remaining := make([]string, 0, len(potentialMentions))
+	for _, username := range potentialMentions {
+		if userId, ok := userIds[strings.ToLower(username)]; ok {
+			if _, inChannel := profileMap[userId]; inChannel {
+				mentionedUserIds[userId] = true
+				continue
+			}
+		}
+
+		remaining = append(remaining, username)
+	}
+
+	return remaining
+}
@@ -33,6 +33,7 @@ type WebConn struct {
 	Locale                    string
 	AllChannelMembers         map[string]string
 	LastAllChannelMembersTime int64
+	guest                     bool
 }
 
 func NewWebConn(ws *websocket.Conn, session model.Session, t goi18n.TranslateFunc, locale string) *WebConn {
@@ -48,6 +49,7 @@ func NewWebConn(ws *websocket.Conn, session model.Session, t goi18n.TranslateFun
 		SessionExpiresAt: session.ExpiresAt,
 		T:                t,
 		Locale:           locale,
+		guest:            session.IsGuest(),
 	}
 }
 
@@ -166,6 +168,7 @@ func (webCon *WebConn) IsAuthenticated() bool {
 
 		webCon.SessionToken = session.Token
 		webCon.SessionExpiresAt = session.ExpiresAt
+		webCon.guest = session.IsGuest()
 	}
 
 	return true
@@ -228,6 +231,11 @@ func (webCon *WebConn) ShouldSendEvent(msg *model.WebSocketEvent) bool {
 		}
 	}
 
+	// Guests only get the events of their channels and the ones meant for them
+	if len(msg.Broadcast.UserId) == 0 && webCon.IsGuest() {
+		return false
+	}
+
 	// Only report events to users who are in the team for the event
 	if len(msg.Broadcast.TeamId) > 0 {
 		return webCon.IsMemberOfTeam(msg.Broadcast.TeamId)
@@ -252,3 +260,9 @@ func (webCon *WebConn) IsMemberOfTeam(teamId string) bool {
 		}
 	}
 }
+
+// IsGuest returns whether the connection belongs to a guest. It's looked up again along with the session whenever the
+// connection's cache is invalidated, such as when the user's roles change.
+func (webCon *WebConn) IsGuest() bool {
+	return webCon.guest
+}
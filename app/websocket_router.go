@@ -57,6 +57,7 @@ func (wr *WebSocketRouter) ServeWebSocket(conn *WebConn, r *model.WebSocketReque
 
 			conn.SessionToken = session.Token
 			conn.UserId = session.UserId
+			conn.guest = session.IsGuest()
 
 			resp := model.NewWebSocketResponse(model.STATUS_OK, r.Seq, nil)
 			resp.DoPreComputeJson()
@@ -0,0 +1,208 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+package main
+
+import (
+	"errors"
+
+	"github.com/mattermost/platform/app"
+	"github.com/mattermost/platform/model"
+	"github.com/spf13/cobra"
+)
+
+const LIST_BOTS_PAGE_SIZE = 200
+
+var botCmd = &cobra.Command{
+	Use:   "bot",
+	Short: "Management of bots",
+}
+
+var botCreateCmd = &cobra.Command{
+	Use:     "create",
+	Short:   "Create a bot",
+	Long:    "Create a bot owned by a user. Bots can't log in with a password and have to use tokens instead.",
+	Example: `  bot create --username buildbot --owner user@example.com --nickname "Build Bot"`,
+	RunE:    botCreateCmdF,
+}
+
+var botListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List bots",
+	Long:    "List the usernames of all bots, or of the bots owned by a user.",
+	Example: "  bot list --owner user@example.com",
+	RunE:    botListCmdF,
+}
+
+var botTokenCmd = &cobra.Command{
+	Use:     "token [bot]",
+	Short:   "Create a token for a bot",
+	Long:    "Create a token for a bot and print it. The token can't be retrieved again later.",
+	Example: "  bot token buildbot",
+	RunE:    botTokenCmdF,
+}
+
+var botAssignCmd = &cobra.Command{
+	Use:     "assign [bot] [owner]",
+	Short:   "Change the owner of a bot",
+	Long:    "Hand a bot over to another user.",
+	Example: "  bot assign buildbot user@example.com",
+	RunE:    botAssignCmdF,
+}
+
+var botDisableCmd = &cobra.Command{
+	Use:     "disable [bots]",
+	Short:   "Disable bots",
+	Long:    "Deactivate bots and revoke all of their tokens.",
+	Example: "  bot disable buildbot",
+	RunE:    botDisableCmdF,
+}
+
+func init() {
+	botCreateCmd.Flags().String("username", "", "Username")
+	botCreateCmd.Flags().String("owner", "", "Owner of the bot")
+	botCreateCmd.Flags().String("nickname", "", "Nickname")
+
+	botListCmd.Flags().String("owner", "", "Only list the bots of this user")
+
+	botCmd.AddCommand(
+		botCreateCmd,
+		botListCmd,
+		botTokenCmd,
+		botAssignCmd,
+		botDisableCmd,
+	)
+}
+
+func botCreateCmdF(cmd *cobra.Command, args []string) error {
+	initDBCommandContextCobra(cmd)
+
+	username, erru := cmd.Flags().GetString("username")
+	if erru != nil || username == "" {
+		return errors.New("Username is required")
+	}
+	ownerArg, erro := cmd.Flags().GetString("owner")
+	if erro != nil || ownerArg == "" {
+		return errors.New("Owner is required")
+	}
+	nickname, _ := cmd.Flags().GetString("nickname")
+
+	owner := getUserFromUserArg(ownerArg)
+	if owner == nil {
+		return errors.New("Unable to find user '" + ownerArg + "'")
+	}
+
+	bot := &model.User{
+		Username: username,
+		Nickname: nickname,
+	}
+
+	if _, err := app.CreateBot(bot, owner.Id); err != nil {
+		return errors.New("Unable to create bot. Error: " + err.Error())
+	}
+
+	CommandPrettyPrintln("Created Bot")
+
+	return nil
+}
+
+func botListCmdF(cmd *cobra.Command, args []string) error {
+	initDBCommandContextCobra(cmd)
+
+	ownerId := ""
+	if ownerArg, _ := cmd.Flags().GetString("owner"); ownerArg != "" {
+		owner := getUserFromUserArg(ownerArg)
+		if owner == nil {
+			return errors.New("Unable to find user '" + ownerArg + "'")
+		}
+		ownerId = owner.Id
+	}
+
+	for page := 0; ; page++ {
+		bots, err := app.GetBotsPage(ownerId, page, LIST_BOTS_PAGE_SIZE)
+		if err != nil {
+			return err
+		}
+
+		for _, bot := range bots {
+			if bot.DeleteAt > 0 {
+				CommandPrettyPrintln(bot.Username + " (disabled)")
+			} else {
+				CommandPrettyPrintln(bot.Username)
+			}
+		}
+
+		if len(bots) < LIST_BOTS_PAGE_SIZE {
+			break
+		}
+	}
+
+	return nil
+}
+
+func botTokenCmdF(cmd *cobra.Command, args []string) error {
+	initDBCommandContextCobra(cmd)
+
+	if len(args) != 1 {
+		return errors.New("Enter the bot to create a token for.")
+	}
+
+	bot := getUserFromUserArg(args[0])
+	if bot == nil {
+		return errors.New("Unable to find bot '" + args[0] + "'")
+	}
+
+	token, err := app.CreateBotToken(bot.Id)
+	if err != nil {
+		return err
+	}
+
+	// The token goes to stdout so that it can be captured by scripts
+	CommandPrintln(token.Token)
+
+	return nil
+}
+
+func botAssignCmdF(cmd *cobra.Command, args []string) error {
+	initDBCommandContextCobra(cmd)
+
+	if len(args) != 2 {
+		return errors.New("Incorrect number of arguments.")
+	}
+
+	bot := getUserFromUserArg(args[0])
+	if bot == nil {
+		return errors.New("Unable to find bot '" + args[0] + "'")
+	}
+
+	owner := getUserFromUserArg(args[1])
+	if owner == nil {
+		return errors.New("Unable to find user '" + args[1] + "'")
+	}
+
+	if _, err := app.UpdateBotOwner(bot.Id, owner.Id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func botDisableCmdF(cmd *cobra.Command, args []string) error {
+	initDBCommandContextCobra(cmd)
+
+	if len(args) < 1 {
+		return errors.New("Enter bot(s) to disable.")
+	}
+
+	bots := getUsersFromUserArgs(args)
+	for i, bot := range bots {
+		if bot == nil || !bot.IsBot {
+			CommandPrintErrorln("Can't find bot '" + args[i] + "'")
+			continue
+		}
+		if _, err := app.UpdateActive(bot, false); err != nil {
+			CommandPrintErrorln("Unable to disable bot '" + args[i] + "'. Error: " + err.Error())
+		}
+	}
+
+	return nil
+}
@@ -55,7 +55,7 @@ var listChannelsCmd = &cobra.Command{
 	Use:   "list [teams]",
 	Short: "List all channels on specified teams.",
 	Long: `List all channels on specified teams.
-Archived channels are appended with ' (archived)'.`,
+Deleted channels are appended with ' (deleted)' and read-only archived channels with ' (archived)'.`,
 	Example: "  channel list myteam",
 	RunE:    listChannelsCmdF,
 }
@@ -69,6 +69,52 @@ Channels can be specified by [team]:[channel]. ie. myteam:mychannel or by channe
 	RunE:    restoreChannelsCmdF,
 }
 
+var modifyChannelsCmd = &cobra.Command{
+	Use:   "modify [channels]",
+	Short: "Modify some channels",
+	Long: `Convert some channels between public and private while keeping their members and history, or change who can post in them.
+Channels can be specified by [team]:[channel]. ie. myteam:mychannel or by channel ID.`,
+	Example: `  channel modify --user admin --private myteam:mychannel
+  channel modify --user admin --public myteam:myprivatechannel
+  channel modify --posting_policy restricted myteam:announcements`,
+	RunE: modifyChannelsCmdF,
+}
+
+var archiveChannelsCmd = &cobra.Command{
+	Use:   "archive [channels]",
+	Short: "Archive some channels",
+	Long: `Make some channels read-only. Archived channels can still be read and searched by their members, but no one can post in them, join them or change their members.
+Channels can be specified by [team]:[channel]. ie. myteam:mychannel or by channel ID.`,
+	Example: "  channel archive --user admin myteam:mychannel",
+	RunE:    archiveChannelsCmdF,
+}
+
+var unarchiveChannelsCmd = &cobra.Command{
+	Use:   "unarchive [channels]",
+	Short: "Unarchive some channels",
+	Long: `Make some archived channels writable again.
+Channels can be specified by [team]:[channel]. ie. myteam:mychannel or by channel ID.`,
+	Example: "  channel unarchive --user admin myteam:mychannel",
+	RunE:    unarchiveChannelsCmdF,
+}
+
+var moveChannelCmd = &cobra.Command{
+	Use:   "move [channel] [team]",
+	Short: "Move a channel to another team",
+	Long: `Move a channel, along with its posts, files and webhooks, to another team. Members that don't belong to the destination team are removed from the channel unless --add_members is given, in which case they're added to the team.
+Channels can be specified by [team]:[channel]. ie. myteam:mychannel or by channel ID.`,
+	Example: "  channel move myteam:mychannel myotherteam",
+	RunE:    moveChannelCmdF,
+}
+
+var listGroupMessagesCmd = &cobra.Command{
+	Use:     "list_group_messages [users]",
+	Short:   "List the group messages of some users",
+	Long:    "List the group message channels that some users belong to, by the usernames of their members.",
+	Example: "  channel list_group_messages user@example.com username",
+	RunE:    listGroupMessagesCmdF,
+}
+
 func init() {
 	channelCreateCmd.Flags().String("name", "", "Channel Name")
 	channelCreateCmd.Flags().String("display_name", "", "Channel Display Name")
@@ -77,6 +123,16 @@ func init() {
 	channelCreateCmd.Flags().String("purpose", "", "Channel purpose")
 	channelCreateCmd.Flags().Bool("private", false, "Create a private channel.")
 
+	modifyChannelsCmd.Flags().Bool("private", false, "Convert the channels to private channels.")
+	modifyChannelsCmd.Flags().Bool("public", false, "Convert the channels to public channels.")
+	modifyChannelsCmd.Flags().String("user", "", "Username, email or ID of the user that the change is posted as")
+	modifyChannelsCmd.Flags().String("posting_policy", "", "Who can post in the channels: 'all' members, or only channel admins and channel announcers with 'restricted'.")
+
+	archiveChannelsCmd.Flags().String("user", "", "Username, email or ID of the user that the archive message is posted as")
+	unarchiveChannelsCmd.Flags().String("user", "", "Username, email or ID of the user that the unarchive message is posted as")
+
+	moveChannelCmd.Flags().Bool("add_members", false, "Add channel members that aren't on the destination team to it instead of removing them from the channel.")
+
 	channelCmd.AddCommand(
 		channelCreateCmd,
 		removeChannelUsersCmd,
@@ -84,6 +140,11 @@ func init() {
 		deleteChannelsCmd,
 		listChannelsCmd,
 		restoreChannelsCmd,
+		modifyChannelsCmd,
+		archiveChannelsCmd,
+		unarchiveChannelsCmd,
+		moveChannelCmd,
+		listGroupMessagesCmd,
 	)
 }
 
@@ -252,6 +313,8 @@ func listChannelsCmdF(cmd *cobra.Command, args []string) error {
 
 			for _, channel := range channels {
 				if channel.DeleteAt > 0 {
+					CommandPrettyPrintln(channel.Name + " (deleted)")
+				} else if channel.IsArchived() {
 					CommandPrettyPrintln(channel.Name + " (archived)")
 				} else {
 					CommandPrettyPrintln(channel.Name)
@@ -287,3 +350,164 @@ func restoreChannelsCmdF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func modifyChannelsCmdF(cmd *cobra.Command, args []string) error {
+	initDBCommandContextCobra(cmd)
+
+	if len(args) < 1 {
+		return errors.New("Enter at least one channel to modify.")
+	}
+
+	private, _ := cmd.Flags().GetBool("private")
+	public, _ := cmd.Flags().GetBool("public")
+	if private && public {
+		return errors.New("Only one of --private and --public can be set.")
+	}
+
+	postingPolicy, _ := cmd.Flags().GetString("posting_policy")
+	if postingPolicy != "" && !model.IsValidChannelPostingPolicy(postingPolicy) {
+		return errors.New("Posting policy must be either '" + model.CHANNEL_POSTING_POLICY_ALL + "' or '" + model.CHANNEL_POSTING_POLICY_RESTRICTED + "'.")
+	}
+
+	if !private && !public && postingPolicy == "" {
+		return errors.New("At least one of --private, --public and --posting_policy must be set.")
+	}
+
+	var user *model.User
+	if private || public {
+		var err error
+		if user, err = getUserFromUserFlag(cmd); err != nil {
+			return err
+		}
+	}
+
+	channelType := model.CHANNEL_OPEN
+	if private {
+		channelType = model.CHANNEL_PRIVATE
+	}
+
+	channels := getChannelsFromChannelArgs(args)
+	for i, channel := range channels {
+		if channel == nil {
+			CommandPrintErrorln("Unable to find channel '" + args[i] + "'")
+			continue
+		}
+		if postingPolicy != "" {
+			var err *model.AppError
+			if channel, err = app.UpdateChannelPostingPolicy(channel, postingPolicy); err != nil {
+				CommandPrintErrorln("Unable to modify channel '" + args[i] + "' error: " + err.Error())
+				continue
+			}
+		}
+		if private || public {
+			if _, err := app.UpdateChannelPrivacy(channel, channelType, user.Id); err != nil {
+				CommandPrintErrorln("Unable to modify channel '" + args[i] + "' error: " + err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+func archiveChannelsCmdF(cmd *cobra.Command, args []string) error {
+	initDBCommandContextCobra(cmd)
+
+	if len(args) < 1 {
+		return errors.New("Enter at least one channel to archive.")
+	}
+
+	user, err := getUserFromUserFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	channels := getChannelsFromChannelArgs(args)
+	for i, channel := range channels {
+		if channel == nil {
+			CommandPrintErrorln("Unable to find channel '" + args[i] + "'")
+			continue
+		}
+		if _, err := app.ArchiveChannel(channel, user.Id); err != nil {
+			CommandPrintErrorln("Unable to archive channel '" + args[i] + "' error: " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+func unarchiveChannelsCmdF(cmd *cobra.Command, args []string) error {
+	initDBCommandContextCobra(cmd)
+
+	if len(args) < 1 {
+		return errors.New("Enter at least one channel to unarchive.")
+	}
+
+	user, err := getUserFromUserFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	channels := getChannelsFromChannelArgs(args)
+	for i, channel := range channels {
+		if channel == nil {
+			CommandPrintErrorln("Unable to find channel '" + args[i] + "'")
+			continue
+		}
+		if _, err := app.UnarchiveChannel(channel, user.Id); err != nil {
+			CommandPrintErrorln("Unable to unarchive channel '" + args[i] + "' error: " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+func moveChannelCmdF(cmd *cobra.Command, args []string) error {
+	initDBCommandContextCobra(cmd)
+
+	if len(args) != 2 {
+		return errors.New("Enter a channel and a team.")
+	}
+
+	channel := getChannelFromChannelArg(args[0])
+	if channel == nil {
+		return errors.New("Unable to find channel '" + args[0] + "'")
+	}
+
+	team := getTeamFromTeamArg(args[1])
+	if team == nil {
+		return errors.New("Unable to find team '" + args[1] + "'")
+	}
+
+	addMembers, _ := cmd.Flags().GetBool("add_members")
+
+	if _, err := app.MoveChannel(channel, team, addMembers, ""); err != nil {
+		return errors.New("Unable to move channel '" + args[0] + "' error: " + err.Error())
+	}
+
+	return nil
+}
+
+func listGroupMessagesCmdF(cmd *cobra.Command, args []string) error {
+	initDBCommandContextCobra(cmd)
+
+	if len(args) < 1 {
+		return errors.New("Enter at least one user.")
+	}
+
+	users := getUsersFromUserArgs(args)
+	for i, user := range users {
+		if user == nil {
+			CommandPrintErrorln("Unable to find user '" + args[i] + "'")
+			continue
+		}
+		if result := <-app.Srv.Store.Channel().GetGroupChannelsForUser(user.Id); result.Err != nil {
+			CommandPrintErrorln("Unable to list group messages for '" + args[i] + "'")
+		} else {
+			for _, channel := range *result.Data.(*model.ChannelList) {
+				CommandPrettyPrintln(channel.Id + " " + channel.DisplayName)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+package main
+
+import (
+	"errors"
+
+	"github.com/mattermost/platform/app"
+	"github.com/mattermost/platform/model"
+	"github.com/spf13/cobra"
+)
+
+const LIST_GROUPS_PAGE_SIZE = 200
+
+var groupCmd = &cobra.Command{
+	Use:   "group",
+	Short: "Management of user groups",
+}
+
+var groupCreateCmd = &cobra.Command{
+	Use:     "create",
+	Short:   "Create a group",
+	Long:    `Create a group of users that can be mentioned with @name.`,
+	Example: `  group create --name oncall --display_name "On Call" --description "Whoever is on call this week"`,
+	RunE:    createGroupCmdF,
+}
+
+var deleteGroupsCmd = &cobra.Command{
+	Use:     "delete [groups]",
+	Short:   "Delete groups",
+	Long:    "Delete some groups along with their memberships. Groups can be specified by name or ID.",
+	Example: "  group delete oncall",
+	RunE:    deleteGroupsCmdF,
+}
+
+var addGroupUsersCmd = &cobra.Command{
+	Use:     "add [group] [users]",
+	Short:   "Add users to group",
+	Long:    "Add some users to group",
+	Example: "  group add oncall user@example.com username",
+	RunE:    addGroupUsersCmdF,
+}
+
+var removeGroupUsersCmd = &cobra.Command{
+	Use:     "remove [group] [users]",
+	Short:   "Remove users from group",
+	Long:    "Remove some users from group",
+	Example: "  group remove oncall user@example.com username",
+	RunE:    removeGroupUsersCmdF,
+}
+
+var listGroupsCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List all groups",
+	Long:    "List the names of all groups on the system.",
+	Example: "  group list",
+	RunE:    listGroupsCmdF,
+}
+
+func init() {
+	groupCreateCmd.Flags().String("name", "", "Group Name")
+	groupCreateCmd.Flags().String("display_name", "", "Group Display Name")
+	groupCreateCmd.Flags().String("description", "", "Group description")
+
+	groupCmd.AddCommand(
+		groupCreateCmd,
+		deleteGroupsCmd,
+		addGroupUsersCmd,
+		removeGroupUsersCmd,
+		listGroupsCmd,
+	)
+}
+
+func createGroupCmdF(cmd *cobra.Command, args []string) error {
+	initDBCommandContextCobra(cmd)
+
+	name, errn := cmd.Flags().GetString("name")
+	if errn != nil || name == "" {
+		return errors.New("Name is required")
+	}
+	displayname, errdn := cmd.Flags().GetString("display_name")
+	if errdn != nil || displayname == "" {
+		return errors.New("Display Name is required")
+	}
+	description, _ := cmd.Flags().GetString("description")
+
+	group := &model.Group{
+		Name:        name,
+		DisplayName: displayname,
+		Description: description,
+	}
+
+	if _, err := app.CreateGroup(group); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func deleteGroupsCmdF(cmd *cobra.Command, args []string) error {
+	initDBCommandContextCobra(cmd)
+
+	if len(args) < 1 {
+		return errors.New("Enter at least one group to delete.")
+	}
+
+	for _, groupArg := range args {
+		group := getGroupFromGroupArg(groupArg)
+		if group == nil {
+			CommandPrintErrorln("Unable to find group '" + groupArg + "'")
+			continue
+		}
+		if err := app.DeleteGroup(group.Id); err != nil {
+			CommandPrintErrorln("Unable to delete group '" + group.Name + "' error: " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+func addGroupUsersCmdF(cmd *cobra.Command, args []string) error {
+	initDBCommandContextCobra(cmd)
+
+	if len(args) < 2 {
+		return errors.New("Not enough arguments.")
+	}
+
+	group := getGroupFromGroupArg(args[0])
+	if group == nil {
+		return errors.New("Unable to find group '" + args[0] + "'")
+	}
+
+	users := getUsersFromUserArgs(args[1:])
+	for i, user := range users {
+		if user == nil {
+			CommandPrintErrorln("Can't find user '" + args[i+1] + "'")
+			continue
+		}
+		if _, err := app.AddGroupMember(group.Id, user.Id); err != nil {
+			CommandPrintErrorln("Unable to add '" + args[i+1] + "' to " + group.Name + ". Error: " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+func removeGroupUsersCmdF(cmd *cobra.Command, args []string) error {
+	initDBCommandContextCobra(cmd)
+
+	if len(args) < 2 {
+		return errors.New("Not enough arguments.")
+	}
+
+	group := getGroupFromGroupArg(args[0])
+	if group == nil {
+		return errors.New("Unable to find group '" + args[0] + "'")
+	}
+
+	users := getUsersFromUserArgs(args[1:])
+	for i, user := range users {
+		if user == nil {
+			CommandPrintErrorln("Can't find user '" + args[i+1] + "'")
+			continue
+		}
+		if err := app.RemoveGroupMember(group.Id, user.Id); err != nil {
+			CommandPrintErrorln("Unable to remove '" + args[i+1] + "' from " + group.Name + ". Error: " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+func listGroupsCmdF(cmd *cobra.Command, args []string) error {
+	initDBCommandContextCobra(cmd)
+
+	page := 0
+	for {
+		groups, err := app.GetGroups(page, LIST_GROUPS_PAGE_SIZE)
+		if err != nil {
+			return err
+		}
+
+		for _, group := range groups {
+			CommandPrettyPrintln(group.Name)
+		}
+
+		if len(groups) < LIST_GROUPS_PAGE_SIZE {
+			break
+		}
+		page++
+	}
+
+	return nil
+}
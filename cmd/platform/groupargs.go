@@ -0,0 +1,23 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+package main
+
+import (
+	"github.com/mattermost/platform/app"
+	"github.com/mattermost/platform/model"
+)
+
+func getGroupFromGroupArg(groupArg string) *model.Group {
+	var group *model.Group
+	if result := <-app.Srv.Store.Group().GetByName(groupArg); result.Err == nil {
+		group = result.Data.(*model.Group)
+	}
+
+	if group == nil {
+		if result := <-app.Srv.Store.Group().Get(groupArg); result.Err == nil {
+			group = result.Data.(*model.Group)
+		}
+	}
+
+	return group
+}
@@ -13,12 +13,10 @@ import (
 	"github.com/spf13/cobra"
 
 	// Plugins
+	_ "github.com/mattermost/platform/ldap"
+	_ "github.com/mattermost/platform/mfa"
 	_ "github.com/mattermost/platform/model/gitlab"
-
-	// Enterprise Deps
-	_ "github.com/dgryski/dgoogauth"
-	_ "github.com/go-ldap/ldap"
-	_ "github.com/mattermost/rsc/qr"
+	_ "github.com/mattermost/platform/model/openid"
 )
 
 //ENTERPRISE_IMPORTS
@@ -34,7 +32,7 @@ func main() {
 
 	resetCmd.Flags().Bool("confirm", false, "Confirm you really want to delete everything and a DB backup has been performed.")
 
-	rootCmd.AddCommand(serverCmd, versionCmd, userCmd, teamCmd, licenseCmd, importCmd, resetCmd, channelCmd, rolesCmd, testCmd, ldapCmd)
+	rootCmd.AddCommand(serverCmd, versionCmd, userCmd, teamCmd, licenseCmd, importCmd, resetCmd, channelCmd, rolesCmd, testCmd, ldapCmd, groupCmd, botCmd)
 
 	flag.Usage = func() {
 		rootCmd.Usage()
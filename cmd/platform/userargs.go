@@ -3,8 +3,11 @@
 package main
 
 import (
+	"errors"
+
 	"github.com/mattermost/platform/app"
 	"github.com/mattermost/platform/model"
+	"github.com/spf13/cobra"
 )
 
 func getUsersFromUserArgs(userArgs []string) []*model.User {
@@ -36,3 +39,18 @@ func getUserFromUserArg(userArg string) *model.User {
 
 	return user
 }
+
+// getUserFromUserFlag looks up the user given by the required --user flag of a command.
+func getUserFromUserFlag(cmd *cobra.Command) (*model.User, error) {
+	userArg, err := cmd.Flags().GetString("user")
+	if err != nil || userArg == "" {
+		return nil, errors.New("User is required")
+	}
+
+	user := getUserFromUserArg(userArg)
+	if user == nil {
+		return nil, errors.New("Unable to find user '" + userArg + "'")
+	}
+
+	return user, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/mattermost/platform/model"
 )
 
+// LdapInterface logs users in with LDAP. See app.SyncProfileAttributesFromDirectory for what DoLogin returns.
 type LdapInterface interface {
 	DoLogin(id string, password string) (*model.User, *model.AppError)
 	GetUser(id string) (*model.User, *model.AppError)
@@ -11,7 +11,8 @@ type MfaInterface interface {
 	GenerateSecret(user *model.User) (string, []byte, *model.AppError)
 	Activate(user *model.User, token string) *model.AppError
 	Deactivate(userId string) *model.AppError
-	ValidateToken(secret, token string) (bool, *model.AppError)
+	ValidateToken(user *model.User, token string) (bool, *model.AppError)
+	GenerateBackupCodes(user *model.User) ([]string, *model.AppError)
 }
 
 var theMfaInterface MfaInterface
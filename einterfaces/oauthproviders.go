@@ -14,6 +14,15 @@ type OauthProvider interface {
 	GetAuthDataFromJson(data io.Reader) string
 }
 
+// OpenIdProvider is implemented by OAuth providers that use OpenID Connect. Their endpoints are discovered from the
+// issuer instead of being configured, and the user data passed to GetUserFromJson comes from the validated ID token
+// rather than from a user API.
+type OpenIdProvider interface {
+	OauthProvider
+	GetEndpoints(sso *model.SSOSettings) (authEndpoint string, tokenEndpoint string, err *model.AppError)
+	GetUserData(sso *model.SSOSettings, accessToken string, idToken string, nonce string) ([]byte, *model.AppError)
+}
+
 var oauthProviders = make(map[string]OauthProvider)
 
 func RegisterOauthProvider(name string, newProvider OauthProvider) {
@@ -7,6 +7,7 @@ import (
 	"github.com/mattermost/platform/model"
 )
 
+// SamlInterface logs users in with SAML. See app.SyncProfileAttributesFromDirectory for what DoLogin returns.
 type SamlInterface interface {
 	ConfigureSP() *model.AppError
 	BuildRequest(relayState string) (*model.SamlAuthRequest, *model.AppError)
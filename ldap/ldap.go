@@ -0,0 +1,564 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package ldap
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	l4g "github.com/alecthomas/log4go"
+	"github.com/go-ldap/ldap"
+	"github.com/mattermost/platform/app"
+	"github.com/mattermost/platform/einterfaces"
+	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
+)
+
+const (
+	LDAP_SYNC_TASK_NAME = "LDAP Syncronization"
+)
+
+// Directory implements LDAP login and synchronization against the server in LdapSettings. Users are matched to their
+// directory entry by the IdAttribute, which is also what they log in with, and are created on their first login.
+type Directory struct {
+}
+
+func init() {
+	// An implementation that was registered already, such as the enterprise one, takes precedence
+	if einterfaces.GetLdapInterface() == nil {
+		einterfaces.RegisterLdapInterface(&Directory{})
+	}
+}
+
+func connect() (*ldap.Conn, *model.AppError) {
+	settings := utils.Cfg.LdapSettings
+
+	address := fmt.Sprintf("%v:%v", *settings.LdapServer, *settings.LdapPort)
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: *settings.SkipCertificateVerification,
+		ServerName:         *settings.LdapServer,
+	}
+
+	var conn *ldap.Conn
+	var err error
+	if *settings.ConnectionSecurity == model.CONN_SECURITY_TLS {
+		conn, err = ldap.DialTLS("tcp", address, tlsConfig)
+	} else {
+		conn, err = ldap.Dial("tcp", address)
+		if err == nil && *settings.ConnectionSecurity == model.CONN_SECURITY_STARTTLS {
+			if err = conn.StartTLS(tlsConfig); err != nil {
+				conn.Close()
+			}
+		}
+	}
+
+	if err != nil {
+		return nil, model.NewLocAppError("ldap.connect", "ldap.connect.app_error", nil, "address="+address+", "+err.Error())
+	}
+
+	conn.SetTimeout(time.Duration(*settings.QueryTimeout) * time.Second)
+
+	return conn, nil
+}
+
+// connectAndBind returns a connection that is bound as the user in BindUsername, which is used to look users up.
+func connectAndBind() (*ldap.Conn, *model.AppError) {
+	conn, err := connect()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.Bind(*utils.Cfg.LdapSettings.BindUsername, *utils.Cfg.LdapSettings.BindPassword); err != nil {
+		conn.Close()
+		return nil, model.NewLocAppError("ldap.connectAndBind", "ldap.bind_admin_user.app_error", nil, err.Error())
+	}
+
+	return conn, nil
+}
+
+// userFilter restricts the filter to the users allowed by the UserFilter setting, if there is one.
+func userFilter(filter string) string {
+	if userFilter := *utils.Cfg.LdapSettings.UserFilter; len(userFilter) > 0 {
+		return "(&" + filter + userFilter + ")"
+	}
+
+	return filter
+}
+
+// attributeMapping holds the directory attributes that users and their profile attributes are filled in from.
+type attributeMapping struct {
+	profileAttributes []*model.ProfileAttribute
+}
+
+func getAttributeMapping() (*attributeMapping, *model.AppError) {
+	attributes, err := app.GetProfileAttributes()
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := &attributeMapping{}
+	for _, attribute := range attributes {
+		if len(attribute.LdapAttribute) > 0 {
+			mapping.profileAttributes = append(mapping.profileAttributes, attribute)
+		}
+	}
+
+	return mapping, nil
+}
+
+// names returns the directory attributes to request in searches.
+func (m *attributeMapping) names() []string {
+	settings := utils.Cfg.LdapSettings
+
+	var names []string
+	for _, name := range []string{
+		*settings.IdAttribute,
+		*settings.UsernameAttribute,
+		*settings.EmailAttribute,
+		*settings.FirstNameAttribute,
+		*settings.LastNameAttribute,
+		*settings.NicknameAttribute,
+		*settings.PositionAttribute,
+	} {
+		if len(name) > 0 {
+			names = append(names, name)
+		}
+	}
+
+	for _, attribute := range m.profileAttributes {
+		names = append(names, attribute.LdapAttribute)
+	}
+
+	return names
+}
+
+func getAttributeValue(entry *ldap.Entry, name string) string {
+	if len(name) == 0 {
+		return ""
+	}
+
+	return strings.TrimSpace(entry.GetAttributeValue(name))
+}
+
+// toUser returns the user described by the directory entry, including the values of their synced profile attributes.
+func (m *attributeMapping) toUser(entry *ldap.Entry) *model.User {
+	settings := utils.Cfg.LdapSettings
+
+	authData := getAttributeValue(entry, *settings.IdAttribute)
+
+	user := &model.User{
+		Username:      model.CleanUsername(getAttributeValue(entry, *settings.UsernameAttribute)),
+		Email:         strings.ToLower(getAttributeValue(entry, *settings.EmailAttribute)),
+		FirstName:     getAttributeValue(entry, *settings.FirstNameAttribute),
+		LastName:      getAttributeValue(entry, *settings.LastNameAttribute),
+		Nickname:      getAttributeValue(entry, *settings.NicknameAttribute),
+		Position:      getAttributeValue(entry, *settings.PositionAttribute),
+		AuthService:   model.USER_AUTH_SERVICE_LDAP,
+		AuthData:      &authData,
+		EmailVerified: true,
+	}
+
+	user.ProfileAttributes = make(model.StringMap)
+	for _, attribute := range m.profileAttributes {
+		if value := getAttributeValue(entry, attribute.LdapAttribute); len(value) > 0 {
+			user.ProfileAttributes[attribute.Name] = value
+		}
+	}
+
+	return user
+}
+
+// search returns the entries that match the filter, paging the results if MaxPageSize is set.
+func search(conn *ldap.Conn, filter string, attributes []string) ([]*ldap.Entry, *model.AppError) {
+	settings := utils.Cfg.LdapSettings
+
+	request := ldap.NewSearchRequest(
+		*settings.BaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.DerefAlways,
+		0,
+		*settings.QueryTimeout,
+		false,
+		filter,
+		attributes,
+		nil,
+	)
+
+	var result *ldap.SearchResult
+	var err error
+	if *settings.MaxPageSize > 0 {
+		result, err = conn.SearchWithPaging(request, uint32(*settings.MaxPageSize))
+	} else {
+		result, err = conn.Search(request)
+	}
+
+	if err != nil {
+		return nil, model.NewLocAppError("ldap.search", "ldap.search.app_error", nil, "filter="+filter+", "+err.Error())
+	}
+
+	return result.Entries, nil
+}
+
+// findEntry returns the directory entry of the user with the given id.
+func findEntry(conn *ldap.Conn, id string, mapping *attributeMapping) (*ldap.Entry, *model.AppError) {
+	filter := userFilter("(" + *utils.Cfg.LdapSettings.IdAttribute + "=" + ldap.EscapeFilter(id) + ")")
+
+	entries, err := search(conn, filter, mapping.names())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, model.NewAppError("ldap.findEntry", "ldap.find_entry.not_found.app_error", nil, "id="+id, http.StatusNotFound)
+	} else if len(entries) > 1 {
+		return nil, model.NewAppError("ldap.findEntry", "ldap.find_entry.multiple.app_error", nil, "id="+id, http.StatusBadRequest)
+	}
+
+	return entries[0], nil
+}
+
+// checkPassword binds to the directory as the user. An empty password is rejected up front since most servers treat a
+// bind without one as an anonymous bind, which succeeds.
+func checkPassword(conn *ldap.Conn, entry *ldap.Entry, password string) *model.AppError {
+	if len(password) == 0 {
+		return model.NewAppError("ldap.checkPassword", "ldap.check_password.invalid.app_error", nil, "dn="+entry.DN, http.StatusUnauthorized)
+	}
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		return model.NewAppError("ldap.checkPassword", "ldap.check_password.invalid.app_error", nil, "dn="+entry.DN+", "+err.Error(), http.StatusUnauthorized)
+	}
+
+	return nil
+}
+
+func checkEnabled(where string) *model.AppError {
+	if !*utils.Cfg.LdapSettings.Enable {
+		return model.NewAppError(where, "ldap.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	return nil
+}
+
+// DoLogin checks the user's password against the directory and returns their account, creating it if this is their
+// first login and updating it with any changes made in the directory otherwise.
+func (d *Directory) DoLogin(id string, password string) (*model.User, *model.AppError) {
+	if err := checkEnabled("Directory.DoLogin"); err != nil {
+		return nil, err
+	}
+
+	mapping, err := getAttributeMapping()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := connectAndBind()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	entry, err := findEntry(conn, id, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkPassword(conn, entry, password); err != nil {
+		return nil, err
+	}
+
+	ldapUser := mapping.toUser(entry)
+
+	if result := <-app.Srv.Store.User().GetByAuth(ldapUser.AuthData, model.USER_AUTH_SERVICE_LDAP); result.Err != nil {
+		user, err := app.CreateUser(ldapUser)
+		if err != nil {
+			return nil, model.NewAppError("Directory.DoLogin", "ldap.do_login.create_user.app_error", nil, "id="+id+", "+err.Error(), http.StatusInternalServerError)
+		}
+
+		user.ProfileAttributes = ldapUser.ProfileAttributes
+		return user, nil
+	} else {
+		user := result.Data.(*model.User)
+
+		if updateFromDirectory(user, ldapUser) {
+			if user, err = app.UpdateUserFromDirectory(user); err != nil {
+				return nil, err
+			}
+		}
+
+		user.ProfileAttributes = ldapUser.ProfileAttributes
+		return user, nil
+	}
+}
+
+// updateFromDirectory copies the fields that come from the directory to the user and returns whether any changed.
+func updateFromDirectory(user *model.User, ldapUser *model.User) bool {
+	settings := utils.Cfg.LdapSettings
+	changed := false
+
+	update := func(field *string, value string, attribute string) {
+		// Fields whose attribute isn't mapped are left for users to fill in themselves
+		if len(attribute) > 0 && *field != value {
+			*field = value
+			changed = true
+		}
+	}
+
+	update(&user.Username, ldapUser.Username, *settings.UsernameAttribute)
+	update(&user.Email, ldapUser.Email, *settings.EmailAttribute)
+	update(&user.FirstName, ldapUser.FirstName, *settings.FirstNameAttribute)
+	update(&user.LastName, ldapUser.LastName, *settings.LastNameAttribute)
+	update(&user.Nickname, ldapUser.Nickname, *settings.NicknameAttribute)
+	update(&user.Position, ldapUser.Position, *settings.PositionAttribute)
+
+	return changed
+}
+
+// GetUser returns the account of the user with the given id. Users who haven't logged in yet don't have an account, in
+// which case an unsaved one is returned with the fields filled in from the directory.
+func (d *Directory) GetUser(id string) (*model.User, *model.AppError) {
+	if err := checkEnabled("Directory.GetUser"); err != nil {
+		return nil, err
+	}
+
+	mapping, err := getAttributeMapping()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := connectAndBind()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	entry, err := findEntry(conn, id, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	ldapUser := mapping.toUser(entry)
+
+	if result := <-app.Srv.Store.User().GetByAuth(ldapUser.AuthData, model.USER_AUTH_SERVICE_LDAP); result.Err == nil {
+		return result.Data.(*model.User), nil
+	}
+
+	return ldapUser, nil
+}
+
+func (d *Directory) CheckPassword(id string, password string) *model.AppError {
+	if err := checkEnabled("Directory.CheckPassword"); err != nil {
+		return err
+	}
+
+	mapping, err := getAttributeMapping()
+	if err != nil {
+		return err
+	}
+
+	conn, err := connectAndBind()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	entry, err := findEntry(conn, id, mapping)
+	if err != nil {
+		return err
+	}
+
+	return checkPassword(conn, entry, password)
+}
+
+// SwitchToLdap changes an email account to log in with the directory account that has the given id.
+func (d *Directory) SwitchToLdap(userId, ldapId, ldapPassword string) *model.AppError {
+	if err := checkEnabled("Directory.SwitchToLdap"); err != nil {
+		return err
+	}
+
+	mapping, err := getAttributeMapping()
+	if err != nil {
+		return err
+	}
+
+	conn, err := connectAndBind()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	entry, err := findEntry(conn, ldapId, mapping)
+	if err != nil {
+		return err
+	}
+
+	if err := checkPassword(conn, entry, ldapPassword); err != nil {
+		return err
+	}
+
+	ldapUser := mapping.toUser(entry)
+
+	if result := <-app.Srv.Store.User().GetByAuth(ldapUser.AuthData, model.USER_AUTH_SERVICE_LDAP); result.Err == nil {
+		return model.NewAppError("Directory.SwitchToLdap", "ldap.switch_to_ldap.already_used.app_error", nil, "user_id="+userId, http.StatusBadRequest)
+	}
+
+	if result := <-app.Srv.Store.User().UpdateAuthData(userId, model.USER_AUTH_SERVICE_LDAP, ldapUser.AuthData, ldapUser.Email, false); result.Err != nil {
+		return result.Err
+	}
+
+	app.InvalidateCacheForUser(userId)
+
+	return nil
+}
+
+func (d *Directory) ValidateFilter(filter string) *model.AppError {
+	if _, err := ldap.CompileFilter(filter); err != nil {
+		return model.NewAppError("Directory.ValidateFilter", "ldap.validate_filter.app_error", nil, "filter="+filter+", "+err.Error(), http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// Syncronize updates the accounts of LDAP users with the changes made in the directory. Users who no longer match the
+// user filter are deactivated, and deactivated users who match it again are reactivated.
+func (d *Directory) Syncronize() *model.AppError {
+	if err := checkEnabled("Directory.Syncronize"); err != nil {
+		return err
+	}
+
+	ldapUsers, err := d.GetAllLdapUsers()
+	if err != nil {
+		return err
+	}
+
+	ldapUsersById := make(map[string]*model.User, len(ldapUsers))
+	for _, ldapUser := range ldapUsers {
+		ldapUsersById[*ldapUser.AuthData] = ldapUser
+	}
+
+	var users []*model.User
+	if result := <-app.Srv.Store.User().GetAllUsingAuthService(model.USER_AUTH_SERVICE_LDAP); result.Err != nil {
+		return result.Err
+	} else {
+		users = result.Data.([]*model.User)
+	}
+
+	// A search that finds nobody is much more likely to come from a wrong base DN or user filter than from everyone
+	// having left, so nobody is deactivated rather than every LDAP user getting logged out
+	if len(ldapUsers) == 0 {
+		for _, user := range users {
+			if user.DeleteAt == 0 {
+				return model.NewLocAppError("Directory.Syncronize", "ldap.syncronize.no_users.app_error", nil, "")
+			}
+		}
+	}
+
+	updated := 0
+	deactivated := 0
+	reactivated := 0
+
+	for _, user := range users {
+		ldapUser, ok := ldapUsersById[*user.AuthData]
+
+		if !ok {
+			if user.DeleteAt == 0 {
+				if _, err := app.UpdateActive(user, false); err != nil {
+					l4g.Error(utils.T("ldap.syncronize.deactivate.error"), user.Id, err.Error())
+				} else {
+					deactivated++
+				}
+			}
+
+			continue
+		}
+
+		if updateFromDirectory(user, ldapUser) {
+			if _, err := app.UpdateUserFromDirectory(user); err != nil {
+				l4g.Error(utils.T("ldap.syncronize.update.error"), user.Id, err.Error())
+				continue
+			}
+			updated++
+		}
+
+		user.ProfileAttributes = ldapUser.ProfileAttributes
+		if err := app.SyncProfileAttributesFromDirectory(user); err != nil {
+			l4g.Error(utils.T("ldap.syncronize.update.error"), user.Id, err.Error())
+		}
+
+		if user.DeleteAt > 0 {
+			if _, err := app.UpdateActive(user, true); err != nil {
+				l4g.Error(utils.T("ldap.syncronize.update.error"), user.Id, err.Error())
+			} else {
+				reactivated++
+			}
+		}
+	}
+
+	l4g.Info(utils.T("ldap.syncronize.done.info"), updated, deactivated, reactivated)
+
+	return nil
+}
+
+// StartLdapSyncJob schedules synchronization every SyncIntervalMinutes. It's called whenever the config is loaded, so
+// it replaces the job that was scheduled for the previous config.
+func (d *Directory) StartLdapSyncJob() {
+	if task := model.GetTaskByName(LDAP_SYNC_TASK_NAME); task != nil {
+		task.Cancel()
+	}
+
+	if !*utils.Cfg.LdapSettings.Enable {
+		return
+	}
+
+	model.CreateRecurringTask(LDAP_SYNC_TASK_NAME, d.SyncNow, time.Duration(*utils.Cfg.LdapSettings.SyncIntervalMinutes)*time.Minute)
+}
+
+func (d *Directory) SyncNow() {
+	if err := d.Syncronize(); err != nil {
+		l4g.Error(utils.T("ldap.syncronize.error"), err.Error())
+	}
+}
+
+// RunTest checks that the server can be reached with the bind credentials and that the user filter is valid.
+func (d *Directory) RunTest() *model.AppError {
+	conn, err := connectAndBind()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if userFilter := *utils.Cfg.LdapSettings.UserFilter; len(userFilter) > 0 {
+		return d.ValidateFilter(userFilter)
+	}
+
+	return nil
+}
+
+// GetAllLdapUsers returns the users described by every entry that matches the user filter and has an id.
+func (d *Directory) GetAllLdapUsers() ([]*model.User, *model.AppError) {
+	mapping, err := getAttributeMapping()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := connectAndBind()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	entries, err := search(conn, userFilter("("+*utils.Cfg.LdapSettings.IdAttribute+"=*)"), mapping.names())
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*model.User, 0, len(entries))
+	for _, entry := range entries {
+		users = append(users, mapping.toUser(entry))
+	}
+
+	return users, nil
+}
@@ -0,0 +1,304 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package ldap
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-ldap/ldap"
+	ber "gopkg.in/asn1-ber.v1"
+)
+
+type testEntry struct {
+	dn         string
+	password   string
+	attributes map[string]string
+}
+
+// testDirectory is an in-process LDAP server that supports just enough of the protocol for the tests: simple binds and
+// searches with paging, filtered by the AND, OR, NOT, equality and presence filters. Attribute names and values are
+// compared case-insensitively, as they are by most real servers.
+type testDirectory struct {
+	listener net.Listener
+
+	mutex     sync.Mutex
+	entries   []*testEntry
+	pageSizes []uint32
+}
+
+func newTestDirectory(t *testing.T) *testDirectory {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &testDirectory{listener: listener}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go d.serve(conn)
+		}
+	}()
+
+	return d
+}
+
+func (d *testDirectory) Close() {
+	d.listener.Close()
+}
+
+func (d *testDirectory) Port() int {
+	return d.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (d *testDirectory) Add(dn string, password string, attributes map[string]string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.entries = append(d.entries, &testEntry{dn: dn, password: password, attributes: attributes})
+}
+
+func (d *testDirectory) Remove(dn string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for i, entry := range d.entries {
+		if entry.dn == dn {
+			d.entries = append(d.entries[:i], d.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+func (d *testDirectory) SetAttribute(dn string, name string, value string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for _, entry := range d.entries {
+		if entry.dn == dn {
+			entry.attributes[name] = value
+		}
+	}
+}
+
+// PageSizes returns the page sizes that searches asked for, which is 0 for searches that weren't paged.
+func (d *testDirectory) PageSizes() []uint32 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return append([]uint32{}, d.pageSizes...)
+}
+
+func (d *testDirectory) serve(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+
+		messageId := packet.Children[0].Value.(int64)
+		request := packet.Children[1]
+
+		var responses []*ber.Packet
+		switch request.Tag {
+		case ldap.ApplicationBindRequest:
+			responses = []*ber.Packet{d.bind(messageId, request)}
+		case ldap.ApplicationSearchRequest:
+			var controls *ber.Packet
+			if len(packet.Children) > 2 {
+				controls = packet.Children[2]
+			}
+			responses = d.search(messageId, request, controls)
+		case ldap.ApplicationUnbindRequest:
+			return
+		default:
+			responses = []*ber.Packet{newResult(messageId, ldap.ApplicationBindResponse, ldap.LDAPResultProtocolError)}
+		}
+
+		for _, response := range responses {
+			if _, err := conn.Write(response.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func newMessage(messageId int64) *ber.Packet {
+	message := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	message.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageId, "MessageID"))
+	return message
+}
+
+func newResult(messageId int64, tag ber.Tag, code int64) *ber.Packet {
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "Result Code"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+
+	message := newMessage(messageId)
+	message.AppendChild(result)
+	return message
+}
+
+func (d *testDirectory) bind(messageId int64, request *ber.Packet) *ber.Packet {
+	dn := request.Children[1].Value.(string)
+	password := string(request.Children[2].Data.Bytes())
+
+	// Like real servers, a bind without a password is an anonymous bind and succeeds whatever the DN
+	if len(password) == 0 {
+		return newResult(messageId, ldap.ApplicationBindResponse, ldap.LDAPResultSuccess)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for _, entry := range d.entries {
+		if entry.dn == dn && entry.password == password {
+			return newResult(messageId, ldap.ApplicationBindResponse, ldap.LDAPResultSuccess)
+		}
+	}
+
+	return newResult(messageId, ldap.ApplicationBindResponse, ldap.LDAPResultInvalidCredentials)
+}
+
+func (d *testDirectory) search(messageId int64, request *ber.Packet, controls *ber.Packet) []*ber.Packet {
+	filter := request.Children[6]
+
+	var attributes []string
+	for _, attribute := range request.Children[7].Children {
+		attributes = append(attributes, attribute.Value.(string))
+	}
+
+	var paging *ldap.ControlPaging
+	if controls != nil {
+		for _, control := range controls.Children {
+			if c, ok := ldap.DecodeControl(control).(*ldap.ControlPaging); ok {
+				paging = c
+			}
+		}
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var matches []*testEntry
+	for _, entry := range d.entries {
+		if entry.matches(filter) {
+			matches = append(matches, entry)
+		}
+	}
+
+	// The paging cookie is the offset of the next page
+	var next int
+	if paging != nil {
+		d.pageSizes = append(d.pageSizes, paging.PagingSize)
+
+		offset, _ := strconv.Atoi(string(paging.Cookie))
+		if offset > len(matches) || paging.PagingSize == 0 {
+			offset = len(matches)
+		}
+		matches = matches[offset:]
+
+		if len(matches) > int(paging.PagingSize) {
+			matches = matches[:paging.PagingSize]
+			next = offset + len(matches)
+		}
+	} else {
+		d.pageSizes = append(d.pageSizes, 0)
+	}
+
+	var responses []*ber.Packet
+	for _, entry := range matches {
+		responses = append(responses, entry.toPacket(messageId, attributes))
+	}
+
+	done := newResult(messageId, ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess)
+	if paging != nil {
+		control := &ldap.ControlPaging{PagingSize: paging.PagingSize}
+		if next > 0 {
+			control.SetCookie([]byte(strconv.Itoa(next)))
+		}
+
+		responseControls := ber.Encode(ber.ClassContext, ber.TypeConstructed, 0, nil, "Controls")
+		responseControls.AppendChild(control.Encode())
+		done.AppendChild(responseControls)
+	}
+
+	return append(responses, done)
+}
+
+func (e *testEntry) getAttribute(name string) (string, bool) {
+	for key, value := range e.attributes {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+func (e *testEntry) matches(filter *ber.Packet) bool {
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !e.matches(child) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if e.matches(child) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return !e.matches(filter.Children[0])
+	case ldap.FilterEqualityMatch:
+		value, ok := e.getAttribute(string(filter.Children[0].Data.Bytes()))
+		return ok && strings.EqualFold(value, string(filter.Children[1].Data.Bytes()))
+	case ldap.FilterPresent:
+		_, ok := e.getAttribute(string(filter.Data.Bytes()))
+		return ok
+	default:
+		return false
+	}
+}
+
+func (e *testEntry) toPacket(messageId int64, attributes []string) *ber.Packet {
+	entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "DN"))
+
+	attributesPacket := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for _, name := range attributes {
+		// Attributes are returned with the name that was asked for since the client compares names case-sensitively
+		if value, ok := e.getAttribute(name); ok {
+			attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+			attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+
+			values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+			values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+			attribute.AppendChild(values)
+
+			attributesPacket.AppendChild(attribute)
+		}
+	}
+	entry.AppendChild(attributesPacket)
+
+	message := newMessage(messageId)
+	message.AppendChild(entry)
+	return message
+}
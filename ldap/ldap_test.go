@@ -0,0 +1,329 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package ldap
+
+import (
+	"testing"
+
+	"github.com/mattermost/platform/app"
+	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
+)
+
+const (
+	TEST_BASE_DN       = "dc=example,dc=com"
+	TEST_BIND_DN       = "cn=admin,dc=example,dc=com"
+	TEST_BIND_PASSWORD = "adminpassword"
+	TEST_PASSWORD      = "userpassword"
+)
+
+// setupDirectory points the LDAP settings at a new test directory and returns it along with a function that restores
+// the original settings.
+func setupDirectory(t *testing.T) (*testDirectory, func()) {
+	app.Setup()
+
+	d := newTestDirectory(t)
+	d.Add(TEST_BIND_DN, TEST_BIND_PASSWORD, map[string]string{"cn": "admin"})
+
+	str := func(s string) *string { return &s }
+	num := func(n int) *int { return &n }
+	enable := true
+	skipCertificateVerification := false
+
+	settings := utils.Cfg.LdapSettings
+	utils.Cfg.LdapSettings = model.LdapSettings{
+		Enable:                      &enable,
+		LdapServer:                  str("127.0.0.1"),
+		LdapPort:                    num(d.Port()),
+		ConnectionSecurity:          str(""),
+		BaseDN:                      str(TEST_BASE_DN),
+		BindUsername:                str(TEST_BIND_DN),
+		BindPassword:                str(TEST_BIND_PASSWORD),
+		UserFilter:                  str(""),
+		FirstNameAttribute:          str("givenName"),
+		LastNameAttribute:           str("sn"),
+		EmailAttribute:              str("mail"),
+		UsernameAttribute:           str("uid"),
+		NicknameAttribute:           str(""),
+		IdAttribute:                 str("employeeID"),
+		PositionAttribute:           str("title"),
+		SyncIntervalMinutes:         num(60),
+		SkipCertificateVerification: &skipCertificateVerification,
+		QueryTimeout:                num(10),
+		MaxPageSize:                 num(0),
+		LoginFieldName:              str(""),
+	}
+
+	return d, func() {
+		utils.Cfg.LdapSettings = settings
+		d.Close()
+	}
+}
+
+// addTestUser adds a user with a unique id and username to the directory and returns their id and DN.
+func addTestUser(d *testDirectory, title string) (string, string) {
+	id := model.NewId()
+	username := "ldap" + id[:10]
+	dn := "uid=" + username + ",ou=users," + TEST_BASE_DN
+
+	d.Add(dn, TEST_PASSWORD, map[string]string{
+		"employeeID":       id,
+		"uid":              username,
+		"mail":             username + "@EXAMPLE.com",
+		"givenName":        "Test",
+		"sn":               "User",
+		"title":            title,
+		"departmentNumber": "R&D",
+	})
+
+	return id, dn
+}
+
+func TestDoLogin(t *testing.T) {
+	d, teardown := setupDirectory(t)
+	defer teardown()
+
+	attribute, err := app.CreateProfileAttribute(&model.ProfileAttribute{
+		Name:          "department" + model.NewId()[:10],
+		DisplayName:   "Department",
+		Type:          model.PROFILE_ATTRIBUTE_TYPE_TEXT,
+		Visibility:    model.PROFILE_ATTRIBUTE_VISIBILITY_ALWAYS,
+		LdapAttribute: "departmentNumber",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.DeleteProfileAttribute(attribute.Id)
+
+	id, dn := addTestUser(d, "Engineer")
+	directory := &Directory{}
+
+	user, err := directory.DoLogin(id, TEST_PASSWORD)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.Username != "ldap"+id[:10] || user.Email != "ldap"+id[:10]+"@example.com" {
+		t.Fatal("should have mapped the username and email", user.Username, user.Email)
+	} else if user.FirstName != "Test" || user.LastName != "User" || user.Position != "Engineer" {
+		t.Fatal("should have mapped the name and position")
+	} else if user.AuthService != model.USER_AUTH_SERVICE_LDAP || !user.EmailVerified {
+		t.Fatal("should have created an LDAP user")
+	} else if user.ProfileAttributes[attribute.Name] != "R&D" {
+		t.Fatal("should have mapped the profile attribute", user.ProfileAttributes)
+	}
+
+	d.SetAttribute(dn, "title", "Manager")
+
+	if user2, err := directory.DoLogin(id, TEST_PASSWORD); err != nil {
+		t.Fatal(err)
+	} else if user2.Id != user.Id {
+		t.Fatal("should have logged in to the same account")
+	} else if user2.Position != "Manager" {
+		t.Fatal("should have updated the position")
+	}
+
+	if _, err := directory.DoLogin(id, "wrongpassword"); err == nil {
+		t.Fatal("should have failed - wrong password")
+	}
+
+	if _, err := directory.DoLogin(id, ""); err == nil {
+		t.Fatal("should have failed - empty password")
+	}
+
+	if _, err := directory.DoLogin(model.NewId(), TEST_PASSWORD); err == nil {
+		t.Fatal("should have failed - unknown user")
+	}
+
+	if _, err := directory.DoLogin("*", TEST_PASSWORD); err == nil {
+		t.Fatal("should have failed - the id should be escaped")
+	}
+
+	*utils.Cfg.LdapSettings.UserFilter = "(title=Engineer)"
+
+	if _, err := directory.DoLogin(id, TEST_PASSWORD); err == nil {
+		t.Fatal("should have failed - excluded by the user filter")
+	}
+
+	*utils.Cfg.LdapSettings.Enable = false
+
+	if _, err := directory.DoLogin(id, TEST_PASSWORD); err == nil {
+		t.Fatal("should have failed - ldap disabled")
+	}
+}
+
+func TestGetUserAndCheckPassword(t *testing.T) {
+	d, teardown := setupDirectory(t)
+	defer teardown()
+
+	id, _ := addTestUser(d, "Engineer")
+	directory := &Directory{}
+
+	if user, err := directory.GetUser(id); err != nil {
+		t.Fatal(err)
+	} else if len(user.Id) != 0 || user.Username != "ldap"+id[:10] {
+		t.Fatal("should have returned an unsaved user from the directory")
+	}
+
+	if err := directory.CheckPassword(id, TEST_PASSWORD); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := directory.CheckPassword(id, "wrongpassword"); err == nil {
+		t.Fatal("should have failed - wrong password")
+	}
+
+	user, err := directory.DoLogin(id, TEST_PASSWORD)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user2, err := directory.GetUser(id); err != nil {
+		t.Fatal(err)
+	} else if user2.Id != user.Id {
+		t.Fatal("should have returned the saved user")
+	}
+}
+
+func TestSyncronize(t *testing.T) {
+	d, teardown := setupDirectory(t)
+	defer teardown()
+
+	*utils.Cfg.LdapSettings.MaxPageSize = 2
+
+	directory := &Directory{}
+
+	var ids []string
+	var dns []string
+	var users []*model.User
+	for i := 0; i < 3; i++ {
+		id, dn := addTestUser(d, "Engineer")
+
+		user, err := directory.DoLogin(id, TEST_PASSWORD)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids = append(ids, id)
+		dns = append(dns, dn)
+		users = append(users, user)
+	}
+
+	d.SetAttribute(dns[0], "uid", "renamed"+ids[0][:10])
+	d.Remove(dns[1])
+
+	if err := directory.Syncronize(); err != nil {
+		t.Fatal(err)
+	}
+
+	paged := 0
+	for _, pageSize := range d.PageSizes() {
+		if pageSize == 2 {
+			paged++
+		}
+	}
+	if paged < 2 {
+		t.Fatal("should have paged the search", d.PageSizes())
+	}
+
+	if user, err := app.GetUser(users[0].Id); err != nil {
+		t.Fatal(err)
+	} else if user.Username != "renamed"+ids[0][:10] {
+		t.Fatal("should have updated the username")
+	}
+
+	if history, err := app.GetUsernameHistory(users[0].Id); err != nil {
+		t.Fatal(err)
+	} else if len(history) != 1 || history[0].OldUsername != users[0].Username {
+		t.Fatal("should have recorded the username change")
+	}
+
+	if user, err := app.GetUser(users[1].Id); err != nil {
+		t.Fatal(err)
+	} else if user.DeleteAt == 0 {
+		t.Fatal("should have deactivated the removed user")
+	}
+
+	if user, err := app.GetUser(users[2].Id); err != nil {
+		t.Fatal(err)
+	} else if user.DeleteAt != 0 || user.Username != users[2].Username {
+		t.Fatal("shouldn't have changed the other user")
+	}
+
+	d.Add(dns[1], TEST_PASSWORD, map[string]string{
+		"employeeID": ids[1],
+		"uid":        users[1].Username,
+		"mail":       users[1].Email,
+		"title":      "Engineer",
+	})
+
+	if err := directory.Syncronize(); err != nil {
+		t.Fatal(err)
+	}
+
+	if user, err := app.GetUser(users[1].Id); err != nil {
+		t.Fatal(err)
+	} else if user.DeleteAt != 0 {
+		t.Fatal("should have reactivated the user")
+	}
+
+	// A user filter that matches nobody shouldn't deactivate everyone
+	*utils.Cfg.LdapSettings.UserFilter = "(title=Nobody)"
+
+	if err := directory.Syncronize(); err == nil {
+		t.Fatal("should have failed - no users found")
+	}
+
+	for _, user := range users {
+		if user, err := app.GetUser(user.Id); err != nil {
+			t.Fatal(err)
+		} else if user.DeleteAt != 0 {
+			t.Fatal("shouldn't have deactivated anyone")
+		}
+	}
+
+	*utils.Cfg.LdapSettings.UserFilter = "(!(employeeID=" + ids[2] + "))"
+
+	if err := directory.Syncronize(); err != nil {
+		t.Fatal(err)
+	}
+
+	if user, err := app.GetUser(users[2].Id); err != nil {
+		t.Fatal(err)
+	} else if user.DeleteAt == 0 {
+		t.Fatal("should have deactivated the user excluded by the user filter")
+	}
+}
+
+func TestRunTestAndValidateFilter(t *testing.T) {
+	_, teardown := setupDirectory(t)
+	defer teardown()
+
+	directory := &Directory{}
+
+	if err := directory.RunTest(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := directory.ValidateFilter("(&(objectClass=user)(memberOf=cn=users))"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := directory.ValidateFilter("(objectClass=user"); err == nil {
+		t.Fatal("should have failed - invalid filter")
+	}
+
+	*utils.Cfg.LdapSettings.UserFilter = "objectClass=user)"
+
+	if err := directory.RunTest(); err == nil {
+		t.Fatal("should have failed - invalid user filter")
+	}
+
+	*utils.Cfg.LdapSettings.UserFilter = ""
+	*utils.Cfg.LdapSettings.BindPassword = "wrongpassword"
+
+	if err := directory.RunTest(); err == nil {
+		t.Fatal("should have failed - wrong bind password")
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package mfa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dgryski/dgoogauth"
+	"github.com/mattermost/platform/app"
+	"github.com/mattermost/platform/einterfaces"
+	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
+	"github.com/mattermost/rsc/qr"
+)
+
+const (
+	SECRET_LENGTH      = 20 // bytes, as recommended by RFC 4226
+	TOKEN_LENGTH       = 6
+	TIME_STEP_SECONDS  = 30
+	TIME_STEP_WINDOW   = 1 // tokens from the time steps either side of the current one are accepted to allow for clock drift
+	BACKUP_CODE_COUNT  = 10
+	BACKUP_CODE_LENGTH = 10
+)
+
+// TotpMfa implements multi-factor authentication with the time-based one-time passwords of RFC 6238 that apps such as
+// Google Authenticator generate. Each token can only be used once, and users can generate one-time backup codes to use
+// when they don't have their phone.
+type TotpMfa struct {
+}
+
+func init() {
+	// An implementation that was registered already, such as the enterprise one, takes precedence
+	if einterfaces.GetMfaInterface() == nil {
+		einterfaces.RegisterMfaInterface(&TotpMfa{})
+	}
+}
+
+func checkEnabled(where string) *model.AppError {
+	if !*utils.Cfg.ServiceSettings.EnableMultifactorAuthentication {
+		return model.NewAppError(where, "mfa.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	return nil
+}
+
+// GenerateSecret gives the user a new secret and returns it along with a PNG of a QR code that authenticator apps can
+// scan to add it. Users with multi-factor authentication active have to turn it off first so that a session alone
+// isn't enough to swap out their authenticator.
+func (m *TotpMfa) GenerateSecret(user *model.User) (string, []byte, *model.AppError) {
+	if err := checkEnabled("TotpMfa.GenerateSecret"); err != nil {
+		return "", nil, err
+	}
+
+	if user.MfaActive {
+		return "", nil, model.NewAppError("TotpMfa.GenerateSecret", "mfa.generate_secret.active.app_error", nil, "user_id="+user.Id, http.StatusBadRequest)
+	}
+
+	secretBytes := make([]byte, SECRET_LENGTH)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", nil, model.NewLocAppError("TotpMfa.GenerateSecret", "mfa.generate_secret.app_error", nil, err.Error())
+	}
+	secret := base32.StdEncoding.EncodeToString(secretBytes)
+
+	code, err := qr.Encode(provisionURI(user, secret), qr.M)
+	if err != nil {
+		return "", nil, model.NewLocAppError("TotpMfa.GenerateSecret", "mfa.generate_secret.create_code.app_error", nil, err.Error())
+	}
+
+	if result := <-app.Srv.Store.User().UpdateMfaSecret(user.Id, secret); result.Err != nil {
+		return "", nil, result.Err
+	}
+
+	app.InvalidateCacheForUser(user.Id)
+
+	return secret, code.PNG(), nil
+}
+
+// provisionURI returns the URI that authenticator apps read from the QR code, labelled with the site name and the
+// user's email address.
+func provisionURI(user *model.User, secret string) string {
+	issuer := utils.Cfg.TeamSettings.SiteName
+
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+
+	return "otpauth://totp/" + url.PathEscape(issuer+":"+user.Email) + "?" + query.Encode()
+}
+
+// Activate turns on multi-factor authentication for the user once they've shown that their authenticator app has the
+// secret by giving a valid token.
+func (m *TotpMfa) Activate(user *model.User, token string) *model.AppError {
+	if err := checkEnabled("TotpMfa.Activate"); err != nil {
+		return err
+	}
+
+	if len(user.MfaSecret) == 0 {
+		return model.NewAppError("TotpMfa.Activate", "mfa.activate.no_secret.app_error", nil, "user_id="+user.Id, http.StatusBadRequest)
+	}
+
+	if ok, err := validateTotpToken(user, normalizeToken(token)); err != nil {
+		return err
+	} else if !ok {
+		return model.NewAppError("TotpMfa.Activate", "mfa.activate.bad_token.app_error", nil, "user_id="+user.Id, http.StatusBadRequest)
+	}
+
+	if result := <-app.Srv.Store.User().UpdateMfaActive(user.Id, true); result.Err != nil {
+		return result.Err
+	}
+
+	app.InvalidateCacheForUser(user.Id)
+
+	return nil
+}
+
+// Deactivate turns off multi-factor authentication for the user and forgets their secret and backup codes.
+func (m *TotpMfa) Deactivate(userId string) *model.AppError {
+	if result := <-app.Srv.Store.User().UpdateMfaActive(userId, false); result.Err != nil {
+		return result.Err
+	}
+
+	if result := <-app.Srv.Store.User().UpdateMfaSecret(userId, ""); result.Err != nil {
+		return result.Err
+	}
+
+	if result := <-app.Srv.Store.Mfa().PermanentDeleteByUser(userId); result.Err != nil {
+		return result.Err
+	}
+
+	app.InvalidateCacheForUser(userId)
+
+	return nil
+}
+
+// ValidateToken returns whether the token is either a TOTP token that the user hasn't used before or one of their
+// unused backup codes. Either way, the token can't be used again.
+func (m *TotpMfa) ValidateToken(user *model.User, token string) (bool, *model.AppError) {
+	token = normalizeToken(token)
+
+	if len(token) == TOKEN_LENGTH {
+		return validateTotpToken(user, token)
+	}
+
+	if result := <-app.Srv.Store.Mfa().UseBackupCode(user.Id, hashBackupCode(token)); result.Err != nil {
+		return false, result.Err
+	} else {
+		return result.Data.(bool), nil
+	}
+}
+
+// GenerateBackupCodes replaces the backup codes of the user with new ones and returns them. Only hashes of the codes
+// are kept, so they can't be shown again.
+func (m *TotpMfa) GenerateBackupCodes(user *model.User) ([]string, *model.AppError) {
+	if err := checkEnabled("TotpMfa.GenerateBackupCodes"); err != nil {
+		return nil, err
+	}
+
+	if !user.MfaActive {
+		return nil, model.NewAppError("TotpMfa.GenerateBackupCodes", "mfa.generate_backup_codes.not_active.app_error", nil, "user_id="+user.Id, http.StatusBadRequest)
+	}
+
+	codes := make([]string, BACKUP_CODE_COUNT)
+	codeHashes := make([]string, BACKUP_CODE_COUNT)
+	for i := range codes {
+		codes[i] = model.NewRandomString(BACKUP_CODE_LENGTH)
+		codeHashes[i] = hashBackupCode(codes[i])
+	}
+
+	if result := <-app.Srv.Store.Mfa().SaveBackupCodes(user.Id, codeHashes); result.Err != nil {
+		return nil, result.Err
+	}
+
+	return codes, nil
+}
+
+func normalizeToken(token string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(token))
+}
+
+func hashBackupCode(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
+}
+
+func validateTotpToken(user *model.User, token string) (bool, *model.AppError) {
+	if len(user.MfaSecret) == 0 {
+		return false, nil
+	}
+
+	timeStep := time.Now().Unix() / TIME_STEP_SECONDS
+
+	for step := timeStep - TIME_STEP_WINDOW; step <= timeStep+TIME_STEP_WINDOW; step++ {
+		code := fmt.Sprintf("%0*d", TOKEN_LENGTH, dgoogauth.ComputeCode(user.MfaSecret, step))
+
+		if subtle.ConstantTimeCompare([]byte(code), []byte(token)) == 1 {
+			// Tokens from a time step that the user has already logged in with, or from an earlier one, are replays
+			if result := <-app.Srv.Store.Mfa().UseTimeStep(user.Id, step); result.Err != nil {
+				return false, result.Err
+			} else {
+				return result.Data.(bool), nil
+			}
+		}
+	}
+
+	return false, nil
+}
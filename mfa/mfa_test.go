@@ -0,0 +1,147 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package mfa
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgryski/dgoogauth"
+	"github.com/mattermost/platform/app"
+	"github.com/mattermost/platform/utils"
+)
+
+func tokenAt(secret string, timeStep int64) string {
+	return fmt.Sprintf("%06d", dgoogauth.ComputeCode(secret, timeStep))
+}
+
+func TestTotpMfa(t *testing.T) {
+	th := app.Setup()
+
+	enableMfa := *utils.Cfg.ServiceSettings.EnableMultifactorAuthentication
+	defer func() {
+		*utils.Cfg.ServiceSettings.EnableMultifactorAuthentication = enableMfa
+	}()
+
+	user := th.CreateUser()
+
+	*utils.Cfg.ServiceSettings.EnableMultifactorAuthentication = false
+
+	if _, _, err := (&TotpMfa{}).GenerateSecret(user); err == nil {
+		t.Fatal("should have failed - mfa disabled")
+	}
+
+	*utils.Cfg.ServiceSettings.EnableMultifactorAuthentication = true
+
+	if err := app.ActivateMfa(user.Id, "123456"); err == nil {
+		t.Fatal("should have failed - no secret")
+	}
+
+	secret, img, err := (&TotpMfa{}).GenerateSecret(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(secret) != 32 || len(img) == 0 {
+		t.Fatal("should have returned a secret and a QR code")
+	}
+
+	timeStep := time.Now().Unix() / TIME_STEP_SECONDS
+
+	if err := app.ActivateMfa(user.Id, tokenAt(secret, timeStep-5)); err == nil {
+		t.Fatal("should have failed - expired token")
+	}
+
+	token := tokenAt(secret, timeStep)
+	if err := app.ActivateMfa(user.Id, token); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err = app.GetUser(user.Id)
+	if err != nil {
+		t.Fatal(err)
+	} else if !user.MfaActive || user.MfaSecret != secret {
+		t.Fatal("should have activated mfa")
+	}
+
+	if _, _, err := (&TotpMfa{}).GenerateSecret(user); err == nil {
+		t.Fatal("should have failed - mfa already active")
+	} else if user, _ := app.GetUser(user.Id); user.MfaSecret != secret {
+		t.Fatal("shouldn't have replaced the active secret")
+	}
+
+	if err := app.CheckUserMfa(user, token); err == nil {
+		t.Fatal("should have failed - token already used")
+	}
+
+	if err := app.CheckUserMfa(user, tokenAt(secret, timeStep+1)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.CheckUserMfa(user, tokenAt(secret, timeStep)); err == nil {
+		t.Fatal("should have failed - token older than the last one used")
+	}
+
+	codes, err := app.GenerateMfaBackupCodes(user.Id)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(codes) != BACKUP_CODE_COUNT {
+		t.Fatal("should have generated backup codes")
+	}
+
+	if err := app.CheckUserMfa(user, strings.ToUpper(codes[0])); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.CheckUserMfa(user, codes[0]); err == nil {
+		t.Fatal("should have failed - backup code already used")
+	}
+
+	if err := app.CheckUserMfa(user, "junkjunkju"); err == nil {
+		t.Fatal("should have failed - bad backup code")
+	}
+
+	newCodes, err := app.GenerateMfaBackupCodes(user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.CheckUserMfa(user, codes[1]); err == nil {
+		t.Fatal("should have failed - backup codes replaced")
+	}
+
+	if err := app.DeactivateMfa(user.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err = app.GetUser(user.Id)
+	if err != nil {
+		t.Fatal(err)
+	} else if user.MfaActive || len(user.MfaSecret) != 0 {
+		t.Fatal("should have deactivated mfa")
+	}
+
+	if ok, err := (&TotpMfa{}).ValidateToken(user, newCodes[0]); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("should have deleted the backup codes")
+	}
+
+	if _, err := app.GenerateMfaBackupCodes(user.Id); err == nil {
+		t.Fatal("should have failed - mfa not active")
+	}
+}
+
+func TestProvisionURI(t *testing.T) {
+	th := app.Setup()
+
+	user := th.CreateUser()
+
+	uri := provisionURI(user, "SECRET")
+	if !strings.HasPrefix(uri, "otpauth://totp/") || !strings.Contains(uri, "secret=SECRET") || !strings.Contains(uri, "issuer=") {
+		t.Fatal("should have returned an otpauth uri", uri)
+	}
+}
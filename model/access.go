@@ -29,6 +29,7 @@ type AccessResponse struct {
 	ExpiresIn    int32  `json:"expires_in"`
 	Scope        string `json:"scope"`
 	RefreshToken string `json:"refresh_token"`
+	IdToken      string `json:"id_token,omitempty"`
 }
 
 // IsValid validates the AccessData and returns an error if it isn't configured
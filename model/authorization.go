@@ -29,7 +29,9 @@ var PERMISSION_ASSIGN_SYSTEM_ADMIN_ROLE *Permission
 var PERMISSION_MANAGE_ROLES *Permission
 var PERMISSION_MANAGE_TEAM_ROLES *Permission
 var PERMISSION_MANAGE_CHANNEL_ROLES *Permission
+var PERMISSION_VIEW_CHANNEL_ANALYTICS *Permission
 var PERMISSION_CREATE_DIRECT_CHANNEL *Permission
+var PERMISSION_CREATE_GROUP_CHANNEL *Permission
 var PERMISSION_MANAGE_PUBLIC_CHANNEL_PROPERTIES *Permission
 var PERMISSION_MANAGE_PRIVATE_CHANNEL_PROPERTIES *Permission
 var PERMISSION_LIST_TEAM_CHANNELS *Permission
@@ -45,7 +47,11 @@ var PERMISSION_MANAGE_WEBHOOKS *Permission
 var PERMISSION_MANAGE_OTHERS_WEBHOOKS *Permission
 var PERMISSION_MANAGE_OAUTH *Permission
 var PERMISSION_MANAGE_SYSTEM_WIDE_OAUTH *Permission
+var PERMISSION_MANAGE_BOTS *Permission
+var PERMISSION_MANAGE_OTHERS_BOTS *Permission
+var PERMISSION_CREATE_USER_ACCESS_TOKEN *Permission
 var PERMISSION_CREATE_POST *Permission
+var PERMISSION_CREATE_POST_RESTRICTED *Permission
 var PERMISSION_EDIT_POST *Permission
 var PERMISSION_EDIT_OTHERS_POSTS *Permission
 var PERMISSION_DELETE_POST *Permission
@@ -55,6 +61,7 @@ var PERMISSION_CREATE_TEAM *Permission
 var PERMISSION_MANAGE_TEAM *Permission
 var PERMISSION_IMPORT_TEAM *Permission
 var PERMISSION_VIEW_TEAM *Permission
+var PERMISSION_MANAGE_GROUPS *Permission
 
 // General permission that encompases all system admin functions
 // in the future this could be broken up to allow access to some
@@ -63,6 +70,7 @@ var PERMISSION_MANAGE_SYSTEM *Permission
 
 var ROLE_SYSTEM_USER *Role
 var ROLE_SYSTEM_ADMIN *Role
+var ROLE_SYSTEM_GUEST *Role
 
 var ROLE_TEAM_USER *Role
 var ROLE_TEAM_ADMIN *Role
@@ -70,6 +78,7 @@ var ROLE_TEAM_ADMIN *Role
 var ROLE_CHANNEL_USER *Role
 var ROLE_CHANNEL_ADMIN *Role
 var ROLE_CHANNEL_GUEST *Role
+var ROLE_CHANNEL_ANNOUNCER *Role
 
 var BuiltInRoles map[string]*Role
 
@@ -139,6 +148,11 @@ func InitalizePermissions() {
 		"authentication.permissions.manage_channel_roles.name",
 		"authentication.permissions.manage_channel_roles.description",
 	}
+	PERMISSION_VIEW_CHANNEL_ANALYTICS = &Permission{
+		"view_channel_analytics",
+		"authentication.permissions.view_channel_analytics.name",
+		"authentication.permissions.view_channel_analytics.description",
+	}
 	PERMISSION_MANAGE_SYSTEM = &Permission{
 		"manage_system",
 		"authentication.permissions.manage_system.name",
@@ -149,6 +163,11 @@ func InitalizePermissions() {
 		"authentication.permissions.create_direct_channel.name",
 		"authentication.permissions.create_direct_channel.description",
 	}
+	PERMISSION_CREATE_GROUP_CHANNEL = &Permission{
+		"create_group_channel",
+		"authentication.permissions.create_group_channel.name",
+		"authentication.permissions.create_group_channel.description",
+	}
 	PERMISSION_MANAGE_PUBLIC_CHANNEL_PROPERTIES = &Permission{
 		"manage__publicchannel_properties",
 		"authentication.permissions.manage_public_channel_properties.name",
@@ -224,11 +243,31 @@ func InitalizePermissions() {
 		"authentication.permissions.manage_sytem_wide_oauth.name",
 		"authentication.permissions.manage_sytem_wide_oauth.description",
 	}
+	PERMISSION_MANAGE_BOTS = &Permission{
+		"manage_bots",
+		"authentication.permissions.manage_bots.name",
+		"authentication.permissions.manage_bots.description",
+	}
+	PERMISSION_MANAGE_OTHERS_BOTS = &Permission{
+		"manage_others_bots",
+		"authentication.permissions.manage_others_bots.name",
+		"authentication.permissions.manage_others_bots.description",
+	}
+	PERMISSION_CREATE_USER_ACCESS_TOKEN = &Permission{
+		"create_user_access_token",
+		"authentication.permissions.create_user_access_token.name",
+		"authentication.permissions.create_user_access_token.description",
+	}
 	PERMISSION_CREATE_POST = &Permission{
 		"create_post",
 		"authentication.permissions.create_post.name",
 		"authentication.permissions.create_post.description",
 	}
+	PERMISSION_CREATE_POST_RESTRICTED = &Permission{
+		"create_post_restricted",
+		"authentication.permissions.create_post_restricted.name",
+		"authentication.permissions.create_post_restricted.description",
+	}
 	PERMISSION_EDIT_POST = &Permission{
 		"edit_post",
 		"authentication.permissions.edit_post.name",
@@ -274,6 +313,11 @@ func InitalizePermissions() {
 		"authentication.permissions.view_team.name",
 		"authentication.permissions.view_team.description",
 	}
+	PERMISSION_MANAGE_GROUPS = &Permission{
+		"manage_groups",
+		"authentication.permissions.manage_groups.name",
+		"authentication.permissions.manage_groups.description",
+	}
 }
 
 func InitalizeRoles() {
@@ -302,9 +346,20 @@ func InitalizeRoles() {
 		"authentication.roles.channel_admin.description",
 		[]string{
 			PERMISSION_MANAGE_CHANNEL_ROLES.Id,
+			PERMISSION_CREATE_POST_RESTRICTED.Id,
+			PERMISSION_VIEW_CHANNEL_ANALYTICS.Id,
 		},
 	}
 	BuiltInRoles[ROLE_CHANNEL_ADMIN.Id] = ROLE_CHANNEL_ADMIN
+	ROLE_CHANNEL_ANNOUNCER = &Role{
+		"channel_announcer",
+		"authentication.roles.channel_announcer.name",
+		"authentication.roles.channel_announcer.description",
+		[]string{
+			PERMISSION_CREATE_POST_RESTRICTED.Id,
+		},
+	}
+	BuiltInRoles[ROLE_CHANNEL_ANNOUNCER.Id] = ROLE_CHANNEL_ANNOUNCER
 	ROLE_CHANNEL_GUEST = &Role{
 		"guest",
 		"authentication.roles.global_guest.name",
@@ -336,6 +391,7 @@ func InitalizeRoles() {
 			PERMISSION_IMPORT_TEAM.Id,
 			PERMISSION_MANAGE_TEAM_ROLES.Id,
 			PERMISSION_MANAGE_CHANNEL_ROLES.Id,
+			PERMISSION_VIEW_CHANNEL_ANALYTICS.Id,
 			PERMISSION_MANAGE_OTHERS_WEBHOOKS.Id,
 			PERMISSION_MANAGE_SLASH_COMMANDS.Id,
 			PERMISSION_MANAGE_OTHERS_SLASH_COMMANDS.Id,
@@ -350,11 +406,22 @@ func InitalizeRoles() {
 		"authentication.roles.global_user.description",
 		[]string{
 			PERMISSION_CREATE_DIRECT_CHANNEL.Id,
+			PERMISSION_CREATE_GROUP_CHANNEL.Id,
 			PERMISSION_PERMANENT_DELETE_USER.Id,
 			PERMISSION_MANAGE_OAUTH.Id,
 		},
 	}
 	BuiltInRoles[ROLE_SYSTEM_USER.Id] = ROLE_SYSTEM_USER
+	// Guests replace the system_user role and only get access to the channels they're explicitly added to
+	ROLE_SYSTEM_GUEST = &Role{
+		"system_guest",
+		"authentication.roles.global_guest.name",
+		"authentication.roles.global_guest.description",
+		[]string{
+			PERMISSION_CREATE_DIRECT_CHANNEL.Id,
+		},
+	}
+	BuiltInRoles[ROLE_SYSTEM_GUEST.Id] = ROLE_SYSTEM_GUEST
 	ROLE_SYSTEM_ADMIN = &Role{
 		"system_admin",
 		"authentication.roles.global_admin.name",
@@ -380,10 +447,13 @@ func InitalizeRoles() {
 							PERMISSION_MANAGE_OTHERS_WEBHOOKS.Id,
 							PERMISSION_EDIT_OTHER_USERS.Id,
 							PERMISSION_MANAGE_OAUTH.Id,
+							PERMISSION_MANAGE_BOTS.Id,
+							PERMISSION_MANAGE_OTHERS_BOTS.Id,
 							PERMISSION_INVITE_USER.Id,
 							PERMISSION_DELETE_POST.Id,
 							PERMISSION_DELETE_OTHERS_POSTS.Id,
 							PERMISSION_CREATE_TEAM.Id,
+							PERMISSION_MANAGE_GROUPS.Id,
 						},
 						ROLE_TEAM_USER.Permissions...,
 					),
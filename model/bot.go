@@ -0,0 +1,22 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+const (
+	// Bots can't receive email, but every user needs a unique address so one is made up from the username
+	BOT_EMAIL_DOMAIN = "bot.localhost"
+)
+
+// MakeBot turns the user into a bot owned by the given user. Bots can't log in with a password or through an
+// authentication service and have to use access tokens instead.
+func (u *User) MakeBot(ownerId string) {
+	u.IsBot = true
+	u.BotOwnerId = ownerId
+	u.Password = ""
+	u.AuthData = nil
+	u.AuthService = ""
+	u.Email = u.Username + "@" + BOT_EMAIL_DOMAIN
+	u.EmailVerified = true
+	u.AllowMarketing = false
+}
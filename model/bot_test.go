@@ -0,0 +1,40 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"testing"
+)
+
+func TestUserMakeBot(t *testing.T) {
+	user := User{Username: "webhookbot", Email: "webhookbot@example.com", Password: "password", AuthService: USER_AUTH_SERVICE_EMAIL}
+	user.PreSave()
+
+	if err := user.IsValid(); err != nil {
+		t.Fatal(err)
+	}
+
+	user.IsBot = true
+	if err := user.IsValid(); err == nil {
+		t.Fatal("bot without an owner shouldn't be valid")
+	}
+
+	user.BotOwnerId = NewId()
+	if err := user.IsValid(); err == nil {
+		t.Fatal("bot with a password shouldn't be valid")
+	}
+
+	user.MakeBot(user.BotOwnerId)
+	if err := user.IsValid(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(user.Password) != 0 || len(user.AuthService) != 0 {
+		t.Fatal("bot shouldn't be able to log in")
+	}
+
+	if user.Email != "webhookbot@"+BOT_EMAIL_DOMAIN || !user.EmailVerified {
+		t.Fatal("bot should've been given an email")
+	}
+}
@@ -4,8 +4,12 @@
 package model
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
 	"encoding/json"
 	"io"
+	"sort"
+	"strings"
 	"unicode/utf8"
 )
 
@@ -13,11 +17,17 @@ const (
 	CHANNEL_OPEN                   = "O"
 	CHANNEL_PRIVATE                = "P"
 	CHANNEL_DIRECT                 = "D"
+	CHANNEL_GROUP                  = "G"
+	CHANNEL_GROUP_MIN_USERS        = 3
+	CHANNEL_GROUP_MAX_USERS        = 8
 	DEFAULT_CHANNEL                = "town-square"
 	CHANNEL_DISPLAY_NAME_MAX_RUNES = 64
 	CHANNEL_NAME_MAX_LENGTH        = 64
 	CHANNEL_HEADER_MAX_RUNES       = 1024
 	CHANNEL_PURPOSE_MAX_RUNES      = 250
+
+	CHANNEL_POSTING_POLICY_ALL        = "all"
+	CHANNEL_POSTING_POLICY_RESTRICTED = "restricted"
 )
 
 type Channel struct {
@@ -35,6 +45,11 @@ type Channel struct {
 	TotalMsgCount int64  `json:"total_msg_count"`
 	ExtraUpdateAt int64  `json:"extra_update_at"`
 	CreatorId     string `json:"creator_id"`
+	ArchiveAt     int64  `json:"archive_at"`
+	PostingPolicy string `json:"posting_policy"`
+
+	// InactiveWarningAt is when the channel was last warned that it would be archived for inactivity, or zero
+	InactiveWarningAt int64 `json:"inactive_warning_at"`
 }
 
 func (o *Channel) ToJson() string {
@@ -91,7 +106,7 @@ func (o *Channel) IsValid() *AppError {
 		return NewLocAppError("Channel.IsValid", "model.channel.is_valid.2_or_more.app_error", nil, "id="+o.Id)
 	}
 
-	if !(o.Type == CHANNEL_OPEN || o.Type == CHANNEL_PRIVATE || o.Type == CHANNEL_DIRECT) {
+	if !(o.Type == CHANNEL_OPEN || o.Type == CHANNEL_PRIVATE || o.Type == CHANNEL_DIRECT || o.Type == CHANNEL_GROUP) {
 		return NewLocAppError("Channel.IsValid", "model.channel.is_valid.type.app_error", nil, "id="+o.Id)
 	}
 
@@ -107,6 +122,10 @@ func (o *Channel) IsValid() *AppError {
 		return NewLocAppError("Channel.IsValid", "model.channel.is_valid.creator_id.app_error", nil, "")
 	}
 
+	if !IsValidChannelPostingPolicy(o.PostingPolicy) {
+		return NewLocAppError("Channel.IsValid", "model.channel.is_valid.posting_policy.app_error", nil, "id="+o.Id)
+	}
+
 	return nil
 }
 
@@ -115,6 +134,10 @@ func (o *Channel) PreSave() {
 		o.Id = NewId()
 	}
 
+	if o.PostingPolicy == "" {
+		o.PostingPolicy = CHANNEL_POSTING_POLICY_ALL
+	}
+
 	o.CreateAt = GetMillis()
 	o.UpdateAt = o.CreateAt
 	o.ExtraUpdateAt = o.CreateAt
@@ -124,10 +147,34 @@ func (o *Channel) PreUpdate() {
 	o.UpdateAt = GetMillis()
 }
 
+// IsArchived returns true if the channel has been made read-only. Unlike deleted channels, archived channels can
+// still be read and searched by their members.
+func (o *Channel) IsArchived() bool {
+	return o.ArchiveAt != 0
+}
+
+// IsGroupOrDirect returns true for direct and group message channels, which don't belong to a team and whose
+// members are fixed when they're created.
+func (o *Channel) IsGroupOrDirect() bool {
+	return o.Type == CHANNEL_DIRECT || o.Type == CHANNEL_GROUP
+}
+
+// IsPostingRestricted returns true for announcement channels, where only channel admins and users given the
+// channel_announcer role can post.
+func (o *Channel) IsPostingRestricted() bool {
+	return o.PostingPolicy == CHANNEL_POSTING_POLICY_RESTRICTED
+}
+
 func (o *Channel) ExtraUpdated() {
 	o.ExtraUpdateAt = GetMillis()
 }
 
+// IsValidChannelPostingPolicy returns true for the known posting policies. Channels created before posting policies
+// were added have an empty one, which behaves like CHANNEL_POSTING_POLICY_ALL.
+func IsValidChannelPostingPolicy(policy string) bool {
+	return policy == "" || policy == CHANNEL_POSTING_POLICY_ALL || policy == CHANNEL_POSTING_POLICY_RESTRICTED
+}
+
 func GetDMNameFromIds(userId1, userId2 string) string {
 	if userId1 > userId2 {
 		return userId2 + "__" + userId1
@@ -135,3 +182,14 @@ func GetDMNameFromIds(userId1, userId2 string) string {
 		return userId1 + "__" + userId2
 	}
 }
+
+// GetGroupNameFromUserIds returns the name of the group message channel between the given users. It only depends
+// on the set of users, so the same group of users always maps to the same channel.
+func GetGroupNameFromUserIds(userIds []string) string {
+	sortedIds := make([]string, len(userIds))
+	copy(sortedIds, userIds)
+	sort.Strings(sortedIds)
+
+	hash := sha1.Sum([]byte(strings.Join(sortedIds, "")))
+	return hex.EncodeToString(hash[:])
+}
@@ -92,6 +92,16 @@ func TestChannelIsValid(t *testing.T) {
 	if err := o.IsValid(); err != nil {
 		t.Fatal(err)
 	}
+
+	o.PostingPolicy = "junk"
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.PostingPolicy = CHANNEL_POSTING_POLICY_RESTRICTED
+	if err := o.IsValid(); err != nil {
+		t.Fatal(err)
+	}
 }
 
 func TestChannelPreSave(t *testing.T) {
@@ -104,3 +114,20 @@ func TestChannelPreUpdate(t *testing.T) {
 	o := Channel{Name: "test"}
 	o.PreUpdate()
 }
+
+func TestGetGroupNameFromUserIds(t *testing.T) {
+	userIds := []string{NewId(), NewId(), NewId()}
+
+	name := GetGroupNameFromUserIds(userIds)
+	if name != GetGroupNameFromUserIds([]string{userIds[2], userIds[0], userIds[1]}) {
+		t.Fatal("name shouldn't depend on the order of the users")
+	}
+
+	if name == GetGroupNameFromUserIds(append(userIds, NewId())) {
+		t.Fatal("different users should have different names")
+	}
+
+	if !IsValidChannelIdentifier(name) || len(name) > CHANNEL_NAME_MAX_LENGTH {
+		t.Fatal("should be a valid channel name", name)
+	}
+}
@@ -746,6 +746,19 @@ func (c *Client) UpdateMfa(activate bool, token string) (*Result, *AppError) {
 	}
 }
 
+// GenerateMfaBackupCodes replaces the current user's multi-factor authentication backup
+// codes with new ones and returns them. Each code can be used once instead of a token.
+// Must be authenticated and have multi-factor authentication active.
+func (c *Client) GenerateMfaBackupCodes() (*Result, *AppError) {
+	if r, err := c.DoApiPost("/users/generate_mfa_backup_codes", ""); err != nil {
+		return nil, err
+	} else {
+		defer closeBody(r)
+		return &Result{r.Header.Get(HEADER_REQUEST_ID),
+			r.Header.Get(HEADER_ETAG_SERVER), ArrayFromJson(r.Body)}, nil
+	}
+}
+
 func (c *Client) AdminResetMfa(userId string) (*Result, *AppError) {
 	m := make(map[string]string)
 	m["user_id"] = userId
@@ -5,7 +5,10 @@ package model
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 )
 
@@ -100,6 +103,54 @@ func (c *Client4) GetPostRoute(postId string) string {
 	return fmt.Sprintf(c.GetPostsRoute()+"/%v", postId)
 }
 
+func (c *Client4) GetImageProxyRoute() string {
+	return fmt.Sprintf("/image")
+}
+
+func (c *Client4) GetBotsRoute() string {
+	return fmt.Sprintf("/bots")
+}
+
+func (c *Client4) GetBotRoute(botUserId string) string {
+	return fmt.Sprintf(c.GetBotsRoute()+"/%v", botUserId)
+}
+
+func (c *Client4) GetProfileAttributesRoute() string {
+	return fmt.Sprintf("/profile_attributes")
+}
+
+func (c *Client4) GetProfileAttributeRoute(attributeId string) string {
+	return fmt.Sprintf(c.GetProfileAttributesRoute()+"/%v", attributeId)
+}
+
+func (c *Client4) GetGroupsRoute() string {
+	return fmt.Sprintf("/groups")
+}
+
+func (c *Client4) GetGroupRoute(groupId string) string {
+	return fmt.Sprintf(c.GetGroupsRoute()+"/%v", groupId)
+}
+
+func (c *Client4) GetGroupByNameRoute(groupName string) string {
+	return fmt.Sprintf(c.GetGroupsRoute()+"/name/%v", groupName)
+}
+
+func (c *Client4) GetGroupMembersRoute(groupId string) string {
+	return c.GetGroupRoute(groupId) + "/members"
+}
+
+func (c *Client4) GetGroupMemberRoute(groupId, userId string) string {
+	return fmt.Sprintf(c.GetGroupMembersRoute(groupId)+"/%v", userId)
+}
+
+func (c *Client4) GetSidebarCategoriesRoute(userId, teamId string) string {
+	return fmt.Sprintf(c.GetUserRoute(userId)+"/teams/%v/channels/categories", teamId)
+}
+
+func (c *Client4) GetSidebarCategoryRoute(userId, teamId, categoryId string) string {
+	return fmt.Sprintf(c.GetSidebarCategoriesRoute(userId, teamId)+"/%v", categoryId)
+}
+
 func (c *Client4) DoApiGet(url string, etag string) (*http.Response, *AppError) {
 	return c.DoApiRequest(http.MethodGet, url, "", etag)
 }
@@ -344,6 +395,51 @@ func (c *Client4) UpdateUserRoles(userId, roles string) (bool, *Response) {
 	}
 }
 
+// CreateUserAccessToken creates a personal access token for a user that expires at the given time, or never if it's
+// zero. The token is only returned here and can't be retrieved later.
+func (c *Client4) CreateUserAccessToken(userId string, description string, expiresAt int64) (*Session, *Response) {
+	requestBody := map[string]string{"description": description}
+	if expiresAt != 0 {
+		requestBody["expires_at"] = strconv.FormatInt(expiresAt, 10)
+	}
+	if r, err := c.DoApiPost(c.GetUserRoute(userId)+"/tokens", MapToJson(requestBody)); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return SessionFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetUserAccessTokens returns the personal access tokens of a user without their values.
+func (c *Client4) GetUserAccessTokens(userId string) ([]*Session, *Response) {
+	if r, err := c.DoApiGet(c.GetUserRoute(userId)+"/tokens", ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return SessionsFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// RevokeUserAccessToken revokes a personal access token of a user.
+func (c *Client4) RevokeUserAccessToken(userId string, tokenId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetUserRoute(userId)+"/tokens/"+tokenId, ""); err != nil {
+		return false, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// GetUsernameHistory returns the username changes of a user, most recent first. Only system admins can do this.
+func (c *Client4) GetUsernameHistory(userId string) ([]*UsernameHistory, *Response) {
+	if r, err := c.DoApiGet(c.GetUserRoute(userId)+"/username_history", ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return UsernameHistoryListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // DeleteUser deactivates a user in the system based on the provided user id string.
 func (c *Client4) DeleteUser(userId string) (bool, *Response) {
 	if r, err := c.DoApiDelete(c.GetUserRoute(userId), ""); err != nil {
@@ -444,6 +540,17 @@ func (c *Client4) CreateDirectChannel(userId1, userId2 string) (*Channel, *Respo
 	}
 }
 
+// CreateGroupChannel returns the group message channel between the given users, creating it if it doesn't exist.
+// The list of users must include the current user.
+func (c *Client4) CreateGroupChannel(userIds []string) (*Channel, *Response) {
+	if r, err := c.DoApiPost(c.GetChannelsRoute()+"/group", ArrayToJson(userIds)); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return ChannelFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // GetChannelMembers gets a page of channel members.
 func (c *Client4) GetChannelMembers(channelId string, page, perPage int, etag string) (*ChannelMembers, *Response) {
 	query := fmt.Sprintf("?page=%v&per_page=%v", page, perPage)
@@ -475,6 +582,74 @@ func (c *Client4) GetChannelMembersForUser(userId, teamId, etag string) (*Channe
 	}
 }
 
+// UpdateChannelPrivacy converts a channel to public (CHANNEL_OPEN) or private (CHANNEL_PRIVATE) while keeping its
+// members and history.
+func (c *Client4) UpdateChannelPrivacy(channelId string, privacy string) (*Channel, *Response) {
+	requestBody := map[string]string{"privacy": privacy}
+	if r, err := c.DoApiPut(c.GetChannelRoute(channelId)+"/privacy", MapToJson(requestBody)); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return ChannelFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// UpdateChannelPostingPolicy changes who can post in a channel to either CHANNEL_POSTING_POLICY_ALL or
+// CHANNEL_POSTING_POLICY_RESTRICTED.
+func (c *Client4) UpdateChannelPostingPolicy(channelId string, postingPolicy string) (*Channel, *Response) {
+	requestBody := map[string]string{"posting_policy": postingPolicy}
+	if r, err := c.DoApiPut(c.GetChannelRoute(channelId)+"/posting_policy", MapToJson(requestBody)); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return ChannelFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// MoveChannel moves a channel to another team. Members that aren't on that team are added to it when addMembers is
+// true and removed from the channel otherwise.
+func (c *Client4) MoveChannel(channelId, teamId string, addMembers bool) (*Channel, *Response) {
+	requestBody := map[string]string{"team_id": teamId, "add_members": strconv.FormatBool(addMembers)}
+	if r, err := c.DoApiPost(c.GetChannelRoute(channelId)+"/move", MapToJson(requestBody)); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return ChannelFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetChannelAnalytics returns the named analytics of a channel, such as "standard" or "post_counts_day", between two
+// times in milliseconds.
+func (c *Client4) GetChannelAnalytics(channelId, name string, startTime, endTime int64) (AnalyticsRows, *Response) {
+	query := fmt.Sprintf("?name=%v&start=%v&end=%v", name, startTime, endTime)
+	if r, err := c.DoApiGet(c.GetChannelRoute(channelId)+"/analytics"+query, ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return AnalyticsRowsFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// ArchiveChannel makes a channel read-only while keeping its history readable.
+func (c *Client4) ArchiveChannel(channelId string) (*Channel, *Response) {
+	if r, err := c.DoApiPost(c.GetChannelRoute(channelId)+"/archive", ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return ChannelFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// UnarchiveChannel makes an archived channel writable again.
+func (c *Client4) UnarchiveChannel(channelId string) (*Channel, *Response) {
+	if r, err := c.DoApiPost(c.GetChannelRoute(channelId)+"/unarchive", ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return ChannelFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // Post Section
 
 // CreatePost creates a post based on the provided post struct.
@@ -518,5 +693,431 @@ func (c *Client4) GetPostsForChannel(channelId string, page, perPage int, etag s
 	}
 }
 
+// GetReactionSummary returns the number of reactions to a post for each emoji and whether the current user
+// reacted with it.
+func (c *Client4) GetReactionSummary(postId string) ([]*ReactionSummary, *Response) {
+	if r, err := c.DoApiGet(c.GetPostRoute(postId)+"/reactions/summary", ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return ReactionSummariesFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetReactionUsers returns a page of the users who reacted to a post with an emoji, in the order that they reacted.
+func (c *Client4) GetReactionUsers(postId, emojiName string, page, perPage int) ([]*User, *Response) {
+	query := fmt.Sprintf("?page=%v&per_page=%v", page, perPage)
+	if r, err := c.DoApiGet(c.GetPostRoute(postId)+"/reactions/"+emojiName+"/users"+query, ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return UserListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
 // Files Section
 // to be filled in..
+
+// Image Proxy Section
+
+// GetProxiedImage gets a remote image through the server's image proxy.
+func (c *Client4) GetProxiedImage(imageURL string) ([]byte, *Response) {
+	if r, err := c.DoApiGet(c.GetImageProxyRoute()+"?url="+url.QueryEscape(imageURL), ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+
+		if data, err := ioutil.ReadAll(r.Body); err != nil {
+			return nil, &Response{StatusCode: r.StatusCode, Error: NewAppError("GetProxiedImage", "model.client.read_image.app_error", nil, err.Error(), r.StatusCode)}
+		} else {
+			return data, BuildResponse(r)
+		}
+	}
+}
+
+// Groups Section
+
+// CreateGroup creates a group that can be mentioned with @name. Must have the manage_groups permission.
+func (c *Client4) CreateGroup(group *Group) (*Group, *Response) {
+	if r, err := c.DoApiPost(c.GetGroupsRoute(), group.ToJson()); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return GroupFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetGroups returns a page of groups.
+func (c *Client4) GetGroups(page, perPage int) ([]*Group, *Response) {
+	query := fmt.Sprintf("?page=%v&per_page=%v", page, perPage)
+	if r, err := c.DoApiGet(c.GetGroupsRoute()+query, ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return GroupListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetGroup returns a group based on the provided group id string.
+func (c *Client4) GetGroup(groupId string) (*Group, *Response) {
+	if r, err := c.DoApiGet(c.GetGroupRoute(groupId), ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return GroupFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetGroupByName returns a group based on the provided group name string.
+func (c *Client4) GetGroupByName(groupName string) (*Group, *Response) {
+	if r, err := c.DoApiGet(c.GetGroupByNameRoute(groupName), ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return GroupFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// UpdateGroup updates a group's name, display name and description. Must have the manage_groups permission.
+func (c *Client4) UpdateGroup(group *Group) (*Group, *Response) {
+	if r, err := c.DoApiPut(c.GetGroupRoute(group.Id), group.ToJson()); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return GroupFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// DeleteGroup deletes a group and its memberships. Must have the manage_groups permission.
+func (c *Client4) DeleteGroup(groupId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetGroupRoute(groupId), ""); err != nil {
+		return false, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// GetGroupMembers returns a page of members of a group.
+func (c *Client4) GetGroupMembers(groupId string, page, perPage int) ([]*GroupMember, *Response) {
+	query := fmt.Sprintf("?page=%v&per_page=%v", page, perPage)
+	if r, err := c.DoApiGet(c.GetGroupMembersRoute(groupId)+query, ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return GroupMembersFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// AddGroupMember adds a user to a group. Must have the manage_groups permission.
+func (c *Client4) AddGroupMember(groupId, userId string) (*GroupMember, *Response) {
+	member := &GroupMember{GroupId: groupId, UserId: userId}
+	if r, err := c.DoApiPost(c.GetGroupMembersRoute(groupId), member.ToJson()); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return GroupMemberFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// RemoveGroupMember removes a user from a group. Must have the manage_groups permission.
+func (c *Client4) RemoveGroupMember(groupId, userId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetGroupMemberRoute(groupId, userId), ""); err != nil {
+		return false, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// Sidebar Categories Section
+
+// GetSidebarCategories returns the sidebar categories of a user on a team in display order.
+func (c *Client4) GetSidebarCategories(userId, teamId string) ([]*SidebarCategory, *Response) {
+	if r, err := c.DoApiGet(c.GetSidebarCategoriesRoute(userId, teamId), ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return SidebarCategoryListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetSidebarCategory returns a single sidebar category of a user on a team.
+func (c *Client4) GetSidebarCategory(userId, teamId, categoryId string) (*SidebarCategory, *Response) {
+	if r, err := c.DoApiGet(c.GetSidebarCategoryRoute(userId, teamId, categoryId), ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return SidebarCategoryFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// CreateSidebarCategory creates a custom sidebar category. Its channels are taken out of their current categories.
+func (c *Client4) CreateSidebarCategory(userId, teamId string, category *SidebarCategory) (*SidebarCategory, *Response) {
+	if r, err := c.DoApiPost(c.GetSidebarCategoriesRoute(userId, teamId), category.ToJson()); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return SidebarCategoryFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// UpdateSidebarCategory updates the channels, collapsed state and, for custom categories, the name of a category.
+func (c *Client4) UpdateSidebarCategory(userId, teamId string, category *SidebarCategory) (*SidebarCategory, *Response) {
+	if r, err := c.DoApiPut(c.GetSidebarCategoryRoute(userId, teamId, category.Id), category.ToJson()); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return SidebarCategoryFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// UpdateSidebarCategoryOrder reorders the sidebar categories of a user on a team.
+func (c *Client4) UpdateSidebarCategoryOrder(userId, teamId string, categoryIds []string) (bool, *Response) {
+	if r, err := c.DoApiPut(c.GetSidebarCategoriesRoute(userId, teamId)+"/order", ArrayToJson(categoryIds)); err != nil {
+		return false, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// DeleteSidebarCategory deletes a custom sidebar category. Its channels go back to the default categories.
+func (c *Client4) DeleteSidebarCategory(userId, teamId, categoryId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetSidebarCategoryRoute(userId, teamId, categoryId), ""); err != nil {
+		return false, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// Status Section
+
+// GetUserStatus returns the status of a user.
+func (c *Client4) GetUserStatus(userId string) (*Status, *Response) {
+	if r, err := c.DoApiGet(c.GetUserRoute(userId)+"/status", ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return StatusFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// UpdateUserStatus manually sets the status of a user. A Do Not Disturb status can be given an end time.
+func (c *Client4) UpdateUserStatus(userId string, status *Status) (*Status, *Response) {
+	if r, err := c.DoApiPut(c.GetUserRoute(userId)+"/status", status.ToJson()); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return StatusFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetUserCustomStatus returns the custom status of a user.
+func (c *Client4) GetUserCustomStatus(userId string) (*CustomStatus, *Response) {
+	if r, err := c.DoApiGet(c.GetUserRoute(userId)+"/custom_status", ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return CustomStatusFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// UpdateUserCustomStatus sets the custom status of a user, replacing any existing one.
+func (c *Client4) UpdateUserCustomStatus(userId string, status *CustomStatus) (*CustomStatus, *Response) {
+	if r, err := c.DoApiPut(c.GetUserRoute(userId)+"/custom_status", status.ToJson()); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return CustomStatusFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// RemoveUserCustomStatus clears the custom status of a user.
+func (c *Client4) RemoveUserCustomStatus(userId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetUserRoute(userId)+"/custom_status", ""); err != nil {
+		return false, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// GetUserOutOfOffice returns the out of office reply of a user while it's in effect.
+func (c *Client4) GetUserOutOfOffice(userId string) (*OutOfOffice, *Response) {
+	if r, err := c.DoApiGet(c.GetUserRoute(userId)+"/out_of_office", ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return OutOfOfficeFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// UpdateUserOutOfOffice sets the out of office reply of a user, replacing any existing one.
+func (c *Client4) UpdateUserOutOfOffice(userId string, outOfOffice *OutOfOffice) (*OutOfOffice, *Response) {
+	if r, err := c.DoApiPut(c.GetUserRoute(userId)+"/out_of_office", outOfOffice.ToJson()); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return OutOfOfficeFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// RemoveUserOutOfOffice turns off the out of office reply of a user.
+func (c *Client4) RemoveUserOutOfOffice(userId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetUserRoute(userId)+"/out_of_office", ""); err != nil {
+		return false, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// Bots Section
+
+// CreateBot creates a bot owned by the current user, or by the user given in BotOwnerId if the current user is a
+// system admin.
+func (c *Client4) CreateBot(bot *User) (*User, *Response) {
+	if r, err := c.DoApiPost(c.GetBotsRoute(), bot.ToJson()); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return UserFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetBots returns a page of the bots owned by the current user, or of every bot for a system admin.
+func (c *Client4) GetBots(page int, perPage int) ([]*User, *Response) {
+	query := fmt.Sprintf("?page=%v&per_page=%v", page, perPage)
+	if r, err := c.DoApiGet(c.GetBotsRoute()+query, ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return UserListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetBot returns a bot.
+func (c *Client4) GetBot(botUserId string) (*User, *Response) {
+	if r, err := c.DoApiGet(c.GetBotRoute(botUserId), ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return UserFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// DisableBot deactivates a bot and revokes all of its tokens.
+func (c *Client4) DisableBot(botUserId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetBotRoute(botUserId), ""); err != nil {
+		return false, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// UpdateBotOwner hands a bot over to another user.
+func (c *Client4) UpdateBotOwner(botUserId string, ownerId string) (*User, *Response) {
+	if r, err := c.DoApiPut(c.GetBotRoute(botUserId)+"/owner", MapToJson(map[string]string{"owner_id": ownerId})); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return UserFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// CreateBotToken creates a token for a bot. The token is only returned here and can't be retrieved later.
+func (c *Client4) CreateBotToken(botUserId string) (*Session, *Response) {
+	if r, err := c.DoApiPost(c.GetBotRoute(botUserId)+"/tokens", ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return SessionFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetBotTokens returns the tokens of a bot without their values.
+func (c *Client4) GetBotTokens(botUserId string) ([]*Session, *Response) {
+	if r, err := c.DoApiGet(c.GetBotRoute(botUserId)+"/tokens", ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return SessionsFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// RevokeBotToken revokes a token of a bot.
+func (c *Client4) RevokeBotToken(botUserId string, tokenId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetBotRoute(botUserId)+"/tokens/"+tokenId, ""); err != nil {
+		return false, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// Profile Attributes Section
+
+// CreateProfileAttribute creates a new profile attribute for every user. Only system admins can do this.
+func (c *Client4) CreateProfileAttribute(attribute *ProfileAttribute) (*ProfileAttribute, *Response) {
+	if r, err := c.DoApiPost(c.GetProfileAttributesRoute(), attribute.ToJson()); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return ProfileAttributeFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// GetProfileAttributes returns every profile attribute.
+func (c *Client4) GetProfileAttributes() ([]*ProfileAttribute, *Response) {
+	if r, err := c.DoApiGet(c.GetProfileAttributesRoute(), ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return ProfileAttributeListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// UpdateProfileAttribute updates a profile attribute. Only system admins can do this.
+func (c *Client4) UpdateProfileAttribute(attribute *ProfileAttribute) (*ProfileAttribute, *Response) {
+	if r, err := c.DoApiPut(c.GetProfileAttributeRoute(attribute.Id), attribute.ToJson()); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return ProfileAttributeFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// DeleteProfileAttribute deletes a profile attribute along with the values that users have for it.
+func (c *Client4) DeleteProfileAttribute(attributeId string) (bool, *Response) {
+	if r, err := c.DoApiDelete(c.GetProfileAttributeRoute(attributeId), ""); err != nil {
+		return false, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return CheckStatusOK(r), BuildResponse(r)
+	}
+}
+
+// SearchUsersByProfileAttribute returns up to perPage users whose value for the attribute starts with the term.
+func (c *Client4) SearchUsersByProfileAttribute(attributeId string, term string, perPage int) ([]*User, *Response) {
+	query := fmt.Sprintf("?term=%v&per_page=%v", url.QueryEscape(term), perPage)
+	if r, err := c.DoApiGet(c.GetProfileAttributeRoute(attributeId)+"/users"+query, ""); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return UserListFromJson(r.Body), BuildResponse(r)
+	}
+}
+
+// UpdateUserProfileAttributes sets the profile attributes of a user, keyed by attribute name. An empty value clears
+// the attribute.
+func (c *Client4) UpdateUserProfileAttributes(userId string, values map[string]string) (map[string]string, *Response) {
+	if r, err := c.DoApiPut(c.GetUserRoute(userId)+"/profile_attributes", MapToJson(values)); err != nil {
+		return nil, &Response{StatusCode: r.StatusCode, Error: err}
+	} else {
+		defer closeBody(r)
+		return MapFromJson(r.Body), BuildResponse(r)
+	}
+}
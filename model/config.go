@@ -27,6 +27,7 @@ const (
 	SERVICE_GITLAB    = "gitlab"
 	SERVICE_GOOGLE    = "google"
 	SERVICE_OFFICE365 = "office365"
+	SERVICE_OPENID    = "openid"
 
 	WEBSERVER_MODE_REGULAR  = "regular"
 	WEBSERVER_MODE_GZIP     = "gzip"
@@ -82,6 +83,7 @@ type ServiceSettings struct {
 	EnableOutgoingWebhooks                   bool
 	EnableCommands                           *bool
 	EnableOnlyAdminIntegrations              *bool
+	EnableUserAccessTokens                   *bool
 	EnablePostUsernameOverride               bool
 	EnablePostIconOverride                   bool
 	EnableTesting                            bool
@@ -106,6 +108,11 @@ type ServiceSettings struct {
 	TimeBetweenUserTypingUpdatesMilliseconds *int64
 	EnableUserTypingMessages                 *bool
 	ClusterLogTimeoutMilliseconds            *int
+	LinkMetadataCacheInMinutes               *int
+	EnableImageProxy                         *bool
+	ImageProxyMaxImageSize                   *int64
+	ImageProxyCacheInMinutes                 *int
+	AllowedUntrustedInternalConnections      *string
 }
 
 type ClusterSettings struct {
@@ -132,6 +139,7 @@ type SSOSettings struct {
 	AuthEndpoint    string
 	TokenEndpoint   string
 	UserApiEndpoint string
+	Issuer          string
 }
 
 type SqlSettings struct {
@@ -252,6 +260,8 @@ type TeamSettings struct {
 	UserStatusAwayTimeout            *int64
 	MaxChannelsPerTeam               *int64
 	MaxNotificationsPerChannel       *int64
+	EnableChannelAutoArchive         *bool
+	UsernameRedirectDays             *int
 }
 
 type LdapSettings struct {
@@ -357,6 +367,7 @@ type Config struct {
 	GitLabSettings       SSOSettings
 	GoogleSettings       SSOSettings
 	Office365Settings    SSOSettings
+	OpenIdSettings       SSOSettings
 	LdapSettings         LdapSettings
 	ComplianceSettings   ComplianceSettings
 	LocalizationSettings LocalizationSettings
@@ -385,6 +396,8 @@ func (o *Config) GetSSOService(service string) *SSOSettings {
 		return &o.GoogleSettings
 	case SERVICE_OFFICE365:
 		return &o.Office365Settings
+	case SERVICE_OPENID:
+		return &o.OpenIdSettings
 	}
 
 	return nil
@@ -579,6 +592,16 @@ func (o *Config) SetDefaults() {
 		*o.TeamSettings.MaxNotificationsPerChannel = 1000
 	}
 
+	if o.TeamSettings.EnableChannelAutoArchive == nil {
+		o.TeamSettings.EnableChannelAutoArchive = new(bool)
+		*o.TeamSettings.EnableChannelAutoArchive = false
+	}
+
+	if o.TeamSettings.UsernameRedirectDays == nil {
+		o.TeamSettings.UsernameRedirectDays = new(int)
+		*o.TeamSettings.UsernameRedirectDays = 30
+	}
+
 	if o.EmailSettings.EnableSignInWithEmail == nil {
 		o.EmailSettings.EnableSignInWithEmail = new(bool)
 
@@ -809,6 +832,11 @@ func (o *Config) SetDefaults() {
 		*o.ServiceSettings.EnableOnlyAdminIntegrations = true
 	}
 
+	if o.ServiceSettings.EnableUserAccessTokens == nil {
+		o.ServiceSettings.EnableUserAccessTokens = new(bool)
+		*o.ServiceSettings.EnableUserAccessTokens = false
+	}
+
 	if o.ServiceSettings.WebsocketPort == nil {
 		o.ServiceSettings.WebsocketPort = new(int)
 		*o.ServiceSettings.WebsocketPort = 80
@@ -1090,7 +1118,36 @@ func (o *Config) SetDefaults() {
 		*o.ServiceSettings.ClusterLogTimeoutMilliseconds = 2000
 	}
 
+	if o.ServiceSettings.LinkMetadataCacheInMinutes == nil {
+		o.ServiceSettings.LinkMetadataCacheInMinutes = new(int)
+		*o.ServiceSettings.LinkMetadataCacheInMinutes = 60
+	}
+
+	if o.ServiceSettings.EnableImageProxy == nil {
+		o.ServiceSettings.EnableImageProxy = new(bool)
+		*o.ServiceSettings.EnableImageProxy = false
+	}
+
+	if o.ServiceSettings.ImageProxyMaxImageSize == nil {
+		o.ServiceSettings.ImageProxyMaxImageSize = new(int64)
+		*o.ServiceSettings.ImageProxyMaxImageSize = 10485760 // 10 MB
+	}
+
+	if o.ServiceSettings.ImageProxyCacheInMinutes == nil {
+		o.ServiceSettings.ImageProxyCacheInMinutes = new(int)
+		*o.ServiceSettings.ImageProxyCacheInMinutes = 60
+	}
+
+	if o.ServiceSettings.AllowedUntrustedInternalConnections == nil {
+		o.ServiceSettings.AllowedUntrustedInternalConnections = new(string)
+		*o.ServiceSettings.AllowedUntrustedInternalConnections = ""
+	}
+
 	o.defaultWebrtcSettings()
+
+	if len(o.OpenIdSettings.Scope) == 0 {
+		o.OpenIdSettings.Scope = "openid profile email"
+	}
 }
 
 func (o *Config) IsValid() *AppError {
@@ -1129,6 +1186,10 @@ func (o *Config) IsValid() *AppError {
 		return NewLocAppError("Config.IsValid", "model.config.is_valid.max_notify_per_channel.app_error", nil, "")
 	}
 
+	if *o.TeamSettings.UsernameRedirectDays < 0 {
+		return NewLocAppError("Config.IsValid", "model.config.is_valid.username_redirect_days.app_error", nil, "")
+	}
+
 	if !(*o.TeamSettings.RestrictDirectMessage == DIRECT_MESSAGE_ANY || *o.TeamSettings.RestrictDirectMessage == DIRECT_MESSAGE_TEAM) {
 		return NewLocAppError("Config.IsValid", "model.config.is_valid.restrict_direct_message.app_error", nil, "")
 	}
@@ -1305,6 +1366,10 @@ func (o *Config) IsValid() *AppError {
 		return NewLocAppError("Config.IsValid", "model.config.is_valid.max_burst.app_error", nil, "")
 	}
 
+	if o.OpenIdSettings.Enable && !IsValidHttpUrl(o.OpenIdSettings.Issuer) {
+		return NewLocAppError("Config.IsValid", "model.config.is_valid.openid_issuer.app_error", nil, "")
+	}
+
 	if err := o.isValidWebrtcSettings(); err != nil {
 		return err
 	}
@@ -1325,6 +1390,18 @@ func (o *Config) IsValid() *AppError {
 		return NewLocAppError("Config.IsValid", "model.config.is_valid.time_between_user_typing.app_error", nil, "")
 	}
 
+	if *o.ServiceSettings.LinkMetadataCacheInMinutes < 0 {
+		return NewLocAppError("Config.IsValid", "model.config.is_valid.link_metadata_cache.app_error", nil, "")
+	}
+
+	if *o.ServiceSettings.ImageProxyMaxImageSize <= 0 {
+		return NewLocAppError("Config.IsValid", "model.config.is_valid.image_proxy_max_image_size.app_error", nil, "")
+	}
+
+	if *o.ServiceSettings.ImageProxyCacheInMinutes < 0 {
+		return NewLocAppError("Config.IsValid", "model.config.is_valid.image_proxy_cache.app_error", nil, "")
+	}
+
 	return nil
 }
 
@@ -1356,6 +1433,10 @@ func (o *Config) Sanitize() {
 		o.GitLabSettings.Secret = FAKE_SETTING
 	}
 
+	if len(o.OpenIdSettings.Secret) > 0 {
+		o.OpenIdSettings.Secret = FAKE_SETTING
+	}
+
 	o.SqlSettings.DataSource = FAKE_SETTING
 	o.SqlSettings.AtRestEncryptKey = FAKE_SETTING
 
@@ -0,0 +1,79 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"unicode/utf8"
+)
+
+const (
+	CUSTOM_STATUS_EMOJI_MAX_LENGTH = 64
+	CUSTOM_STATUS_TEXT_MAX_RUNES   = 100
+)
+
+// CustomStatus is a short message with an emoji that a user shows next to their name, such as "In a meeting". It's
+// cleared automatically after ExpiresAt unless that is zero.
+type CustomStatus struct {
+	UserId    string `json:"user_id"`
+	Emoji     string `json:"emoji"`
+	Text      string `json:"text"`
+	ExpiresAt int64  `json:"expires_at"`
+	UpdateAt  int64  `json:"update_at"`
+}
+
+func (o *CustomStatus) IsValid() *AppError {
+	if len(o.UserId) != 26 {
+		return NewAppError("CustomStatus.IsValid", "model.custom_status.is_valid.user_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.Emoji) == 0 && len(o.Text) == 0 {
+		return NewAppError("CustomStatus.IsValid", "model.custom_status.is_valid.empty.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	if len(o.Emoji) > CUSTOM_STATUS_EMOJI_MAX_LENGTH {
+		return NewAppError("CustomStatus.IsValid", "model.custom_status.is_valid.emoji.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(o.Text) > CUSTOM_STATUS_TEXT_MAX_RUNES {
+		return NewAppError("CustomStatus.IsValid", "model.custom_status.is_valid.text.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	if o.ExpiresAt < 0 {
+		return NewAppError("CustomStatus.IsValid", "model.custom_status.is_valid.expires_at.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (o *CustomStatus) PreSave() {
+	o.UpdateAt = GetMillis()
+}
+
+// IsExpired returns whether the custom status should no longer be shown at the given time.
+func (o *CustomStatus) IsExpired(now int64) bool {
+	return o.ExpiresAt > 0 && o.ExpiresAt <= now
+}
+
+func (o *CustomStatus) ToJson() string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return ""
+	} else {
+		return string(b)
+	}
+}
+
+func CustomStatusFromJson(data io.Reader) *CustomStatus {
+	decoder := json.NewDecoder(data)
+	var o CustomStatus
+	err := decoder.Decode(&o)
+	if err == nil {
+		return &o
+	} else {
+		return nil
+	}
+}
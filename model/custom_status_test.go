@@ -0,0 +1,69 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCustomStatusJson(t *testing.T) {
+	o := CustomStatus{UserId: NewId(), Emoji: "calendar", Text: "In a meeting", ExpiresAt: GetMillis()}
+	ro := CustomStatusFromJson(strings.NewReader(o.ToJson()))
+
+	if ro.UserId != o.UserId || ro.Emoji != o.Emoji || ro.Text != o.Text || ro.ExpiresAt != o.ExpiresAt {
+		t.Fatal("custom status should have matched")
+	}
+}
+
+func TestCustomStatusIsValid(t *testing.T) {
+	o := CustomStatus{}
+
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.UserId = NewId()
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid without an emoji or text")
+	}
+
+	o.Emoji = "palm_tree"
+	if err := o.IsValid(); err != nil {
+		t.Fatal(err)
+	}
+
+	o.Text = strings.Repeat("a", CUSTOM_STATUS_TEXT_MAX_RUNES+1)
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.Text = "On vacation until Monday"
+	o.Emoji = strings.Repeat("a", CUSTOM_STATUS_EMOJI_MAX_LENGTH+1)
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.Emoji = ""
+	o.ExpiresAt = -1
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+}
+
+func TestCustomStatusIsExpired(t *testing.T) {
+	now := GetMillis()
+
+	if (&CustomStatus{}).IsExpired(now) {
+		t.Fatal("custom status without an expiry shouldn't expire")
+	}
+
+	if (&CustomStatus{ExpiresAt: now + 1000}).IsExpired(now) {
+		t.Fatal("shouldn't have expired yet")
+	}
+
+	if !(&CustomStatus{ExpiresAt: now}).IsExpired(now) {
+		t.Fatal("should have expired")
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurring quiet hours are kept in the notify props of the user. Start and end are "HH:MM" times, days is a comma
+// separated list of weekdays from 0 for Sunday to 6 for Saturday that defaults to every day, and the timezone is an
+// IANA name that defaults to UTC. Quiet hours that end before they start run past midnight.
+const (
+	DND_SCHEDULE_START_NOTIFY_PROP    = "dnd_schedule_start"
+	DND_SCHEDULE_END_NOTIFY_PROP      = "dnd_schedule_end"
+	DND_SCHEDULE_DAYS_NOTIFY_PROP     = "dnd_schedule_days"
+	DND_SCHEDULE_TIMEZONE_NOTIFY_PROP = "dnd_schedule_timezone"
+)
+
+// IsValidDNDSchedule returns whether the quiet hours in the notify props, if any, can be understood.
+func IsValidDNDSchedule(props StringMap) bool {
+	if len(props[DND_SCHEDULE_START_NOTIFY_PROP]) == 0 && len(props[DND_SCHEDULE_END_NOTIFY_PROP]) == 0 {
+		return true
+	}
+
+	start, ok := parseDNDScheduleTime(props[DND_SCHEDULE_START_NOTIFY_PROP])
+	if !ok {
+		return false
+	}
+
+	end, ok := parseDNDScheduleTime(props[DND_SCHEDULE_END_NOTIFY_PROP])
+	if !ok || start == end {
+		return false
+	}
+
+	if _, ok := parseDNDScheduleDays(props[DND_SCHEDULE_DAYS_NOTIFY_PROP]); !ok {
+		return false
+	}
+
+	if _, ok := parseDNDScheduleLocation(props[DND_SCHEDULE_TIMEZONE_NOTIFY_PROP]); !ok {
+		return false
+	}
+
+	return true
+}
+
+// IsInDNDSchedule returns whether the given time falls within the quiet hours in the notify props.
+func IsInDNDSchedule(props StringMap, t time.Time) bool {
+	start, ok := parseDNDScheduleTime(props[DND_SCHEDULE_START_NOTIFY_PROP])
+	if !ok {
+		return false
+	}
+
+	end, ok := parseDNDScheduleTime(props[DND_SCHEDULE_END_NOTIFY_PROP])
+	if !ok || start == end {
+		return false
+	}
+
+	days, ok := parseDNDScheduleDays(props[DND_SCHEDULE_DAYS_NOTIFY_PROP])
+	if !ok {
+		return false
+	}
+
+	location, ok := parseDNDScheduleLocation(props[DND_SCHEDULE_TIMEZONE_NOTIFY_PROP])
+	if !ok {
+		return false
+	}
+
+	local := t.In(location)
+	minute := local.Hour()*60 + local.Minute()
+
+	if start < end {
+		return days[local.Weekday()] && minute >= start && minute < end
+	}
+
+	// The quiet hours run past midnight, so the early hours belong to the day before
+	if minute >= start {
+		return days[local.Weekday()]
+	} else if minute < end {
+		return days[(local.Weekday()+6)%7]
+	}
+
+	return false
+}
+
+// parseDNDScheduleTime returns the number of minutes since midnight of an "HH:MM" time.
+func parseDNDScheduleTime(s string) (int, bool) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 || len(parts[0]) != 2 || len(parts[1]) != 2 {
+		return 0, false
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil || hours < 0 || hours > 23 {
+		return 0, false
+	}
+
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil || minutes < 0 || minutes > 59 {
+		return 0, false
+	}
+
+	return hours*60 + minutes, true
+}
+
+func parseDNDScheduleDays(s string) (map[time.Weekday]bool, bool) {
+	days := make(map[time.Weekday]bool, 7)
+
+	if len(s) == 0 {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			days[day] = true
+		}
+		return days, true
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		day, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || day < int(time.Sunday) || day > int(time.Saturday) {
+			return nil, false
+		}
+		days[time.Weekday(day)] = true
+	}
+
+	return days, true
+}
+
+func parseDNDScheduleLocation(s string) (*time.Location, bool) {
+	if len(s) == 0 {
+		return time.UTC, true
+	}
+
+	location, err := time.LoadLocation(s)
+	if err != nil {
+		return nil, false
+	}
+
+	return location, true
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsValidDNDSchedule(t *testing.T) {
+	cases := []struct {
+		props    StringMap
+		expected bool
+	}{
+		{StringMap{}, true},
+		{StringMap{DND_SCHEDULE_START_NOTIFY_PROP: "22:00", DND_SCHEDULE_END_NOTIFY_PROP: "07:00"}, true},
+		{StringMap{DND_SCHEDULE_START_NOTIFY_PROP: "22:00", DND_SCHEDULE_END_NOTIFY_PROP: "07:00", DND_SCHEDULE_DAYS_NOTIFY_PROP: "1,2,3,4,5", DND_SCHEDULE_TIMEZONE_NOTIFY_PROP: "UTC"}, true},
+		{StringMap{DND_SCHEDULE_START_NOTIFY_PROP: "22:00"}, false},
+		{StringMap{DND_SCHEDULE_START_NOTIFY_PROP: "24:00", DND_SCHEDULE_END_NOTIFY_PROP: "07:00"}, false},
+		{StringMap{DND_SCHEDULE_START_NOTIFY_PROP: "7:00", DND_SCHEDULE_END_NOTIFY_PROP: "09:00"}, false},
+		{StringMap{DND_SCHEDULE_START_NOTIFY_PROP: "09:00", DND_SCHEDULE_END_NOTIFY_PROP: "09:00"}, false},
+		{StringMap{DND_SCHEDULE_START_NOTIFY_PROP: "22:00", DND_SCHEDULE_END_NOTIFY_PROP: "07:00", DND_SCHEDULE_DAYS_NOTIFY_PROP: "7"}, false},
+		{StringMap{DND_SCHEDULE_START_NOTIFY_PROP: "22:00", DND_SCHEDULE_END_NOTIFY_PROP: "07:00", DND_SCHEDULE_TIMEZONE_NOTIFY_PROP: "Nowhere/Special"}, false},
+	}
+
+	for _, c := range cases {
+		if IsValidDNDSchedule(c.props) != c.expected {
+			t.Fatalf("expected %v for %v", c.expected, c.props)
+		}
+	}
+}
+
+func TestIsInDNDSchedule(t *testing.T) {
+	// Monday
+	monday := time.Date(2017, time.April, 3, 0, 0, 0, 0, time.UTC)
+
+	daytime := StringMap{DND_SCHEDULE_START_NOTIFY_PROP: "09:00", DND_SCHEDULE_END_NOTIFY_PROP: "12:30", DND_SCHEDULE_DAYS_NOTIFY_PROP: "1"}
+
+	if !IsInDNDSchedule(daytime, monday.Add(10*time.Hour)) {
+		t.Fatal("should be in quiet hours")
+	}
+
+	if IsInDNDSchedule(daytime, monday.Add(12*time.Hour+30*time.Minute)) {
+		t.Fatal("quiet hours should be over")
+	}
+
+	if IsInDNDSchedule(daytime, monday.Add(24*time.Hour+10*time.Hour)) {
+		t.Fatal("shouldn't be in quiet hours on another day")
+	}
+
+	overnight := StringMap{DND_SCHEDULE_START_NOTIFY_PROP: "22:00", DND_SCHEDULE_END_NOTIFY_PROP: "07:00", DND_SCHEDULE_DAYS_NOTIFY_PROP: "1"}
+
+	if !IsInDNDSchedule(overnight, monday.Add(23*time.Hour)) {
+		t.Fatal("should be in quiet hours before midnight")
+	}
+
+	if !IsInDNDSchedule(overnight, monday.Add(24*time.Hour+6*time.Hour)) {
+		t.Fatal("should be in quiet hours after midnight")
+	}
+
+	if IsInDNDSchedule(overnight, monday.Add(6*time.Hour)) {
+		t.Fatal("early hours belong to the day before")
+	}
+
+	if IsInDNDSchedule(StringMap{}, monday) {
+		t.Fatal("shouldn't be in quiet hours without a schedule")
+	}
+
+	if location, err := time.LoadLocation("America/New_York"); err == nil {
+		zoned := StringMap{DND_SCHEDULE_START_NOTIFY_PROP: "09:00", DND_SCHEDULE_END_NOTIFY_PROP: "12:00", DND_SCHEDULE_TIMEZONE_NOTIFY_PROP: location.String()}
+
+		if !IsInDNDSchedule(zoned, time.Date(2017, time.April, 3, 10, 0, 0, 0, location).UTC()) {
+			t.Fatal("quiet hours should be applied in the timezone")
+		}
+
+		if IsInDNDSchedule(zoned, monday.Add(10*time.Hour)) {
+			t.Fatal("quiet hours shouldn't be applied in UTC")
+		}
+	}
+}
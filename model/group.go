@@ -0,0 +1,184 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"unicode/utf8"
+)
+
+const (
+	GROUP_NAME_MAX_LENGTH        = 64
+	GROUP_DISPLAY_NAME_MAX_RUNES = 64
+	GROUP_DESCRIPTION_MAX_RUNES  = 1024
+)
+
+type Group struct {
+	Id          string `json:"id"`
+	CreateAt    int64  `json:"create_at"`
+	UpdateAt    int64  `json:"update_at"`
+	DeleteAt    int64  `json:"delete_at"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Description string `json:"description"`
+	CreatorId   string `json:"creator_id"`
+}
+
+type GroupMember struct {
+	GroupId  string `json:"group_id"`
+	UserId   string `json:"user_id"`
+	CreateAt int64  `json:"create_at"`
+}
+
+func (o *Group) IsValid() *AppError {
+	if len(o.Id) != 26 {
+		return NewLocAppError("Group.IsValid", "model.group.is_valid.id.app_error", nil, "")
+	}
+
+	if o.CreateAt == 0 {
+		return NewLocAppError("Group.IsValid", "model.group.is_valid.create_at.app_error", nil, "id="+o.Id)
+	}
+
+	if o.UpdateAt == 0 {
+		return NewLocAppError("Group.IsValid", "model.group.is_valid.update_at.app_error", nil, "id="+o.Id)
+	}
+
+	if !IsValidGroupName(o.Name) {
+		return NewLocAppError("Group.IsValid", "model.group.is_valid.name.app_error", nil, "id="+o.Id)
+	}
+
+	if utf8.RuneCountInString(o.DisplayName) == 0 || utf8.RuneCountInString(o.DisplayName) > GROUP_DISPLAY_NAME_MAX_RUNES {
+		return NewLocAppError("Group.IsValid", "model.group.is_valid.display_name.app_error", nil, "id="+o.Id)
+	}
+
+	if utf8.RuneCountInString(o.Description) > GROUP_DESCRIPTION_MAX_RUNES {
+		return NewLocAppError("Group.IsValid", "model.group.is_valid.description.app_error", nil, "id="+o.Id)
+	}
+
+	if len(o.CreatorId) > 26 {
+		return NewLocAppError("Group.IsValid", "model.group.is_valid.creator_id.app_error", nil, "id="+o.Id)
+	}
+
+	return nil
+}
+
+func (o *Group) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	o.CreateAt = GetMillis()
+	o.UpdateAt = o.CreateAt
+}
+
+func (o *Group) PreUpdate() {
+	o.UpdateAt = GetMillis()
+}
+
+// IsValidGroupName checks that a group can be mentioned by name. Group names share the username character set
+// and must not clash with the channel-wide mentions.
+func IsValidGroupName(name string) bool {
+	if !IsValidUsername(name) || len(name) > GROUP_NAME_MAX_LENGTH {
+		return false
+	}
+
+	return name != "here"
+}
+
+func (o *Group) ToJson() string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return ""
+	} else {
+		return string(b)
+	}
+}
+
+func GroupFromJson(data io.Reader) *Group {
+	decoder := json.NewDecoder(data)
+	var o Group
+	err := decoder.Decode(&o)
+	if err == nil {
+		return &o
+	} else {
+		return nil
+	}
+}
+
+func GroupListToJson(groups []*Group) string {
+	b, err := json.Marshal(groups)
+	if err != nil {
+		return ""
+	} else {
+		return string(b)
+	}
+}
+
+func GroupListFromJson(data io.Reader) []*Group {
+	decoder := json.NewDecoder(data)
+	var groups []*Group
+	err := decoder.Decode(&groups)
+	if err == nil {
+		return groups
+	} else {
+		return nil
+	}
+}
+
+func (o *GroupMember) IsValid() *AppError {
+	if len(o.GroupId) != 26 {
+		return NewLocAppError("GroupMember.IsValid", "model.group_member.is_valid.group_id.app_error", nil, "")
+	}
+
+	if len(o.UserId) != 26 {
+		return NewLocAppError("GroupMember.IsValid", "model.group_member.is_valid.user_id.app_error", nil, "")
+	}
+
+	return nil
+}
+
+func (o *GroupMember) PreSave() {
+	o.CreateAt = GetMillis()
+}
+
+func (o *GroupMember) ToJson() string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return ""
+	} else {
+		return string(b)
+	}
+}
+
+func GroupMemberFromJson(data io.Reader) *GroupMember {
+	decoder := json.NewDecoder(data)
+	var o GroupMember
+	err := decoder.Decode(&o)
+	if err == nil {
+		return &o
+	} else {
+		return nil
+	}
+}
+
+func GroupMembersToJson(members []*GroupMember) string {
+	b, err := json.Marshal(members)
+	if err != nil {
+		return ""
+	} else {
+		return string(b)
+	}
+}
+
+func GroupMembersFromJson(data io.Reader) []*GroupMember {
+	decoder := json.NewDecoder(data)
+	var members []*GroupMember
+	err := decoder.Decode(&members)
+	if err == nil {
+		return members
+	} else {
+		return nil
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGroupJson(t *testing.T) {
+	o := Group{Id: NewId(), Name: NewId()}
+	json := o.ToJson()
+	ro := GroupFromJson(strings.NewReader(json))
+
+	if o.Id != ro.Id {
+		t.Fatal("Ids do not match")
+	}
+
+	groups := GroupListFromJson(strings.NewReader(GroupListToJson([]*Group{&o})))
+	if len(groups) != 1 || groups[0].Id != o.Id {
+		t.Fatal("group list did not round trip")
+	}
+}
+
+func TestGroupIsValid(t *testing.T) {
+	o := Group{}
+
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.PreSave()
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.Name = "oncall"
+	o.DisplayName = "On Call"
+	if err := o.IsValid(); err != nil {
+		t.Fatal(err)
+	}
+
+	o.Name = "On Call"
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.Name = "here"
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.Name = "channel"
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.Name = strings.Repeat("a", 65)
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.Name = "oncall"
+	o.DisplayName = strings.Repeat("a", 65)
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.DisplayName = "On Call"
+	o.Description = strings.Repeat("a", 1025)
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+}
+
+func TestGroupMemberIsValid(t *testing.T) {
+	o := GroupMember{}
+
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.GroupId = NewId()
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.UserId = NewId()
+	if err := o.IsValid(); err != nil {
+		t.Fatal(err)
+	}
+
+	members := GroupMembersFromJson(strings.NewReader(GroupMembersToJson([]*GroupMember{&o})))
+	if len(members) != 1 || members[0].UserId != o.UserId {
+		t.Fatal("member list did not round trip")
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+// MfaTimeStep is the most recent TOTP time step that a user authenticated with. Tokens from that time step or an
+// earlier one are rejected so that a token can't be used twice.
+type MfaTimeStep struct {
+	UserId   string `json:"user_id"`
+	TimeStep int64  `json:"time_step"`
+}
+
+// MfaBackupCode is a one-time code that a user can authenticate with instead of a TOTP token, for example after
+// losing their phone. Only a hash of the code is kept.
+type MfaBackupCode struct {
+	UserId   string `json:"user_id"`
+	CodeHash string `json:"code_hash"`
+	CreateAt int64  `json:"create_at"`
+}
@@ -0,0 +1,11 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+const (
+	USER_AUTH_SERVICE_OPENID = "openid"
+
+	OPENID_NONCE_COOKIE         = "MMOIDCNONCE"
+	OPENID_NONCE_COOKIE_MAX_AGE = 30 * 60
+)
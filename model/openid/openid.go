@@ -0,0 +1,405 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package oauthopenid
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattermost/platform/einterfaces"
+	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
+	"gopkg.in/square/go-jose.v1"
+)
+
+const (
+	DISCOVERY_PATH             = "/.well-known/openid-configuration"
+	DISCOVERY_CACHE_SECONDS    = 60 * 60
+	MIN_REFRESH_SECONDS        = 60
+	ALLOWED_CLOCK_SKEW_SECONDS = 60
+	REQUEST_TIMEOUT            = 30 * time.Second
+)
+
+// OpenIdProvider logs users in with any identity provider that supports OpenID Connect, such as Keycloak. Only the
+// issuer has to be configured since everything else is read from the provider's discovery document.
+type OpenIdProvider struct {
+	mutex       sync.Mutex
+	discovery   map[string]*discovery
+	refreshedAt map[string]int64
+}
+
+// OpenIdUser holds the standard claims that users are created from.
+type OpenIdUser struct {
+	Subject           string `json:"sub"`
+	PreferredUsername string `json:"preferred_username"`
+	Nickname          string `json:"nickname"`
+	Email             string `json:"email"`
+	EmailVerified     *bool  `json:"email_verified"`
+	Name              string `json:"name"`
+	GivenName         string `json:"given_name"`
+	FamilyName        string `json:"family_name"`
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+// discovery is the discovery document of an issuer along with its signing keys.
+type discovery struct {
+	discoveryDocument
+	keys      *jose.JsonWebKeySet
+	expiresAt int64
+}
+
+type idTokenClaims struct {
+	Issuer          string   `json:"iss"`
+	Subject         string   `json:"sub"`
+	Audience        audience `json:"aud"`
+	AuthorizedParty string   `json:"azp"`
+	ExpiresAt       int64    `json:"exp"`
+	IssuedAt        int64    `json:"iat"`
+	Nonce           string   `json:"nonce"`
+}
+
+// audience is the aud claim, which is either a single client id or a list of them.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+
+	*a = audience(multiple)
+	return nil
+}
+
+func (a audience) contains(clientId string) bool {
+	for _, id := range a {
+		if id == clientId {
+			return true
+		}
+	}
+
+	return false
+}
+
+func init() {
+	provider := &OpenIdProvider{}
+	einterfaces.RegisterOauthProvider(model.USER_AUTH_SERVICE_OPENID, provider)
+}
+
+func userFromOpenIdUser(oiu *OpenIdUser) *model.User {
+	user := &model.User{}
+
+	username := oiu.PreferredUsername
+	if username == "" {
+		username = oiu.Nickname
+	}
+	if username == "" {
+		username = strings.Split(oiu.Email, "@")[0]
+	}
+	user.Username = model.CleanUsername(username)
+
+	if oiu.GivenName != "" || oiu.FamilyName != "" {
+		user.FirstName = oiu.GivenName
+		user.LastName = oiu.FamilyName
+	} else if splitName := strings.SplitN(strings.TrimSpace(oiu.Name), " ", 2); len(splitName) == 2 {
+		user.FirstName = splitName[0]
+		user.LastName = splitName[1]
+	} else {
+		user.FirstName = oiu.Name
+	}
+
+	user.Email = strings.ToLower(strings.TrimSpace(oiu.Email))
+	userId := oiu.Subject
+	user.AuthData = &userId
+	user.AuthService = model.USER_AUTH_SERVICE_OPENID
+
+	return user
+}
+
+func openIdUserFromJson(data io.Reader) *OpenIdUser {
+	decoder := json.NewDecoder(data)
+	var oiu OpenIdUser
+	err := decoder.Decode(&oiu)
+	if err == nil {
+		return &oiu
+	} else {
+		return nil
+	}
+}
+
+// IsValid returns whether the claims identify the user and give an email address that the provider hasn't said is
+// unverified.
+func (oiu *OpenIdUser) IsValid() bool {
+	if len(oiu.Subject) == 0 {
+		return false
+	}
+
+	if len(oiu.Email) == 0 {
+		return false
+	}
+
+	if oiu.EmailVerified != nil && !*oiu.EmailVerified {
+		return false
+	}
+
+	return true
+}
+
+func (m *OpenIdProvider) GetIdentifier() string {
+	return model.USER_AUTH_SERVICE_OPENID
+}
+
+func (m *OpenIdProvider) GetUserFromJson(data io.Reader) *model.User {
+	oiu := openIdUserFromJson(data)
+	if oiu != nil && oiu.IsValid() {
+		return userFromOpenIdUser(oiu)
+	}
+
+	return &model.User{}
+}
+
+func (m *OpenIdProvider) GetAuthDataFromJson(data io.Reader) string {
+	oiu := openIdUserFromJson(data)
+	if oiu != nil && oiu.IsValid() {
+		return oiu.Subject
+	}
+
+	return ""
+}
+
+// The issuer is configured by an admin and is often on an internal network, so unlike utils.HttpClient these transports
+// don't refuse to connect to internal addresses
+var secureTransport = &http.Transport{}
+var insecureTransport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+
+func httpClient() *http.Client {
+	if utils.Cfg.ServiceSettings.EnableInsecureOutgoingConnections != nil && *utils.Cfg.ServiceSettings.EnableInsecureOutgoingConnections {
+		return &http.Client{Transport: insecureTransport, Timeout: REQUEST_TIMEOUT}
+	}
+
+	return &http.Client{Transport: secureTransport, Timeout: REQUEST_TIMEOUT}
+}
+
+func getJson(url string, accessToken string, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if len(accessToken) > 0 {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	resp, err := httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("unexpected status " + resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// getDiscovery returns the discovery document and signing keys of the issuer, which are cached for an hour. Passing
+// refresh fetches them again regardless, which is used when an ID token is signed with a key that isn't known yet. Since
+// anyone can send such a token, that only happens once a minute per issuer.
+func (m *OpenIdProvider) getDiscovery(issuer string, refresh bool) (*discovery, *model.AppError) {
+	now := time.Now().Unix()
+
+	m.mutex.Lock()
+	cached, ok := m.discovery[issuer]
+	if ok && refresh && m.refreshedAt[issuer]+MIN_REFRESH_SECONDS > now {
+		refresh = false
+	}
+	if ok && !refresh && cached.expiresAt > now {
+		m.mutex.Unlock()
+		return cached, nil
+	}
+	if refresh {
+		if m.refreshedAt == nil {
+			m.refreshedAt = make(map[string]int64)
+		}
+		m.refreshedAt[issuer] = now
+	}
+	m.mutex.Unlock()
+
+	// The mutex isn't held while fetching so that a slow issuer doesn't hold up logins that can use the cache
+	d := &discovery{}
+	if err := getJson(strings.TrimSuffix(issuer, "/")+DISCOVERY_PATH, "", &d.discoveryDocument); err != nil {
+		return nil, model.NewLocAppError("OpenIdProvider.getDiscovery", "openid.discovery.app_error", nil, "issuer="+issuer+", "+err.Error())
+	}
+
+	// The issuer in the document has to be the one it was fetched from so that a provider can't speak for another
+	if d.Issuer != issuer || len(d.AuthorizationEndpoint) == 0 || len(d.TokenEndpoint) == 0 || len(d.JwksUri) == 0 {
+		return nil, model.NewLocAppError("OpenIdProvider.getDiscovery", "openid.discovery.invalid.app_error", nil, "issuer="+issuer+", discovered_issuer="+d.Issuer)
+	}
+
+	d.keys = &jose.JsonWebKeySet{}
+	if err := getJson(d.JwksUri, "", d.keys); err != nil {
+		return nil, model.NewLocAppError("OpenIdProvider.getDiscovery", "openid.discovery.keys.app_error", nil, "jwks_uri="+d.JwksUri+", "+err.Error())
+	}
+
+	d.expiresAt = time.Now().Unix() + DISCOVERY_CACHE_SECONDS
+
+	m.mutex.Lock()
+	if m.discovery == nil {
+		m.discovery = make(map[string]*discovery)
+	}
+	m.discovery[issuer] = d
+	m.mutex.Unlock()
+
+	return d, nil
+}
+
+func (m *OpenIdProvider) GetEndpoints(sso *model.SSOSettings) (string, string, *model.AppError) {
+	d, err := m.getDiscovery(sso.Issuer, false)
+	if err != nil {
+		return "", "", err
+	}
+
+	return d.AuthorizationEndpoint, d.TokenEndpoint, nil
+}
+
+// GetUserData validates the ID token and returns its claims as JSON for GetUserFromJson. Providers may leave profile
+// claims out of the ID token and only return them from their user info endpoint, so any claims from there that the ID
+// token doesn't have are added.
+func (m *OpenIdProvider) GetUserData(sso *model.SSOSettings, accessToken string, idToken string, nonce string) ([]byte, *model.AppError) {
+	claims, err := m.ValidateIdToken(sso, idToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := m.getDiscovery(sso.Issuer, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(d.UserInfoEndpoint) > 0 && len(accessToken) > 0 {
+		userInfo := map[string]interface{}{}
+		if err := getJson(d.UserInfoEndpoint, accessToken, &userInfo); err != nil {
+			return nil, model.NewLocAppError("OpenIdProvider.GetUserData", "openid.get_user_data.user_info.app_error", nil, err.Error())
+		}
+
+		// The user info has to be about the user that the ID token is for
+		if userInfo["sub"] != claims["sub"] {
+			return nil, model.NewLocAppError("OpenIdProvider.GetUserData", "openid.get_user_data.subject.app_error", nil, "")
+		}
+
+		for name, value := range userInfo {
+			if _, ok := claims[name]; !ok {
+				claims[name] = value
+			}
+		}
+	}
+
+	data, _ := json.Marshal(claims)
+	return data, nil
+}
+
+// ValidateIdToken checks that the ID token was signed by the issuer for this client and the login with the given nonce,
+// and that it hasn't expired. It returns the claims of the token.
+func (m *OpenIdProvider) ValidateIdToken(sso *model.SSOSettings, idToken string, nonce string) (map[string]interface{}, *model.AppError) {
+	if len(idToken) == 0 {
+		return nil, model.NewLocAppError("OpenIdProvider.ValidateIdToken", "openid.validate_id_token.missing.app_error", nil, "")
+	}
+
+	signed, err := jose.ParseSigned(idToken)
+	if err != nil || len(signed.Signatures) != 1 {
+		return nil, model.NewLocAppError("OpenIdProvider.ValidateIdToken", "openid.validate_id_token.parse.app_error", nil, "")
+	}
+
+	payload, appErr := m.verify(sso.Issuer, signed)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	var claims idTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, model.NewLocAppError("OpenIdProvider.ValidateIdToken", "openid.validate_id_token.parse.app_error", nil, err.Error())
+	}
+
+	now := time.Now().Unix()
+
+	if claims.Issuer != sso.Issuer {
+		return nil, model.NewLocAppError("OpenIdProvider.ValidateIdToken", "openid.validate_id_token.issuer.app_error", nil, "iss="+claims.Issuer)
+	} else if !claims.Audience.contains(sso.Id) || (len(claims.Audience) > 1 && claims.AuthorizedParty != sso.Id) {
+		return nil, model.NewLocAppError("OpenIdProvider.ValidateIdToken", "openid.validate_id_token.audience.app_error", nil, "")
+	} else if claims.ExpiresAt+ALLOWED_CLOCK_SKEW_SECONDS < now || claims.IssuedAt-ALLOWED_CLOCK_SKEW_SECONDS > now {
+		return nil, model.NewLocAppError("OpenIdProvider.ValidateIdToken", "openid.validate_id_token.expired.app_error", nil, "")
+	} else if len(nonce) == 0 || subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, model.NewLocAppError("OpenIdProvider.ValidateIdToken", "openid.validate_id_token.nonce.app_error", nil, "")
+	} else if len(claims.Subject) == 0 {
+		return nil, model.NewLocAppError("OpenIdProvider.ValidateIdToken", "openid.validate_id_token.subject.app_error", nil, "")
+	}
+
+	allClaims := map[string]interface{}{}
+	json.Unmarshal(payload, &allClaims)
+
+	return allClaims, nil
+}
+
+// verify checks the signature of the token against the issuer's published keys, fetching them again if the token
+// names a key that isn't known yet in case the issuer has rotated its keys. Keys embedded in the token are ignored.
+func (m *OpenIdProvider) verify(issuer string, signed *jose.JsonWebSignature) ([]byte, *model.AppError) {
+	header := signed.Signatures[0].Header
+
+	// Only asymmetric algorithms are accepted so that a public key can never be used as an HMAC secret
+	switch jose.SignatureAlgorithm(header.Algorithm) {
+	case jose.RS256, jose.RS384, jose.RS512, jose.PS256, jose.PS384, jose.PS512, jose.ES256, jose.ES384, jose.ES512:
+	default:
+		return nil, model.NewLocAppError("OpenIdProvider.verify", "openid.validate_id_token.algorithm.app_error", nil, "alg="+header.Algorithm)
+	}
+
+	for _, refresh := range []bool{false, true} {
+		d, err := m.getDiscovery(issuer, refresh)
+		if err != nil {
+			return nil, err
+		}
+
+		keys := d.keys.Keys
+		if len(header.KeyID) > 0 {
+			keys = d.keys.Key(header.KeyID)
+		}
+
+		for _, key := range keys {
+			if !key.IsPublic() || (len(key.Algorithm) > 0 && key.Algorithm != header.Algorithm) {
+				continue
+			}
+
+			if payload, err := signed.Verify(&key); err == nil {
+				return payload, nil
+			}
+		}
+
+		if len(keys) > 0 {
+			break
+		}
+	}
+
+	return nil, model.NewLocAppError("OpenIdProvider.verify", "openid.validate_id_token.signature.app_error", nil, "kid="+header.KeyID)
+}
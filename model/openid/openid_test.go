@@ -0,0 +1,280 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package oauthopenid
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mattermost/platform/model"
+	"gopkg.in/square/go-jose.v1"
+)
+
+const TEST_CLIENT_ID = "testclient"
+
+// testIssuer is an identity provider that serves a discovery document, its signing keys and user info.
+type testIssuer struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	keyId    string
+	userInfo map[string]interface{}
+	fetches  int32
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	issuer := &testIssuer{keyId: "key1"}
+	issuer.key = newKey(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(DISCOVERY_PATH, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&issuer.fetches, 1)
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer.server.URL,
+			"authorization_endpoint": issuer.server.URL + "/auth",
+			"token_endpoint":         issuer.server.URL + "/token",
+			"userinfo_endpoint":      issuer.server.URL + "/userinfo",
+			"jwks_uri":               issuer.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jose.JsonWebKeySet{Keys: []jose.JsonWebKey{
+			{Key: &issuer.key.PublicKey, KeyID: issuer.keyId, Algorithm: string(jose.RS256), Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer accesstoken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(issuer.userInfo)
+	})
+	issuer.server = httptest.NewServer(mux)
+
+	return issuer
+}
+
+func newKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func (issuer *testIssuer) settings() *model.SSOSettings {
+	return &model.SSOSettings{Enable: true, Id: TEST_CLIENT_ID, Issuer: issuer.server.URL}
+}
+
+func (issuer *testIssuer) claims(nonce string) map[string]interface{} {
+	return map[string]interface{}{
+		"iss":   issuer.server.URL,
+		"sub":   "user1",
+		"aud":   TEST_CLIENT_ID,
+		"exp":   time.Now().Unix() + 300,
+		"iat":   time.Now().Unix(),
+		"nonce": nonce,
+	}
+}
+
+func sign(t *testing.T, key *rsa.PrivateKey, keyId string, claims map[string]interface{}) string {
+	signer, err := jose.NewSigner(jose.RS256, &jose.JsonWebKey{Key: key, KeyID: keyId})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, _ := json.Marshal(claims)
+	signed, err := signer.Sign(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := signed.CompactSerialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func TestGetEndpoints(t *testing.T) {
+	issuer := newTestIssuer(t)
+	defer issuer.server.Close()
+
+	provider := &OpenIdProvider{}
+
+	if authEndpoint, tokenEndpoint, err := provider.GetEndpoints(issuer.settings()); err != nil {
+		t.Fatal(err)
+	} else if authEndpoint != issuer.server.URL+"/auth" || tokenEndpoint != issuer.server.URL+"/token" {
+		t.Fatal("should have discovered the endpoints", authEndpoint, tokenEndpoint)
+	}
+
+	sso := issuer.settings()
+	sso.Issuer = issuer.server.URL + "/"
+	if _, _, err := provider.GetEndpoints(sso); err == nil {
+		t.Fatal("should have failed - discovered issuer doesn't match")
+	}
+}
+
+func TestValidateIdToken(t *testing.T) {
+	issuer := newTestIssuer(t)
+	defer issuer.server.Close()
+
+	provider := &OpenIdProvider{}
+	sso := issuer.settings()
+	nonce := model.NewId()
+
+	if claims, err := provider.ValidateIdToken(sso, sign(t, issuer.key, issuer.keyId, issuer.claims(nonce)), nonce); err != nil {
+		t.Fatal(err)
+	} else if claims["sub"] != "user1" {
+		t.Fatal("should have returned the claims")
+	}
+
+	if _, err := provider.ValidateIdToken(sso, sign(t, issuer.key, issuer.keyId, issuer.claims(nonce)), model.NewId()); err == nil {
+		t.Fatal("should have failed - wrong nonce")
+	}
+
+	if _, err := provider.ValidateIdToken(sso, sign(t, issuer.key, issuer.keyId, issuer.claims("")), ""); err == nil {
+		t.Fatal("should have failed - no nonce")
+	}
+
+	claims := issuer.claims(nonce)
+	claims["aud"] = "otherclient"
+	if _, err := provider.ValidateIdToken(sso, sign(t, issuer.key, issuer.keyId, claims), nonce); err == nil {
+		t.Fatal("should have failed - wrong audience")
+	}
+
+	claims["aud"] = []string{"otherclient", TEST_CLIENT_ID}
+	if _, err := provider.ValidateIdToken(sso, sign(t, issuer.key, issuer.keyId, claims), nonce); err == nil {
+		t.Fatal("should have failed - multiple audiences without azp")
+	}
+
+	claims["azp"] = TEST_CLIENT_ID
+	if _, err := provider.ValidateIdToken(sso, sign(t, issuer.key, issuer.keyId, claims), nonce); err != nil {
+		t.Fatal(err)
+	}
+
+	claims = issuer.claims(nonce)
+	claims["exp"] = time.Now().Unix() - 2*ALLOWED_CLOCK_SKEW_SECONDS
+	if _, err := provider.ValidateIdToken(sso, sign(t, issuer.key, issuer.keyId, claims), nonce); err == nil {
+		t.Fatal("should have failed - expired")
+	}
+
+	claims = issuer.claims(nonce)
+	claims["iss"] = "https://example.com"
+	if _, err := provider.ValidateIdToken(sso, sign(t, issuer.key, issuer.keyId, claims), nonce); err == nil {
+		t.Fatal("should have failed - wrong issuer")
+	}
+
+	if _, err := provider.ValidateIdToken(sso, sign(t, newKey(t), issuer.keyId, issuer.claims(nonce)), nonce); err == nil {
+		t.Fatal("should have failed - signed with another key")
+	}
+
+	token := sign(t, issuer.key, issuer.keyId, issuer.claims(nonce))
+	parts := strings.Split(token, ".")
+	tampered, _ := json.Marshal(map[string]interface{}{"sub": "admin"})
+	if _, err := provider.ValidateIdToken(sso, parts[0]+"."+base64.RawURLEncoding.EncodeToString(tampered)+"."+parts[2], nonce); err == nil {
+		t.Fatal("should have failed - tampered payload")
+	}
+
+	if _, err := provider.ValidateIdToken(sso, "", nonce); err == nil {
+		t.Fatal("should have failed - no token")
+	}
+
+	// Tokens signed with a new key are accepted once the issuer publishes it
+	issuer.key = newKey(t)
+	issuer.keyId = "key2"
+	if _, err := provider.ValidateIdToken(sso, sign(t, issuer.key, issuer.keyId, issuer.claims(nonce)), nonce); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tokens naming unknown keys only make the keys get fetched again once a minute
+	fetches := atomic.LoadInt32(&issuer.fetches)
+	issuer.key = newKey(t)
+	issuer.keyId = "key3"
+	if _, err := provider.ValidateIdToken(sso, sign(t, issuer.key, issuer.keyId, issuer.claims(nonce)), nonce); err == nil {
+		t.Fatal("should have failed - keys fetched again too soon")
+	} else if atomic.LoadInt32(&issuer.fetches) != fetches {
+		t.Fatal("shouldn't have fetched the keys again")
+	}
+}
+
+func TestGetUserData(t *testing.T) {
+	issuer := newTestIssuer(t)
+	defer issuer.server.Close()
+
+	provider := &OpenIdProvider{}
+	sso := issuer.settings()
+	nonce := model.NewId()
+
+	claims := issuer.claims(nonce)
+	claims["preferred_username"] = "Test.User"
+	issuer.userInfo = map[string]interface{}{
+		"sub":                "user1",
+		"preferred_username": "ignored",
+		"email":              "Test.User@Example.com",
+		"email_verified":     true,
+		"given_name":         "Test",
+		"family_name":        "User",
+	}
+
+	data, err := provider.GetUserData(sso, "accesstoken", sign(t, issuer.key, issuer.keyId, claims), nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := provider.GetUserFromJson(bytes.NewReader(data))
+	if user.Username != "test.user" || user.Email != "test.user@example.com" {
+		t.Fatal("should have mapped the username and email", user.Username, user.Email)
+	} else if user.FirstName != "Test" || user.LastName != "User" {
+		t.Fatal("should have mapped the names")
+	} else if user.AuthService != model.USER_AUTH_SERVICE_OPENID || *user.AuthData != "user1" {
+		t.Fatal("should have set the auth data")
+	}
+
+	if authData := provider.GetAuthDataFromJson(bytes.NewReader(data)); authData != "user1" {
+		t.Fatal("should have returned the subject")
+	}
+
+	issuer.userInfo["sub"] = "user2"
+	if _, err := provider.GetUserData(sso, "accesstoken", sign(t, issuer.key, issuer.keyId, claims), nonce); err == nil {
+		t.Fatal("should have failed - user info for another user")
+	}
+
+	if _, err := provider.GetUserData(sso, "badtoken", sign(t, issuer.key, issuer.keyId, claims), nonce); err == nil {
+		t.Fatal("should have failed - user info request rejected")
+	}
+}
+
+func TestGetUserFromJson(t *testing.T) {
+	provider := &OpenIdProvider{}
+
+	user := provider.GetUserFromJson(strings.NewReader(`{"sub": "user1", "email": "someone@example.com", "name": "Some One Else"}`))
+	if user.Username != "someone" {
+		t.Fatal("should have used the email address for the username", user.Username)
+	} else if user.FirstName != "Some" || user.LastName != "One Else" {
+		t.Fatal("should have split the name")
+	}
+
+	if user := provider.GetUserFromJson(strings.NewReader(`{"sub": "user1", "email": "someone@example.com", "email_verified": false}`)); user.AuthData != nil {
+		t.Fatal("should have rejected an unverified email address")
+	}
+
+	if user := provider.GetUserFromJson(strings.NewReader(`{"email": "someone@example.com"}`)); user.AuthData != nil {
+		t.Fatal("should have rejected claims without a subject")
+	}
+
+	if authData := provider.GetAuthDataFromJson(strings.NewReader(`not json`)); authData != "" {
+		t.Fatal("should have rejected invalid json")
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"unicode/utf8"
+)
+
+const (
+	OUT_OF_OFFICE_MESSAGE_MAX_RUNES = 1000
+)
+
+// OutOfOffice is an automatic reply that is sent on behalf of a user to the people who message or mention them between
+// StartAt and EndAt. A zero StartAt starts right away. It's removed automatically once EndAt has passed.
+type OutOfOffice struct {
+	UserId   string `json:"user_id"`
+	Message  string `json:"message"`
+	StartAt  int64  `json:"start_at"`
+	EndAt    int64  `json:"end_at"`
+	UpdateAt int64  `json:"update_at"`
+}
+
+// OutOfOfficeReply records when a user last got an automatic reply from someone who is out of office so that they
+// aren't sent more than one a day.
+type OutOfOfficeReply struct {
+	UserId   string `json:"user_id"`
+	SenderId string `json:"sender_id"`
+	ReplyAt  int64  `json:"reply_at"`
+}
+
+func (o *OutOfOffice) IsValid() *AppError {
+	if len(o.UserId) != 26 {
+		return NewAppError("OutOfOffice.IsValid", "model.out_of_office.is_valid.user_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.Message) == 0 || utf8.RuneCountInString(o.Message) > OUT_OF_OFFICE_MESSAGE_MAX_RUNES {
+		return NewAppError("OutOfOffice.IsValid", "model.out_of_office.is_valid.message.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	if o.StartAt < 0 || o.EndAt <= o.StartAt {
+		return NewAppError("OutOfOffice.IsValid", "model.out_of_office.is_valid.end_at.app_error", nil, "user_id="+o.UserId, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (o *OutOfOffice) PreSave() {
+	o.UpdateAt = GetMillis()
+}
+
+// IsActive returns whether automatic replies should be sent at the given time.
+func (o *OutOfOffice) IsActive(now int64) bool {
+	return o.StartAt <= now && now < o.EndAt
+}
+
+// IsExpired returns whether the out of office period is over at the given time.
+func (o *OutOfOffice) IsExpired(now int64) bool {
+	return o.EndAt <= now
+}
+
+func (o *OutOfOffice) ToJson() string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return ""
+	} else {
+		return string(b)
+	}
+}
+
+func OutOfOfficeFromJson(data io.Reader) *OutOfOffice {
+	decoder := json.NewDecoder(data)
+	var o OutOfOffice
+	err := decoder.Decode(&o)
+	if err == nil {
+		return &o
+	} else {
+		return nil
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOutOfOfficeJson(t *testing.T) {
+	o := OutOfOffice{UserId: NewId(), Message: "On vacation", EndAt: GetMillis()}
+	ro := OutOfOfficeFromJson(strings.NewReader(o.ToJson()))
+
+	if ro.UserId != o.UserId || ro.Message != o.Message || ro.EndAt != o.EndAt {
+		t.Fatal("out of office should have matched")
+	}
+}
+
+func TestOutOfOfficeIsValid(t *testing.T) {
+	o := OutOfOffice{}
+
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.UserId = NewId()
+	o.Message = "On vacation"
+	o.EndAt = 2000
+	if err := o.IsValid(); err != nil {
+		t.Fatal(err)
+	}
+
+	o.StartAt = 2000
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid when ending before it starts")
+	}
+
+	o.StartAt = 1000
+	o.Message = strings.Repeat("a", OUT_OF_OFFICE_MESSAGE_MAX_RUNES+1)
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid with a long message")
+	}
+}
+
+func TestOutOfOfficeIsActive(t *testing.T) {
+	o := OutOfOffice{StartAt: 1000, EndAt: 2000}
+
+	if o.IsActive(999) || !o.IsActive(1000) || !o.IsActive(1999) || o.IsActive(2000) {
+		t.Fatal("should only be active between the start and end")
+	}
+
+	if o.IsExpired(1999) || !o.IsExpired(2000) {
+		t.Fatal("should only be expired from the end")
+	}
+}
@@ -24,6 +24,11 @@ const (
 	POST_DISPLAYNAME_CHANGE    = "system_displayname_change"
 	POST_PURPOSE_CHANGE        = "system_purpose_change"
 	POST_CHANNEL_DELETED       = "system_channel_deleted"
+	POST_CHANNEL_ARCHIVED      = "system_channel_archived"
+	POST_CHANNEL_UNARCHIVED    = "system_channel_unarchived"
+	POST_CHANGE_CHANNEL_TYPE   = "system_change_channel_type"
+	POST_CHANNEL_INACTIVE      = "system_channel_inactive"
+	POST_OUT_OF_OFFICE         = "system_out_of_office"
 	POST_EPHEMERAL             = "system_ephemeral"
 	POST_FILEIDS_MAX_RUNES     = 150
 	POST_FILENAMES_MAX_RUNES   = 4000
@@ -128,7 +133,9 @@ func (o *Post) IsValid() *AppError {
 		o.Type == POST_JOIN_CHANNEL || o.Type == POST_LEAVE_CHANNEL ||
 		o.Type == POST_REMOVE_FROM_CHANNEL || o.Type == POST_ADD_TO_CHANNEL ||
 		o.Type == POST_SLACK_ATTACHMENT || o.Type == POST_HEADER_CHANGE || o.Type == POST_PURPOSE_CHANGE ||
-		o.Type == POST_DISPLAYNAME_CHANGE || o.Type == POST_CHANNEL_DELETED) {
+		o.Type == POST_DISPLAYNAME_CHANGE || o.Type == POST_CHANNEL_DELETED ||
+		o.Type == POST_CHANNEL_ARCHIVED || o.Type == POST_CHANNEL_UNARCHIVED || o.Type == POST_CHANGE_CHANNEL_TYPE ||
+		o.Type == POST_CHANNEL_INACTIVE || o.Type == POST_OUT_OF_OFFICE) {
 		return NewLocAppError("Post.IsValid", "model.post.is_valid.type.app_error", nil, "id="+o.Type)
 	}
 
@@ -0,0 +1,175 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"unicode/utf8"
+)
+
+const (
+	PROFILE_ATTRIBUTE_TYPE_TEXT  = "text"
+	PROFILE_ATTRIBUTE_TYPE_PHONE = "phone"
+	PROFILE_ATTRIBUTE_TYPE_URL   = "url"
+	PROFILE_ATTRIBUTE_TYPE_USER  = "user"
+
+	PROFILE_ATTRIBUTE_VISIBILITY_ALWAYS  = "always"
+	PROFILE_ATTRIBUTE_VISIBILITY_PRIVATE = "private"
+
+	PROFILE_ATTRIBUTE_NAME_MAX_LENGTH        = 64
+	PROFILE_ATTRIBUTE_DISPLAY_NAME_MAX_RUNES = 64
+	PROFILE_ATTRIBUTE_SYNC_MAX_LENGTH        = 128
+	PROFILE_ATTRIBUTE_VALUE_MAX_RUNES        = 255
+)
+
+var validProfileAttributeName = regexp.MustCompile(`^[a-z0-9_]+$`)
+var validProfileAttributePhone = regexp.MustCompile(`^[0-9+\-(). ]+$`)
+
+// ProfileAttribute is an admin defined field on user profiles, such as a department or a phone number. Private
+// attributes are only shown to the user they belong to and to admins. Attributes can be kept in sync with an LDAP or
+// SAML attribute, in which case users can't change them themselves.
+type ProfileAttribute struct {
+	Id            string `json:"id"`
+	CreateAt      int64  `json:"create_at"`
+	UpdateAt      int64  `json:"update_at"`
+	Name          string `json:"name"`
+	DisplayName   string `json:"display_name"`
+	Type          string `json:"type"`
+	Visibility    string `json:"visibility"`
+	LdapAttribute string `json:"ldap_attribute"`
+	SamlAttribute string `json:"saml_attribute"`
+}
+
+// ProfileAttributeValue is the value of a profile attribute for a single user.
+type ProfileAttributeValue struct {
+	UserId      string `json:"user_id"`
+	AttributeId string `json:"attribute_id"`
+	Value       string `json:"value"`
+	UpdateAt    int64  `json:"update_at"`
+}
+
+func (o *ProfileAttribute) IsValid() *AppError {
+	if len(o.Id) != 26 {
+		return NewAppError("ProfileAttribute.IsValid", "model.profile_attribute.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("ProfileAttribute.IsValid", "model.profile_attribute.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.UpdateAt == 0 {
+		return NewAppError("ProfileAttribute.IsValid", "model.profile_attribute.is_valid.update_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.Name) == 0 || len(o.Name) > PROFILE_ATTRIBUTE_NAME_MAX_LENGTH || !validProfileAttributeName.MatchString(o.Name) {
+		return NewAppError("ProfileAttribute.IsValid", "model.profile_attribute.is_valid.name.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.DisplayName) == 0 || utf8.RuneCountInString(o.DisplayName) > PROFILE_ATTRIBUTE_DISPLAY_NAME_MAX_RUNES {
+		return NewAppError("ProfileAttribute.IsValid", "model.profile_attribute.is_valid.display_name.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	switch o.Type {
+	case PROFILE_ATTRIBUTE_TYPE_TEXT, PROFILE_ATTRIBUTE_TYPE_PHONE, PROFILE_ATTRIBUTE_TYPE_URL, PROFILE_ATTRIBUTE_TYPE_USER:
+	default:
+		return NewAppError("ProfileAttribute.IsValid", "model.profile_attribute.is_valid.type.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.Visibility != PROFILE_ATTRIBUTE_VISIBILITY_ALWAYS && o.Visibility != PROFILE_ATTRIBUTE_VISIBILITY_PRIVATE {
+		return NewAppError("ProfileAttribute.IsValid", "model.profile_attribute.is_valid.visibility.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.LdapAttribute) > PROFILE_ATTRIBUTE_SYNC_MAX_LENGTH || len(o.SamlAttribute) > PROFILE_ATTRIBUTE_SYNC_MAX_LENGTH {
+		return NewAppError("ProfileAttribute.IsValid", "model.profile_attribute.is_valid.sync.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (o *ProfileAttribute) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	o.CreateAt = GetMillis()
+	o.UpdateAt = o.CreateAt
+
+	if o.Visibility == "" {
+		o.Visibility = PROFILE_ATTRIBUTE_VISIBILITY_ALWAYS
+	}
+}
+
+func (o *ProfileAttribute) PreUpdate() {
+	o.UpdateAt = GetMillis()
+}
+
+// IsValidValue returns whether the value can be stored for the attribute. Empty values are never stored since they
+// clear the attribute instead.
+func (o *ProfileAttribute) IsValidValue(value string) bool {
+	if len(value) == 0 || utf8.RuneCountInString(value) > PROFILE_ATTRIBUTE_VALUE_MAX_RUNES {
+		return false
+	}
+
+	switch o.Type {
+	case PROFILE_ATTRIBUTE_TYPE_PHONE:
+		return validProfileAttributePhone.MatchString(value)
+	case PROFILE_ATTRIBUTE_TYPE_URL:
+		u, err := url.ParseRequestURI(value)
+		return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+	case PROFILE_ATTRIBUTE_TYPE_USER:
+		return len(value) == 26
+	}
+
+	return true
+}
+
+// IsSyncedFrom returns whether the attribute is kept in sync with the given authentication service.
+func (o *ProfileAttribute) IsSyncedFrom(authService string) bool {
+	return (authService == USER_AUTH_SERVICE_LDAP && len(o.LdapAttribute) > 0) ||
+		(authService == USER_AUTH_SERVICE_SAML && len(o.SamlAttribute) > 0)
+}
+
+func (o *ProfileAttribute) ToJson() string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return ""
+	} else {
+		return string(b)
+	}
+}
+
+func ProfileAttributeFromJson(data io.Reader) *ProfileAttribute {
+	decoder := json.NewDecoder(data)
+	var o ProfileAttribute
+	err := decoder.Decode(&o)
+	if err == nil {
+		return &o
+	} else {
+		return nil
+	}
+}
+
+func ProfileAttributeListToJson(l []*ProfileAttribute) string {
+	b, err := json.Marshal(l)
+	if err != nil {
+		return ""
+	} else {
+		return string(b)
+	}
+}
+
+func ProfileAttributeListFromJson(data io.Reader) []*ProfileAttribute {
+	decoder := json.NewDecoder(data)
+	var o []*ProfileAttribute
+	err := decoder.Decode(&o)
+	if err == nil {
+		return o
+	} else {
+		return nil
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProfileAttributeJson(t *testing.T) {
+	o := ProfileAttribute{Id: NewId(), Name: "department", DisplayName: "Department", Type: PROFILE_ATTRIBUTE_TYPE_TEXT}
+	ro := ProfileAttributeFromJson(strings.NewReader(o.ToJson()))
+
+	if ro.Id != o.Id || ro.Name != o.Name || ro.DisplayName != o.DisplayName || ro.Type != o.Type {
+		t.Fatal("profile attribute should have matched")
+	}
+
+	l := ProfileAttributeListFromJson(strings.NewReader(ProfileAttributeListToJson([]*ProfileAttribute{&o})))
+	if len(l) != 1 || l[0].Id != o.Id {
+		t.Fatal("profile attribute list should have matched")
+	}
+}
+
+func TestProfileAttributeIsValid(t *testing.T) {
+	o := ProfileAttribute{}
+
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.Name = "department"
+	o.DisplayName = "Department"
+	o.Type = PROFILE_ATTRIBUTE_TYPE_TEXT
+	o.PreSave()
+
+	if o.Visibility != PROFILE_ATTRIBUTE_VISIBILITY_ALWAYS {
+		t.Fatal("should have defaulted to always visible")
+	}
+
+	if err := o.IsValid(); err != nil {
+		t.Fatal(err)
+	}
+
+	o.Name = "Department"
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid with an upper case name")
+	}
+
+	o.Name = "department"
+	o.Type = "junk"
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.Type = PROFILE_ATTRIBUTE_TYPE_PHONE
+	o.Visibility = "junk"
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.Visibility = PROFILE_ATTRIBUTE_VISIBILITY_PRIVATE
+	o.LdapAttribute = strings.Repeat("a", PROFILE_ATTRIBUTE_SYNC_MAX_LENGTH+1)
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+}
+
+func TestProfileAttributeIsValidValue(t *testing.T) {
+	o := ProfileAttribute{Type: PROFILE_ATTRIBUTE_TYPE_TEXT}
+
+	if o.IsValidValue("") || o.IsValidValue(strings.Repeat("a", PROFILE_ATTRIBUTE_VALUE_MAX_RUNES+1)) {
+		t.Fatal("should be invalid")
+	}
+
+	if !o.IsValidValue("Engineering") {
+		t.Fatal("should be valid")
+	}
+
+	o.Type = PROFILE_ATTRIBUTE_TYPE_PHONE
+	if !o.IsValidValue("+1 (555) 123-4567") || o.IsValidValue("call me") {
+		t.Fatal("should only allow phone numbers")
+	}
+
+	o.Type = PROFILE_ATTRIBUTE_TYPE_URL
+	if !o.IsValidValue("https://example.com/me") || o.IsValidValue("javascript:alert(1)") {
+		t.Fatal("should only allow web links")
+	}
+
+	o.Type = PROFILE_ATTRIBUTE_TYPE_USER
+	if !o.IsValidValue(NewId()) || o.IsValidValue("someone") {
+		t.Fatal("should only allow user ids")
+	}
+}
+
+func TestProfileAttributeIsSyncedFrom(t *testing.T) {
+	o := ProfileAttribute{LdapAttribute: "department"}
+
+	if !o.IsSyncedFrom(USER_AUTH_SERVICE_LDAP) || o.IsSyncedFrom(USER_AUTH_SERVICE_SAML) || o.IsSyncedFrom(USER_AUTH_SERVICE_EMAIL) {
+		t.Fatal("should only be synced from LDAP")
+	}
+}
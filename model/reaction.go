@@ -8,6 +8,13 @@ import (
 	"io"
 )
 
+const (
+	REACTION_NOTIFY_PROP = "reactions"
+	REACTION_NOTIFY_NONE = "none"
+	REACTION_NOTIFY_DM   = "dm"
+	REACTION_NOTIFY_ALL  = "all"
+)
+
 type Reaction struct {
 	UserId    string `json:"user_id"`
 	PostId    string `json:"post_id"`
@@ -51,6 +58,38 @@ func ReactionsFromJson(data io.Reader) []*Reaction {
 	}
 }
 
+// ReactionSummary is the number of times that a post was reacted to with one emoji and whether the current user
+// was one of the people who reacted.
+type ReactionSummary struct {
+	EmojiName string `json:"emoji_name"`
+	Count     int64  `json:"count"`
+	Reacted   bool   `json:"reacted"`
+}
+
+func ReactionSummariesToJson(o []*ReactionSummary) string {
+	if b, err := json.Marshal(o); err != nil {
+		return ""
+	} else {
+		return string(b)
+	}
+}
+
+func ReactionSummariesFromJson(data io.Reader) []*ReactionSummary {
+	var o []*ReactionSummary
+
+	if err := json.NewDecoder(data).Decode(&o); err != nil {
+		return nil
+	} else {
+		return o
+	}
+}
+
+func IsValidReactionNotifyLevel(level string) bool {
+	return level == REACTION_NOTIFY_NONE ||
+		level == REACTION_NOTIFY_DM ||
+		level == REACTION_NOTIFY_ALL
+}
+
 func (o *Reaction) IsValid() *AppError {
 	if len(o.UserId) != 26 {
 		return NewLocAppError("Reaction.IsValid", "model.reaction.is_valid.user_id.app_error", nil, "user_id="+o.UserId)
@@ -10,11 +10,18 @@ import (
 )
 
 const (
-	SESSION_COOKIE_TOKEN  = "MMAUTHTOKEN"
-	SESSION_CACHE_SIZE    = 25000
-	SESSION_PROP_PLATFORM = "platform"
-	SESSION_PROP_OS       = "os"
-	SESSION_PROP_BROWSER  = "browser"
+	SESSION_COOKIE_TOKEN     = "MMAUTHTOKEN"
+	SESSION_CACHE_SIZE       = 25000
+	SESSION_PROP_PLATFORM    = "platform"
+	SESSION_PROP_OS          = "os"
+	SESSION_PROP_BROWSER     = "browser"
+	SESSION_PROP_TYPE        = "type"
+	SESSION_PROP_DESCRIPTION = "description"
+
+	SESSION_TYPE_BOT_TOKEN         = "bot_token"
+	SESSION_TYPE_USER_ACCESS_TOKEN = "user_access_token"
+
+	SESSION_DESCRIPTION_MAX_RUNES = 255
 )
 
 type Session struct {
@@ -115,6 +122,20 @@ func (me *Session) IsMobileApp() bool {
 		(strings.HasPrefix(me.DeviceId, PUSH_NOTIFY_APPLE+":") || strings.HasPrefix(me.DeviceId, PUSH_NOTIFY_ANDROID+":"))
 }
 
+// IsBotToken returns whether the session was created as an access token for a bot rather than by logging in.
+func (me *Session) IsBotToken() bool {
+	return me.Props[SESSION_PROP_TYPE] == SESSION_TYPE_BOT_TOKEN
+}
+
+// IsUserAccessToken returns whether the session is a personal access token that a user created for automation.
+func (me *Session) IsUserAccessToken() bool {
+	return me.Props[SESSION_PROP_TYPE] == SESSION_TYPE_USER_ACCESS_TOKEN
+}
+
+func (me *Session) IsGuest() bool {
+	return IsInRole(me.Roles, ROLE_SYSTEM_GUEST.Id)
+}
+
 func (me *Session) GetUserRoles() []string {
 	return strings.Fields(me.Roles)
 }
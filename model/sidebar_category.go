@@ -0,0 +1,165 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/json"
+	"io"
+	"unicode/utf8"
+)
+
+const (
+	SIDEBAR_CATEGORY_CUSTOM          = "custom"
+	SIDEBAR_CATEGORY_FAVORITES       = "favorites"
+	SIDEBAR_CATEGORY_CHANNELS        = "channels"
+	SIDEBAR_CATEGORY_DIRECT_MESSAGES = "direct_messages"
+
+	SIDEBAR_CATEGORY_DISPLAY_NAME_MAX_RUNES = 64
+	SIDEBAR_CATEGORY_MAX_CHANNELS           = 1000
+)
+
+// SidebarCategory is a group of channels in a user's sidebar on one team. Every user has one favorites, channels
+// and direct messages category per team and can add custom ones. A channel belongs to at most one category.
+type SidebarCategory struct {
+	Id          string      `json:"id"`
+	UserId      string      `json:"user_id"`
+	TeamId      string      `json:"team_id"`
+	CreateAt    int64       `json:"create_at"`
+	UpdateAt    int64       `json:"update_at"`
+	Type        string      `json:"type"`
+	DisplayName string      `json:"display_name"`
+	SortOrder   int64       `json:"sort_order"`
+	Collapsed   bool        `json:"collapsed"`
+	ChannelIds  StringArray `json:"channel_ids"`
+}
+
+func (o *SidebarCategory) IsValid() *AppError {
+	if len(o.Id) != 26 {
+		return NewLocAppError("SidebarCategory.IsValid", "model.sidebar_category.is_valid.id.app_error", nil, "")
+	}
+
+	if len(o.UserId) != 26 {
+		return NewLocAppError("SidebarCategory.IsValid", "model.sidebar_category.is_valid.user_id.app_error", nil, "id="+o.Id)
+	}
+
+	if len(o.TeamId) != 26 {
+		return NewLocAppError("SidebarCategory.IsValid", "model.sidebar_category.is_valid.team_id.app_error", nil, "id="+o.Id)
+	}
+
+	if o.CreateAt == 0 {
+		return NewLocAppError("SidebarCategory.IsValid", "model.sidebar_category.is_valid.create_at.app_error", nil, "id="+o.Id)
+	}
+
+	if o.UpdateAt == 0 {
+		return NewLocAppError("SidebarCategory.IsValid", "model.sidebar_category.is_valid.update_at.app_error", nil, "id="+o.Id)
+	}
+
+	if !IsValidSidebarCategoryType(o.Type) {
+		return NewLocAppError("SidebarCategory.IsValid", "model.sidebar_category.is_valid.type.app_error", nil, "id="+o.Id)
+	}
+
+	if utf8.RuneCountInString(o.DisplayName) == 0 || utf8.RuneCountInString(o.DisplayName) > SIDEBAR_CATEGORY_DISPLAY_NAME_MAX_RUNES {
+		return NewLocAppError("SidebarCategory.IsValid", "model.sidebar_category.is_valid.display_name.app_error", nil, "id="+o.Id)
+	}
+
+	if len(o.ChannelIds) > SIDEBAR_CATEGORY_MAX_CHANNELS {
+		return NewLocAppError("SidebarCategory.IsValid", "model.sidebar_category.is_valid.channel_ids.app_error", nil, "id="+o.Id)
+	}
+
+	for _, channelId := range o.ChannelIds {
+		if len(channelId) != 26 {
+			return NewLocAppError("SidebarCategory.IsValid", "model.sidebar_category.is_valid.channel_ids.app_error", nil, "id="+o.Id)
+		}
+	}
+
+	return nil
+}
+
+func (o *SidebarCategory) PreSave() {
+	if o.Id == "" {
+		if o.Type == SIDEBAR_CATEGORY_CUSTOM {
+			o.Id = NewId()
+		} else {
+			o.Id = defaultSidebarCategoryId(o.UserId, o.TeamId, o.Type)
+		}
+	}
+
+	if o.ChannelIds == nil {
+		o.ChannelIds = StringArray{}
+	}
+
+	o.CreateAt = GetMillis()
+	o.UpdateAt = o.CreateAt
+}
+
+// defaultSidebarCategoryId returns the id of one of the default categories of a user on a team. It's always the same
+// so that the primary key keeps a user from ending up with two categories of the same type.
+func defaultSidebarCategoryId(userId string, teamId string, categoryType string) string {
+	hash := sha256.Sum256([]byte(userId + teamId + categoryType))
+
+	var b bytes.Buffer
+	encoder := base32.NewEncoder(encoding, &b)
+	encoder.Write(hash[:16])
+	encoder.Close()
+	b.Truncate(26) // removes the '==' padding
+	return b.String()
+}
+
+func (o *SidebarCategory) PreUpdate() {
+	if o.ChannelIds == nil {
+		o.ChannelIds = StringArray{}
+	}
+
+	o.UpdateAt = GetMillis()
+}
+
+func IsValidSidebarCategoryType(categoryType string) bool {
+	return categoryType == SIDEBAR_CATEGORY_CUSTOM ||
+		categoryType == SIDEBAR_CATEGORY_FAVORITES ||
+		categoryType == SIDEBAR_CATEGORY_CHANNELS ||
+		categoryType == SIDEBAR_CATEGORY_DIRECT_MESSAGES
+}
+
+func (o *SidebarCategory) ToJson() string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return ""
+	} else {
+		return string(b)
+	}
+}
+
+func SidebarCategoryFromJson(data io.Reader) *SidebarCategory {
+	decoder := json.NewDecoder(data)
+	var o SidebarCategory
+	err := decoder.Decode(&o)
+	if err == nil {
+		return &o
+	} else {
+		return nil
+	}
+}
+
+func SidebarCategoryListToJson(categories []*SidebarCategory) string {
+	b, err := json.Marshal(categories)
+	if err != nil {
+		return ""
+	} else {
+		return string(b)
+	}
+}
+
+func SidebarCategoryListFromJson(data io.Reader) []*SidebarCategory {
+	decoder := json.NewDecoder(data)
+	var categories []*SidebarCategory
+	err := decoder.Decode(&categories)
+	if err == nil {
+		return categories
+	} else {
+		return nil
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSidebarCategoryJson(t *testing.T) {
+	o := SidebarCategory{Id: NewId(), ChannelIds: StringArray{NewId()}}
+	json := o.ToJson()
+	ro := SidebarCategoryFromJson(strings.NewReader(json))
+
+	if o.Id != ro.Id || len(ro.ChannelIds) != 1 || ro.ChannelIds[0] != o.ChannelIds[0] {
+		t.Fatal("category did not round trip")
+	}
+
+	categories := SidebarCategoryListFromJson(strings.NewReader(SidebarCategoryListToJson([]*SidebarCategory{&o})))
+	if len(categories) != 1 || categories[0].Id != o.Id {
+		t.Fatal("category list did not round trip")
+	}
+}
+
+func TestSidebarCategoryIsValid(t *testing.T) {
+	o := SidebarCategory{}
+
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.PreSave()
+	o.UserId = NewId()
+	o.TeamId = NewId()
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.Type = SIDEBAR_CATEGORY_CUSTOM
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.DisplayName = "Projects"
+	if err := o.IsValid(); err != nil {
+		t.Fatal(err)
+	}
+
+	o.DisplayName = strings.Repeat("a", SIDEBAR_CATEGORY_DISPLAY_NAME_MAX_RUNES+1)
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.DisplayName = "Projects"
+	o.ChannelIds = StringArray{"junk"}
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.ChannelIds = StringArray{NewId()}
+	if err := o.IsValid(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSidebarCategoryPreSave(t *testing.T) {
+	userId := NewId()
+	teamId := NewId()
+
+	o1 := SidebarCategory{UserId: userId, TeamId: teamId, Type: SIDEBAR_CATEGORY_FAVORITES}
+	o1.PreSave()
+	o2 := SidebarCategory{UserId: userId, TeamId: teamId, Type: SIDEBAR_CATEGORY_FAVORITES}
+	o2.PreSave()
+	if len(o1.Id) != 26 || o1.Id != o2.Id {
+		t.Fatal("default categories of the same type should have the same id")
+	}
+
+	o3 := SidebarCategory{UserId: userId, TeamId: teamId, Type: SIDEBAR_CATEGORY_CHANNELS}
+	o3.PreSave()
+	if o3.Id == o1.Id {
+		t.Fatal("default categories of different types should have different ids")
+	}
+
+	o4 := SidebarCategory{UserId: userId, TeamId: teamId, Type: SIDEBAR_CATEGORY_CUSTOM}
+	o4.PreSave()
+	o5 := SidebarCategory{UserId: userId, TeamId: teamId, Type: SIDEBAR_CATEGORY_CUSTOM}
+	o5.PreSave()
+	if len(o4.Id) != 26 || o4.Id == o5.Id {
+		t.Fatal("custom categories should have unique ids")
+	}
+}
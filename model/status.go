@@ -12,6 +12,7 @@ const (
 	STATUS_OFFLINE         = "offline"
 	STATUS_AWAY            = "away"
 	STATUS_ONLINE          = "online"
+	STATUS_DND             = "dnd"
 	STATUS_CACHE_SIZE      = 25000
 	STATUS_CHANNEL_TIMEOUT = 20000  // 20 seconds
 	STATUS_MIN_UPDATE_TIME = 120000 // 2 minutes
@@ -23,6 +24,20 @@ type Status struct {
 	Manual         bool   `json:"manual"`
 	LastActivityAt int64  `json:"last_activity_at"`
 	ActiveChannel  string `json:"active_channel" db:"-"`
+	DNDEndTime     int64  `json:"dnd_end_time"`
+}
+
+// IsDND returns whether the user is in Do Not Disturb mode at the given time. DND without an end time lasts until
+// the user changes their status.
+func (o *Status) IsDND(now int64) bool {
+	return o.Status == STATUS_DND && (o.DNDEndTime == 0 || now < o.DNDEndTime)
+}
+
+func IsValidStatus(status string) bool {
+	return status == STATUS_ONLINE ||
+		status == STATUS_AWAY ||
+		status == STATUS_OFFLINE ||
+		status == STATUS_DND
 }
 
 func (o *Status) ToJson() string {
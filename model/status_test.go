@@ -9,7 +9,7 @@ import (
 )
 
 func TestStatus(t *testing.T) {
-	status := Status{NewId(), STATUS_ONLINE, true, 0, ""}
+	status := Status{NewId(), STATUS_ONLINE, true, 0, "", 0}
 	json := status.ToJson()
 	status2 := StatusFromJson(strings.NewReader(json))
 
@@ -29,3 +29,23 @@ func TestStatus(t *testing.T) {
 		t.Fatal("Manual should have matched")
 	}
 }
+
+func TestStatusIsDND(t *testing.T) {
+	now := GetMillis()
+
+	if (&Status{Status: STATUS_ONLINE}).IsDND(now) {
+		t.Fatal("online shouldn't be DND")
+	}
+
+	if !(&Status{Status: STATUS_DND}).IsDND(now) {
+		t.Fatal("DND without an end time should last")
+	}
+
+	if !(&Status{Status: STATUS_DND, DNDEndTime: now + 1000}).IsDND(now) {
+		t.Fatal("DND should last until the end time")
+	}
+
+	if (&Status{Status: STATUS_DND, DNDEndTime: now}).IsDND(now) {
+		t.Fatal("DND should be over after the end time")
+	}
+}
@@ -22,6 +22,8 @@ const (
 	TEAM_DISPLAY_NAME_MAX_RUNES     = 64
 	TEAM_EMAIL_MAX_LENGTH           = 128
 	TEAM_NAME_MAX_LENGTH            = 64
+
+	TEAM_CHANNEL_AUTO_ARCHIVE_MAX_EXEMPTIONS = 25
 )
 
 type Team struct {
@@ -38,6 +40,13 @@ type Team struct {
 	AllowedDomains  string `json:"allowed_domains"`
 	InviteId        string `json:"invite_id"`
 	AllowOpenInvite bool   `json:"allow_open_invite"`
+
+	// Public and private channels without any posts for ChannelAutoArchiveDays are warned and then archived after
+	// ChannelAutoArchiveGraceDays, unless they're listed by name in ChannelAutoArchiveExemptions. Zero days turns
+	// this off for the team.
+	ChannelAutoArchiveDays       int         `json:"channel_auto_archive_days"`
+	ChannelAutoArchiveGraceDays  int         `json:"channel_auto_archive_grace_days"`
+	ChannelAutoArchiveExemptions StringArray `json:"channel_auto_archive_exemptions"`
 }
 
 type Invites struct {
@@ -190,6 +199,20 @@ func (o *Team) IsValid() *AppError {
 		return NewAppError("Team.IsValid", "model.team.is_valid.domains.app_error", nil, "id="+o.Id, http.StatusBadRequest)
 	}
 
+	if o.ChannelAutoArchiveDays < 0 || o.ChannelAutoArchiveGraceDays < 0 {
+		return NewAppError("Team.IsValid", "model.team.is_valid.channel_auto_archive_days.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.ChannelAutoArchiveExemptions) > TEAM_CHANNEL_AUTO_ARCHIVE_MAX_EXEMPTIONS {
+		return NewAppError("Team.IsValid", "model.team.is_valid.channel_auto_archive_exemptions.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	for _, name := range o.ChannelAutoArchiveExemptions {
+		if len(name) > CHANNEL_NAME_MAX_LENGTH || !IsValidChannelIdentifier(name) {
+			return NewAppError("Team.IsValid", "model.team.is_valid.channel_auto_archive_exemptions.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+		}
+	}
+
 	return nil
 }
 
@@ -204,10 +227,34 @@ func (o *Team) PreSave() {
 	if len(o.InviteId) == 0 {
 		o.InviteId = NewId()
 	}
+
+	if o.ChannelAutoArchiveExemptions == nil {
+		o.ChannelAutoArchiveExemptions = StringArray{}
+	}
 }
 
 func (o *Team) PreUpdate() {
 	o.UpdateAt = GetMillis()
+
+	if o.ChannelAutoArchiveExemptions == nil {
+		o.ChannelAutoArchiveExemptions = StringArray{}
+	}
+}
+
+// IsChannelAutoArchiveExempt returns whether a channel can never be archived automatically on this team. The default
+// channel is always exempt.
+func (o *Team) IsChannelAutoArchiveExempt(channel *Channel) bool {
+	if channel.Name == DEFAULT_CHANNEL {
+		return true
+	}
+
+	for _, name := range o.ChannelAutoArchiveExemptions {
+		if name == channel.Name {
+			return true
+		}
+	}
+
+	return false
 }
 
 func IsReservedTeamName(s string) bool {
@@ -62,6 +62,38 @@ func TestTeamIsValid(t *testing.T) {
 	if err := o.IsValid(); err != nil {
 		t.Fatal(err)
 	}
+
+	o.ChannelAutoArchiveDays = -1
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.ChannelAutoArchiveDays = 30
+	o.ChannelAutoArchiveExemptions = StringArray{"Not A Channel"}
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.ChannelAutoArchiveExemptions = StringArray{"off-topic"}
+	if err := o.IsValid(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTeamIsChannelAutoArchiveExempt(t *testing.T) {
+	o := Team{ChannelAutoArchiveExemptions: StringArray{"off-topic"}}
+
+	if !o.IsChannelAutoArchiveExempt(&Channel{Name: DEFAULT_CHANNEL}) {
+		t.Fatal("the default channel should be exempt")
+	}
+
+	if !o.IsChannelAutoArchiveExempt(&Channel{Name: "off-topic"}) {
+		t.Fatal("listed channels should be exempt")
+	}
+
+	if o.IsChannelAutoArchiveExempt(&Channel{Name: "random"}) {
+		t.Fatal("other channels shouldn't be exempt")
+	}
 }
 
 func TestTeamPreSave(t *testing.T) {
@@ -0,0 +1,65 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"time"
+)
+
+// The timezone of a user is kept as a map so that clients can keep reporting the timezone they detect while the user
+// has chosen one manually. Both timezones are IANA names such as "America/Toronto".
+const (
+	USER_TIMEZONE_USE_AUTOMATIC = "useAutomaticTimezone"
+	USER_TIMEZONE_AUTOMATIC     = "automaticTimezone"
+	USER_TIMEZONE_MANUAL        = "manualTimezone"
+
+	USER_TIMEZONE_MAX_LENGTH = 256
+)
+
+func DefaultUserTimezone() StringMap {
+	return StringMap{
+		USER_TIMEZONE_USE_AUTOMATIC: "true",
+		USER_TIMEZONE_AUTOMATIC:     "",
+		USER_TIMEZONE_MANUAL:        "",
+	}
+}
+
+// IsValidUserTimezone returns whether the timezones in the map, if any, are known.
+func IsValidUserTimezone(timezone StringMap) bool {
+	if len(MapToJson(timezone)) > USER_TIMEZONE_MAX_LENGTH {
+		return false
+	}
+
+	for _, key := range []string{USER_TIMEZONE_AUTOMATIC, USER_TIMEZONE_MANUAL} {
+		if name := timezone[key]; len(name) > 0 {
+			if _, err := time.LoadLocation(name); err != nil {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// GetPreferredTimezone returns the name of the timezone the user wants to see times in, or an empty string if they
+// don't have one.
+func (u *User) GetPreferredTimezone() string {
+	if u.Timezone[USER_TIMEZONE_USE_AUTOMATIC] == "true" {
+		return u.Timezone[USER_TIMEZONE_AUTOMATIC]
+	}
+
+	return u.Timezone[USER_TIMEZONE_MANUAL]
+}
+
+// GetTimezoneLocation returns the location to show times to the user in, falling back to the server's timezone if the
+// user doesn't have one.
+func (u *User) GetTimezoneLocation() *time.Location {
+	if name := u.GetPreferredTimezone(); len(name) > 0 {
+		if location, err := time.LoadLocation(name); err == nil {
+			return location
+		}
+	}
+
+	return time.Local
+}
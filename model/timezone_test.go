@@ -0,0 +1,45 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsValidUserTimezone(t *testing.T) {
+	if !IsValidUserTimezone(nil) || !IsValidUserTimezone(DefaultUserTimezone()) {
+		t.Fatal("should be valid without a timezone")
+	}
+
+	if !IsValidUserTimezone(StringMap{USER_TIMEZONE_AUTOMATIC: "UTC", USER_TIMEZONE_MANUAL: "UTC"}) {
+		t.Fatal("should be valid")
+	}
+
+	if IsValidUserTimezone(StringMap{USER_TIMEZONE_MANUAL: "Mars/Olympus_Mons"}) {
+		t.Fatal("should be invalid with an unknown timezone")
+	}
+
+	if IsValidUserTimezone(StringMap{"junk": strings.Repeat("a", USER_TIMEZONE_MAX_LENGTH)}) {
+		t.Fatal("should be invalid when too long")
+	}
+}
+
+func TestUserGetPreferredTimezone(t *testing.T) {
+	user := User{}
+	if user.GetPreferredTimezone() != "" || user.GetTimezoneLocation() != time.Local {
+		t.Fatal("should fall back to the server's timezone")
+	}
+
+	user.Timezone = StringMap{USER_TIMEZONE_USE_AUTOMATIC: "true", USER_TIMEZONE_AUTOMATIC: "UTC", USER_TIMEZONE_MANUAL: "Local"}
+	if user.GetPreferredTimezone() != "UTC" || user.GetTimezoneLocation() != time.UTC {
+		t.Fatal("should have used the automatic timezone")
+	}
+
+	user.Timezone[USER_TIMEZONE_USE_AUTOMATIC] = "false"
+	if user.GetPreferredTimezone() != "Local" {
+		t.Fatal("should have used the manual timezone")
+	}
+}
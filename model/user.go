@@ -32,31 +32,36 @@ const (
 )
 
 type User struct {
-	Id                 string    `json:"id"`
-	CreateAt           int64     `json:"create_at,omitempty"`
-	UpdateAt           int64     `json:"update_at,omitempty"`
-	DeleteAt           int64     `json:"delete_at"`
-	Username           string    `json:"username"`
-	Password           string    `json:"password,omitempty"`
-	AuthData           *string   `json:"auth_data,omitempty"`
-	AuthService        string    `json:"auth_service"`
-	Email              string    `json:"email"`
-	EmailVerified      bool      `json:"email_verified,omitempty"`
-	Nickname           string    `json:"nickname"`
-	FirstName          string    `json:"first_name"`
-	LastName           string    `json:"last_name"`
-	Position           string    `json:"position"`
-	Roles              string    `json:"roles"`
-	AllowMarketing     bool      `json:"allow_marketing,omitempty"`
-	Props              StringMap `json:"props,omitempty"`
-	NotifyProps        StringMap `json:"notify_props,omitempty"`
-	LastPasswordUpdate int64     `json:"last_password_update,omitempty"`
-	LastPictureUpdate  int64     `json:"last_picture_update,omitempty"`
-	FailedAttempts     int       `json:"failed_attempts,omitempty"`
-	Locale             string    `json:"locale"`
-	MfaActive          bool      `json:"mfa_active,omitempty"`
-	MfaSecret          string    `json:"mfa_secret,omitempty"`
-	LastActivityAt     int64     `db:"-" json:"last_activity_at,omitempty"`
+	Id                 string        `json:"id"`
+	CreateAt           int64         `json:"create_at,omitempty"`
+	UpdateAt           int64         `json:"update_at,omitempty"`
+	DeleteAt           int64         `json:"delete_at"`
+	Username           string        `json:"username"`
+	Password           string        `json:"password,omitempty"`
+	AuthData           *string       `json:"auth_data,omitempty"`
+	AuthService        string        `json:"auth_service"`
+	Email              string        `json:"email"`
+	EmailVerified      bool          `json:"email_verified,omitempty"`
+	Nickname           string        `json:"nickname"`
+	FirstName          string        `json:"first_name"`
+	LastName           string        `json:"last_name"`
+	Position           string        `json:"position"`
+	Roles              string        `json:"roles"`
+	AllowMarketing     bool          `json:"allow_marketing,omitempty"`
+	Props              StringMap     `json:"props,omitempty"`
+	NotifyProps        StringMap     `json:"notify_props,omitempty"`
+	LastPasswordUpdate int64         `json:"last_password_update,omitempty"`
+	LastPictureUpdate  int64         `json:"last_picture_update,omitempty"`
+	FailedAttempts     int           `json:"failed_attempts,omitempty"`
+	Locale             string        `json:"locale"`
+	Timezone           StringMap     `json:"timezone"`
+	MfaActive          bool          `json:"mfa_active,omitempty"`
+	MfaSecret          string        `json:"mfa_secret,omitempty"`
+	IsBot              bool          `json:"is_bot,omitempty"`
+	BotOwnerId         string        `json:"bot_owner_id,omitempty"`
+	LastActivityAt     int64         `db:"-" json:"last_activity_at,omitempty"`
+	CustomStatus       *CustomStatus `db:"-" json:"custom_status,omitempty"`
+	ProfileAttributes  StringMap     `db:"-" json:"profile_attributes,omitempty"`
 }
 
 // IsValid validates the user and returns an error if it isn't configured
@@ -111,6 +116,18 @@ func (u *User) IsValid() *AppError {
 		return NewAppError("User.IsValid", "model.user.is_valid.auth_data_pwd.app_error", nil, "user_id="+u.Id, http.StatusBadRequest)
 	}
 
+	if u.IsBot && len(u.BotOwnerId) != 26 {
+		return NewAppError("User.IsValid", "model.user.is_valid.bot_owner_id.app_error", nil, "user_id="+u.Id, http.StatusBadRequest)
+	}
+
+	if u.IsBot && (len(u.Password) > 0 || len(u.AuthService) > 0) {
+		return NewAppError("User.IsValid", "model.user.is_valid.bot_auth.app_error", nil, "user_id="+u.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidUserTimezone(u.Timezone) {
+		return NewAppError("User.IsValid", "model.user.is_valid.timezone.app_error", nil, "user_id="+u.Id, http.StatusBadRequest)
+	}
+
 	return nil
 }
 
@@ -152,6 +169,10 @@ func (u *User) PreSave() {
 		u.SetDefaultNotifications()
 	}
 
+	if u.Timezone == nil {
+		u.Timezone = DefaultUserTimezone()
+	}
+
 	if len(u.Password) > 0 {
 		u.Password = HashPassword(u.Password)
 	}
@@ -190,6 +211,7 @@ func (u *User) SetDefaultNotifications() {
 	u.NotifyProps["desktop_sound"] = "true"
 	u.NotifyProps["mention_keys"] = u.Username + ",@" + u.Username
 	u.NotifyProps["channel"] = "true"
+	u.NotifyProps[REACTION_NOTIFY_PROP] = REACTION_NOTIFY_NONE
 
 	if u.FirstName == "" {
 		u.NotifyProps["first_name"] = "false"
@@ -355,6 +377,11 @@ func IsValidUserRoles(userRoles string) bool {
 		return false
 	}
 
+	// Guests can't hold any other system role
+	if len(roles) > 1 && IsInRole(userRoles, ROLE_SYSTEM_GUEST.Id) {
+		return false
+	}
+
 	return true
 }
 
@@ -384,6 +411,11 @@ func IsInRole(userRoles string, inRole string) bool {
 	return false
 }
 
+// IsGuest returns true if the user only has access to the channels they've been explicitly added to.
+func (u *User) IsGuest() bool {
+	return IsInRole(u.Roles, ROLE_SYSTEM_GUEST.Id)
+}
+
 func (u *User) IsSSOUser() bool {
 	if u.AuthService != "" && u.AuthService != USER_AUTH_SERVICE_EMAIL {
 		return true
@@ -392,7 +424,7 @@ func (u *User) IsSSOUser() bool {
 }
 
 func (u *User) IsOAuthUser() bool {
-	if u.AuthService == USER_AUTH_SERVICE_GITLAB {
+	if u.AuthService == USER_AUTH_SERVICE_GITLAB || u.AuthService == USER_AUTH_SERVICE_OPENID {
 		return true
 	}
 	return false
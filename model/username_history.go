@@ -0,0 +1,79 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// UsernameHistory records a change of a user's username so that mentions of and lookups by the old username can be
+// redirected to the user for a while.
+type UsernameHistory struct {
+	Id          string `json:"id"`
+	UserId      string `json:"user_id"`
+	OldUsername string `json:"old_username"`
+	NewUsername string `json:"new_username"`
+	CreateAt    int64  `json:"create_at"`
+}
+
+func (o *UsernameHistory) IsValid() *AppError {
+	if len(o.Id) != 26 {
+		return NewAppError("UsernameHistory.IsValid", "model.username_history.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if len(o.UserId) != 26 {
+		return NewAppError("UsernameHistory.IsValid", "model.username_history.is_valid.user_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidUsername(o.OldUsername) || !IsValidUsername(o.NewUsername) || o.OldUsername == o.NewUsername {
+		return NewAppError("UsernameHistory.IsValid", "model.username_history.is_valid.username.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("UsernameHistory.IsValid", "model.username_history.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (o *UsernameHistory) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	if o.CreateAt == 0 {
+		o.CreateAt = GetMillis()
+	}
+}
+
+func (o *UsernameHistory) ToJson() string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return ""
+	} else {
+		return string(b)
+	}
+}
+
+func UsernameHistoryListToJson(l []*UsernameHistory) string {
+	b, err := json.Marshal(l)
+	if err != nil {
+		return ""
+	} else {
+		return string(b)
+	}
+}
+
+func UsernameHistoryListFromJson(data io.Reader) []*UsernameHistory {
+	decoder := json.NewDecoder(data)
+	var o []*UsernameHistory
+	err := decoder.Decode(&o)
+	if err == nil {
+		return o
+	} else {
+		return nil
+	}
+}
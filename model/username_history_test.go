@@ -0,0 +1,45 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUsernameHistoryJson(t *testing.T) {
+	o := UsernameHistory{Id: NewId(), UserId: NewId(), OldUsername: "old", NewUsername: "new"}
+	l := UsernameHistoryListFromJson(strings.NewReader(UsernameHistoryListToJson([]*UsernameHistory{&o})))
+
+	if len(l) != 1 || l[0].Id != o.Id || l[0].OldUsername != o.OldUsername || l[0].NewUsername != o.NewUsername {
+		t.Fatal("username history should have matched")
+	}
+}
+
+func TestUsernameHistoryIsValid(t *testing.T) {
+	o := UsernameHistory{}
+
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid")
+	}
+
+	o.UserId = NewId()
+	o.OldUsername = "old"
+	o.NewUsername = "new"
+	o.PreSave()
+
+	if err := o.IsValid(); err != nil {
+		t.Fatal(err)
+	}
+
+	o.NewUsername = "old"
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid when the username didn't change")
+	}
+
+	o.NewUsername = "New User"
+	if err := o.IsValid(); err == nil {
+		t.Fatal("should be invalid with a bad username")
+	}
+}
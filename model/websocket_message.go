@@ -14,7 +14,9 @@ const (
 	WEBSOCKET_EVENT_POST_EDITED        = "post_edited"
 	WEBSOCKET_EVENT_POST_DELETED       = "post_deleted"
 	WEBSOCKET_EVENT_CHANNEL_DELETED    = "channel_deleted"
+	WEBSOCKET_EVENT_CHANNEL_UPDATED    = "channel_updated"
 	WEBSOCKET_EVENT_DIRECT_ADDED       = "direct_added"
+	WEBSOCKET_EVENT_GROUP_ADDED        = "group_added"
 	WEBSOCKET_EVENT_NEW_USER           = "new_user"
 	WEBSOCKET_EVENT_LEAVE_TEAM         = "leave_team"
 	WEBSOCKET_EVENT_UPDATE_TEAM        = "update_team"
@@ -29,6 +31,12 @@ const (
 	WEBSOCKET_AUTHENTICATION_CHALLENGE = "authentication_challenge"
 	WEBSOCKET_EVENT_REACTION_ADDED     = "reaction_added"
 	WEBSOCKET_EVENT_REACTION_REMOVED   = "reaction_removed"
+
+	WEBSOCKET_EVENT_SIDEBAR_CATEGORY_CREATED       = "sidebar_category_created"
+	WEBSOCKET_EVENT_SIDEBAR_CATEGORY_UPDATED       = "sidebar_category_updated"
+	WEBSOCKET_EVENT_SIDEBAR_CATEGORY_DELETED       = "sidebar_category_deleted"
+	WEBSOCKET_EVENT_SIDEBAR_CATEGORY_ORDER_UPDATED = "sidebar_category_order_updated"
+	WEBSOCKET_EVENT_CUSTOM_STATUS_CHANGE           = "custom_status_change"
 )
 
 type WebSocketMessage interface {
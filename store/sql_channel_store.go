@@ -62,6 +62,7 @@ func NewSqlChannelStore(sqlStore *SqlStore) ChannelStore {
 		table.ColMap("Header").SetMaxSize(1024)
 		table.ColMap("Purpose").SetMaxSize(250)
 		table.ColMap("CreatorId").SetMaxSize(26)
+		table.ColMap("PostingPolicy").SetMaxSize(32)
 
 		tablem := db.AddTableWithName(model.ChannelMember{}, "ChannelMembers").SetKeys(false, "ChannelId", "UserId")
 		tablem.ColMap("ChannelId").SetMaxSize(26)
@@ -93,6 +94,8 @@ func (s SqlChannelStore) Save(channel *model.Channel) StoreChannel {
 		var result StoreResult
 		if channel.Type == model.CHANNEL_DIRECT {
 			result.Err = model.NewLocAppError("SqlChannelStore.Save", "store.sql_channel.save.direct_channel.app_error", nil, "")
+		} else if channel.Type == model.CHANNEL_GROUP {
+			result.Err = model.NewLocAppError("SqlChannelStore.Save", "store.sql_channel.save.group_channel.app_error", nil, "")
 		} else {
 			if transaction, err := s.GetMaster().Begin(); err != nil {
 				result.Err = model.NewLocAppError("SqlChannelStore.Save", "store.sql_channel.save.open_transaction.app_error", nil, err.Error())
@@ -194,6 +197,59 @@ func (s SqlChannelStore) SaveDirectChannel(directchannel *model.Channel, member1
 	return storeChannel
 }
 
+func (s SqlChannelStore) SaveGroupChannel(groupChannel *model.Channel, members []*model.ChannelMember) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		var result StoreResult
+
+		if groupChannel.Type != model.CHANNEL_GROUP {
+			result.Err = model.NewLocAppError("SqlChannelStore.SaveGroupChannel", "store.sql_channel.save_group_channel.not_group.app_error", nil, "")
+		} else {
+			if transaction, err := s.GetMaster().Begin(); err != nil {
+				result.Err = model.NewLocAppError("SqlChannelStore.SaveGroupChannel", "store.sql_channel.save_group_channel.open_transaction.app_error", nil, err.Error())
+			} else {
+				groupChannel.TeamId = ""
+				channelResult := s.saveChannelT(transaction, groupChannel)
+
+				if channelResult.Err != nil {
+					transaction.Rollback()
+					result.Err = channelResult.Err
+					result.Data = channelResult.Data
+				} else {
+					newChannel := channelResult.Data.(*model.Channel)
+
+					details := ""
+					for _, member := range members {
+						// Members need new channel ID
+						member.ChannelId = newChannel.Id
+
+						if memberResult := s.saveMemberT(transaction, member, newChannel); memberResult.Err != nil {
+							details += "UserId=" + member.UserId + " Err: " + memberResult.Err.Message + " "
+						}
+					}
+
+					if details != "" {
+						transaction.Rollback()
+						result.Err = model.NewLocAppError("SqlChannelStore.SaveGroupChannel", "store.sql_channel.save_group_channel.add_members.app_error", nil, details)
+					} else {
+						if err := transaction.Commit(); err != nil {
+							result.Err = model.NewLocAppError("SqlChannelStore.SaveGroupChannel", "store.sql_channel.save_group_channel.commit.app_error", nil, err.Error())
+						} else {
+							result = channelResult
+						}
+					}
+				}
+			}
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
 func (s SqlChannelStore) saveChannelT(transaction *gorp.Transaction, channel *model.Channel) StoreResult {
 	result := StoreResult{}
 
@@ -207,7 +263,7 @@ func (s SqlChannelStore) saveChannelT(transaction *gorp.Transaction, channel *mo
 		return result
 	}
 
-	if channel.Type != model.CHANNEL_DIRECT {
+	if !channel.IsGroupOrDirect() {
 		if count, err := transaction.SelectInt("SELECT COUNT(0) FROM Channels WHERE TeamId = :TeamId AND DeleteAt = 0 AND (Type = 'O' OR Type = 'P')", map[string]interface{}{"TeamId": channel.TeamId}); err != nil {
 			result.Err = model.NewLocAppError("SqlChannelStore.Save", "store.sql_channel.save_channel.current_count.app_error", nil, "teamId="+channel.TeamId+", "+err.Error())
 			return result
@@ -393,6 +449,75 @@ func (s SqlChannelStore) SetDeleteAt(channelId string, deleteAt int64, updateAt
 	return storeChannel
 }
 
+func (s SqlChannelStore) SetArchiveAt(channelId string, archiveAt int64, updateAt int64) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		_, err := s.GetMaster().Exec("Update Channels SET ArchiveAt = :ArchiveAt, UpdateAt = :UpdateAt WHERE Id = :ChannelId", map[string]interface{}{"ArchiveAt": archiveAt, "UpdateAt": updateAt, "ChannelId": channelId})
+		if err != nil {
+			result.Err = model.NewLocAppError("SqlChannelStore.SetArchiveAt", "store.sql_channel.set_archive_at.app_error", nil, "id="+channelId+", err="+err.Error())
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlChannelStore) SetInactiveWarningAt(channelId string, warningAt int64) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		_, err := s.GetMaster().Exec("UPDATE Channels SET InactiveWarningAt = :InactiveWarningAt WHERE Id = :ChannelId", map[string]interface{}{"InactiveWarningAt": warningAt, "ChannelId": channelId})
+		if err != nil {
+			result.Err = model.NewLocAppError("SqlChannelStore.SetInactiveWarningAt", "store.sql_channel.set_inactive_warning_at.app_error", nil, "id="+channelId+", err="+err.Error())
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+// GetChannelsForAutoArchive returns the public and private channels on a team that haven't had any posts since the
+// given time, along with the ones that have already been warned that they'll be archived.
+func (s SqlChannelStore) GetChannelsForAutoArchive(teamId string, inactiveSince int64) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		var channels []*model.Channel
+		if _, err := s.GetReplica().Select(&channels,
+			`SELECT
+				*
+			FROM
+				Channels
+			WHERE
+				TeamId = :TeamId
+				AND Type IN (:OpenType, :PrivateType)
+				AND DeleteAt = 0
+				AND ArchiveAt = 0
+				AND ((LastPostAt < :InactiveSince AND CreateAt < :InactiveSince) OR InactiveWarningAt > 0)`,
+			map[string]interface{}{"TeamId": teamId, "OpenType": model.CHANNEL_OPEN, "PrivateType": model.CHANNEL_PRIVATE, "InactiveSince": inactiveSince}); err != nil {
+			result.Err = model.NewLocAppError("SqlChannelStore.GetChannelsForAutoArchive", "store.sql_channel.get_channels_for_auto_archive.app_error", nil, "team_id="+teamId+", err="+err.Error())
+		} else {
+			result.Data = channels
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
 func (s SqlChannelStore) PermanentDeleteByTeam(teamId string) StoreChannel {
 	storeChannel := make(StoreChannel, 1)
 
@@ -476,6 +601,28 @@ func (s SqlChannelStore) GetChannels(teamId string, userId string) StoreChannel
 	return storeChannel
 }
 
+func (s SqlChannelStore) GetGroupChannelsForUser(userId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		data := &model.ChannelList{}
+		_, err := s.GetReplica().Select(data, "SELECT Channels.* FROM Channels, ChannelMembers WHERE Id = ChannelId AND UserId = :UserId AND Type = :Type AND DeleteAt = 0 ORDER BY DisplayName", map[string]interface{}{"UserId": userId, "Type": model.CHANNEL_GROUP})
+
+		if err != nil {
+			result.Err = model.NewLocAppError("SqlChannelStore.GetGroupChannelsForUser", "store.sql_channel.get_group_channels_for_user.app_error", nil, "userId="+userId+", err="+err.Error())
+		} else {
+			result.Data = data
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
 func (s SqlChannelStore) GetMoreChannels(teamId string, userId string, offset int, limit int) StoreChannel {
 	storeChannel := make(StoreChannel, 1)
 
@@ -1263,6 +1410,51 @@ func (s SqlChannelStore) AnalyticsTypeCount(teamId string, channelType string) S
 	return storeChannel
 }
 
+// AnalyticsMemberCountsByDay returns how many members a channel gained or lost on each day. Channel memberships
+// don't record when they were created, so this is worked out from the join, leave, add and remove system messages.
+func (s SqlChannelStore) AnalyticsMemberCountsByDay(channelId string, startTime int64, endTime int64) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		day := analyticsDayExpression("Posts.CreateAt")
+		query :=
+			`SELECT
+				` + day + ` AS Name,
+				SUM(CASE WHEN Posts.Type IN (:JoinType, :AddType) THEN 1 ELSE -1 END) AS Value
+			FROM
+				Posts
+			WHERE
+				Posts.ChannelId = :ChannelId
+				AND Posts.Type IN (:JoinType, :AddType, :LeaveType, :RemoveType)
+				AND Posts.CreateAt >= :StartTime
+				AND Posts.CreateAt <= :EndTime
+			GROUP BY ` + day + `
+			ORDER BY Name ASC`
+
+		var rows model.AnalyticsRows
+		if _, err := s.GetReplica().Select(&rows, query, map[string]interface{}{
+			"ChannelId":  channelId,
+			"StartTime":  startTime,
+			"EndTime":    endTime,
+			"JoinType":   model.POST_JOIN_CHANNEL,
+			"AddType":    model.POST_ADD_TO_CHANNEL,
+			"LeaveType":  model.POST_LEAVE_CHANNEL,
+			"RemoveType": model.POST_REMOVE_FROM_CHANNEL,
+		}); err != nil {
+			result.Err = model.NewLocAppError("SqlChannelStore.AnalyticsMemberCountsByDay", "store.sql_channel.analytics_member_counts_by_day.app_error", nil, "channel_id="+channelId+", "+err.Error())
+		} else {
+			result.Data = rows
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
 func (s SqlChannelStore) ExtraUpdateByUser(userId string, time int64) StoreChannel {
 	storeChannel := make(StoreChannel, 1)
 
@@ -1455,3 +1647,36 @@ func (s SqlChannelStore) GetMembersByIds(channelId string, userIds []string) Sto
 
 	return storeChannel
 }
+
+// GetUserIdsInSameChannels returns the ids of every user who shares a channel with the given user, including the user
+// themselves.
+func (s SqlChannelStore) GetUserIdsInSameChannels(userId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		var userIds []string
+		if _, err := s.GetReplica().Select(&userIds,
+			`SELECT DISTINCT
+				Others.UserId
+			FROM
+				ChannelMembers Mine,
+				ChannelMembers Others,
+				Channels
+			WHERE
+				Mine.UserId = :UserId
+				AND Others.ChannelId = Mine.ChannelId
+				AND Channels.Id = Mine.ChannelId
+				AND Channels.DeleteAt = 0`, map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewAppError("SqlChannelStore.GetUserIdsInSameChannels", "store.sql_channel.get_user_ids_in_same_channels.app_error", nil, "userId="+userId+", "+err.Error(), http.StatusInternalServerError)
+		} else {
+			result.Data = userIds
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/mattermost/platform/model"
+	"github.com/mattermost/platform/utils"
 )
 
 func TestChannelStoreSave(t *testing.T) {
@@ -113,6 +114,68 @@ func TestChannelStoreSaveDirectChannel(t *testing.T) {
 	}
 }
 
+func TestChannelStoreSaveGroupChannel(t *testing.T) {
+	Setup()
+
+	members := []*model.ChannelMember{}
+	userIds := []string{}
+	for i := 0; i < 3; i++ {
+		u := &model.User{}
+		u.Email = model.NewId()
+		u.Nickname = model.NewId()
+		Must(store.User().Save(u))
+
+		userIds = append(userIds, u.Id)
+		members = append(members, &model.ChannelMember{UserId: u.Id, NotifyProps: model.GetDefaultChannelNotifyProps()})
+	}
+
+	o1 := model.Channel{}
+	o1.DisplayName = "Name"
+	o1.Name = model.GetGroupNameFromUserIds(userIds)
+	o1.Type = model.CHANNEL_GROUP
+
+	if err := (<-store.Channel().Save(&o1)).Err; err == nil {
+		t.Fatal("shouldn't be able to save a group channel without members")
+	}
+
+	if err := (<-store.Channel().SaveGroupChannel(&o1, members)).Err; err != nil {
+		t.Fatal("couldn't save group channel", err)
+	}
+
+	if saved := (<-store.Channel().GetMembers(o1.Id, 0, 100)).Data.(*model.ChannelMembers); len(*saved) != 3 {
+		t.Fatal("should have saved 3 members")
+	}
+
+	if channels := (<-store.Channel().GetGroupChannelsForUser(userIds[0])).Data.(*model.ChannelList); len(*channels) != 1 || (*channels)[0].Id != o1.Id {
+		t.Fatal("should have returned the group channel")
+	}
+
+	// Attempt to save a group channel that already exists
+	o1a := model.Channel{
+		DisplayName: o1.DisplayName,
+		Name:        o1.Name,
+		Type:        o1.Type,
+	}
+
+	if result := <-store.Channel().SaveGroupChannel(&o1a, members); result.Err == nil {
+		t.Fatal("should've failed to save a duplicate group channel")
+	} else if result.Err.Id != CHANNEL_EXISTS_ERROR {
+		t.Fatal("should've returned CHANNEL_EXISTS_ERROR")
+	} else if returned := result.Data.(*model.Channel); returned.Id != o1.Id {
+		t.Fatal("should've returned original channel when saving a duplicate group channel")
+	}
+
+	// Attempt to save a non-group channel
+	o2 := model.Channel{}
+	o2.TeamId = model.NewId()
+	o2.DisplayName = "Name"
+	o2.Name = "a" + model.NewId() + "b"
+	o2.Type = model.CHANNEL_OPEN
+	if err := (<-store.Channel().SaveGroupChannel(&o2, members)).Err; err == nil {
+		t.Fatal("Should not be able to save non-group channel")
+	}
+}
+
 func TestChannelStoreCreateDirectChannel(t *testing.T) {
 	Setup()
 
@@ -350,6 +413,74 @@ func TestChannelStoreDelete(t *testing.T) {
 	}
 }
 
+func TestChannelStoreSetArchiveAt(t *testing.T) {
+	Setup()
+
+	o1 := model.Channel{}
+	o1.TeamId = model.NewId()
+	o1.DisplayName = "Channel1"
+	o1.Name = "a" + model.NewId() + "b"
+	o1.Type = model.CHANNEL_OPEN
+	Must(store.Channel().Save(&o1))
+
+	archiveAt := model.GetMillis()
+	if r := <-store.Channel().SetArchiveAt(o1.Id, archiveAt, archiveAt); r.Err != nil {
+		t.Fatal(r.Err)
+	}
+
+	if r := <-store.Channel().Get(o1.Id, false); r.Err != nil {
+		t.Fatal(r.Err)
+	} else if channel := r.Data.(*model.Channel); channel.ArchiveAt != archiveAt || !channel.IsArchived() {
+		t.Fatal("channel should be archived")
+	} else if channel.DeleteAt != 0 {
+		t.Fatal("archiving shouldn't delete the channel")
+	}
+
+	if r := <-store.Channel().SetArchiveAt(o1.Id, 0, model.GetMillis()); r.Err != nil {
+		t.Fatal(r.Err)
+	}
+
+	if r := <-store.Channel().Get(o1.Id, false); r.Err != nil {
+		t.Fatal(r.Err)
+	} else if r.Data.(*model.Channel).IsArchived() {
+		t.Fatal("channel should be unarchived")
+	}
+}
+
+func TestChannelStoreGetChannelsForAutoArchive(t *testing.T) {
+	Setup()
+
+	teamId := model.NewId()
+	now := model.GetMillis()
+
+	Must(store.Channel().Save(&model.Channel{TeamId: teamId, DisplayName: "Inactive", Name: "a" + model.NewId() + "b", Type: model.CHANNEL_OPEN}))
+	o2 := Must(store.Channel().Save(&model.Channel{TeamId: teamId, DisplayName: "Warned", Name: "a" + model.NewId() + "b", Type: model.CHANNEL_PRIVATE})).(*model.Channel)
+	o3 := Must(store.Channel().Save(&model.Channel{TeamId: teamId, DisplayName: "Archived", Name: "a" + model.NewId() + "b", Type: model.CHANNEL_OPEN})).(*model.Channel)
+	Must(store.Channel().Save(&model.Channel{TeamId: teamId, DisplayName: "New", Name: "a" + model.NewId() + "b", Type: model.CHANNEL_OPEN}))
+
+	Must(store.Channel().SetArchiveAt(o3.Id, now, now))
+
+	if channels := Must(store.Channel().GetChannelsForAutoArchive(teamId, now-1000*60)).([]*model.Channel); len(channels) != 0 {
+		t.Fatal("new channels shouldn't be returned")
+	}
+
+	if channels := Must(store.Channel().GetChannelsForAutoArchive(teamId, now+1000*60)).([]*model.Channel); len(channels) != 3 {
+		t.Fatal("should've returned the inactive channels that aren't archived", len(channels))
+	}
+
+	Must(store.Channel().SetInactiveWarningAt(o2.Id, now))
+
+	if channels := Must(store.Channel().GetChannelsForAutoArchive(teamId, now-1000*60)).([]*model.Channel); len(channels) != 1 || channels[0].Id != o2.Id {
+		t.Fatal("should've returned the warned channel")
+	} else if channels[0].InactiveWarningAt != now {
+		t.Fatal("warning time should've been saved")
+	}
+
+	if channels := Must(store.Channel().GetChannelsForAutoArchive(model.NewId(), now+1000*60)).([]*model.Channel); len(channels) != 0 {
+		t.Fatal("shouldn't have returned channels on other teams")
+	}
+}
+
 func TestChannelStoreGetByName(t *testing.T) {
 	Setup()
 
@@ -1401,3 +1532,68 @@ func TestChannelStoreGetMembersByIds(t *testing.T) {
 		t.Fatal("empty user ids - should have failed")
 	}
 }
+
+func TestChannelStoreGetUserIdsInSameChannels(t *testing.T) {
+	Setup()
+
+	o1 := model.Channel{}
+	o1.TeamId = model.NewId()
+	o1.DisplayName = "ChannelA"
+	o1.Name = "a" + model.NewId() + "b"
+	o1.Type = model.CHANNEL_PRIVATE
+	Must(store.Channel().Save(&o1))
+
+	o2 := model.Channel{}
+	o2.TeamId = o1.TeamId
+	o2.DisplayName = "ChannelB"
+	o2.Name = "a" + model.NewId() + "b"
+	o2.Type = model.CHANNEL_OPEN
+	Must(store.Channel().Save(&o2))
+
+	userId := model.NewId()
+	channelmateId := model.NewId()
+	strangerId := model.NewId()
+
+	Must(store.Channel().SaveMember(&model.ChannelMember{ChannelId: o1.Id, UserId: userId, NotifyProps: model.GetDefaultChannelNotifyProps()}))
+	Must(store.Channel().SaveMember(&model.ChannelMember{ChannelId: o1.Id, UserId: channelmateId, NotifyProps: model.GetDefaultChannelNotifyProps()}))
+	Must(store.Channel().SaveMember(&model.ChannelMember{ChannelId: o2.Id, UserId: strangerId, NotifyProps: model.GetDefaultChannelNotifyProps()}))
+
+	if r := <-store.Channel().GetUserIdsInSameChannels(userId); r.Err != nil {
+		t.Fatal(r.Err)
+	} else if userIds := r.Data.([]string); len(userIds) != 2 {
+		t.Fatal("should've returned the user and their channelmate")
+	} else {
+		for _, id := range userIds {
+			if id == strangerId {
+				t.Fatal("shouldn't have returned users from other channels")
+			}
+		}
+	}
+
+	Must(store.Channel().Delete(o1.Id, model.GetMillis()))
+
+	if r := <-store.Channel().GetUserIdsInSameChannels(userId); r.Err != nil {
+		t.Fatal(r.Err)
+	} else if len(r.Data.([]string)) != 0 {
+		t.Fatal("shouldn't have returned users from deleted channels")
+	}
+}
+
+func TestChannelStoreAnalyticsMemberCountsByDay(t *testing.T) {
+	Setup()
+
+	channelId := model.NewId()
+	day := utils.MillisFromTime(utils.Yesterday())
+	earlier := day - (1000 * 60 * 60 * 24 * 2)
+
+	Must(store.Post().Save(&model.Post{ChannelId: channelId, UserId: model.NewId(), CreateAt: earlier, Message: "joined", Type: model.POST_JOIN_CHANNEL}))
+	Must(store.Post().Save(&model.Post{ChannelId: channelId, UserId: model.NewId(), CreateAt: earlier, Message: "added", Type: model.POST_ADD_TO_CHANNEL}))
+	Must(store.Post().Save(&model.Post{ChannelId: channelId, UserId: model.NewId(), CreateAt: day, Message: "left", Type: model.POST_LEAVE_CHANNEL}))
+	Must(store.Post().Save(&model.Post{ChannelId: channelId, UserId: model.NewId(), CreateAt: day, Message: "hello"}))
+
+	if rows := Must(store.Channel().AnalyticsMemberCountsByDay(channelId, earlier-1000, day+1000)).(model.AnalyticsRows); len(rows) != 2 {
+		t.Fatal("should've returned two days", rows)
+	} else if rows[0].Value != 2 || rows[1].Value != -1 {
+		t.Fatal("wrong member changes")
+	}
+}
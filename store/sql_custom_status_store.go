@@ -0,0 +1,191 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"github.com/mattermost/platform/model"
+)
+
+type SqlCustomStatusStore struct {
+	*SqlStore
+}
+
+func NewSqlCustomStatusStore(sqlStore *SqlStore) CustomStatusStore {
+	s := &SqlCustomStatusStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.CustomStatus{}, "CustomStatuses").SetKeys(false, "UserId")
+		table.ColMap("UserId").SetMaxSize(26)
+		table.ColMap("Emoji").SetMaxSize(model.CUSTOM_STATUS_EMOJI_MAX_LENGTH)
+		table.ColMap("Text").SetMaxSize(model.CUSTOM_STATUS_TEXT_MAX_RUNES * 4)
+	}
+
+	return s
+}
+
+func (s SqlCustomStatusStore) CreateIndexesIfNotExists() {
+	s.CreateIndexIfNotExists("idx_customstatuses_expires_at", "CustomStatuses", "ExpiresAt")
+}
+
+func (s SqlCustomStatusStore) SaveOrUpdate(status *model.CustomStatus) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		status.PreSave()
+		if result.Err = status.IsValid(); result.Err != nil {
+			storeChannel <- result
+			close(storeChannel)
+			return
+		}
+
+		if err := s.GetMaster().SelectOne(&model.CustomStatus{}, "SELECT * FROM CustomStatuses WHERE UserId = :UserId", map[string]interface{}{"UserId": status.UserId}); err == nil {
+			if _, err := s.GetMaster().Update(status); err != nil {
+				result.Err = model.NewLocAppError("SqlCustomStatusStore.SaveOrUpdate", "store.sql_custom_status.update.app_error", nil, "user_id="+status.UserId+", "+err.Error())
+			}
+		} else {
+			if err := s.GetMaster().Insert(status); err != nil {
+				result.Err = model.NewLocAppError("SqlCustomStatusStore.SaveOrUpdate", "store.sql_custom_status.save.app_error", nil, "user_id="+status.UserId+", "+err.Error())
+			}
+		}
+
+		if result.Err == nil {
+			result.Data = status
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlCustomStatusStore) Get(userId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		var status model.CustomStatus
+		if err := s.GetReplica().SelectOne(&status, "SELECT * FROM CustomStatuses WHERE UserId = :UserId", map[string]interface{}{"UserId": userId}); err != nil {
+			if err == sql.ErrNoRows {
+				result.Err = model.NewAppError("SqlCustomStatusStore.Get", "store.sql_custom_status.get.missing.app_error", nil, "user_id="+userId, http.StatusNotFound)
+			} else {
+				result.Err = model.NewLocAppError("SqlCustomStatusStore.Get", "store.sql_custom_status.get.app_error", nil, "user_id="+userId+", "+err.Error())
+			}
+		} else {
+			result.Data = &status
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlCustomStatusStore) GetByIds(userIds []string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if len(userIds) == 0 {
+			result.Data = []*model.CustomStatus{}
+			storeChannel <- result
+			close(storeChannel)
+			return
+		}
+
+		props := make(map[string]interface{})
+		idQuery := ""
+
+		for index, userId := range userIds {
+			if len(idQuery) > 0 {
+				idQuery += ", "
+			}
+
+			props["userId"+strconv.Itoa(index)] = userId
+			idQuery += ":userId" + strconv.Itoa(index)
+		}
+
+		var statuses []*model.CustomStatus
+		if _, err := s.GetReplica().Select(&statuses, "SELECT * FROM CustomStatuses WHERE UserId IN ("+idQuery+")", props); err != nil {
+			result.Err = model.NewLocAppError("SqlCustomStatusStore.GetByIds", "store.sql_custom_status.get.app_error", nil, err.Error())
+		} else {
+			result.Data = statuses
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+// GetExpired returns the custom statuses that have an expiry at or before the given time.
+func (s SqlCustomStatusStore) GetExpired(now int64) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		var statuses []*model.CustomStatus
+		if _, err := s.GetReplica().Select(&statuses, "SELECT * FROM CustomStatuses WHERE ExpiresAt > 0 AND ExpiresAt <= :Now", map[string]interface{}{"Now": now}); err != nil {
+			result.Err = model.NewLocAppError("SqlCustomStatusStore.GetExpired", "store.sql_custom_status.get_expired.app_error", nil, err.Error())
+		} else {
+			result.Data = statuses
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+// DeleteExpired deletes the custom status of the user if it has an expiry at or before the given time. The data of the
+// result is whether it was deleted, which it isn't if the user has set a new status since it was found to be expired.
+func (s SqlCustomStatusStore) DeleteExpired(userId string, now int64) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if sqlResult, err := s.GetMaster().Exec("DELETE FROM CustomStatuses WHERE UserId = :UserId AND ExpiresAt > 0 AND ExpiresAt <= :Now", map[string]interface{}{"UserId": userId, "Now": now}); err != nil {
+			result.Err = model.NewLocAppError("SqlCustomStatusStore.DeleteExpired", "store.sql_custom_status.delete.app_error", nil, "user_id="+userId+", "+err.Error())
+		} else if count, _ := sqlResult.RowsAffected(); count == 1 {
+			result.Data = true
+		} else {
+			result.Data = false
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlCustomStatusStore) Delete(userId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if _, err := s.GetMaster().Exec("DELETE FROM CustomStatuses WHERE UserId = :UserId", map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewLocAppError("SqlCustomStatusStore.Delete", "store.sql_custom_status.delete.app_error", nil, "user_id="+userId+", "+err.Error())
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/platform/model"
+)
+
+func TestCustomStatusStore(t *testing.T) {
+	Setup()
+
+	status := &model.CustomStatus{UserId: model.NewId(), Emoji: "calendar", Text: "In a meeting"}
+	if result := <-store.CustomStatus().SaveOrUpdate(status); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	status.Text = "Out for lunch"
+	if result := <-store.CustomStatus().SaveOrUpdate(status); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	if rstatus := Must(store.CustomStatus().Get(status.UserId)).(*model.CustomStatus); rstatus.Text != status.Text {
+		t.Fatal("custom status should've been updated")
+	}
+
+	if result := <-store.CustomStatus().SaveOrUpdate(&model.CustomStatus{UserId: model.NewId()}); result.Err == nil {
+		t.Fatal("shouldn't be able to save an empty custom status")
+	}
+
+	expired := &model.CustomStatus{UserId: model.NewId(), Emoji: "palm_tree", ExpiresAt: model.GetMillis() - 1000}
+	Must(store.CustomStatus().SaveOrUpdate(expired))
+
+	if statuses := Must(store.CustomStatus().GetByIds([]string{status.UserId, expired.UserId, model.NewId()})).([]*model.CustomStatus); len(statuses) != 2 {
+		t.Fatal("should've returned 2 custom statuses")
+	}
+
+	found := false
+	for _, rstatus := range Must(store.CustomStatus().GetExpired(model.GetMillis())).([]*model.CustomStatus) {
+		if rstatus.UserId == status.UserId {
+			t.Fatal("custom status without an expiry shouldn't be returned")
+		} else if rstatus.UserId == expired.UserId {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Fatal("expired custom status should've been returned")
+	}
+
+	if deleted := Must(store.CustomStatus().DeleteExpired(status.UserId, model.GetMillis())).(bool); deleted {
+		t.Fatal("custom status without an expiry shouldn't be deleted")
+	} else if result := <-store.CustomStatus().Get(status.UserId); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	if deleted := Must(store.CustomStatus().DeleteExpired(expired.UserId, model.GetMillis())).(bool); !deleted {
+		t.Fatal("expired custom status should've been deleted")
+	} else if result := <-store.CustomStatus().Get(expired.UserId); result.Err == nil {
+		t.Fatal("expired custom status should've been deleted")
+	}
+
+	Must(store.CustomStatus().Delete(status.UserId))
+
+	if result := <-store.CustomStatus().Get(status.UserId); result.Err == nil {
+		t.Fatal("custom status should've been deleted")
+	}
+}
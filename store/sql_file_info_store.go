@@ -241,3 +241,65 @@ func (fs SqlFileInfoStore) DeleteForPost(postId string) StoreChannel {
 
 	return storeChannel
 }
+
+func (fs SqlFileInfoStore) GetForChannel(channelId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		var infos []*model.FileInfo
+
+		if _, err := fs.GetReplica().Select(&infos,
+			`SELECT
+				FileInfo.*
+			FROM
+				FileInfo, Posts
+			WHERE
+				FileInfo.PostId = Posts.Id
+				AND Posts.ChannelId = :ChannelId
+			ORDER BY
+				FileInfo.CreateAt`, map[string]interface{}{"ChannelId": channelId}); err != nil {
+			result.Err = model.NewLocAppError("SqlFileInfoStore.GetForChannel",
+				"store.sql_file_info.get_for_channel.app_error", nil, "channel_id="+channelId+", "+err.Error())
+		} else {
+			result.Data = infos
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (fs SqlFileInfoStore) UpdatePaths(info *model.FileInfo) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		info.UpdateAt = model.GetMillis()
+
+		if _, err := fs.GetMaster().Exec(
+			`UPDATE
+				FileInfo
+			SET
+				Path = :Path,
+				ThumbnailPath = :ThumbnailPath,
+				PreviewPath = :PreviewPath,
+				UpdateAt = :UpdateAt
+			WHERE
+				Id = :Id`, map[string]interface{}{"Path": info.Path, "ThumbnailPath": info.ThumbnailPath, "PreviewPath": info.PreviewPath, "UpdateAt": info.UpdateAt, "Id": info.Id}); err != nil {
+			result.Err = model.NewLocAppError("SqlFileInfoStore.UpdatePaths",
+				"store.sql_file_info.update_paths.app_error", nil, "file_id="+info.Id+", err="+err.Error())
+		} else {
+			result.Data = info
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
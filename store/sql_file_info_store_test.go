@@ -212,3 +212,32 @@ func TestFileInfoDeleteForPost(t *testing.T) {
 		t.Fatal("shouldn't have returned any file infos")
 	}
 }
+
+func TestFileInfoGetForChannelAndUpdatePaths(t *testing.T) {
+	Setup()
+
+	userId := model.NewId()
+	channelId := model.NewId()
+
+	post := Must(store.Post().Save(&model.Post{ChannelId: channelId, UserId: userId, Message: "message"})).(*model.Post)
+	otherPost := Must(store.Post().Save(&model.Post{ChannelId: model.NewId(), UserId: userId, Message: "message"})).(*model.Post)
+
+	info := Must(store.FileInfo().Save(&model.FileInfo{PostId: post.Id, CreatorId: userId, Path: "file.txt"})).(*model.FileInfo)
+	Must(store.FileInfo().Save(&model.FileInfo{PostId: otherPost.Id, CreatorId: userId, Path: "file.txt"}))
+
+	if infos := Must(store.FileInfo().GetForChannel(channelId)).([]*model.FileInfo); len(infos) != 1 {
+		t.Fatal("should've returned exactly one file info")
+	} else if infos[0].Id != info.Id {
+		t.Fatal("returned incorrect file info")
+	}
+
+	info.Path = "moved/file.txt"
+	info.ThumbnailPath = "moved/file_thumb.jpg"
+	if result := <-store.FileInfo().UpdatePaths(info); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	if updated := Must(store.FileInfo().Get(info.Id)).(*model.FileInfo); updated.Path != "moved/file.txt" || updated.ThumbnailPath != "moved/file_thumb.jpg" {
+		t.Fatal("paths should've been updated")
+	}
+}
@@ -0,0 +1,390 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/mattermost/platform/model"
+)
+
+type SqlGroupStore struct {
+	*SqlStore
+}
+
+func NewSqlGroupStore(sqlStore *SqlStore) GroupStore {
+	s := &SqlGroupStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.Group{}, "UserGroups").SetKeys(false, "Id")
+		table.ColMap("Id").SetMaxSize(26)
+		table.ColMap("Name").SetMaxSize(64)
+		table.ColMap("DisplayName").SetMaxSize(64)
+		table.ColMap("Description").SetMaxSize(1024)
+		table.ColMap("CreatorId").SetMaxSize(26)
+
+		table.SetUniqueTogether("Name", "DeleteAt")
+
+		tablem := db.AddTableWithName(model.GroupMember{}, "UserGroupMembers").SetKeys(false, "GroupId", "UserId")
+		tablem.ColMap("GroupId").SetMaxSize(26)
+		tablem.ColMap("UserId").SetMaxSize(26)
+	}
+
+	return s
+}
+
+func (s SqlGroupStore) CreateIndexesIfNotExists() {
+	s.CreateIndexIfNotExists("idx_usergroups_name", "UserGroups", "Name")
+	s.CreateIndexIfNotExists("idx_usergroups_delete_at", "UserGroups", "DeleteAt")
+	s.CreateIndexIfNotExists("idx_usergroupmembers_user_id", "UserGroupMembers", "UserId")
+}
+
+func (s SqlGroupStore) Save(group *model.Group) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if len(group.Id) > 0 {
+			result.Err = model.NewAppError("SqlGroupStore.Save", "store.sql_group.save.existing.app_error", nil, "id="+group.Id, http.StatusBadRequest)
+			storeChannel <- result
+			close(storeChannel)
+			return
+		}
+
+		group.PreSave()
+		if result.Err = group.IsValid(); result.Err != nil {
+			storeChannel <- result
+			close(storeChannel)
+			return
+		}
+
+		if err := s.GetMaster().Insert(group); err != nil {
+			if IsUniqueConstraintError(err.Error(), []string{"Name", "usergroups_name_deleteat_key"}) {
+				result.Err = model.NewAppError("SqlGroupStore.Save", "store.sql_group.save.name_exists.app_error", nil, "id="+group.Id+", "+err.Error(), http.StatusBadRequest)
+			} else {
+				result.Err = model.NewLocAppError("SqlGroupStore.Save", "store.sql_group.save.app_error", nil, "id="+group.Id+", "+err.Error())
+			}
+		} else {
+			result.Data = group
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlGroupStore) Update(group *model.Group) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		group.PreUpdate()
+		if result.Err = group.IsValid(); result.Err != nil {
+			storeChannel <- result
+			close(storeChannel)
+			return
+		}
+
+		if count, err := s.GetMaster().Update(group); err != nil {
+			if IsUniqueConstraintError(err.Error(), []string{"Name", "usergroups_name_deleteat_key"}) {
+				result.Err = model.NewAppError("SqlGroupStore.Update", "store.sql_group.save.name_exists.app_error", nil, "id="+group.Id+", "+err.Error(), http.StatusBadRequest)
+			} else {
+				result.Err = model.NewLocAppError("SqlGroupStore.Update", "store.sql_group.update.app_error", nil, "id="+group.Id+", "+err.Error())
+			}
+		} else if count != 1 {
+			result.Err = model.NewLocAppError("SqlGroupStore.Update", "store.sql_group.update.app_error", nil, "id="+group.Id)
+		} else {
+			result.Data = group
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlGroupStore) Get(id string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		var group *model.Group
+
+		if err := s.GetReplica().SelectOne(&group,
+			`SELECT
+				*
+			FROM
+				UserGroups
+			WHERE
+				Id = :Id
+				AND DeleteAt = 0`, map[string]interface{}{"Id": id}); err != nil {
+			result.Err = model.NewAppError("SqlGroupStore.Get", "store.sql_group.get.app_error", nil, "id="+id+", "+err.Error(), http.StatusNotFound)
+		} else {
+			result.Data = group
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlGroupStore) GetByName(name string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		var group *model.Group
+
+		if err := s.GetReplica().SelectOne(&group,
+			`SELECT
+				*
+			FROM
+				UserGroups
+			WHERE
+				Name = :Name
+				AND DeleteAt = 0`, map[string]interface{}{"Name": name}); err != nil {
+			result.Err = model.NewAppError("SqlGroupStore.GetByName", "store.sql_group.get_by_name.app_error", nil, "name="+name+", "+err.Error(), http.StatusNotFound)
+		} else {
+			result.Data = group
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlGroupStore) GetAll(offset int, limit int) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		var groups []*model.Group
+
+		if _, err := s.GetReplica().Select(&groups,
+			`SELECT
+				*
+			FROM
+				UserGroups
+			WHERE
+				DeleteAt = 0
+			ORDER BY
+				Name ASC
+			LIMIT :Limit
+			OFFSET :Offset`, map[string]interface{}{"Limit": limit, "Offset": offset}); err != nil {
+			result.Err = model.NewLocAppError("SqlGroupStore.GetAll", "store.sql_group.get_all.app_error", nil, err.Error())
+		} else {
+			result.Data = groups
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlGroupStore) Delete(id string, time int64) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if sqlResult, err := s.GetMaster().Exec(
+			`UPDATE
+				UserGroups
+			SET
+				DeleteAt = :DeleteAt,
+				UpdateAt = :UpdateAt
+			WHERE
+				Id = :Id
+				AND DeleteAt = 0`, map[string]interface{}{"DeleteAt": time, "UpdateAt": time, "Id": id}); err != nil {
+			result.Err = model.NewLocAppError("SqlGroupStore.Delete", "store.sql_group.delete.app_error", nil, "id="+id+", err="+err.Error())
+		} else if rows, _ := sqlResult.RowsAffected(); rows == 0 {
+			result.Err = model.NewAppError("SqlGroupStore.Delete", "store.sql_group.delete.no_results.app_error", nil, "id="+id, http.StatusNotFound)
+		} else if _, err := s.GetMaster().Exec("DELETE FROM UserGroupMembers WHERE GroupId = :GroupId", map[string]interface{}{"GroupId": id}); err != nil {
+			result.Err = model.NewLocAppError("SqlGroupStore.Delete", "store.sql_group.delete.app_error", nil, "id="+id+", err="+err.Error())
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlGroupStore) SaveMember(member *model.GroupMember) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		member.PreSave()
+		if result.Err = member.IsValid(); result.Err != nil {
+			storeChannel <- result
+			close(storeChannel)
+			return
+		}
+
+		if err := s.GetMaster().Insert(member); err != nil {
+			if IsUniqueConstraintError(err.Error(), []string{"GroupId", "usergroupmembers_pkey", "PRIMARY"}) {
+				result.Err = model.NewAppError("SqlGroupStore.SaveMember", "store.sql_group.save_member.exists.app_error", nil, "group_id="+member.GroupId+", user_id="+member.UserId+", "+err.Error(), http.StatusBadRequest)
+			} else {
+				result.Err = model.NewLocAppError("SqlGroupStore.SaveMember", "store.sql_group.save_member.save.app_error", nil, "group_id="+member.GroupId+", user_id="+member.UserId+", "+err.Error())
+			}
+		} else {
+			result.Data = member
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlGroupStore) RemoveMember(groupId string, userId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if _, err := s.GetMaster().Exec("DELETE FROM UserGroupMembers WHERE GroupId = :GroupId AND UserId = :UserId", map[string]interface{}{"GroupId": groupId, "UserId": userId}); err != nil {
+			result.Err = model.NewLocAppError("SqlGroupStore.RemoveMember", "store.sql_group.remove_member.app_error", nil, "group_id="+groupId+", user_id="+userId+", "+err.Error())
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlGroupStore) GetMembers(groupId string, offset int, limit int) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		var members []*model.GroupMember
+
+		if _, err := s.GetReplica().Select(&members,
+			`SELECT
+				*
+			FROM
+				UserGroupMembers
+			WHERE
+				GroupId = :GroupId
+			ORDER BY
+				CreateAt ASC
+			LIMIT :Limit
+			OFFSET :Offset`, map[string]interface{}{"GroupId": groupId, "Limit": limit, "Offset": offset}); err != nil {
+			result.Err = model.NewLocAppError("SqlGroupStore.GetMembers", "store.sql_group.get_members.app_error", nil, "group_id="+groupId+", "+err.Error())
+		} else {
+			result.Data = members
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+type groupNameAndUserId struct {
+	Name   string
+	UserId string
+}
+
+// GetMemberIdsByGroupNames returns a map of group name to the ids of that group's members for every group in the
+// list that exists. Groups without members are included with an empty list.
+func (s SqlGroupStore) GetMemberIdsByGroupNames(names []string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		memberIds := make(map[string][]string)
+
+		if len(names) == 0 {
+			result.Data = memberIds
+			storeChannel <- result
+			close(storeChannel)
+			return
+		}
+
+		props := make(map[string]interface{})
+		nameQuery := ""
+
+		for index, name := range names {
+			if len(nameQuery) > 0 {
+				nameQuery += ", "
+			}
+
+			props["name"+strconv.Itoa(index)] = name
+			nameQuery += ":name" + strconv.Itoa(index)
+		}
+
+		var rows []*groupNameAndUserId
+
+		if _, err := s.GetReplica().Select(&rows,
+			`SELECT
+				UserGroups.Name AS Name,
+				COALESCE(UserGroupMembers.UserId, '') AS UserId
+			FROM
+				UserGroups
+				LEFT JOIN UserGroupMembers ON UserGroupMembers.GroupId = UserGroups.Id
+			WHERE
+				UserGroups.DeleteAt = 0
+				AND UserGroups.Name IN (`+nameQuery+`)`, props); err != nil {
+			result.Err = model.NewLocAppError("SqlGroupStore.GetMemberIdsByGroupNames", "store.sql_group.get_member_ids_by_group_names.app_error", nil, err.Error())
+		} else {
+			for _, row := range rows {
+				if _, ok := memberIds[row.Name]; !ok {
+					memberIds[row.Name] = []string{}
+				}
+
+				if len(row.UserId) > 0 {
+					memberIds[row.Name] = append(memberIds[row.Name], row.UserId)
+				}
+			}
+
+			result.Data = memberIds
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlGroupStore) PermanentDeleteMembersByUser(userId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if _, err := s.GetMaster().Exec("DELETE FROM UserGroupMembers WHERE UserId = :UserId", map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewLocAppError("SqlGroupStore.PermanentDeleteMembersByUser", "store.sql_group.permanent_delete_members_by_user.app_error", nil, "user_id="+userId+", "+err.Error())
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
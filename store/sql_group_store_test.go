@@ -0,0 +1,134 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/platform/model"
+)
+
+func TestGroupStoreSaveGetUpdateDelete(t *testing.T) {
+	Setup()
+
+	g1 := &model.Group{Name: "g" + model.NewId(), DisplayName: "Group 1", CreatorId: model.NewId()}
+	if result := <-store.Group().Save(g1); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	if len(g1.Id) != 26 {
+		t.Fatal("should have set the group id")
+	}
+
+	if result := <-store.Group().Save(g1); result.Err == nil {
+		t.Fatal("shouldn't be able to save an existing group")
+	}
+
+	g2 := &model.Group{Name: g1.Name, DisplayName: "Group 2"}
+	if result := <-store.Group().Save(g2); result.Err == nil {
+		t.Fatal("shouldn't be able to save a group with a duplicate name")
+	}
+
+	if result := <-store.Group().Get(g1.Id); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if result.Data.(*model.Group).Name != g1.Name {
+		t.Fatal("got the wrong group")
+	}
+
+	if result := <-store.Group().GetByName(g1.Name); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if result.Data.(*model.Group).Id != g1.Id {
+		t.Fatal("got the wrong group")
+	}
+
+	g1.DisplayName = "Renamed"
+	if result := <-store.Group().Update(g1); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if result.Data.(*model.Group).DisplayName != "Renamed" {
+		t.Fatal("should have updated the display name")
+	}
+
+	if result := <-store.Group().GetAll(0, 1000); result.Err != nil {
+		t.Fatal(result.Err)
+	} else {
+		found := false
+		for _, group := range result.Data.([]*model.Group) {
+			if group.Id == g1.Id {
+				found = true
+			}
+		}
+
+		if !found {
+			t.Fatal("should have returned the group")
+		}
+	}
+
+	if result := <-store.Group().Delete(g1.Id, model.GetMillis()); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	if result := <-store.Group().Get(g1.Id); result.Err == nil {
+		t.Fatal("shouldn't return a deleted group")
+	}
+
+	if result := <-store.Group().Save(g2); result.Err != nil {
+		t.Fatal("should be able to reuse the name of a deleted group", result.Err)
+	}
+}
+
+func TestGroupStoreMembers(t *testing.T) {
+	Setup()
+
+	g1 := &model.Group{Name: "g" + model.NewId(), DisplayName: "Group 1"}
+	Must(store.Group().Save(g1))
+
+	g2 := &model.Group{Name: "g" + model.NewId(), DisplayName: "Group 2"}
+	Must(store.Group().Save(g2))
+
+	userId1 := model.NewId()
+	userId2 := model.NewId()
+
+	if result := <-store.Group().SaveMember(&model.GroupMember{GroupId: g1.Id, UserId: userId1}); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	if result := <-store.Group().SaveMember(&model.GroupMember{GroupId: g1.Id, UserId: userId1}); result.Err == nil {
+		t.Fatal("shouldn't be able to add the same member twice")
+	}
+
+	Must(store.Group().SaveMember(&model.GroupMember{GroupId: g1.Id, UserId: userId2}))
+
+	if result := <-store.Group().GetMembers(g1.Id, 0, 100); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if members := result.Data.([]*model.GroupMember); len(members) != 2 {
+		t.Fatal("should have 2 members")
+	}
+
+	if result := <-store.Group().GetMemberIdsByGroupNames([]string{g1.Name, g2.Name, "missing"}); result.Err != nil {
+		t.Fatal(result.Err)
+	} else {
+		memberIds := result.Data.(map[string][]string)
+
+		if len(memberIds[g1.Name]) != 2 {
+			t.Fatal("should have returned both members of the first group")
+		}
+
+		if ids, ok := memberIds[g2.Name]; !ok || len(ids) != 0 {
+			t.Fatal("should have returned the empty group")
+		}
+
+		if _, ok := memberIds["missing"]; ok {
+			t.Fatal("shouldn't have returned a group that doesn't exist")
+		}
+	}
+
+	Must(store.Group().RemoveMember(g1.Id, userId1))
+	Must(store.Group().PermanentDeleteMembersByUser(userId2))
+
+	if result := <-store.Group().GetMembers(g1.Id, 0, 100); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if members := result.Data.([]*model.GroupMember); len(members) != 0 {
+		t.Fatal("should have no members left")
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"github.com/mattermost/platform/model"
+)
+
+type SqlMfaStore struct {
+	*SqlStore
+}
+
+func NewSqlMfaStore(sqlStore *SqlStore) MfaStore {
+	s := &SqlMfaStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.MfaTimeStep{}, "MfaTimeSteps").SetKeys(false, "UserId")
+		table.ColMap("UserId").SetMaxSize(26)
+
+		tablec := db.AddTableWithName(model.MfaBackupCode{}, "MfaBackupCodes").SetKeys(false, "UserId", "CodeHash")
+		tablec.ColMap("UserId").SetMaxSize(26)
+		tablec.ColMap("CodeHash").SetMaxSize(64)
+	}
+
+	return s
+}
+
+func (s SqlMfaStore) CreateIndexesIfNotExists() {
+}
+
+// UseTimeStep records that the user authenticated with a token from the given time step. The data of the result is
+// false if the user already authenticated with a token from that time step or a later one.
+func (s SqlMfaStore) UseTimeStep(userId string, timeStep int64) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		// Only moving the time step forward makes this safe against two logins racing with the same token
+		if sqlResult, err := s.GetMaster().Exec("UPDATE MfaTimeSteps SET TimeStep = :TimeStep WHERE UserId = :UserId AND TimeStep < :TimeStep", map[string]interface{}{"UserId": userId, "TimeStep": timeStep}); err != nil {
+			result.Err = model.NewLocAppError("SqlMfaStore.UseTimeStep", "store.sql_mfa.use_time_step.app_error", nil, "user_id="+userId+", "+err.Error())
+		} else if count, _ := sqlResult.RowsAffected(); count == 1 {
+			result.Data = true
+		} else if err := s.GetMaster().Insert(&model.MfaTimeStep{UserId: userId, TimeStep: timeStep}); err != nil {
+			if IsUniqueConstraintError(err.Error(), []string{"PRIMARY", "mfatimesteps_pkey"}) {
+				result.Data = false
+			} else {
+				result.Err = model.NewLocAppError("SqlMfaStore.UseTimeStep", "store.sql_mfa.use_time_step.app_error", nil, "user_id="+userId+", "+err.Error())
+			}
+		} else {
+			result.Data = true
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+// SaveBackupCodes replaces the backup codes of the user with the given hashes.
+func (s SqlMfaStore) SaveBackupCodes(userId string, codeHashes []string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		transaction, err := s.GetMaster().Begin()
+		if err != nil {
+			result.Err = model.NewLocAppError("SqlMfaStore.SaveBackupCodes", "store.sql_mfa.save_backup_codes.app_error", nil, "user_id="+userId+", "+err.Error())
+			storeChannel <- result
+			close(storeChannel)
+			return
+		}
+
+		if _, err := transaction.Exec("DELETE FROM MfaBackupCodes WHERE UserId = :UserId", map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewLocAppError("SqlMfaStore.SaveBackupCodes", "store.sql_mfa.save_backup_codes.app_error", nil, "user_id="+userId+", "+err.Error())
+		} else {
+			createAt := model.GetMillis()
+			for _, codeHash := range codeHashes {
+				if err := transaction.Insert(&model.MfaBackupCode{UserId: userId, CodeHash: codeHash, CreateAt: createAt}); err != nil {
+					result.Err = model.NewLocAppError("SqlMfaStore.SaveBackupCodes", "store.sql_mfa.save_backup_codes.app_error", nil, "user_id="+userId+", "+err.Error())
+					break
+				}
+			}
+		}
+
+		if result.Err == nil {
+			if err := transaction.Commit(); err != nil {
+				result.Err = model.NewLocAppError("SqlMfaStore.SaveBackupCodes", "store.sql_mfa.save_backup_codes.app_error", nil, "user_id="+userId+", "+err.Error())
+			}
+		} else {
+			transaction.Rollback()
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+// UseBackupCode removes the backup code with the given hash. The data of the result is false if the user doesn't have
+// that code, which includes it having been used already.
+func (s SqlMfaStore) UseBackupCode(userId string, codeHash string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if sqlResult, err := s.GetMaster().Exec("DELETE FROM MfaBackupCodes WHERE UserId = :UserId AND CodeHash = :CodeHash", map[string]interface{}{"UserId": userId, "CodeHash": codeHash}); err != nil {
+			result.Err = model.NewLocAppError("SqlMfaStore.UseBackupCode", "store.sql_mfa.use_backup_code.app_error", nil, "user_id="+userId+", "+err.Error())
+		} else {
+			count, _ := sqlResult.RowsAffected()
+			result.Data = count == 1
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlMfaStore) PermanentDeleteByUser(userId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if _, err := s.GetMaster().Exec("DELETE FROM MfaBackupCodes WHERE UserId = :UserId", map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewLocAppError("SqlMfaStore.PermanentDeleteByUser", "store.sql_mfa.permanent_delete_by_user.app_error", nil, "user_id="+userId+", "+err.Error())
+		} else if _, err := s.GetMaster().Exec("DELETE FROM MfaTimeSteps WHERE UserId = :UserId", map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewLocAppError("SqlMfaStore.PermanentDeleteByUser", "store.sql_mfa.permanent_delete_by_user.app_error", nil, "user_id="+userId+", "+err.Error())
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/platform/model"
+)
+
+func TestMfaStore(t *testing.T) {
+	Setup()
+
+	userId := model.NewId()
+
+	if !Must(store.Mfa().UseTimeStep(userId, 100)).(bool) {
+		t.Fatal("should've accepted the first time step")
+	}
+
+	if Must(store.Mfa().UseTimeStep(userId, 100)).(bool) {
+		t.Fatal("shouldn't accept the same time step twice")
+	}
+
+	if Must(store.Mfa().UseTimeStep(userId, 99)).(bool) {
+		t.Fatal("shouldn't accept an earlier time step")
+	}
+
+	if !Must(store.Mfa().UseTimeStep(userId, 101)).(bool) {
+		t.Fatal("should've accepted a later time step")
+	}
+
+	Must(store.Mfa().SaveBackupCodes(userId, []string{"hash1", "hash2"}))
+
+	if !Must(store.Mfa().UseBackupCode(userId, "hash1")).(bool) {
+		t.Fatal("should've accepted the backup code")
+	}
+
+	if Must(store.Mfa().UseBackupCode(userId, "hash1")).(bool) {
+		t.Fatal("shouldn't accept a backup code twice")
+	}
+
+	if Must(store.Mfa().UseBackupCode(model.NewId(), "hash2")).(bool) {
+		t.Fatal("shouldn't accept the backup code of another user")
+	}
+
+	Must(store.Mfa().SaveBackupCodes(userId, []string{"hash3"}))
+
+	if Must(store.Mfa().UseBackupCode(userId, "hash2")).(bool) {
+		t.Fatal("saving new backup codes should've replaced the old ones")
+	}
+
+	Must(store.Mfa().PermanentDeleteByUser(userId))
+
+	if Must(store.Mfa().UseBackupCode(userId, "hash3")).(bool) {
+		t.Fatal("should've deleted the backup codes")
+	}
+
+	if !Must(store.Mfa().UseTimeStep(userId, 100)).(bool) {
+		t.Fatal("should've deleted the last time step")
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"github.com/mattermost/platform/model"
+)
+
+type SqlOutOfOfficeStore struct {
+	*SqlStore
+}
+
+func NewSqlOutOfOfficeStore(sqlStore *SqlStore) OutOfOfficeStore {
+	s := &SqlOutOfOfficeStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.OutOfOffice{}, "OutOfOffice").SetKeys(false, "UserId")
+		table.ColMap("UserId").SetMaxSize(26)
+		table.ColMap("Message").SetMaxSize(model.OUT_OF_OFFICE_MESSAGE_MAX_RUNES * 4)
+
+		tabler := db.AddTableWithName(model.OutOfOfficeReply{}, "OutOfOfficeReplies").SetKeys(false, "UserId", "SenderId")
+		tabler.ColMap("UserId").SetMaxSize(26)
+		tabler.ColMap("SenderId").SetMaxSize(26)
+	}
+
+	return s
+}
+
+func (s SqlOutOfOfficeStore) CreateIndexesIfNotExists() {
+	s.CreateIndexIfNotExists("idx_outofoffice_end_at", "OutOfOffice", "EndAt")
+}
+
+func (s SqlOutOfOfficeStore) SaveOrUpdate(outOfOffice *model.OutOfOffice) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		outOfOffice.PreSave()
+		if result.Err = outOfOffice.IsValid(); result.Err != nil {
+			storeChannel <- result
+			close(storeChannel)
+			return
+		}
+
+		if err := s.GetMaster().SelectOne(&model.OutOfOffice{}, "SELECT * FROM OutOfOffice WHERE UserId = :UserId", map[string]interface{}{"UserId": outOfOffice.UserId}); err == nil {
+			if _, err := s.GetMaster().Update(outOfOffice); err != nil {
+				result.Err = model.NewLocAppError("SqlOutOfOfficeStore.SaveOrUpdate", "store.sql_out_of_office.update.app_error", nil, "user_id="+outOfOffice.UserId+", "+err.Error())
+			}
+		} else {
+			if err := s.GetMaster().Insert(outOfOffice); err != nil {
+				result.Err = model.NewLocAppError("SqlOutOfOfficeStore.SaveOrUpdate", "store.sql_out_of_office.save.app_error", nil, "user_id="+outOfOffice.UserId+", "+err.Error())
+			}
+		}
+
+		if result.Err == nil {
+			result.Data = outOfOffice
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlOutOfOfficeStore) Get(userId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		var outOfOffice model.OutOfOffice
+		if err := s.GetReplica().SelectOne(&outOfOffice, "SELECT * FROM OutOfOffice WHERE UserId = :UserId", map[string]interface{}{"UserId": userId}); err != nil {
+			if err == sql.ErrNoRows {
+				result.Err = model.NewAppError("SqlOutOfOfficeStore.Get", "store.sql_out_of_office.get.missing.app_error", nil, "user_id="+userId, http.StatusNotFound)
+			} else {
+				result.Err = model.NewLocAppError("SqlOutOfOfficeStore.Get", "store.sql_out_of_office.get.app_error", nil, "user_id="+userId+", "+err.Error())
+			}
+		} else {
+			result.Data = &outOfOffice
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlOutOfOfficeStore) GetByIds(userIds []string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if len(userIds) == 0 {
+			result.Data = []*model.OutOfOffice{}
+			storeChannel <- result
+			close(storeChannel)
+			return
+		}
+
+		props := make(map[string]interface{})
+		idQuery := ""
+
+		for index, userId := range userIds {
+			if len(idQuery) > 0 {
+				idQuery += ", "
+			}
+
+			props["userId"+strconv.Itoa(index)] = userId
+			idQuery += ":userId" + strconv.Itoa(index)
+		}
+
+		var outOfOffices []*model.OutOfOffice
+		if _, err := s.GetReplica().Select(&outOfOffices, "SELECT * FROM OutOfOffice WHERE UserId IN ("+idQuery+")", props); err != nil {
+			result.Err = model.NewLocAppError("SqlOutOfOfficeStore.GetByIds", "store.sql_out_of_office.get.app_error", nil, err.Error())
+		} else {
+			result.Data = outOfOffices
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+// GetExpired returns the out of office replies that ended at or before the given time.
+func (s SqlOutOfOfficeStore) GetExpired(now int64) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		var outOfOffices []*model.OutOfOffice
+		if _, err := s.GetReplica().Select(&outOfOffices, "SELECT * FROM OutOfOffice WHERE EndAt <= :Now", map[string]interface{}{"Now": now}); err != nil {
+			result.Err = model.NewLocAppError("SqlOutOfOfficeStore.GetExpired", "store.sql_out_of_office.get_expired.app_error", nil, err.Error())
+		} else {
+			result.Data = outOfOffices
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+// DeleteExpired removes the user's out of office reply if it ended at or before the given time. The data of the result
+// is whether it was removed, which it isn't if the user has saved a new period since it was found to be expired.
+func (s SqlOutOfOfficeStore) DeleteExpired(userId string, now int64) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if sqlResult, err := s.GetMaster().Exec("DELETE FROM OutOfOffice WHERE UserId = :UserId AND EndAt <= :Now", map[string]interface{}{"UserId": userId, "Now": now}); err != nil {
+			result.Err = model.NewLocAppError("SqlOutOfOfficeStore.DeleteExpired", "store.sql_out_of_office.delete.app_error", nil, "user_id="+userId+", "+err.Error())
+		} else if count, _ := sqlResult.RowsAffected(); count == 1 {
+			result.Data = true
+		} else {
+			result.Data = false
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+// Delete removes the user's out of office reply along with the record of who it was sent to.
+func (s SqlOutOfOfficeStore) Delete(userId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if _, err := s.GetMaster().Exec("DELETE FROM OutOfOfficeReplies WHERE UserId = :UserId", map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewLocAppError("SqlOutOfOfficeStore.Delete", "store.sql_out_of_office.delete.app_error", nil, "user_id="+userId+", "+err.Error())
+		} else if _, err := s.GetMaster().Exec("DELETE FROM OutOfOffice WHERE UserId = :UserId", map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewLocAppError("SqlOutOfOfficeStore.Delete", "store.sql_out_of_office.delete.app_error", nil, "user_id="+userId+", "+err.Error())
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+// ClaimReply records that a reply is being sent to the sender unless one was already sent at or after the given time.
+// The data of the result is whether the reply should be sent, which is only true for one of several concurrent claims.
+func (s SqlOutOfOfficeStore) ClaimReply(reply *model.OutOfOfficeReply, since int64) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		// Only moving the reply time past the given time makes this safe against two posts racing for the reply
+		if sqlResult, err := s.GetMaster().Exec("UPDATE OutOfOfficeReplies SET ReplyAt = :ReplyAt WHERE UserId = :UserId AND SenderId = :SenderId AND ReplyAt < :Since", map[string]interface{}{"UserId": reply.UserId, "SenderId": reply.SenderId, "ReplyAt": reply.ReplyAt, "Since": since}); err != nil {
+			result.Err = model.NewLocAppError("SqlOutOfOfficeStore.ClaimReply", "store.sql_out_of_office.save_reply.app_error", nil, "user_id="+reply.UserId+", sender_id="+reply.SenderId+", "+err.Error())
+		} else if count, _ := sqlResult.RowsAffected(); count == 1 {
+			result.Data = true
+		} else if err := s.GetMaster().Insert(reply); err != nil {
+			if IsUniqueConstraintError(err.Error(), []string{"PRIMARY", "outofofficereplies_pkey"}) {
+				result.Data = false
+			} else {
+				result.Err = model.NewLocAppError("SqlOutOfOfficeStore.ClaimReply", "store.sql_out_of_office.save_reply.app_error", nil, "user_id="+reply.UserId+", sender_id="+reply.SenderId+", "+err.Error())
+			}
+		} else {
+			result.Data = true
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlOutOfOfficeStore) GetReply(userId string, senderId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		var reply model.OutOfOfficeReply
+		if err := s.GetReplica().SelectOne(&reply, "SELECT * FROM OutOfOfficeReplies WHERE UserId = :UserId AND SenderId = :SenderId", map[string]interface{}{"UserId": userId, "SenderId": senderId}); err != nil {
+			if err == sql.ErrNoRows {
+				result.Err = model.NewAppError("SqlOutOfOfficeStore.GetReply", "store.sql_out_of_office.get_reply.missing.app_error", nil, "user_id="+userId+", sender_id="+senderId, http.StatusNotFound)
+			} else {
+				result.Err = model.NewLocAppError("SqlOutOfOfficeStore.GetReply", "store.sql_out_of_office.get_reply.app_error", nil, "user_id="+userId+", sender_id="+senderId+", "+err.Error())
+			}
+		} else {
+			result.Data = &reply
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/platform/model"
+)
+
+func TestOutOfOfficeStore(t *testing.T) {
+	Setup()
+
+	outOfOffice := &model.OutOfOffice{UserId: model.NewId(), Message: "On vacation", EndAt: model.GetMillis() + 60*60*1000}
+	if result := <-store.OutOfOffice().SaveOrUpdate(outOfOffice); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	outOfOffice.Message = "At a conference"
+	if result := <-store.OutOfOffice().SaveOrUpdate(outOfOffice); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	if routOfOffice := Must(store.OutOfOffice().Get(outOfOffice.UserId)).(*model.OutOfOffice); routOfOffice.Message != outOfOffice.Message {
+		t.Fatal("out of office should've been updated")
+	}
+
+	if result := <-store.OutOfOffice().SaveOrUpdate(&model.OutOfOffice{UserId: model.NewId(), Message: "On vacation"}); result.Err == nil {
+		t.Fatal("shouldn't be able to save an out of office without an end")
+	}
+
+	expired := &model.OutOfOffice{UserId: model.NewId(), Message: "On vacation", StartAt: 1000, EndAt: model.GetMillis() - 1000}
+	Must(store.OutOfOffice().SaveOrUpdate(expired))
+
+	if outOfOffices := Must(store.OutOfOffice().GetByIds([]string{outOfOffice.UserId, expired.UserId, model.NewId()})).([]*model.OutOfOffice); len(outOfOffices) != 2 {
+		t.Fatal("should've returned 2 out of office replies")
+	}
+
+	found := false
+	for _, routOfOffice := range Must(store.OutOfOffice().GetExpired(model.GetMillis())).([]*model.OutOfOffice) {
+		if routOfOffice.UserId == outOfOffice.UserId {
+			t.Fatal("out of office that hasn't ended shouldn't be returned")
+		} else if routOfOffice.UserId == expired.UserId {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Fatal("expired out of office should've been returned")
+	}
+
+	reply := &model.OutOfOfficeReply{UserId: outOfOffice.UserId, SenderId: model.NewId(), ReplyAt: 1000}
+	if claimed := Must(store.OutOfOffice().ClaimReply(reply, 500)).(bool); !claimed {
+		t.Fatal("first reply should've been claimed")
+	}
+
+	if claimed := Must(store.OutOfOffice().ClaimReply(&model.OutOfOfficeReply{UserId: reply.UserId, SenderId: reply.SenderId, ReplyAt: 1500}, 500)).(bool); claimed {
+		t.Fatal("reply shouldn't have been claimed twice")
+	}
+
+	reply.ReplyAt = 2000
+	if claimed := Must(store.OutOfOffice().ClaimReply(reply, 1800)).(bool); !claimed {
+		t.Fatal("later reply should've been claimed")
+	}
+
+	if rreply := Must(store.OutOfOffice().GetReply(reply.UserId, reply.SenderId)).(*model.OutOfOfficeReply); rreply.ReplyAt != 2000 {
+		t.Fatal("reply should've been updated")
+	}
+
+	if deleted := Must(store.OutOfOffice().DeleteExpired(outOfOffice.UserId, model.GetMillis())).(bool); deleted {
+		t.Fatal("out of office that hasn't ended shouldn't be deleted")
+	}
+
+	if deleted := Must(store.OutOfOffice().DeleteExpired(expired.UserId, model.GetMillis())).(bool); !deleted {
+		t.Fatal("expired out of office should've been deleted")
+	} else if result := <-store.OutOfOffice().Get(expired.UserId); result.Err == nil {
+		t.Fatal("expired out of office should've been deleted")
+	}
+
+	Must(store.OutOfOffice().Delete(outOfOffice.UserId))
+
+	if result := <-store.OutOfOffice().Get(outOfOffice.UserId); result.Err == nil {
+		t.Fatal("out of office should've been deleted")
+	}
+
+	if result := <-store.OutOfOffice().GetReply(reply.UserId, reply.SenderId); result.Err == nil {
+		t.Fatal("replies should've been deleted with the out of office")
+	}
+}
@@ -1132,3 +1132,154 @@ func (s SqlPostStore) AnalyticsPostCount(teamId string, mustHaveFile bool, mustH
 
 	return storeChannel
 }
+
+// analyticsDayExpression returns the SQL expression that formats a millisecond timestamp column as a YYYY-MM-DD day.
+func analyticsDayExpression(column string) string {
+	if utils.Cfg.SqlSettings.DriverName == model.DATABASE_DRIVER_POSTGRES {
+		return "TO_CHAR(DATE(TO_TIMESTAMP(" + column + " / 1000)), 'YYYY-MM-DD')"
+	}
+
+	return "DATE(FROM_UNIXTIME(" + column + " / 1000))"
+}
+
+func (s SqlPostStore) AnalyticsPostCountsByDayForChannel(channelId string, startTime int64, endTime int64) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		day := analyticsDayExpression("Posts.CreateAt")
+		query :=
+			`SELECT
+				` + day + ` AS Name,
+				COUNT(Posts.Id) AS Value
+			FROM
+				Posts
+			WHERE
+				Posts.ChannelId = :ChannelId
+				AND Posts.DeleteAt = 0
+				AND Posts.Type NOT LIKE '` + model.POST_SYSTEM_MESSAGE_PREFIX + `%'
+				AND Posts.CreateAt >= :StartTime
+				AND Posts.CreateAt <= :EndTime
+			GROUP BY ` + day + `
+			ORDER BY Name ASC`
+
+		var rows model.AnalyticsRows
+		if _, err := s.GetReplica().Select(&rows, query, map[string]interface{}{"ChannelId": channelId, "StartTime": startTime, "EndTime": endTime}); err != nil {
+			result.Err = model.NewLocAppError("SqlPostStore.AnalyticsPostCountsByDayForChannel", "store.sql_post.analytics_posts_count_by_day_for_channel.app_error", nil, "channel_id="+channelId+", "+err.Error())
+		} else {
+			result.Data = rows
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlPostStore) AnalyticsPosterCountForChannel(channelId string, startTime int64, endTime int64) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		query :=
+			`SELECT
+				COUNT(DISTINCT Posts.UserId)
+			FROM
+				Posts
+			WHERE
+				Posts.ChannelId = :ChannelId
+				AND Posts.DeleteAt = 0
+				AND Posts.Type NOT LIKE '` + model.POST_SYSTEM_MESSAGE_PREFIX + `%'
+				AND Posts.CreateAt >= :StartTime
+				AND Posts.CreateAt <= :EndTime`
+
+		if v, err := s.GetReplica().SelectInt(query, map[string]interface{}{"ChannelId": channelId, "StartTime": startTime, "EndTime": endTime}); err != nil {
+			result.Err = model.NewLocAppError("SqlPostStore.AnalyticsPosterCountForChannel", "store.sql_post.analytics_poster_count_for_channel.app_error", nil, "channel_id="+channelId+", "+err.Error())
+		} else {
+			result.Data = v
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+// AnalyticsTopPostersForChannel returns the users who posted the most in a channel, with each row named after the
+// id of the user.
+func (s SqlPostStore) AnalyticsTopPostersForChannel(channelId string, startTime int64, endTime int64, limit int) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		query :=
+			`SELECT
+				Posts.UserId AS Name,
+				COUNT(Posts.Id) AS Value
+			FROM
+				Posts
+			WHERE
+				Posts.ChannelId = :ChannelId
+				AND Posts.DeleteAt = 0
+				AND Posts.Type NOT LIKE '` + model.POST_SYSTEM_MESSAGE_PREFIX + `%'
+				AND Posts.CreateAt >= :StartTime
+				AND Posts.CreateAt <= :EndTime
+			GROUP BY Posts.UserId
+			ORDER BY Value DESC, Name ASC
+			LIMIT :Limit`
+
+		var rows model.AnalyticsRows
+		if _, err := s.GetReplica().Select(&rows, query, map[string]interface{}{"ChannelId": channelId, "StartTime": startTime, "EndTime": endTime, "Limit": limit}); err != nil {
+			result.Err = model.NewLocAppError("SqlPostStore.AnalyticsTopPostersForChannel", "store.sql_post.analytics_top_posters_for_channel.app_error", nil, "channel_id="+channelId+", "+err.Error())
+		} else {
+			result.Data = rows
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlPostStore) AnalyticsFileCountsByDayForChannel(channelId string, startTime int64, endTime int64) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		day := analyticsDayExpression("FileInfo.CreateAt")
+		query :=
+			`SELECT
+				` + day + ` AS Name,
+				COUNT(FileInfo.Id) AS Value
+			FROM
+				FileInfo
+			INNER JOIN
+				Posts ON FileInfo.PostId = Posts.Id
+			WHERE
+				Posts.ChannelId = :ChannelId
+				AND FileInfo.DeleteAt = 0
+				AND FileInfo.CreateAt >= :StartTime
+				AND FileInfo.CreateAt <= :EndTime
+			GROUP BY ` + day + `
+			ORDER BY Name ASC`
+
+		var rows model.AnalyticsRows
+		if _, err := s.GetReplica().Select(&rows, query, map[string]interface{}{"ChannelId": channelId, "StartTime": startTime, "EndTime": endTime}); err != nil {
+			result.Err = model.NewLocAppError("SqlPostStore.AnalyticsFileCountsByDayForChannel", "store.sql_post.analytics_file_counts_by_day_for_channel.app_error", nil, "channel_id="+channelId+", "+err.Error())
+		} else {
+			result.Data = rows
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
@@ -1041,6 +1041,50 @@ func TestPostCountsByDay(t *testing.T) {
 	}
 }
 
+func TestPostStoreChannelAnalytics(t *testing.T) {
+	Setup()
+
+	channelId := model.NewId()
+	userId1 := model.NewId()
+	userId2 := model.NewId()
+	day := utils.MillisFromTime(utils.Yesterday())
+
+	o1 := Must(store.Post().Save(&model.Post{ChannelId: channelId, UserId: userId1, CreateAt: day, Message: "a" + model.NewId() + "b"})).(*model.Post)
+	Must(store.Post().Save(&model.Post{ChannelId: channelId, UserId: userId1, CreateAt: day, Message: "a" + model.NewId() + "b"}))
+	Must(store.Post().Save(&model.Post{ChannelId: channelId, UserId: userId2, CreateAt: day - (1000 * 60 * 60 * 24 * 2), Message: "a" + model.NewId() + "b"}))
+	Must(store.Post().Save(&model.Post{ChannelId: channelId, UserId: userId2, CreateAt: day, Message: "a" + model.NewId() + "b", Type: model.POST_JOIN_CHANNEL}))
+	Must(store.Post().Save(&model.Post{ChannelId: model.NewId(), UserId: userId2, CreateAt: day, Message: "a" + model.NewId() + "b"}))
+
+	Must(store.FileInfo().Save(&model.FileInfo{PostId: o1.Id, CreatorId: userId1, Path: "file.txt", CreateAt: day}))
+
+	start := day - (1000 * 60 * 60 * 24 * 7)
+	end := day + 1000
+
+	if rows := Must(store.Post().AnalyticsPostCountsByDayForChannel(channelId, start, end)).(model.AnalyticsRows); len(rows) != 2 {
+		t.Fatal("should've returned two days", rows)
+	} else if rows[0].Value != 1 || rows[1].Value != 2 {
+		t.Fatal("wrong values, system messages shouldn't be counted")
+	}
+
+	if rows := Must(store.Post().AnalyticsPostCountsByDayForChannel(channelId, day-1000, end)).(model.AnalyticsRows); len(rows) != 1 {
+		t.Fatal("should've only returned posts in the range")
+	}
+
+	if count := Must(store.Post().AnalyticsPosterCountForChannel(channelId, start, end)).(int64); count != 2 {
+		t.Fatal("wrong poster count", count)
+	}
+
+	if rows := Must(store.Post().AnalyticsTopPostersForChannel(channelId, start, end, 1)).(model.AnalyticsRows); len(rows) != 1 {
+		t.Fatal("should've been limited to one poster")
+	} else if rows[0].Name != userId1 || rows[0].Value != 2 {
+		t.Fatal("wrong top poster")
+	}
+
+	if rows := Must(store.Post().AnalyticsFileCountsByDayForChannel(channelId, start, end)).(model.AnalyticsRows); len(rows) != 1 || rows[0].Value != 1 {
+		t.Fatal("wrong file counts", rows)
+	}
+}
+
 func TestPostStoreGetFlaggedPosts(t *testing.T) {
 	Setup()
 
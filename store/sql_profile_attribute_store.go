@@ -0,0 +1,297 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/platform/model"
+)
+
+type SqlProfileAttributeStore struct {
+	*SqlStore
+}
+
+func NewSqlProfileAttributeStore(sqlStore *SqlStore) ProfileAttributeStore {
+	s := &SqlProfileAttributeStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.ProfileAttribute{}, "ProfileAttributes").SetKeys(false, "Id")
+		table.ColMap("Id").SetMaxSize(26)
+		table.ColMap("Name").SetMaxSize(model.PROFILE_ATTRIBUTE_NAME_MAX_LENGTH).SetUnique(true)
+		table.ColMap("DisplayName").SetMaxSize(model.PROFILE_ATTRIBUTE_DISPLAY_NAME_MAX_RUNES * 4)
+		table.ColMap("Type").SetMaxSize(32)
+		table.ColMap("Visibility").SetMaxSize(32)
+		table.ColMap("LdapAttribute").SetMaxSize(model.PROFILE_ATTRIBUTE_SYNC_MAX_LENGTH)
+		table.ColMap("SamlAttribute").SetMaxSize(model.PROFILE_ATTRIBUTE_SYNC_MAX_LENGTH)
+
+		tablev := db.AddTableWithName(model.ProfileAttributeValue{}, "ProfileAttributeValues").SetKeys(false, "UserId", "AttributeId")
+		tablev.ColMap("UserId").SetMaxSize(26)
+		tablev.ColMap("AttributeId").SetMaxSize(26)
+		tablev.ColMap("Value").SetMaxSize(model.PROFILE_ATTRIBUTE_VALUE_MAX_RUNES * 4)
+	}
+
+	return s
+}
+
+func (s SqlProfileAttributeStore) CreateIndexesIfNotExists() {
+	s.CreateIndexIfNotExists("idx_profileattributevalues_attribute_id", "ProfileAttributeValues", "AttributeId")
+}
+
+func (s SqlProfileAttributeStore) Save(attribute *model.ProfileAttribute) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if len(attribute.Id) > 0 {
+			result.Err = model.NewAppError("SqlProfileAttributeStore.Save", "store.sql_profile_attribute.save.existing.app_error", nil, "id="+attribute.Id, http.StatusBadRequest)
+			storeChannel <- result
+			close(storeChannel)
+			return
+		}
+
+		attribute.PreSave()
+		if result.Err = attribute.IsValid(); result.Err != nil {
+			storeChannel <- result
+			close(storeChannel)
+			return
+		}
+
+		if err := s.GetMaster().Insert(attribute); err != nil {
+			if IsUniqueConstraintError(err.Error(), []string{"Name", "profileattributes_name_key"}) {
+				result.Err = model.NewAppError("SqlProfileAttributeStore.Save", "store.sql_profile_attribute.save.name_exists.app_error", nil, "id="+attribute.Id+", "+err.Error(), http.StatusBadRequest)
+			} else {
+				result.Err = model.NewLocAppError("SqlProfileAttributeStore.Save", "store.sql_profile_attribute.save.app_error", nil, "id="+attribute.Id+", "+err.Error())
+			}
+		} else {
+			result.Data = attribute
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlProfileAttributeStore) Update(attribute *model.ProfileAttribute) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		attribute.PreUpdate()
+		if result.Err = attribute.IsValid(); result.Err != nil {
+			storeChannel <- result
+			close(storeChannel)
+			return
+		}
+
+		if count, err := s.GetMaster().Update(attribute); err != nil {
+			if IsUniqueConstraintError(err.Error(), []string{"Name", "profileattributes_name_key"}) {
+				result.Err = model.NewAppError("SqlProfileAttributeStore.Update", "store.sql_profile_attribute.save.name_exists.app_error", nil, "id="+attribute.Id+", "+err.Error(), http.StatusBadRequest)
+			} else {
+				result.Err = model.NewLocAppError("SqlProfileAttributeStore.Update", "store.sql_profile_attribute.update.app_error", nil, "id="+attribute.Id+", "+err.Error())
+			}
+		} else if count != 1 {
+			result.Err = model.NewAppError("SqlProfileAttributeStore.Update", "store.sql_profile_attribute.get.missing.app_error", nil, "id="+attribute.Id, http.StatusNotFound)
+		} else {
+			result.Data = attribute
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlProfileAttributeStore) Get(id string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		var attribute model.ProfileAttribute
+		if err := s.GetReplica().SelectOne(&attribute, "SELECT * FROM ProfileAttributes WHERE Id = :Id", map[string]interface{}{"Id": id}); err != nil {
+			if err == sql.ErrNoRows {
+				result.Err = model.NewAppError("SqlProfileAttributeStore.Get", "store.sql_profile_attribute.get.missing.app_error", nil, "id="+id, http.StatusNotFound)
+			} else {
+				result.Err = model.NewLocAppError("SqlProfileAttributeStore.Get", "store.sql_profile_attribute.get.app_error", nil, "id="+id+", "+err.Error())
+			}
+		} else {
+			result.Data = &attribute
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlProfileAttributeStore) GetAll() StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		var attributes []*model.ProfileAttribute
+		if _, err := s.GetReplica().Select(&attributes, "SELECT * FROM ProfileAttributes ORDER BY Name"); err != nil {
+			result.Err = model.NewLocAppError("SqlProfileAttributeStore.GetAll", "store.sql_profile_attribute.get.app_error", nil, err.Error())
+		} else {
+			result.Data = attributes
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+// Delete removes the attribute along with the values that users have for it.
+func (s SqlProfileAttributeStore) Delete(id string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if _, err := s.GetMaster().Exec("DELETE FROM ProfileAttributeValues WHERE AttributeId = :Id", map[string]interface{}{"Id": id}); err != nil {
+			result.Err = model.NewLocAppError("SqlProfileAttributeStore.Delete", "store.sql_profile_attribute.delete.app_error", nil, "id="+id+", "+err.Error())
+		} else if _, err := s.GetMaster().Exec("DELETE FROM ProfileAttributes WHERE Id = :Id", map[string]interface{}{"Id": id}); err != nil {
+			result.Err = model.NewLocAppError("SqlProfileAttributeStore.Delete", "store.sql_profile_attribute.delete.app_error", nil, "id="+id+", "+err.Error())
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlProfileAttributeStore) SaveValue(value *model.ProfileAttributeValue) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		value.UpdateAt = model.GetMillis()
+
+		if err := s.GetMaster().SelectOne(&model.ProfileAttributeValue{}, "SELECT * FROM ProfileAttributeValues WHERE UserId = :UserId AND AttributeId = :AttributeId", map[string]interface{}{"UserId": value.UserId, "AttributeId": value.AttributeId}); err == nil {
+			if _, err := s.GetMaster().Update(value); err != nil {
+				result.Err = model.NewLocAppError("SqlProfileAttributeStore.SaveValue", "store.sql_profile_attribute.save_value.app_error", nil, "user_id="+value.UserId+", attribute_id="+value.AttributeId+", "+err.Error())
+			}
+		} else {
+			if err := s.GetMaster().Insert(value); err != nil {
+				result.Err = model.NewLocAppError("SqlProfileAttributeStore.SaveValue", "store.sql_profile_attribute.save_value.app_error", nil, "user_id="+value.UserId+", attribute_id="+value.AttributeId+", "+err.Error())
+			}
+		}
+
+		if result.Err == nil {
+			result.Data = value
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlProfileAttributeStore) DeleteValue(userId string, attributeId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if _, err := s.GetMaster().Exec("DELETE FROM ProfileAttributeValues WHERE UserId = :UserId AND AttributeId = :AttributeId", map[string]interface{}{"UserId": userId, "AttributeId": attributeId}); err != nil {
+			result.Err = model.NewLocAppError("SqlProfileAttributeStore.DeleteValue", "store.sql_profile_attribute.delete_value.app_error", nil, "user_id="+userId+", attribute_id="+attributeId+", "+err.Error())
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlProfileAttributeStore) GetValuesForUser(userId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		var values []*model.ProfileAttributeValue
+		if _, err := s.GetReplica().Select(&values, "SELECT * FROM ProfileAttributeValues WHERE UserId = :UserId", map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewLocAppError("SqlProfileAttributeStore.GetValuesForUser", "store.sql_profile_attribute.get_values.app_error", nil, "user_id="+userId+", "+err.Error())
+		} else {
+			result.Data = values
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+// SearchUsers returns the active users whose value for the attribute starts with the term, ignoring case.
+func (s SqlProfileAttributeStore) SearchUsers(attributeId string, term string, limit int) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		term = strings.ToLower(strings.NewReplacer("%", "", "_", "").Replace(term))
+
+		var users []*model.User
+		if _, err := s.GetReplica().Select(&users,
+			`SELECT
+				Users.*
+			FROM
+				Users, ProfileAttributeValues
+			WHERE
+				Users.Id = ProfileAttributeValues.UserId
+				AND Users.DeleteAt = 0
+				AND ProfileAttributeValues.AttributeId = :AttributeId
+				AND LOWER(ProfileAttributeValues.Value) LIKE :Term
+			ORDER BY Users.Username ASC
+			LIMIT :Limit`, map[string]interface{}{"AttributeId": attributeId, "Term": term + "%", "Limit": limit}); err != nil {
+			result.Err = model.NewLocAppError("SqlProfileAttributeStore.SearchUsers", "store.sql_profile_attribute.search_users.app_error", nil, "attribute_id="+attributeId+", "+err.Error())
+		} else {
+			for _, u := range users {
+				u.Password = ""
+				u.AuthData = new(string)
+				*u.AuthData = ""
+			}
+			result.Data = users
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlProfileAttributeStore) PermanentDeleteValuesByUser(userId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if _, err := s.GetMaster().Exec("DELETE FROM ProfileAttributeValues WHERE UserId = :UserId", map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewLocAppError("SqlProfileAttributeStore.PermanentDeleteValuesByUser", "store.sql_profile_attribute.delete_value.app_error", nil, "user_id="+userId+", "+err.Error())
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/platform/model"
+)
+
+func TestProfileAttributeStore(t *testing.T) {
+	Setup()
+
+	attribute := &model.ProfileAttribute{Name: "department" + model.NewId()[:10], DisplayName: "Department", Type: model.PROFILE_ATTRIBUTE_TYPE_TEXT}
+	if result := <-store.ProfileAttribute().Save(attribute); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+	defer func() {
+		Must(store.ProfileAttribute().Delete(attribute.Id))
+	}()
+
+	if result := <-store.ProfileAttribute().Save(&model.ProfileAttribute{Name: attribute.Name, DisplayName: "Other", Type: model.PROFILE_ATTRIBUTE_TYPE_TEXT}); result.Err == nil {
+		t.Fatal("shouldn't be able to save two attributes with the same name")
+	}
+
+	attribute.Visibility = model.PROFILE_ATTRIBUTE_VISIBILITY_PRIVATE
+	Must(store.ProfileAttribute().Update(attribute))
+
+	if rattribute := Must(store.ProfileAttribute().Get(attribute.Id)).(*model.ProfileAttribute); rattribute.Visibility != model.PROFILE_ATTRIBUTE_VISIBILITY_PRIVATE {
+		t.Fatal("attribute should've been updated")
+	}
+
+	found := false
+	for _, a := range Must(store.ProfileAttribute().GetAll()).([]*model.ProfileAttribute) {
+		if a.Id == attribute.Id {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Fatal("should've returned the attribute")
+	}
+
+	u1 := &model.User{Email: model.NewId(), Username: "n" + model.NewId()}
+	Must(store.User().Save(u1))
+
+	Must(store.ProfileAttribute().SaveValue(&model.ProfileAttributeValue{UserId: u1.Id, AttributeId: attribute.Id, Value: "Sales"}))
+	Must(store.ProfileAttribute().SaveValue(&model.ProfileAttributeValue{UserId: u1.Id, AttributeId: attribute.Id, Value: "Engineering"}))
+
+	if values := Must(store.ProfileAttribute().GetValuesForUser(u1.Id)).([]*model.ProfileAttributeValue); len(values) != 1 || values[0].Value != "Engineering" {
+		t.Fatal("value should've been updated")
+	}
+
+	if users := Must(store.ProfileAttribute().SearchUsers(attribute.Id, "engin", 100)).([]*model.User); len(users) != 1 || users[0].Id != u1.Id {
+		t.Fatal("should've found the user by the start of the value")
+	}
+
+	if users := Must(store.ProfileAttribute().SearchUsers(attribute.Id, "sales", 100)).([]*model.User); len(users) != 0 {
+		t.Fatal("shouldn't have found the user by the old value")
+	}
+
+	Must(store.ProfileAttribute().DeleteValue(u1.Id, attribute.Id))
+
+	if values := Must(store.ProfileAttribute().GetValuesForUser(u1.Id)).([]*model.ProfileAttributeValue); len(values) != 0 {
+		t.Fatal("value should've been deleted")
+	}
+
+	Must(store.ProfileAttribute().SaveValue(&model.ProfileAttributeValue{UserId: u1.Id, AttributeId: attribute.Id, Value: "Engineering"}))
+	Must(store.ProfileAttribute().PermanentDeleteValuesByUser(u1.Id))
+
+	if values := Must(store.ProfileAttribute().GetValuesForUser(u1.Id)).([]*model.ProfileAttributeValue); len(values) != 0 {
+		t.Fatal("values should've been deleted")
+	}
+}
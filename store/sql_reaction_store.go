@@ -178,6 +178,71 @@ func (s SqlReactionStore) GetForPost(postId string) StoreChannel {
 	return storeChannel
 }
 
+func (s SqlReactionStore) GetSummaryForPost(postId string, userId string) StoreChannel {
+	storeChannel := make(StoreChannel)
+
+	go func() {
+		result := StoreResult{}
+
+		var summaries []*model.ReactionSummary
+
+		if _, err := s.GetReplica().Select(&summaries,
+			`SELECT
+				EmojiName,
+				COUNT(*) AS Count,
+				SUM(CASE WHEN UserId = :UserId THEN 1 ELSE 0 END) > 0 AS Reacted
+			FROM
+				Reactions
+			WHERE
+				PostId = :PostId
+			GROUP BY
+				EmojiName
+			ORDER BY
+				MIN(CreateAt)`, map[string]interface{}{"PostId": postId, "UserId": userId}); err != nil {
+			result.Err = model.NewLocAppError("SqlReactionStore.GetSummaryForPost", "store.sql_reaction.get_summary_for_post.app_error", nil, "post_id="+postId+", "+err.Error())
+		} else {
+			result.Data = summaries
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlReactionStore) GetUserIdsForEmoji(postId string, emojiName string, offset int, limit int) StoreChannel {
+	storeChannel := make(StoreChannel)
+
+	go func() {
+		result := StoreResult{}
+
+		var userIds []string
+
+		if _, err := s.GetReplica().Select(&userIds,
+			`SELECT
+				UserId
+			FROM
+				Reactions
+			WHERE
+				PostId = :PostId
+				AND EmojiName = :EmojiName
+			ORDER BY
+				CreateAt
+			LIMIT :Limit
+			OFFSET :Offset`, map[string]interface{}{"PostId": postId, "EmojiName": emojiName, "Limit": limit, "Offset": offset}); err != nil {
+			result.Err = model.NewLocAppError("SqlReactionStore.GetUserIdsForEmoji", "store.sql_reaction.get_user_ids_for_emoji.app_error", nil, "post_id="+postId+", emoji_name="+emojiName+", "+err.Error())
+		} else {
+			result.Data = userIds
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
 func (s SqlReactionStore) DeleteAllWithEmojiName(emojiName string) StoreChannel {
 	storeChannel := make(StoreChannel)
 
@@ -180,6 +180,47 @@ func TestReactionGetForPost(t *testing.T) {
 	}
 }
 
+func TestReactionGetSummaryForPost(t *testing.T) {
+	Setup()
+
+	postId := model.NewId()
+	userId := model.NewId()
+	otherUserId := model.NewId()
+
+	reactions := []*model.Reaction{
+		{UserId: otherUserId, PostId: postId, EmojiName: "smile", CreateAt: 1000},
+		{UserId: userId, PostId: postId, EmojiName: "smile", CreateAt: 1001},
+		{UserId: otherUserId, PostId: postId, EmojiName: "sad", CreateAt: 1002},
+		{UserId: userId, PostId: model.NewId(), EmojiName: "sad", CreateAt: 1003},
+	}
+
+	for _, reaction := range reactions {
+		Must(store.Reaction().Save(reaction))
+	}
+
+	if result := <-store.Reaction().GetSummaryForPost(postId, userId); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if summaries := result.Data.([]*model.ReactionSummary); len(summaries) != 2 {
+		t.Fatal("should've returned a summary for each emoji")
+	} else if summaries[0].EmojiName != "smile" || summaries[0].Count != 2 || !summaries[0].Reacted {
+		t.Fatal("should've counted both smile reactions, including the user's", summaries[0])
+	} else if summaries[1].EmojiName != "sad" || summaries[1].Count != 1 || summaries[1].Reacted {
+		t.Fatal("should've only counted the sad reaction on this post", summaries[1])
+	}
+
+	if result := <-store.Reaction().GetUserIdsForEmoji(postId, "smile", 0, 1); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if userIds := result.Data.([]string); len(userIds) != 1 || userIds[0] != otherUserId {
+		t.Fatal("should've returned the first user to react")
+	}
+
+	if result := <-store.Reaction().GetUserIdsForEmoji(postId, "smile", 1, 1); result.Err != nil {
+		t.Fatal(result.Err)
+	} else if userIds := result.Data.([]string); len(userIds) != 1 || userIds[0] != userId {
+		t.Fatal("should've returned the second user to react")
+	}
+}
+
 func TestReactionDeleteAllWithEmojiName(t *testing.T) {
 	Setup()
 
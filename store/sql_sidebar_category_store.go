@@ -0,0 +1,190 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"net/http"
+
+	"github.com/mattermost/platform/model"
+)
+
+type SqlSidebarCategoryStore struct {
+	*SqlStore
+}
+
+func NewSqlSidebarCategoryStore(sqlStore *SqlStore) SidebarCategoryStore {
+	s := &SqlSidebarCategoryStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.SidebarCategory{}, "SidebarCategories").SetKeys(false, "Id")
+		table.ColMap("Id").SetMaxSize(26)
+		table.ColMap("UserId").SetMaxSize(26)
+		table.ColMap("TeamId").SetMaxSize(26)
+		table.ColMap("Type").SetMaxSize(32)
+		table.ColMap("DisplayName").SetMaxSize(64)
+		table.ColMap("ChannelIds").SetMaxSize(model.SIDEBAR_CATEGORY_MAX_CHANNELS * 30)
+	}
+
+	return s
+}
+
+func (s SqlSidebarCategoryStore) CreateIndexesIfNotExists() {
+	s.CreateIndexIfNotExists("idx_sidebarcategories_user_id", "SidebarCategories", "UserId")
+}
+
+func (s SqlSidebarCategoryStore) Save(category *model.SidebarCategory) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if len(category.Id) > 0 {
+			result.Err = model.NewAppError("SqlSidebarCategoryStore.Save", "store.sql_sidebar_category.save.existing.app_error", nil, "id="+category.Id, http.StatusBadRequest)
+			storeChannel <- result
+			close(storeChannel)
+			return
+		}
+
+		category.PreSave()
+		if result.Err = category.IsValid(); result.Err != nil {
+			storeChannel <- result
+			close(storeChannel)
+			return
+		}
+
+		if err := s.GetMaster().Insert(category); err != nil {
+			if IsUniqueConstraintError(err.Error(), []string{"PRIMARY", "sidebarcategories_pkey"}) {
+				result.Err = model.NewAppError("SqlSidebarCategoryStore.Save", "store.sql_sidebar_category.save.exists.app_error", nil, "id="+category.Id+", "+err.Error(), http.StatusBadRequest)
+			} else {
+				result.Err = model.NewLocAppError("SqlSidebarCategoryStore.Save", "store.sql_sidebar_category.save.app_error", nil, "id="+category.Id+", "+err.Error())
+			}
+		} else {
+			result.Data = category
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlSidebarCategoryStore) Update(category *model.SidebarCategory) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		category.PreUpdate()
+		if result.Err = category.IsValid(); result.Err != nil {
+			storeChannel <- result
+			close(storeChannel)
+			return
+		}
+
+		if count, err := s.GetMaster().Update(category); err != nil {
+			result.Err = model.NewLocAppError("SqlSidebarCategoryStore.Update", "store.sql_sidebar_category.update.app_error", nil, "id="+category.Id+", "+err.Error())
+		} else if count != 1 {
+			result.Err = model.NewLocAppError("SqlSidebarCategoryStore.Update", "store.sql_sidebar_category.update.app_error", nil, "id="+category.Id)
+		} else {
+			result.Data = category
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlSidebarCategoryStore) Get(id string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		var category *model.SidebarCategory
+
+		if err := s.GetReplica().SelectOne(&category,
+			`SELECT
+				*
+			FROM
+				SidebarCategories
+			WHERE
+				Id = :Id`, map[string]interface{}{"Id": id}); err != nil {
+			result.Err = model.NewAppError("SqlSidebarCategoryStore.Get", "store.sql_sidebar_category.get.app_error", nil, "id="+id+", "+err.Error(), http.StatusNotFound)
+		} else {
+			result.Data = category
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlSidebarCategoryStore) GetForTeam(userId string, teamId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		var categories []*model.SidebarCategory
+
+		if _, err := s.GetReplica().Select(&categories,
+			`SELECT
+				*
+			FROM
+				SidebarCategories
+			WHERE
+				UserId = :UserId
+				AND TeamId = :TeamId
+			ORDER BY
+				SortOrder ASC, CreateAt ASC`, map[string]interface{}{"UserId": userId, "TeamId": teamId}); err != nil {
+			result.Err = model.NewLocAppError("SqlSidebarCategoryStore.GetForTeam", "store.sql_sidebar_category.get_for_team.app_error", nil, "user_id="+userId+", team_id="+teamId+", "+err.Error())
+		} else {
+			result.Data = categories
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlSidebarCategoryStore) Delete(id string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if _, err := s.GetMaster().Exec("DELETE FROM SidebarCategories WHERE Id = :Id", map[string]interface{}{"Id": id}); err != nil {
+			result.Err = model.NewLocAppError("SqlSidebarCategoryStore.Delete", "store.sql_sidebar_category.delete.app_error", nil, "id="+id+", "+err.Error())
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlSidebarCategoryStore) PermanentDeleteByUser(userId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if _, err := s.GetMaster().Exec("DELETE FROM SidebarCategories WHERE UserId = :UserId", map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewLocAppError("SqlSidebarCategoryStore.PermanentDeleteByUser", "store.sql_sidebar_category.permanent_delete_by_user.app_error", nil, "user_id="+userId+", "+err.Error())
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
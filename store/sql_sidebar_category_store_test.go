@@ -0,0 +1,87 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/platform/model"
+)
+
+func TestSidebarCategoryStoreSaveGetUpdate(t *testing.T) {
+	Setup()
+
+	category := &model.SidebarCategory{
+		UserId:      model.NewId(),
+		TeamId:      model.NewId(),
+		Type:        model.SIDEBAR_CATEGORY_CUSTOM,
+		DisplayName: "Projects",
+		ChannelIds:  model.StringArray{model.NewId()},
+	}
+
+	if result := <-store.SidebarCategory().Save(category); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	if result := <-store.SidebarCategory().Save(category); result.Err == nil {
+		t.Fatal("shouldn't be able to save an existing category")
+	}
+
+	if rcategory := Must(store.SidebarCategory().Get(category.Id)).(*model.SidebarCategory); len(rcategory.ChannelIds) != 1 || rcategory.ChannelIds[0] != category.ChannelIds[0] {
+		t.Fatal("channel ids should've been saved")
+	}
+
+	category.Collapsed = true
+	category.ChannelIds = nil
+	if result := <-store.SidebarCategory().Update(category); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	if rcategory := Must(store.SidebarCategory().Get(category.Id)).(*model.SidebarCategory); !rcategory.Collapsed || len(rcategory.ChannelIds) != 0 {
+		t.Fatal("category should've been updated")
+	}
+
+	if result := <-store.SidebarCategory().Get(model.NewId()); result.Err == nil {
+		t.Fatal("shouldn't have found a category")
+	}
+}
+
+func TestSidebarCategoryStoreGetForTeam(t *testing.T) {
+	Setup()
+
+	userId := model.NewId()
+	teamId := model.NewId()
+
+	c1 := Must(store.SidebarCategory().Save(&model.SidebarCategory{UserId: userId, TeamId: teamId, Type: model.SIDEBAR_CATEGORY_CHANNELS, DisplayName: "Channels", SortOrder: 1})).(*model.SidebarCategory)
+	c2 := Must(store.SidebarCategory().Save(&model.SidebarCategory{UserId: userId, TeamId: teamId, Type: model.SIDEBAR_CATEGORY_FAVORITES, DisplayName: "Favorites", SortOrder: 0})).(*model.SidebarCategory)
+	Must(store.SidebarCategory().Save(&model.SidebarCategory{UserId: userId, TeamId: model.NewId(), Type: model.SIDEBAR_CATEGORY_CHANNELS, DisplayName: "Channels"}))
+
+	if result := <-store.SidebarCategory().Save(&model.SidebarCategory{UserId: userId, TeamId: teamId, Type: model.SIDEBAR_CATEGORY_CHANNELS, DisplayName: "Channels"}); result.Err == nil {
+		t.Fatal("shouldn't be able to save a second default category of the same type")
+	} else if result.Err.Id != "store.sql_sidebar_category.save.exists.app_error" {
+		t.Fatal("should have failed with the category already existing", result.Err.Id)
+	}
+
+	if categories := Must(store.SidebarCategory().GetForTeam(userId, teamId)).([]*model.SidebarCategory); len(categories) != 2 {
+		t.Fatal("should've returned the categories on the team")
+	} else if categories[0].Id != c2.Id || categories[1].Id != c1.Id {
+		t.Fatal("categories should be sorted")
+	}
+
+	if result := <-store.SidebarCategory().Delete(c1.Id); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	if categories := Must(store.SidebarCategory().GetForTeam(userId, teamId)).([]*model.SidebarCategory); len(categories) != 1 {
+		t.Fatal("category should've been deleted")
+	}
+
+	if result := <-store.SidebarCategory().PermanentDeleteByUser(userId); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	if categories := Must(store.SidebarCategory().GetForTeam(userId, teamId)).([]*model.SidebarCategory); len(categories) != 0 {
+		t.Fatal("categories should've been deleted")
+	}
+}
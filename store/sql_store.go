@@ -65,28 +65,35 @@ const (
 )
 
 type SqlStore struct {
-	master        *gorp.DbMap
-	replicas      []*gorp.DbMap
-	team          TeamStore
-	channel       ChannelStore
-	post          PostStore
-	user          UserStore
-	audit         AuditStore
-	compliance    ComplianceStore
-	session       SessionStore
-	oauth         OAuthStore
-	system        SystemStore
-	webhook       WebhookStore
-	command       CommandStore
-	preference    PreferenceStore
-	license       LicenseStore
-	recovery      PasswordRecoveryStore
-	emoji         EmojiStore
-	status        StatusStore
-	fileInfo      FileInfoStore
-	reaction      ReactionStore
-	SchemaVersion string
-	rrCounter     int64
+	master           *gorp.DbMap
+	replicas         []*gorp.DbMap
+	team             TeamStore
+	channel          ChannelStore
+	post             PostStore
+	user             UserStore
+	audit            AuditStore
+	compliance       ComplianceStore
+	session          SessionStore
+	oauth            OAuthStore
+	system           SystemStore
+	webhook          WebhookStore
+	command          CommandStore
+	preference       PreferenceStore
+	license          LicenseStore
+	recovery         PasswordRecoveryStore
+	emoji            EmojiStore
+	status           StatusStore
+	fileInfo         FileInfoStore
+	reaction         ReactionStore
+	group            GroupStore
+	sidebarCategory  SidebarCategoryStore
+	customStatus     CustomStatusStore
+	profileAttribute ProfileAttributeStore
+	outOfOffice      OutOfOfficeStore
+	usernameHistory  UsernameHistoryStore
+	mfa              MfaStore
+	SchemaVersion    string
+	rrCounter        int64
 }
 
 func initConnection() *SqlStore {
@@ -136,6 +143,13 @@ func NewSqlStore() Store {
 	sqlStore.status = NewSqlStatusStore(sqlStore)
 	sqlStore.fileInfo = NewSqlFileInfoStore(sqlStore)
 	sqlStore.reaction = NewSqlReactionStore(sqlStore)
+	sqlStore.group = NewSqlGroupStore(sqlStore)
+	sqlStore.sidebarCategory = NewSqlSidebarCategoryStore(sqlStore)
+	sqlStore.customStatus = NewSqlCustomStatusStore(sqlStore)
+	sqlStore.profileAttribute = NewSqlProfileAttributeStore(sqlStore)
+	sqlStore.outOfOffice = NewSqlOutOfOfficeStore(sqlStore)
+	sqlStore.usernameHistory = NewSqlUsernameHistoryStore(sqlStore)
+	sqlStore.mfa = NewSqlMfaStore(sqlStore)
 
 	err := sqlStore.master.CreateTablesIfNotExists()
 	if err != nil {
@@ -164,6 +178,13 @@ func NewSqlStore() Store {
 	sqlStore.status.(*SqlStatusStore).CreateIndexesIfNotExists()
 	sqlStore.fileInfo.(*SqlFileInfoStore).CreateIndexesIfNotExists()
 	sqlStore.reaction.(*SqlReactionStore).CreateIndexesIfNotExists()
+	sqlStore.group.(*SqlGroupStore).CreateIndexesIfNotExists()
+	sqlStore.sidebarCategory.(*SqlSidebarCategoryStore).CreateIndexesIfNotExists()
+	sqlStore.customStatus.(*SqlCustomStatusStore).CreateIndexesIfNotExists()
+	sqlStore.profileAttribute.(*SqlProfileAttributeStore).CreateIndexesIfNotExists()
+	sqlStore.outOfOffice.(*SqlOutOfOfficeStore).CreateIndexesIfNotExists()
+	sqlStore.usernameHistory.(*SqlUsernameHistoryStore).CreateIndexesIfNotExists()
+	sqlStore.mfa.(*SqlMfaStore).CreateIndexesIfNotExists()
 
 	sqlStore.preference.(*SqlPreferenceStore).DeleteUnusedFeatures()
 
@@ -687,6 +708,34 @@ func (ss *SqlStore) Reaction() ReactionStore {
 	return ss.reaction
 }
 
+func (ss *SqlStore) Group() GroupStore {
+	return ss.group
+}
+
+func (ss *SqlStore) SidebarCategory() SidebarCategoryStore {
+	return ss.sidebarCategory
+}
+
+func (ss *SqlStore) CustomStatus() CustomStatusStore {
+	return ss.customStatus
+}
+
+func (ss *SqlStore) ProfileAttribute() ProfileAttributeStore {
+	return ss.profileAttribute
+}
+
+func (ss *SqlStore) OutOfOffice() OutOfOfficeStore {
+	return ss.outOfOffice
+}
+
+func (ss *SqlStore) UsernameHistory() UsernameHistoryStore {
+	return ss.usernameHistory
+}
+
+func (ss *SqlStore) Mfa() MfaStore {
+	return ss.mfa
+}
+
 func (ss *SqlStore) DropAllTables() {
 	ss.master.TruncateTables()
 }
@@ -237,5 +237,27 @@ func UpgradeDatabaseToVersion37(sqlStore *SqlStore) {
 	// if shouldPerformUpgrade(sqlStore, VERSION_3_6_0, VERSION_3_7_0) {
 	// Add EditAt column to Posts
 	sqlStore.CreateColumnIfNotExists("Posts", "EditAt", " bigint", " bigint", "0")
+
+	// Add ArchiveAt column to Channels
+	sqlStore.CreateColumnIfNotExists("Channels", "ArchiveAt", "bigint", "bigint", "0")
+
+	// Add PostingPolicy column to Channels
+	sqlStore.CreateColumnIfNotExists("Channels", "PostingPolicy", "varchar(32)", "varchar(32)", "all")
+
+	// Add columns for archiving inactive channels
+	sqlStore.CreateColumnIfNotExists("Channels", "InactiveWarningAt", "bigint", "bigint", "0")
+	sqlStore.CreateColumnIfNotExists("Teams", "ChannelAutoArchiveDays", "int", "integer", "0")
+	sqlStore.CreateColumnIfNotExists("Teams", "ChannelAutoArchiveGraceDays", "int", "integer", "0")
+	sqlStore.CreateColumnIfNotExists("Teams", "ChannelAutoArchiveExemptions", "varchar(2000)", "varchar(2000)", "[]")
+
+	// Add DNDEndTime column to Status
+	sqlStore.CreateColumnIfNotExists("Status", "DNDEndTime", "bigint", "bigint", "0")
+
+	// Add columns for bot accounts to Users
+	sqlStore.CreateColumnIfNotExists("Users", "IsBot", "tinyint(1)", "boolean", "0")
+	sqlStore.CreateColumnIfNotExists("Users", "BotOwnerId", "varchar(26)", "varchar(26)", "")
+
+	// Add Timezone column to Users
+	sqlStore.CreateColumnIfNotExists("Users", "Timezone", "varchar(256)", "varchar(256)", "{}")
 	// }
 }
@@ -67,8 +67,10 @@ func NewSqlUserStore(sqlStore *SqlStore) UserStore {
 		table.ColMap("Props").SetMaxSize(4000)
 		table.ColMap("NotifyProps").SetMaxSize(2000)
 		table.ColMap("Locale").SetMaxSize(5)
+		table.ColMap("Timezone").SetMaxSize(model.USER_TIMEZONE_MAX_LENGTH)
 		table.ColMap("MfaSecret").SetMaxSize(128)
 		table.ColMap("Position").SetMaxSize(64)
+		table.ColMap("BotOwnerId").SetMaxSize(26)
 	}
 
 	return us
@@ -157,10 +159,17 @@ func (us SqlUserStore) Update(user *model.User, trustedUpdateData bool) StoreCha
 			user.FailedAttempts = oldUser.FailedAttempts
 			user.MfaSecret = oldUser.MfaSecret
 			user.MfaActive = oldUser.MfaActive
+			user.IsBot = oldUser.IsBot
+
+			// Clients that don't know about timezones shouldn't clear them
+			if user.Timezone == nil {
+				user.Timezone = oldUser.Timezone
+			}
 
 			if !trustedUpdateData {
 				user.Roles = oldUser.Roles
 				user.DeleteAt = oldUser.DeleteAt
+				user.BotOwnerId = oldUser.BotOwnerId
 			}
 
 			if user.IsOAuthUser() {
@@ -526,6 +535,38 @@ func (us SqlUserStore) GetAllProfiles(offset int, limit int) StoreChannel {
 	return storeChannel
 }
 
+func (us SqlUserStore) GetBots(ownerId string, offset int, limit int) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		query := "SELECT * FROM Users WHERE IsBot = true"
+		if len(ownerId) > 0 {
+			query += " AND BotOwnerId = :OwnerId"
+		}
+		query += " ORDER BY Username ASC LIMIT :Limit OFFSET :Offset"
+
+		var users []*model.User
+		if _, err := us.GetReplica().Select(&users, query, map[string]interface{}{"OwnerId": ownerId, "Offset": offset, "Limit": limit}); err != nil {
+			result.Err = model.NewLocAppError("SqlUserStore.GetBots", "store.sql_user.get_bots.app_error", nil, "owner_id="+ownerId+", "+err.Error())
+		} else {
+			for _, u := range users {
+				u.Password = ""
+				u.AuthData = new(string)
+				*u.AuthData = ""
+			}
+
+			result.Data = users
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
 func (s SqlUserStore) GetEtagForProfiles(teamId string) StoreChannel {
 	storeChannel := make(StoreChannel, 1)
 
@@ -1088,7 +1129,7 @@ func (us SqlUserStore) GetTotalUsersCount() StoreChannel {
 	go func() {
 		result := StoreResult{}
 
-		if count, err := us.GetReplica().SelectInt("SELECT COUNT(Id) FROM Users"); err != nil {
+		if count, err := us.GetReplica().SelectInt("SELECT COUNT(Id) FROM Users WHERE IsBot = false"); err != nil {
 			result.Err = model.NewLocAppError("SqlUserStore.GetTotalUsersCount", "store.sql_user.get_total_users_count.app_error", nil, err.Error())
 		} else {
 			result.Data = count
@@ -1128,9 +1169,9 @@ func (us SqlUserStore) AnalyticsUniqueUserCount(teamId string) StoreChannel {
 
 		query := ""
 		if len(teamId) > 0 {
-			query = "SELECT COUNT(DISTINCT Users.Email) From Users, TeamMembers WHERE TeamMembers.TeamId = :TeamId AND Users.Id = TeamMembers.UserId AND TeamMembers.DeleteAt = 0 AND Users.DeleteAt = 0"
+			query = "SELECT COUNT(DISTINCT Users.Email) From Users, TeamMembers WHERE TeamMembers.TeamId = :TeamId AND Users.Id = TeamMembers.UserId AND TeamMembers.DeleteAt = 0 AND Users.DeleteAt = 0 AND Users.IsBot = false"
 		} else {
-			query = "SELECT COUNT(DISTINCT Email) FROM Users WHERE DeleteAt = 0"
+			query = "SELECT COUNT(DISTINCT Email) FROM Users WHERE DeleteAt = 0 AND IsBot = false"
 		}
 
 		v, err := us.GetReplica().SelectInt(query, map[string]interface{}{"TeamId": teamId})
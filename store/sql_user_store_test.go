@@ -190,6 +190,56 @@ func TestUserCount(t *testing.T) {
 	}
 }
 
+func TestUserStoreGetBots(t *testing.T) {
+	Setup()
+
+	ownerId := model.NewId()
+
+	u1 := &model.User{Username: "a" + model.NewId()}
+	u1.MakeBot(ownerId)
+	Must(store.User().Save(u1))
+
+	u2 := &model.User{Username: "a" + model.NewId()}
+	u2.MakeBot(model.NewId())
+	Must(store.User().Save(u2))
+
+	u3 := &model.User{}
+	u3.Email = model.NewId()
+	Must(store.User().Save(u3))
+
+	count := Must(store.User().GetTotalUsersCount()).(int64)
+	uniqueCount := Must(store.User().AnalyticsUniqueUserCount("")).(int64)
+
+	if bots := Must(store.User().GetBots(ownerId, 0, 100)).([]*model.User); len(bots) != 1 || bots[0].Id != u1.Id {
+		t.Fatal("should've returned the bot of the owner")
+	}
+
+	found := false
+	for _, bot := range Must(store.User().GetBots("", 0, 10000)).([]*model.User) {
+		if bot.Id == u3.Id {
+			t.Fatal("shouldn't have returned a regular user")
+		} else if bot.Id == u2.Id {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Fatal("should've returned every bot")
+	}
+
+	u4 := &model.User{Username: "a" + model.NewId()}
+	u4.MakeBot(ownerId)
+	Must(store.User().Save(u4))
+
+	if Must(store.User().GetTotalUsersCount()).(int64) != count {
+		t.Fatal("bots shouldn't be counted as users")
+	}
+
+	if Must(store.User().AnalyticsUniqueUserCount("")).(int64) != uniqueCount {
+		t.Fatal("bots shouldn't be counted as unique users")
+	}
+}
+
 func TestUserStoreGetAllProfiles(t *testing.T) {
 	Setup()
 
@@ -0,0 +1,154 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/platform/model"
+)
+
+type SqlUsernameHistoryStore struct {
+	*SqlStore
+}
+
+func NewSqlUsernameHistoryStore(sqlStore *SqlStore) UsernameHistoryStore {
+	s := &SqlUsernameHistoryStore{sqlStore}
+
+	for _, db := range sqlStore.GetAllConns() {
+		table := db.AddTableWithName(model.UsernameHistory{}, "UsernameHistory").SetKeys(false, "Id")
+		table.ColMap("Id").SetMaxSize(26)
+		table.ColMap("UserId").SetMaxSize(26)
+		table.ColMap("OldUsername").SetMaxSize(64)
+		table.ColMap("NewUsername").SetMaxSize(64)
+	}
+
+	return s
+}
+
+func (s SqlUsernameHistoryStore) CreateIndexesIfNotExists() {
+	s.CreateIndexIfNotExists("idx_usernamehistory_user_id", "UsernameHistory", "UserId")
+	s.CreateIndexIfNotExists("idx_usernamehistory_old_username", "UsernameHistory", "OldUsername")
+}
+
+func (s SqlUsernameHistoryStore) Save(history *model.UsernameHistory) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		history.PreSave()
+		if result.Err = history.IsValid(); result.Err != nil {
+			storeChannel <- result
+			close(storeChannel)
+			return
+		}
+
+		if err := s.GetMaster().Insert(history); err != nil {
+			result.Err = model.NewLocAppError("SqlUsernameHistoryStore.Save", "store.sql_username_history.save.app_error", nil, "user_id="+history.UserId+", "+err.Error())
+		} else {
+			result.Data = history
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+// GetByUser returns the username changes of the user, most recent first.
+func (s SqlUsernameHistoryStore) GetByUser(userId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		var history []*model.UsernameHistory
+		if _, err := s.GetReplica().Select(&history, "SELECT * FROM UsernameHistory WHERE UserId = :UserId ORDER BY CreateAt DESC", map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewLocAppError("SqlUsernameHistoryStore.GetByUser", "store.sql_username_history.get.app_error", nil, "user_id="+userId+", "+err.Error())
+		} else {
+			result.Data = history
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+// GetUserIdsByOldUsernames returns a map from old username to the id of the user who gave it up at or after the given
+// time. Usernames that currently belong to a user are left out, and if several users gave up the same username, the
+// most recent one wins.
+func (s SqlUsernameHistoryStore) GetUserIdsByOldUsernames(usernames []string, since int64) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		userIds := make(map[string]string)
+
+		if len(usernames) == 0 {
+			result.Data = userIds
+			storeChannel <- result
+			close(storeChannel)
+			return
+		}
+
+		props := map[string]interface{}{"Since": since}
+		nameQuery := ""
+
+		for index, username := range usernames {
+			if len(nameQuery) > 0 {
+				nameQuery += ", "
+			}
+
+			props["username"+strconv.Itoa(index)] = strings.ToLower(username)
+			nameQuery += ":username" + strconv.Itoa(index)
+		}
+
+		var history []*model.UsernameHistory
+		if _, err := s.GetReplica().Select(&history,
+			`SELECT
+				*
+			FROM
+				UsernameHistory
+			WHERE
+				OldUsername IN (`+nameQuery+`)
+				AND CreateAt >= :Since
+				AND OldUsername NOT IN (SELECT Username FROM Users WHERE Username IN (`+nameQuery+`))
+			ORDER BY CreateAt ASC`, props); err != nil {
+			result.Err = model.NewLocAppError("SqlUsernameHistoryStore.GetUserIdsByOldUsernames", "store.sql_username_history.get.app_error", nil, err.Error())
+		} else {
+			for _, change := range history {
+				userIds[change.OldUsername] = change.UserId
+			}
+			result.Data = userIds
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
+
+func (s SqlUsernameHistoryStore) PermanentDeleteByUser(userId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		if _, err := s.GetMaster().Exec("DELETE FROM UsernameHistory WHERE UserId = :UserId", map[string]interface{}{"UserId": userId}); err != nil {
+			result.Err = model.NewLocAppError("SqlUsernameHistoryStore.PermanentDeleteByUser", "store.sql_username_history.permanent_delete_by_user.app_error", nil, "user_id="+userId+", "+err.Error())
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/platform/model"
+)
+
+func TestUsernameHistoryStore(t *testing.T) {
+	Setup()
+
+	u1 := &model.User{Email: model.NewId(), Username: "u" + model.NewId()}
+	Must(store.User().Save(u1))
+
+	oldUsername := "u" + model.NewId()
+	takenUsername := u1.Username
+
+	Must(store.UsernameHistory().Save(&model.UsernameHistory{UserId: model.NewId(), OldUsername: oldUsername, NewUsername: "u" + model.NewId(), CreateAt: 1000}))
+
+	userId := model.NewId()
+	Must(store.UsernameHistory().Save(&model.UsernameHistory{UserId: userId, OldUsername: oldUsername, NewUsername: "u" + model.NewId(), CreateAt: model.GetMillis() - 1000}))
+	Must(store.UsernameHistory().Save(&model.UsernameHistory{UserId: userId, OldUsername: takenUsername, NewUsername: oldUsername + "x"}))
+
+	if result := <-store.UsernameHistory().Save(&model.UsernameHistory{UserId: userId, OldUsername: oldUsername, NewUsername: oldUsername}); result.Err == nil {
+		t.Fatal("shouldn't be able to save a change to the same username")
+	}
+
+	if history := Must(store.UsernameHistory().GetByUser(userId)).([]*model.UsernameHistory); len(history) != 2 || history[0].OldUsername != takenUsername {
+		t.Fatal("should've returned the changes most recent first")
+	}
+
+	userIds := Must(store.UsernameHistory().GetUserIdsByOldUsernames([]string{oldUsername, takenUsername, "u" + model.NewId()}, 0)).(map[string]string)
+	if len(userIds) != 1 || userIds[oldUsername] != userId {
+		t.Fatal("should've returned the most recent user to give up the username", userIds)
+	}
+
+	userIds = Must(store.UsernameHistory().GetUserIdsByOldUsernames([]string{oldUsername}, model.GetMillis()+1000)).(map[string]string)
+	if len(userIds) != 0 {
+		t.Fatal("shouldn't have returned changes from before the given time")
+	}
+
+	Must(store.UsernameHistory().PermanentDeleteByUser(userId))
+
+	if history := Must(store.UsernameHistory().GetByUser(userId)).([]*model.UsernameHistory); len(history) != 0 {
+		t.Fatal("history should've been deleted")
+	}
+}
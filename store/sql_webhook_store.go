@@ -440,3 +440,24 @@ func (s SqlWebhookStore) AnalyticsOutgoingCount(teamId string) StoreChannel {
 
 	return storeChannel
 }
+
+func (s SqlWebhookStore) UpdateTeamIdForChannel(channelId string, teamId string) StoreChannel {
+	storeChannel := make(StoreChannel, 1)
+
+	go func() {
+		result := StoreResult{}
+
+		props := map[string]interface{}{"TeamId": teamId, "ChannelId": channelId, "UpdateAt": model.GetMillis()}
+
+		if _, err := s.GetMaster().Exec("UPDATE IncomingWebhooks SET TeamId = :TeamId, UpdateAt = :UpdateAt WHERE ChannelId = :ChannelId", props); err != nil {
+			result.Err = model.NewLocAppError("SqlWebhookStore.UpdateTeamIdForChannel", "store.sql_webhooks.update_team_id_for_channel.app_error", nil, "channelId="+channelId+", err="+err.Error())
+		} else if _, err := s.GetMaster().Exec("UPDATE OutgoingWebhooks SET TeamId = :TeamId, UpdateAt = :UpdateAt WHERE ChannelId = :ChannelId", props); err != nil {
+			result.Err = model.NewLocAppError("SqlWebhookStore.UpdateTeamIdForChannel", "store.sql_webhooks.update_team_id_for_channel.app_error", nil, "channelId="+channelId+", err="+err.Error())
+		}
+
+		storeChannel <- result
+		close(storeChannel)
+	}()
+
+	return storeChannel
+}
@@ -321,6 +321,38 @@ func TestWebhookStoreUpdateOutgoing(t *testing.T) {
 	}
 }
 
+func TestWebhookStoreUpdateTeamIdForChannel(t *testing.T) {
+	Setup()
+
+	channelId := model.NewId()
+	teamId := model.NewId()
+
+	i1 := &model.IncomingWebhook{}
+	i1.ChannelId = channelId
+	i1.UserId = model.NewId()
+	i1.TeamId = model.NewId()
+	i1 = Must(store.Webhook().SaveIncoming(i1)).(*model.IncomingWebhook)
+
+	o1 := &model.OutgoingWebhook{}
+	o1.ChannelId = channelId
+	o1.CreatorId = model.NewId()
+	o1.TeamId = model.NewId()
+	o1.CallbackURLs = []string{"http://nowhere.com/"}
+	o1 = Must(store.Webhook().SaveOutgoing(o1)).(*model.OutgoingWebhook)
+
+	if result := <-store.Webhook().UpdateTeamIdForChannel(channelId, teamId); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	if hook := Must(store.Webhook().GetIncoming(i1.Id, false)).(*model.IncomingWebhook); hook.TeamId != teamId {
+		t.Fatal("incoming webhook should've been moved")
+	}
+
+	if hook := Must(store.Webhook().GetOutgoing(o1.Id)).(*model.OutgoingWebhook); hook.TeamId != teamId {
+		t.Fatal("outgoing webhook should've been moved")
+	}
+}
+
 func TestWebhookStoreCountIncoming(t *testing.T) {
 	Setup()
 
@@ -47,6 +47,13 @@ type Store interface {
 	Status() StatusStore
 	FileInfo() FileInfoStore
 	Reaction() ReactionStore
+	Group() GroupStore
+	SidebarCategory() SidebarCategoryStore
+	CustomStatus() CustomStatusStore
+	ProfileAttribute() ProfileAttributeStore
+	OutOfOffice() OutOfOfficeStore
+	UsernameHistory() UsernameHistoryStore
+	Mfa() MfaStore
 	MarkSystemRanUnitTests()
 	Close()
 	DropAllTables()
@@ -85,6 +92,7 @@ type ChannelStore interface {
 	Save(channel *model.Channel) StoreChannel
 	CreateDirectChannel(userId string, otherUserId string) StoreChannel
 	SaveDirectChannel(channel *model.Channel, member1 *model.ChannelMember, member2 *model.ChannelMember) StoreChannel
+	SaveGroupChannel(channel *model.Channel, members []*model.ChannelMember) StoreChannel
 	Update(channel *model.Channel) StoreChannel
 	Get(id string, allowFromCache bool) StoreChannel
 	InvalidateChannel(id string)
@@ -92,12 +100,16 @@ type ChannelStore interface {
 	GetFromMaster(id string) StoreChannel
 	Delete(channelId string, time int64) StoreChannel
 	SetDeleteAt(channelId string, deleteAt int64, updateAt int64) StoreChannel
+	SetArchiveAt(channelId string, archiveAt int64, updateAt int64) StoreChannel
+	SetInactiveWarningAt(channelId string, warningAt int64) StoreChannel
+	GetChannelsForAutoArchive(teamId string, inactiveSince int64) StoreChannel
 	PermanentDeleteByTeam(teamId string) StoreChannel
 	PermanentDelete(channelId string) StoreChannel
 	GetByName(team_id string, name string, allowFromCache bool) StoreChannel
 	GetByNameIncludeDeleted(team_id string, name string, allowFromCache bool) StoreChannel
 	GetDeletedByName(team_id string, name string) StoreChannel
 	GetChannels(teamId string, userId string) StoreChannel
+	GetGroupChannelsForUser(userId string) StoreChannel
 	GetMoreChannels(teamId string, userId string, offset int, limit int) StoreChannel
 	GetChannelCounts(teamId string, userId string) StoreChannel
 	GetTeamChannels(teamId string) StoreChannel
@@ -121,11 +133,13 @@ type ChannelStore interface {
 	SetLastViewedAt(channelId string, userId string, newLastViewedAt int64) StoreChannel
 	IncrementMentionCount(channelId string, userId string) StoreChannel
 	AnalyticsTypeCount(teamId string, channelType string) StoreChannel
+	AnalyticsMemberCountsByDay(channelId string, startTime int64, endTime int64) StoreChannel
 	ExtraUpdateByUser(userId string, time int64) StoreChannel
 	GetMembersForUser(teamId string, userId string) StoreChannel
 	SearchInTeam(teamId string, term string) StoreChannel
 	SearchMore(userId string, teamId string, term string) StoreChannel
 	GetMembersByIds(channelId string, userIds []string) StoreChannel
+	GetUserIdsInSameChannels(userId string) StoreChannel
 }
 
 type PostStore interface {
@@ -146,6 +160,10 @@ type PostStore interface {
 	AnalyticsUserCountsWithPostsByDay(teamId string) StoreChannel
 	AnalyticsPostCountsByDay(teamId string) StoreChannel
 	AnalyticsPostCount(teamId string, mustHaveFile bool, mustHaveHashtag bool) StoreChannel
+	AnalyticsPostCountsByDayForChannel(channelId string, startTime int64, endTime int64) StoreChannel
+	AnalyticsPosterCountForChannel(channelId string, startTime int64, endTime int64) StoreChannel
+	AnalyticsTopPostersForChannel(channelId string, startTime int64, endTime int64, limit int) StoreChannel
+	AnalyticsFileCountsByDayForChannel(channelId string, startTime int64, endTime int64) StoreChannel
 	InvalidateLastPostTimeCache(channelId string)
 }
 
@@ -167,6 +185,7 @@ type UserStore interface {
 	GetProfilesNotInChannel(teamId string, channelId string, offset int, limit int) StoreChannel
 	GetProfilesByUsernames(usernames []string, teamId string) StoreChannel
 	GetAllProfiles(offset int, limit int) StoreChannel
+	GetBots(ownerId string, offset int, limit int) StoreChannel
 	GetProfiles(teamId string, offset int, limit int) StoreChannel
 	GetProfileByIds(userId []string, allowFromCache bool) StoreChannel
 	InvalidatProfileCacheForUser(userId string)
@@ -263,6 +282,7 @@ type WebhookStore interface {
 	DeleteOutgoing(webhookId string, time int64) StoreChannel
 	PermanentDeleteOutgoingByUser(userId string) StoreChannel
 	UpdateOutgoing(hook *model.OutgoingWebhook) StoreChannel
+	UpdateTeamIdForChannel(channelId string, teamId string) StoreChannel
 	AnalyticsIncomingCount(teamId string) StoreChannel
 	AnalyticsOutgoingCount(teamId string) StoreChannel
 	InvalidateWebhookCache(webhook string)
@@ -330,11 +350,85 @@ type FileInfoStore interface {
 	InvalidateFileInfosForPostCache(postId string)
 	AttachToPost(fileId string, postId string) StoreChannel
 	DeleteForPost(postId string) StoreChannel
+	GetForChannel(channelId string) StoreChannel
+	UpdatePaths(info *model.FileInfo) StoreChannel
 }
 
 type ReactionStore interface {
 	Save(reaction *model.Reaction) StoreChannel
 	Delete(reaction *model.Reaction) StoreChannel
 	GetForPost(postId string) StoreChannel
+	GetSummaryForPost(postId string, userId string) StoreChannel
+	GetUserIdsForEmoji(postId string, emojiName string, offset int, limit int) StoreChannel
 	DeleteAllWithEmojiName(emojiName string) StoreChannel
 }
+
+type GroupStore interface {
+	Save(group *model.Group) StoreChannel
+	Update(group *model.Group) StoreChannel
+	Get(id string) StoreChannel
+	GetByName(name string) StoreChannel
+	GetAll(offset int, limit int) StoreChannel
+	Delete(id string, time int64) StoreChannel
+	SaveMember(member *model.GroupMember) StoreChannel
+	RemoveMember(groupId string, userId string) StoreChannel
+	GetMembers(groupId string, offset int, limit int) StoreChannel
+	GetMemberIdsByGroupNames(names []string) StoreChannel
+	PermanentDeleteMembersByUser(userId string) StoreChannel
+}
+
+type SidebarCategoryStore interface {
+	Save(category *model.SidebarCategory) StoreChannel
+	Update(category *model.SidebarCategory) StoreChannel
+	Get(id string) StoreChannel
+	GetForTeam(userId string, teamId string) StoreChannel
+	Delete(id string) StoreChannel
+	PermanentDeleteByUser(userId string) StoreChannel
+}
+
+type CustomStatusStore interface {
+	SaveOrUpdate(status *model.CustomStatus) StoreChannel
+	Get(userId string) StoreChannel
+	GetByIds(userIds []string) StoreChannel
+	GetExpired(now int64) StoreChannel
+	DeleteExpired(userId string, now int64) StoreChannel
+	Delete(userId string) StoreChannel
+}
+
+type ProfileAttributeStore interface {
+	Save(attribute *model.ProfileAttribute) StoreChannel
+	Update(attribute *model.ProfileAttribute) StoreChannel
+	Get(id string) StoreChannel
+	GetAll() StoreChannel
+	Delete(id string) StoreChannel
+	SaveValue(value *model.ProfileAttributeValue) StoreChannel
+	DeleteValue(userId string, attributeId string) StoreChannel
+	GetValuesForUser(userId string) StoreChannel
+	SearchUsers(attributeId string, term string, limit int) StoreChannel
+	PermanentDeleteValuesByUser(userId string) StoreChannel
+}
+
+type OutOfOfficeStore interface {
+	SaveOrUpdate(outOfOffice *model.OutOfOffice) StoreChannel
+	Get(userId string) StoreChannel
+	GetByIds(userIds []string) StoreChannel
+	GetExpired(now int64) StoreChannel
+	DeleteExpired(userId string, now int64) StoreChannel
+	Delete(userId string) StoreChannel
+	ClaimReply(reply *model.OutOfOfficeReply, since int64) StoreChannel
+	GetReply(userId string, senderId string) StoreChannel
+}
+
+type UsernameHistoryStore interface {
+	Save(history *model.UsernameHistory) StoreChannel
+	GetByUser(userId string) StoreChannel
+	GetUserIdsByOldUsernames(usernames []string, since int64) StoreChannel
+	PermanentDeleteByUser(userId string) StoreChannel
+}
+
+type MfaStore interface {
+	UseTimeStep(userId string, timeStep int64) StoreChannel
+	SaveBackupCodes(userId string, codeHashes []string) StoreChannel
+	UseBackupCode(userId string, codeHash string) StoreChannel
+	PermanentDeleteByUser(userId string) StoreChannel
+}
@@ -134,6 +134,18 @@ func SetDefaultRolesBasedOnConfig() {
 		model.ROLE_SYSTEM_USER.Permissions = append(
 			model.ROLE_SYSTEM_USER.Permissions,
 			model.PERMISSION_MANAGE_OAUTH.Id,
+			model.PERMISSION_MANAGE_BOTS.Id,
+		)
+	}
+
+	if *Cfg.ServiceSettings.EnableUserAccessTokens {
+		model.ROLE_SYSTEM_USER.Permissions = append(
+			model.ROLE_SYSTEM_USER.Permissions,
+			model.PERMISSION_CREATE_USER_ACCESS_TOKEN.Id,
+		)
+		model.ROLE_SYSTEM_ADMIN.Permissions = append(
+			model.ROLE_SYSTEM_ADMIN.Permissions,
+			model.PERMISSION_CREATE_USER_ACCESS_TOKEN.Id,
 		)
 	}
 
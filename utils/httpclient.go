@@ -0,0 +1,146 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package utils
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	connectTimeout = 3 * time.Second
+	requestTimeout = 30 * time.Second
+)
+
+var reservedIPRanges []*net.IPNet
+
+func init() {
+	for _, cidr := range []string{
+		"0.0.0.0/8",      // "this" network
+		"10.0.0.0/8",     // private
+		"100.64.0.0/10",  // carrier-grade NAT
+		"127.0.0.0/8",    // loopback
+		"169.254.0.0/16", // link-local
+		"172.16.0.0/12",  // private
+		"192.0.0.0/24",   // IETF protocol assignments
+		"192.168.0.0/16", // private
+		"198.18.0.0/15",  // benchmarking
+		"224.0.0.0/4",    // multicast
+		"240.0.0.0/4",    // reserved
+		"::/128",         // unspecified
+		"::1/128",        // loopback
+		"fc00::/7",       // unique local
+		"fe80::/10",      // link-local
+		"ff00::/8",       // multicast
+	} {
+		_, parsed, _ := net.ParseCIDR(cidr)
+		reservedIPRanges = append(reservedIPRanges, parsed)
+	}
+}
+
+// IsReservedIP returns true if the given address is a loopback, private, link-local or otherwise
+// non-public address that the server should never be tricked into connecting to on behalf of a user.
+func IsReservedIP(ip net.IP) bool {
+	for _, ipRange := range reservedIPRanges {
+		if ipRange.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+var errAddressForbidden = errors.New("address forbidden")
+
+// isAllowedInternalConnection returns true if ServiceSettings.AllowedUntrustedInternalConnections lets the server
+// connect to the given internal address. Each entry in the setting is a host name, an IP address or a CIDR range.
+func isAllowedInternalConnection(host string, ip net.IP) bool {
+	if Cfg.ServiceSettings.AllowedUntrustedInternalConnections == nil {
+		return false
+	}
+
+	for _, allowed := range strings.Fields(*Cfg.ServiceSettings.AllowedUntrustedInternalConnections) {
+		if strings.EqualFold(allowed, host) {
+			return true
+		} else if _, ipRange, err := net.ParseCIDR(allowed); err == nil && ipRange.Contains(ip) {
+			return true
+		} else if allowedIP := net.ParseIP(allowed); allowedIP != nil && allowedIP.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func dialBlockingReservedIPs(network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return nil, err
+	}
+
+	var firstErr error
+	for _, ip := range ips {
+		if IsReservedIP(ip) && !isAllowedInternalConnection(host, ip) {
+			if firstErr == nil {
+				firstErr = errAddressForbidden
+			}
+			continue
+		}
+
+		// Dial the resolved address rather than the host name so that a second lookup can't hand us a different one
+		conn, err := net.DialTimeout(network, net.JoinHostPort(ip.String(), port), connectTimeout)
+		if err == nil {
+			return conn, nil
+		} else if firstErr == nil || firstErr == errAddressForbidden {
+			firstErr = err
+		}
+	}
+
+	if firstErr == nil {
+		firstErr = errors.New("no addresses found for " + host)
+	}
+
+	return nil, firstErr
+}
+
+// The transports are shared by every client so that connections are reused rather than leaked with each request
+var (
+	secureTransport   = newTransport(false)
+	insecureTransport = newTransport(true)
+)
+
+func newTransport(insecure bool) *http.Transport {
+	return &http.Transport{
+		Dial:                  dialBlockingReservedIPs,
+		TLSHandshakeTimeout:   connectTimeout,
+		ResponseHeaderTimeout: requestTimeout,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   4,
+		IdleConnTimeout:       90 * time.Second,
+		TLSClientConfig:       &tls.Config{InsecureSkipVerify: insecure},
+	}
+}
+
+// HttpClient returns an http.Client for fetching user-supplied URLs. It refuses to connect to internal
+// addresses other than those in ServiceSettings.AllowedUntrustedInternalConnections and honours
+// ServiceSettings.EnableInsecureOutgoingConnections for TLS verification.
+func HttpClient() *http.Client {
+	tr := secureTransport
+	if Cfg.ServiceSettings.EnableInsecureOutgoingConnections != nil && *Cfg.ServiceSettings.EnableInsecureOutgoingConnections {
+		tr = insecureTransport
+	}
+
+	return &http.Client{
+		Transport: tr,
+		Timeout:   requestTimeout,
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package utils
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsReservedIP(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "::1", "fd00::1", "fe80::1", "0.0.0.0"} {
+		if !IsReservedIP(net.ParseIP(addr)) {
+			t.Fatal("should be reserved", addr)
+		}
+	}
+
+	for _, addr := range []string{"8.8.8.8", "52.1.2.3", "2001:4860:4860::8888"} {
+		if IsReservedIP(net.ParseIP(addr)) {
+			t.Fatal("should not be reserved", addr)
+		}
+	}
+}
+
+func TestHttpClientBlocksInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("secret"))
+	}))
+	defer server.Close()
+
+	if resp, err := HttpClient().Get(server.URL); err == nil {
+		resp.Body.Close()
+		t.Fatal("should have refused to connect to a loopback address")
+	}
+}
+
+func TestHttpClientAllowsConfiguredInternalAddresses(t *testing.T) {
+	LoadConfig("config.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	allowed := *Cfg.ServiceSettings.AllowedUntrustedInternalConnections
+	defer func() {
+		*Cfg.ServiceSettings.AllowedUntrustedInternalConnections = allowed
+	}()
+
+	for _, setting := range []string{"127.0.0.1", "localhost 127.0.0.0/8"} {
+		*Cfg.ServiceSettings.AllowedUntrustedInternalConnections = setting
+		if resp, err := HttpClient().Get(server.URL); err != nil {
+			t.Fatal(setting, err)
+		} else {
+			resp.Body.Close()
+		}
+	}
+
+	// Connections made while the address was allowed would otherwise be reused
+	secureTransport.CloseIdleConnections()
+
+	*Cfg.ServiceSettings.AllowedUntrustedInternalConnections = "10.0.0.0/8 localhost"
+	if resp, err := HttpClient().Get(server.URL); err == nil {
+		resp.Body.Close()
+		t.Fatal("should have refused to connect to an address that isn't allowed")
+	}
+}
@@ -0,0 +1,868 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MarkdownOptions controls how mentions are rendered. Either function may be nil, and a mention that can't be
+// resolved is left exactly as it was written.
+type MarkdownOptions struct {
+	// ResolveUserMention returns the display name of the user with the given username
+	ResolveUserMention func(username string) (string, bool)
+
+	// ResolveChannelMention returns the display name of the channel with the given name
+	ResolveChannelMention func(channelName string) (string, bool)
+}
+
+const (
+	markdownParagraph = iota
+	markdownHeading
+	markdownCodeBlock
+	markdownBlockQuote
+	markdownList
+	markdownRule
+)
+
+type markdownBlock struct {
+	kind     int
+	level    int    // heading level
+	text     string // inline text of paragraphs and headings, or the contents of a code block
+	ordered  bool
+	start    int
+	loose    bool
+	items    [][]*markdownBlock
+	children []*markdownBlock
+}
+
+const (
+	markdownText = iota
+	markdownCode
+	markdownStrong
+	markdownEmphasis
+	markdownStrikethrough
+	markdownLink
+	markdownImage
+	markdownLineBreak
+)
+
+type markdownInline struct {
+	kind     int
+	text     string
+	url      string
+	children []*markdownInline
+}
+
+var (
+	markdownHeadingRegex    = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	markdownRuleRegex       = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	markdownBlockQuoteRegex = regexp.MustCompile(`^ {0,3}> ?`)
+	markdownListItemRegex   = regexp.MustCompile(`^( {0,3})([-*+]|(\d{1,9})[.)])(?:[ \t]+|$)`)
+
+	markdownUsernameRegex    = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9\.\-_]*`)
+	markdownChannelNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9\-_]*`)
+)
+
+// MarkdownToHTML renders a message as HTML that is safe to include in an email. Raw HTML in the message is
+// escaped, and only http, https and mailto links are kept.
+func MarkdownToHTML(markdown string, options *MarkdownOptions) string {
+	var buf bytes.Buffer
+	renderMarkdownBlocksHTML(&buf, parseMarkdownBlocks(splitMarkdownLines(markdown)), false, options)
+	return buf.String()
+}
+
+// MarkdownToPlainText renders a message as plain text with its formatting removed, for use in push notifications.
+func MarkdownToPlainText(markdown string, options *MarkdownOptions) string {
+	return renderMarkdownBlocksText(parseMarkdownBlocks(splitMarkdownLines(markdown)), options)
+}
+
+func splitMarkdownLines(markdown string) []string {
+	markdown = strings.Replace(markdown, "\r\n", "\n", -1)
+	return strings.Split(strings.TrimRight(markdown, "\n"), "\n")
+}
+
+func parseMarkdownBlocks(lines []string) []*markdownBlock {
+	var blocks []*markdownBlock
+	var paragraph []string
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, &markdownBlock{kind: markdownParagraph, text: strings.Join(paragraph, "\n")})
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		if isBlankMarkdownLine(line) {
+			flushParagraph()
+			i++
+			continue
+		}
+
+		if fence, ok := parseMarkdownFence(line); ok {
+			flushParagraph()
+
+			var code []string
+			for i++; i < len(lines); i++ {
+				if isClosingMarkdownFence(lines[i], fence) {
+					i++
+					break
+				}
+				code = append(code, lines[i])
+			}
+
+			blocks = append(blocks, &markdownBlock{kind: markdownCodeBlock, text: strings.Join(code, "\n")})
+			continue
+		}
+
+		if match := markdownHeadingRegex.FindStringSubmatch(line); match != nil {
+			flushParagraph()
+			blocks = append(blocks, &markdownBlock{kind: markdownHeading, level: len(match[1]), text: match[2]})
+			i++
+			continue
+		}
+
+		if markdownRuleRegex.MatchString(line) {
+			flushParagraph()
+			blocks = append(blocks, &markdownBlock{kind: markdownRule})
+			i++
+			continue
+		}
+
+		if markdownBlockQuoteRegex.MatchString(line) {
+			flushParagraph()
+
+			var quoted []string
+			for ; i < len(lines); i++ {
+				if loc := markdownBlockQuoteRegex.FindStringIndex(lines[i]); loc != nil {
+					quoted = append(quoted, lines[i][loc[1]:])
+				} else if !isBlankMarkdownLine(lines[i]) && !isBlankMarkdownLine(quoted[len(quoted)-1]) && !startsMarkdownBlock(lines[i]) {
+					// A line without a marker continues the quoted paragraph
+					quoted = append(quoted, lines[i])
+				} else {
+					break
+				}
+			}
+
+			blocks = append(blocks, &markdownBlock{kind: markdownBlockQuote, children: parseMarkdownBlocks(quoted)})
+			continue
+		}
+
+		if match := markdownListItemRegex.FindStringSubmatch(line); match != nil {
+			// Only lists that start with a non-empty item (and at 1, if they're ordered) can interrupt a paragraph
+			if len(paragraph) == 0 || (!isBlankMarkdownLine(line[len(match[0]):]) && (match[3] == "" || match[3] == "1")) {
+				flushParagraph()
+
+				var list *markdownBlock
+				list, i = parseMarkdownList(lines, i)
+				blocks = append(blocks, list)
+				continue
+			}
+		}
+
+		if len(paragraph) == 0 && markdownIndentation(line) >= 4 {
+			var code []string
+			for ; i < len(lines) && (isBlankMarkdownLine(lines[i]) || markdownIndentation(lines[i]) >= 4); i++ {
+				code = append(code, stripMarkdownIndentation(lines[i], 4))
+			}
+
+			for len(code) > 0 && isBlankMarkdownLine(code[len(code)-1]) {
+				code = code[:len(code)-1]
+			}
+
+			blocks = append(blocks, &markdownBlock{kind: markdownCodeBlock, text: strings.Join(code, "\n")})
+			continue
+		}
+
+		paragraph = append(paragraph, strings.TrimSpace(line))
+		i++
+	}
+
+	flushParagraph()
+
+	return blocks
+}
+
+func parseMarkdownList(lines []string, start int) (*markdownBlock, int) {
+	first := markdownListItemRegex.FindStringSubmatch(lines[start])
+	marker := first[2][len(first[2])-1]
+
+	list := &markdownBlock{kind: markdownList, ordered: first[3] != ""}
+	if list.ordered {
+		list.start, _ = strconv.Atoi(first[3])
+	}
+
+	i := start
+	for i < len(lines) {
+		match := markdownListItemRegex.FindStringSubmatch(lines[i])
+		if match == nil || match[2][len(match[2])-1] != marker || markdownRuleRegex.MatchString(lines[i]) {
+			break
+		}
+
+		// The item's contents are lined up with the first character after the marker
+		indent := len(match[0])
+		item := []string{lines[i][len(match[0]):]}
+		if isBlankMarkdownLine(item[0]) {
+			indent = len(match[1]) + len(match[2]) + 1
+		}
+
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+
+			if isBlankMarkdownLine(line) {
+				// A blank line only belongs to the item if the item continues after it
+				if i+1 < len(lines) && !isBlankMarkdownLine(lines[i+1]) && markdownIndentation(lines[i+1]) >= indent {
+					item = append(item, "")
+					list.loose = true
+					continue
+				}
+				break
+			}
+
+			if markdownIndentation(line) >= indent {
+				item = append(item, stripMarkdownIndentation(line, indent))
+			} else if !isBlankMarkdownLine(item[len(item)-1]) && !startsMarkdownBlock(line) {
+				// A line that isn't indented continues the item's last paragraph
+				item = append(item, line)
+			} else {
+				break
+			}
+		}
+
+		list.items = append(list.items, parseMarkdownBlocks(item))
+
+		// Blank lines between items make the list loose, but anything else after them ends the list
+		next := i
+		for next < len(lines) && isBlankMarkdownLine(lines[next]) {
+			next++
+		}
+
+		if next != i {
+			if next < len(lines) {
+				if match := markdownListItemRegex.FindStringSubmatch(lines[next]); match != nil && match[2][len(match[2])-1] == marker {
+					list.loose = true
+					i = next
+					continue
+				}
+			}
+			break
+		}
+	}
+
+	return list, i
+}
+
+func parseMarkdownFence(line string) (string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return "", false
+	}
+
+	for _, c := range []byte{'`', '~'} {
+		if n := countLeadingMarkdownChars(trimmed, c); n >= 3 {
+			// Backticks in the info string mean that this is actually inline code
+			if c == '`' && strings.ContainsRune(trimmed[n:], '`') {
+				return "", false
+			}
+
+			return trimmed[:n], true
+		}
+	}
+
+	return "", false
+}
+
+func isClosingMarkdownFence(line string, fence string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return false
+	}
+
+	n := countLeadingMarkdownChars(trimmed, fence[0])
+	return n >= len(fence) && isBlankMarkdownLine(trimmed[n:])
+}
+
+func startsMarkdownBlock(line string) bool {
+	_, isFence := parseMarkdownFence(line)
+
+	return isFence ||
+		markdownHeadingRegex.MatchString(line) ||
+		markdownRuleRegex.MatchString(line) ||
+		markdownBlockQuoteRegex.MatchString(line) ||
+		markdownListItemRegex.MatchString(line)
+}
+
+func isBlankMarkdownLine(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func countLeadingMarkdownChars(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
+
+// markdownIndentation returns the number of columns that a line is indented by, counting tabs as 4 columns.
+func markdownIndentation(line string) int {
+	columns := 0
+	for _, c := range line {
+		if c == ' ' {
+			columns++
+		} else if c == '\t' {
+			columns += 4 - columns%4
+		} else {
+			break
+		}
+	}
+	return columns
+}
+
+func stripMarkdownIndentation(line string, columns int) string {
+	removed := 0
+	for i, c := range line {
+		if removed >= columns {
+			return line[i:]
+		}
+
+		if c == ' ' {
+			removed++
+		} else if c == '\t' {
+			removed += 4 - removed%4
+		} else {
+			return line[i:]
+		}
+	}
+	return ""
+}
+
+func parseMarkdownInlines(s string, options *MarkdownOptions) []*markdownInline {
+	var inlines []*markdownInline
+	var text bytes.Buffer
+
+	add := func(inline *markdownInline) {
+		if text.Len() > 0 {
+			inlines = append(inlines, &markdownInline{kind: markdownText, text: text.String()})
+			text.Reset()
+		}
+
+		if inline != nil {
+			inlines = append(inlines, inline)
+		}
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		if c == '\\' && i+1 < len(s) && isMarkdownPunctuation(s[i+1]) {
+			text.WriteByte(s[i+1])
+			i += 2
+			continue
+		}
+
+		if c == '\n' {
+			add(&markdownInline{kind: markdownLineBreak})
+			i++
+			continue
+		}
+
+		if c == '`' {
+			if code, length, ok := parseMarkdownCodeSpan(s[i:]); ok {
+				add(&markdownInline{kind: markdownCode, text: code})
+				i += length
+				continue
+			}
+
+			// An unmatched run of backticks is literal text, and none of them can start a code span
+			n := countLeadingMarkdownChars(s[i:], '`')
+			text.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+
+		if c == '!' && strings.HasPrefix(s[i+1:], "[") {
+			if label, url, length, ok := parseMarkdownLink(s[i+1:]); ok {
+				add(&markdownInline{kind: markdownImage, text: label, url: url})
+				i += length + 1
+				continue
+			}
+		}
+
+		if c == '[' {
+			if label, url, length, ok := parseMarkdownLink(s[i:]); ok {
+				add(&markdownInline{kind: markdownLink, url: url, children: removeMarkdownLinks(parseMarkdownInlines(label, options))})
+				i += length
+				continue
+			}
+		}
+
+		if c == '<' {
+			if end := strings.IndexByte(s[i:], '>'); end > 0 {
+				if url := s[i+1 : i+end]; isSafeMarkdownUrl(url) && !strings.ContainsAny(url, " \t\n<") {
+					add(&markdownInline{kind: markdownLink, url: url, children: []*markdownInline{{kind: markdownText, text: url}}})
+					i += end + 1
+					continue
+				}
+			}
+		}
+
+		if c == '*' || c == '_' || c == '~' {
+			if inline, length, ok := parseMarkdownEmphasis(s, i, options); ok {
+				add(inline)
+				i += length
+				continue
+			}
+		}
+
+		if isMarkdownWordStart(s, i) {
+			if url := parseMarkdownAutolink(s[i:]); url != "" {
+				add(&markdownInline{kind: markdownLink, url: url, children: []*markdownInline{{kind: markdownText, text: url}}})
+				i += len(url)
+				continue
+			}
+
+			if c == '@' && options != nil && options.ResolveUserMention != nil {
+				if mention, length := resolveMarkdownMention(s[i+1:], markdownUsernameRegex, options.ResolveUserMention); length > 0 {
+					text.WriteString("@" + mention)
+					i += length + 1
+					continue
+				}
+			}
+
+			if c == '~' && options != nil && options.ResolveChannelMention != nil {
+				if mention, length := resolveMarkdownMention(s[i+1:], markdownChannelNameRegex, options.ResolveChannelMention); length > 0 {
+					text.WriteString("~" + mention)
+					i += length + 1
+					continue
+				}
+			}
+		}
+
+		text.WriteByte(c)
+		i++
+	}
+
+	add(nil)
+
+	return inlines
+}
+
+// removeMarkdownLinks replaces any links in the label of another link with their text, since links can't be nested.
+func removeMarkdownLinks(inlines []*markdownInline) []*markdownInline {
+	result := make([]*markdownInline, 0, len(inlines))
+
+	for _, inline := range inlines {
+		if inline.kind == markdownLink {
+			result = append(result, removeMarkdownLinks(inline.children)...)
+		} else {
+			if len(inline.children) > 0 {
+				inline.children = removeMarkdownLinks(inline.children)
+			}
+			result = append(result, inline)
+		}
+	}
+
+	return result
+}
+
+func parseMarkdownCodeSpan(s string) (string, int, bool) {
+	n := countLeadingMarkdownChars(s, '`')
+
+	for i := n; i < len(s); {
+		if s[i] != '`' {
+			i++
+			continue
+		}
+
+		closing := countLeadingMarkdownChars(s[i:], '`')
+		if closing == n {
+			code := strings.Replace(s[n:i], "\n", " ", -1)
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+				code = code[1 : len(code)-1]
+			}
+
+			return code, i + closing, true
+		}
+
+		i += closing
+	}
+
+	return "", 0, false
+}
+
+// parseMarkdownLink parses a link of the form [label](url "title") from the start of s and returns the label,
+// the url and the length of the link.
+func parseMarkdownLink(s string) (string, string, int, bool) {
+	depth := 0
+	labelEnd := -1
+
+	for i := 0; i < len(s) && labelEnd < 0; i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				labelEnd = i
+			}
+		}
+	}
+
+	if labelEnd < 0 || labelEnd+1 >= len(s) || s[labelEnd+1] != '(' {
+		return "", "", 0, false
+	}
+
+	depth = 0
+	for i := labelEnd + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				destination := strings.TrimSpace(s[labelEnd+2 : i])
+
+				// Ignore the title, if there is one
+				if fields := strings.Fields(destination); len(fields) > 0 {
+					destination = fields[0]
+				}
+
+				destination = strings.TrimSuffix(strings.TrimPrefix(destination, "<"), ">")
+
+				return s[1:labelEnd], destination, i + 1, true
+			}
+		case '\n':
+			return "", "", 0, false
+		}
+	}
+
+	return "", "", 0, false
+}
+
+func parseMarkdownEmphasis(s string, start int, options *MarkdownOptions) (*markdownInline, int, bool) {
+	for _, delimiter := range []string{"**", "__", "~~", "*", "_"} {
+		if !strings.HasPrefix(s[start:], delimiter) {
+			continue
+		}
+
+		open := start + len(delimiter)
+		if next, _ := utf8.DecodeRuneInString(s[open:]); open >= len(s) || unicode.IsSpace(next) {
+			continue
+		}
+
+		// Underscores inside of words, like in snake_case, don't cause emphasis
+		if delimiter[0] == '_' && !isMarkdownWordStart(s, start) {
+			continue
+		}
+
+		close := findMarkdownEmphasisCloser(s, open, delimiter)
+		if close < 0 {
+			continue
+		}
+
+		var kind int
+		switch delimiter {
+		case "**", "__":
+			kind = markdownStrong
+		case "~~":
+			kind = markdownStrikethrough
+		default:
+			kind = markdownEmphasis
+		}
+
+		return &markdownInline{kind: kind, children: parseMarkdownInlines(s[open:close], options)}, close + len(delimiter) - start, true
+	}
+
+	return nil, 0, false
+}
+
+func findMarkdownEmphasisCloser(s string, open int, delimiter string) int {
+	for i := open + 1; i < len(s); {
+		switch s[i] {
+		case '\\':
+			i += 2
+			continue
+		case '`':
+			if _, length, ok := parseMarkdownCodeSpan(s[i:]); ok {
+				i += length
+				continue
+			}
+		case delimiter[0]:
+			run := countLeadingMarkdownChars(s[i:], delimiter[0])
+
+			if run < len(delimiter) || (len(delimiter) == 1 && run == 2) {
+				// This run belongs to a different kind of emphasis
+				i += run
+				continue
+			}
+
+			prev, _ := utf8.DecodeLastRuneInString(s[:i])
+			next, _ := utf8.DecodeRuneInString(s[i+run:])
+			if unicode.IsSpace(prev) || (delimiter[0] == '_' && isMarkdownWordChar(next)) {
+				i += run
+				continue
+			}
+
+			// With a longer run like the end of ***text***, the inner delimiters belong to nested emphasis
+			return i + run - len(delimiter)
+		}
+
+		i++
+	}
+
+	return -1
+}
+
+func parseMarkdownAutolink(s string) string {
+	lower := strings.ToLower(s)
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") && !strings.HasPrefix(lower, "www.") {
+		return ""
+	}
+
+	end := strings.IndexAny(s, " \t\n<")
+	if end < 0 {
+		end = len(s)
+	}
+	url := s[:end]
+
+	// Trailing punctuation is almost always part of the sentence rather than the link
+	for len(url) > 0 {
+		last := url[len(url)-1]
+		if strings.IndexByte(".,:;!?\"'*_~", last) >= 0 {
+			url = url[:len(url)-1]
+		} else if last == ')' && strings.Count(url, "(") < strings.Count(url, ")") {
+			url = url[:len(url)-1]
+		} else {
+			break
+		}
+	}
+
+	if lower := strings.ToLower(url); lower == "http://" || lower == "https://" || lower == "www." {
+		return ""
+	}
+
+	return url
+}
+
+// resolveMarkdownMention returns the display name for the mention at the start of s and the length of the name
+// that was mentioned. Since names can contain periods, dashes and underscores, those are removed from the end of
+// the mention one at a time until it matches something.
+func resolveMarkdownMention(s string, nameRegex *regexp.Regexp, resolve func(string) (string, bool)) (string, int) {
+	name := nameRegex.FindString(s)
+
+	for len(name) > 0 {
+		if displayName, ok := resolve(strings.ToLower(name)); ok {
+			return displayName, len(name)
+		}
+
+		if last := name[len(name)-1]; last != '.' && last != '-' && last != '_' {
+			break
+		}
+		name = name[:len(name)-1]
+	}
+
+	return "", 0
+}
+
+func isMarkdownWordStart(s string, i int) bool {
+	if i == 0 {
+		return true
+	}
+
+	prev, _ := utf8.DecodeLastRuneInString(s[:i])
+	return !isMarkdownWordChar(prev) && prev != '@' && prev != '/'
+}
+
+func isMarkdownWordChar(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+func isMarkdownPunctuation(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+func isSafeMarkdownUrl(url string) bool {
+	lower := strings.ToLower(url)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "mailto:")
+}
+
+func markdownLinkHref(url string) string {
+	if strings.HasPrefix(strings.ToLower(url), "www.") {
+		return "http://" + url
+	}
+	return url
+}
+
+func renderMarkdownBlocksHTML(buf *bytes.Buffer, blocks []*markdownBlock, tight bool, options *MarkdownOptions) {
+	for i, block := range blocks {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+
+		switch block.kind {
+		case markdownParagraph:
+			if tight {
+				renderMarkdownInlinesHTML(buf, parseMarkdownInlines(block.text, options))
+			} else {
+				buf.WriteString("<p>")
+				renderMarkdownInlinesHTML(buf, parseMarkdownInlines(block.text, options))
+				buf.WriteString("</p>")
+			}
+		case markdownHeading:
+			fmt.Fprintf(buf, "<h%d>", block.level)
+			renderMarkdownInlinesHTML(buf, parseMarkdownInlines(block.text, options))
+			fmt.Fprintf(buf, "</h%d>", block.level)
+		case markdownCodeBlock:
+			buf.WriteString("<pre><code>")
+			buf.WriteString(html.EscapeString(block.text))
+			buf.WriteString("</code></pre>")
+		case markdownBlockQuote:
+			buf.WriteString("<blockquote>\n")
+			renderMarkdownBlocksHTML(buf, block.children, false, options)
+			buf.WriteString("\n</blockquote>")
+		case markdownList:
+			tag := "ul"
+			if block.ordered {
+				tag = "ol"
+			}
+
+			if block.ordered && block.start != 1 {
+				fmt.Fprintf(buf, "<ol start=\"%d\">\n", block.start)
+			} else {
+				fmt.Fprintf(buf, "<%s>\n", tag)
+			}
+
+			for _, item := range block.items {
+				buf.WriteString("<li>")
+				renderMarkdownBlocksHTML(buf, item, !block.loose, options)
+				buf.WriteString("</li>\n")
+			}
+
+			fmt.Fprintf(buf, "</%s>", tag)
+		case markdownRule:
+			buf.WriteString("<hr>")
+		}
+	}
+}
+
+func renderMarkdownInlinesHTML(buf *bytes.Buffer, inlines []*markdownInline) {
+	for _, inline := range inlines {
+		switch inline.kind {
+		case markdownText:
+			buf.WriteString(html.EscapeString(inline.text))
+		case markdownCode:
+			buf.WriteString("<code>")
+			buf.WriteString(html.EscapeString(inline.text))
+			buf.WriteString("</code>")
+		case markdownStrong:
+			buf.WriteString("<strong>")
+			renderMarkdownInlinesHTML(buf, inline.children)
+			buf.WriteString("</strong>")
+		case markdownEmphasis:
+			buf.WriteString("<em>")
+			renderMarkdownInlinesHTML(buf, inline.children)
+			buf.WriteString("</em>")
+		case markdownStrikethrough:
+			buf.WriteString("<del>")
+			renderMarkdownInlinesHTML(buf, inline.children)
+			buf.WriteString("</del>")
+		case markdownLink:
+			if href := markdownLinkHref(inline.url); isSafeMarkdownUrl(href) {
+				buf.WriteString("<a href=\"" + html.EscapeString(href) + "\">")
+				renderMarkdownInlinesHTML(buf, inline.children)
+				buf.WriteString("</a>")
+			} else {
+				renderMarkdownInlinesHTML(buf, inline.children)
+			}
+		case markdownImage:
+			// Images are linked to rather than embedded so that emails can't be used to track when they're read
+			text := inline.text
+			if text == "" {
+				text = inline.url
+			}
+
+			if isSafeMarkdownUrl(inline.url) {
+				buf.WriteString("<a href=\"" + html.EscapeString(inline.url) + "\">" + html.EscapeString(text) + "</a>")
+			} else {
+				buf.WriteString(html.EscapeString(text))
+			}
+		case markdownLineBreak:
+			buf.WriteString("<br>\n")
+		}
+	}
+}
+
+func renderMarkdownBlocksText(blocks []*markdownBlock, options *MarkdownOptions) string {
+	parts := make([]string, 0, len(blocks))
+
+	for _, block := range blocks {
+		var text string
+
+		switch block.kind {
+		case markdownParagraph, markdownHeading:
+			text = renderMarkdownInlinesText(parseMarkdownInlines(block.text, options))
+		case markdownCodeBlock:
+			text = block.text
+		case markdownBlockQuote:
+			text = renderMarkdownBlocksText(block.children, options)
+		case markdownList:
+			items := make([]string, len(block.items))
+			for i, item := range block.items {
+				prefix := "- "
+				if block.ordered {
+					prefix = strconv.Itoa(block.start+i) + ". "
+				}
+
+				// Line up the rest of the item with its first line
+				itemText := renderMarkdownBlocksText(item, options)
+				items[i] = prefix + strings.Replace(itemText, "\n", "\n"+strings.Repeat(" ", len(prefix)), -1)
+			}
+			text = strings.Join(items, "\n")
+		}
+
+		if text != "" {
+			parts = append(parts, text)
+		}
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+func renderMarkdownInlinesText(inlines []*markdownInline) string {
+	var buf bytes.Buffer
+
+	for _, inline := range inlines {
+		switch inline.kind {
+		case markdownText, markdownCode:
+			buf.WriteString(inline.text)
+		case markdownStrong, markdownEmphasis, markdownStrikethrough, markdownLink:
+			buf.WriteString(renderMarkdownInlinesText(inline.children))
+		case markdownImage:
+			if inline.text != "" {
+				buf.WriteString(inline.text)
+			} else {
+				buf.WriteString(inline.url)
+			}
+		case markdownLineBreak:
+			buf.WriteString("\n")
+		}
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2017 Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package utils
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateMarkdownGoldenFiles = flag.Bool("update-markdown", false, "rewrite the expected output of the markdown golden file tests")
+
+var testMarkdownOptions = &MarkdownOptions{
+	ResolveUserMention: func(username string) (string, bool) {
+		displayName, ok := map[string]string{"alice": "Alice Smith", "bob": "Bob"}[username]
+		return displayName, ok
+	},
+	ResolveChannelMention: func(channelName string) (string, bool) {
+		displayName, ok := map[string]string{"town-square": "Town Square", "off-topic": "Off-Topic"}[channelName]
+		return displayName, ok
+	},
+}
+
+// TestMarkdownGoldenFiles renders every testdata/markdown/*.md file and compares the output to the .html and .txt
+// files next to it. Run with -update-markdown to regenerate them after an intended change.
+func TestMarkdownGoldenFiles(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "markdown", "*.md"))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(inputs) == 0 {
+		t.Fatal("no golden files found")
+	}
+
+	for _, input := range inputs {
+		markdown, err := ioutil.ReadFile(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		base := strings.TrimSuffix(input, ".md")
+		checkMarkdownGoldenFile(t, base+".html", MarkdownToHTML(string(markdown), testMarkdownOptions)+"\n")
+		checkMarkdownGoldenFile(t, base+".txt", MarkdownToPlainText(string(markdown), testMarkdownOptions)+"\n")
+	}
+}
+
+func checkMarkdownGoldenFile(t *testing.T, path string, actual string) {
+	if *updateMarkdownGoldenFiles {
+		if err := ioutil.WriteFile(path, []byte(actual), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual != string(expected) {
+		t.Errorf("%v doesn't match the rendered output\nexpected:\n%v\nactual:\n%v", path, string(expected), actual)
+	}
+}
+
+func TestMarkdownWithoutOptions(t *testing.T) {
+	if html := MarkdownToHTML("@alice in ~town-square", nil); html != "<p>@alice in ~town-square</p>" {
+		t.Fatal("mentions shouldn't be resolved without options", html)
+	}
+
+	if text := MarkdownToPlainText("", nil); text != "" {
+		t.Fatal("empty messages should render as empty text", text)
+	}
+}